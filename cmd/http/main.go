@@ -36,24 +36,38 @@ func main() {
 func run() error {
 	// Load environment variables
 	cfg := config.New()
-	logger.New(cfg.Application)
+	logLevel := logger.New(cfg.Log)
 
 	slog.Info("starting the application")
 
 	ctx := context.Background()
-	app, cleanup := app.New(ctx, cfg)
+	app, cleanup := app.New(ctx, cfg, logLevel)
 	defer cleanup()
 
-	handler := server.SetupRoutes(app.Handlers, app.Services, app.Adapters)
+	handler := server.SetupRoutes(cfg, app.Handlers, app.Services, app.Adapters)
 	srv := server.New(cfg.HTTP, handler)
+	servers := []*server.Server{srv}
+
+	if app.Adapters.MetricsHandler != nil {
+		metricsSrv := server.NewMetrics(cfg.Metrics, app.Adapters.MetricsHandler)
+		servers = append(servers, metricsSrv)
+		go func() {
+			err := metricsSrv.Serve()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				err = fmt.Errorf("metrics server error: %s", err)
+				app.ErrTracker.CaptureException(ctx, err)
+				slog.Error(err.Error())
+			}
+		}()
+	}
 
 	done := make(chan bool)
-	go gracefulShutdown(srv, done, app.ErrTracker)
+	go gracefulShutdown(servers, done, app.ErrTracker)
 
 	err := srv.Serve()
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		err = fmt.Errorf("http server error: %s", err)
-		app.ErrTracker.CaptureException(err)
+		app.ErrTracker.CaptureException(ctx, err)
 		return err
 	}
 	app.ErrTracker.Flush(2 * time.Second)
@@ -62,8 +76,8 @@ func run() error {
 	return err
 }
 
-// gracefulShutdown manages the graceful shutdown process of the HTTP server.
-func gracefulShutdown(server *server.Server, done chan bool, errTrackerAdapter ports.ErrTrackerAdapter) {
+// gracefulShutdown manages the graceful shutdown process of the HTTP servers.
+func gracefulShutdown(servers []*server.Server, done chan bool, errTrackerAdapter ports.ErrTrackerAdapter) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -77,10 +91,12 @@ func gracefulShutdown(server *server.Server, done chan bool, errTrackerAdapter p
 	// the request it is currently handling
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if err := server.Shutdown(ctx); err != nil {
-		err = fmt.Errorf("server forced to shutdown with error: %v", err)
-		errTrackerAdapter.CaptureException(err)
-		slog.Error(err.Error())
+	for _, server := range servers {
+		if err := server.Shutdown(ctx); err != nil {
+			err = fmt.Errorf("server forced to shutdown with error: %v", err)
+			errTrackerAdapter.CaptureException(ctx, err)
+			slog.Error(err.Error())
+		}
 	}
 
 	slog.Info("server exiting")
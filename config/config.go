@@ -1,8 +1,13 @@
 package config
 
 import (
+	"crypto/tls"
 	"fmt"
 	"go-starter/pkg/env"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,17 +17,78 @@ const (
 	EnvDevelopment = "development"
 )
 
+const (
+	StorageDriverS3    = "s3"
+	StorageDriverLocal = "local"
+)
+
+const (
+	ErrTrackerDriverSentry = "sentry"
+	ErrTrackerDriverLog    = "log"
+	ErrTrackerDriverNoop   = "noop"
+)
+
+const (
+	ScannerDriverNone   = "none"
+	ScannerDriverClamAV = "clamav"
+)
+
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
+const (
+	CacheDriverRedis   = "redis"
+	CacheDriverMemory  = "memory"
+	CacheDriverTwoTier = "two_tier"
+)
+
+const (
+	RateLimitStrategyFixedWindow          = "fixed_window"
+	RateLimitStrategySlidingWindowLog     = "sliding_window_log"
+	RateLimitStrategySlidingWindowCounter = "sliding_window_counter"
+	RateLimitStrategyGCRA                 = "gcra"
+)
+
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyUser   = "user"
+	RateLimitKeyAPIKey = "api_key"
+)
+
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
 type (
 	// Container contains environment variables for the application, database, http server, ...
 	Container struct {
-		Application *App
-		DB          *DB
-		HTTP        *HTTP
-		Redis       *Redis
-		Token       *Token
-		ErrTracker  *ErrTracker
-		Mailer      *Mailer
-		FileUpload  *FileUpload
+		Application  *App
+		Log          *Log
+		DB           *DB
+		HTTP         *HTTP
+		Redis        *Redis
+		Cache        *Cache
+		Token        *Token
+		ErrTracker   *ErrTracker
+		Tracing      *Tracing
+		Mailer       *Mailer
+		MailThrottle *MailThrottle
+		FileUpload   *FileUpload
+		FileScanner  *FileScanner
+		RateLimit    *RateLimit
+		Metrics      *Metrics
+		Health       *Health
 	}
 
 	// App contains all the environment variables for the application.
@@ -31,6 +97,16 @@ type (
 		BaseURL string
 	}
 
+	// Log contains all the environment variables for the logs.
+	// Level is the minimum level logged at startup, it can be changed at runtime by the admins.
+	// Sampling maps route patterns (e.g., "GET /readyz") to the ratio of their requests whose logs are kept,
+	// the warnings and errors being always kept.
+	Log struct {
+		Level    string
+		Format   string
+		Sampling map[string]float64
+	}
+
 	// DB contains all the environment variables for the database.
 	DB struct {
 		Addr         string
@@ -40,15 +116,64 @@ type (
 	}
 
 	// HTTP contains all the environment variables for the http server.
+	// TrustedProxies are the IP addresses or CIDRs of the proxies whose forwarding headers are trusted.
 	HTTP struct {
-		Port int
+		Port           int
+		TrustedProxies []string
+	}
+
+	// Metrics contains all the environment variables for the Prometheus metrics.
+	// The metrics are served on Port, apart from the API, so that they are not exposed publicly.
+	Metrics struct {
+		Enabled bool
+		Port    int
+	}
+
+	// Health contains all the environment variables for the health checks of the dependencies.
+	// Each check fails after CheckTimeout, and their report is reused for CacheTTL.
+	Health struct {
+		CheckTimeout time.Duration
+		CacheTTL     time.Duration
 	}
 
 	// Redis contains all the environment variables for the cache service.
+	// Addrs holds the address of the server in standalone mode, of the sentinels in sentinel mode,
+	// and of some nodes in cluster mode. TLS is used when TLSEnabled is true, the server certificate being verified
+	// against TLSCAFile if set, and the client certificate being sent if TLSCertFile and TLSKeyFile are set.
+	// The circuit breaker stops calling the server after BreakerFailureThreshold consecutive failures,
+	// then lets one call through every BreakerOpenTimeout to detect its recovery.
 	Redis struct {
-		Addr     string
-		Password string
-		DB       int
+		Mode             string
+		Addrs            []string
+		Username         string
+		Password         string
+		DB               int
+		SentinelMaster   string
+		SentinelPassword string
+
+		TLSEnabled    bool
+		TLSCAFile     string
+		TLSCertFile   string
+		TLSKeyFile    string
+		TLSServerName string
+		TLSMinVersion uint16
+
+		BreakerFailureThreshold int
+		BreakerOpenTimeout      time.Duration
+	}
+
+	// Cache contains all the environment variables for the cache.
+	// The memory driver keeps the cache in the process, for single instance deployments, and the two_tier driver
+	// keeps the keys starting with one of the LocalPrefixes in the process for at most LocalTTL, in front of Redis.
+	// MaxEntries and MaxSize (in bytes) bound the cache kept in the process.
+	// LoadLockTimeout, when positive, makes a single instance load a value missing from the cache, e.g. a user.
+	Cache struct {
+		Driver          string
+		MaxEntries      int
+		MaxSize         int64
+		LocalTTL        time.Duration
+		LocalPrefixes   []string
+		LoadLockTimeout time.Duration
 	}
 
 	// Token contains all the environment variables for the token service.
@@ -59,26 +184,106 @@ type (
 	}
 
 	// ErrTracker contains all the environment variables for the error tracking.
+	// The errors are reported to each of the Drivers, e.g. to Sentry and to the logs.
+	// The request bodies attached to the reports are scrubbed: the fields whose name contains an item of BodyDenylist
+	// are dropped, only the fields listed in BodyAllowlist are kept for its route patterns (e.g., "POST /v1/auth/login"),
+	// and the bodies larger than MaxBodySize bytes once scrubbed are left out.
 	ErrTracker struct {
+		Drivers          []string
 		DSN              string
 		TracesSampleRate float64
+		BodyDenylist     []string
+		BodyAllowlist    map[string][]string
+		MaxBodySize      int
+	}
+
+	// Tracing contains all the environment variables for the OpenTelemetry tracing.
+	// The spans are exported with OTLP over HTTP to OTLPEndpoint, a URL such as http://localhost:4318,
+	// and SampleRatio of the traces started by the application are kept; the ones started upstream follow their parent.
+	Tracing struct {
+		Enabled      bool
+		OTLPEndpoint string
+		ServiceName  string
+		SampleRatio  float64
 	}
 
 	// Mailer contains all the environment variables for the mailer.
 	Mailer struct {
-		Region    string
-		AccessKey string
-		SecretKey string
-		From      string
-		DebugTo   string
+		Region          string
+		AccessKey       string
+		SecretKey       string
+		From            string
+		DebugTo         string
+		WebhookTopicARN string
+	}
+
+	// MailThrottle contains all the environment variables for the email quotas.
+	// Templates without their own quotas use the default ones.
+	MailThrottle struct {
+		Default   MailQuota
+		Templates map[string]MailQuota
+	}
+
+	// MailQuota contains the maximum number of emails of a template sent per recipient and per user.
+	// A zero value disables the quota.
+	MailQuota struct {
+		RecipientHourly int
+		RecipientDaily  int
+		UserHourly      int
+		UserDaily       int
 	}
 
 	// FileUpload contains all the environment variables for the file uploader.
+	// The S3 variables are only used by the s3 driver, the local ones by the local driver.
 	FileUpload struct {
-		Region    string
-		AccessKey string
-		SecretKey string
-		Bucket    string
+		Driver       string
+		Region       string
+		AccessKey    string
+		SecretKey    string
+		Bucket       string
+		Endpoint     string
+		UsePathStyle bool
+		LocalDir     string
+		SigningKey   string
+
+		PresignedUploadDuration      time.Duration
+		PendingUploadCleanupInterval time.Duration
+
+		// MaxFileSize and UserStorageQuota are in bytes.
+		MaxFileSize            int64
+		UserStorageQuota       int64
+		PrivateFileURLDuration time.Duration
+	}
+
+	// FileScanner contains all the environment variables for the malware scanning of the uploaded files.
+	// Addr and Timeout are only used by the clamav driver.
+	FileScanner struct {
+		Driver  string
+		Addr    string
+		Timeout time.Duration
+	}
+
+	// RateLimit contains all the environment variables for the rate limit policies.
+	// A request is counted against every policy matching its route.
+	// APIKeys are the API keys the clients are keyed by, the requests with other keys are keyed by IP address.
+	RateLimit struct {
+		Policies []RateLimitPolicy
+		APIKeys  []string
+	}
+
+	// RateLimitPolicy contains the limit of the requests to the routes matching its patterns.
+	// Routes are http.ServeMux patterns, such as "/" or "POST /v1/auth/login".
+	// KeyBy lists the parts of the request the clients are told apart by, combined in this order;
+	// a part missing from the request, such as the user of an anonymous request, is replaced by the IP address.
+	// RoleLimits overrides Limit for the authenticated users of the given roles.
+	RateLimitPolicy struct {
+		Name       string
+		Routes     []string
+		Limit      int64
+		Window     time.Duration
+		Strategy   string
+		KeyBy      []string
+		RoleLimits map[string]int64
 	}
 )
 
@@ -89,6 +294,8 @@ func New() *Container {
 		BaseURL: env.GetString("BASE_URL"),
 	}
 
+	log := newLog(app.Env)
+
 	db := &DB{
 		Addr:         env.GetString("DB_ADDR"),
 		MaxOpenConns: env.GetOptionalInt("DB_MAX_OPEN_CONNS", 30),
@@ -97,13 +304,47 @@ func New() *Container {
 	}
 
 	http := &HTTP{
-		Port: env.GetOptionalInt("HTTP_PORT", 8080),
+		Port:           env.GetOptionalInt("HTTP_PORT", 8080),
+		TrustedProxies: splitList(env.GetOptionalString("TRUSTED_PROXIES", "")),
+	}
+
+	metrics := &Metrics{
+		Enabled: env.GetOptionalBool("METRICS_ENABLED", true),
+		Port:    env.GetOptionalInt("METRICS_PORT", 9090),
+	}
+
+	health := &Health{
+		CheckTimeout: env.GetOptionalDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		CacheTTL:     env.GetOptionalDuration("HEALTH_CACHE_TTL", 5*time.Second),
 	}
 
 	redis := &Redis{
-		Addr:     env.GetString("REDIS_ADDR"),
-		Password: env.GetOptionalString("REDIS_PASSWORD", ""),
-		DB:       env.GetOptionalInt("REDIS_DB", 0),
+		Mode:             env.GetOptionalString("REDIS_MODE", RedisModeStandalone),
+		Addrs:            splitList(env.GetOptionalString("REDIS_ADDR", "")),
+		Username:         env.GetOptionalString("REDIS_USERNAME", ""),
+		Password:         env.GetOptionalString("REDIS_PASSWORD", ""),
+		DB:               env.GetOptionalInt("REDIS_DB", 0),
+		SentinelMaster:   env.GetOptionalString("REDIS_SENTINEL_MASTER", ""),
+		SentinelPassword: env.GetOptionalString("REDIS_SENTINEL_PASSWORD", ""),
+
+		TLSEnabled:    env.GetOptionalBool("REDIS_TLS_ENABLED", false),
+		TLSCAFile:     env.GetOptionalString("REDIS_TLS_CA_FILE", ""),
+		TLSCertFile:   env.GetOptionalString("REDIS_TLS_CERT_FILE", ""),
+		TLSKeyFile:    env.GetOptionalString("REDIS_TLS_KEY_FILE", ""),
+		TLSServerName: env.GetOptionalString("REDIS_TLS_SERVER_NAME", ""),
+		TLSMinVersion: parseTLSVersion(env.GetOptionalString("REDIS_TLS_MIN_VERSION", "1.2")),
+
+		BreakerFailureThreshold: env.GetOptionalInt("REDIS_BREAKER_FAILURE_THRESHOLD", 5),
+		BreakerOpenTimeout:      env.GetOptionalDuration("REDIS_BREAKER_OPEN_TIMEOUT", 10*time.Second),
+	}
+
+	cache := &Cache{
+		Driver:          env.GetOptionalString("CACHE_DRIVER", CacheDriverRedis),
+		MaxEntries:      env.GetOptionalInt("CACHE_MAX_ENTRIES", 10000),
+		MaxSize:         int64(env.GetOptionalInt("CACHE_MAX_SIZE_MB", 64)) << 20,
+		LocalTTL:        env.GetOptionalDuration("CACHE_LOCAL_TTL", 30*time.Second),
+		LocalPrefixes:   splitList(env.GetOptionalString("CACHE_LOCAL_PREFIXES", "user:")),
+		LoadLockTimeout: env.GetOptionalDuration("CACHE_LOAD_LOCK_TIMEOUT", 0),
 	}
 
 	token := &Token{
@@ -112,35 +353,70 @@ func New() *Container {
 		PasswordResetTokenDuration:     env.GetOptionalDuration("PASSWORD_RESET_TOKEN_DURATION", 15*time.Minute),
 	}
 
-	errTracker := &ErrTracker{
-		DSN:              env.GetOptionalString("SENTRY_DSN", ""),
-		TracesSampleRate: env.GetOptionalFloat64("SENTRY_TRACES_SAMPLE_RATE", 1.0),
+	errTracker := newErrTracker()
+
+	tracing := &Tracing{
+		Enabled:      env.GetOptionalBool("TRACING_ENABLED", false),
+		OTLPEndpoint: env.GetOptionalString("TRACING_OTLP_ENDPOINT", "http://localhost:4318"),
+		ServiceName:  env.GetOptionalString("TRACING_SERVICE_NAME", "go-starter"),
+		SampleRatio:  env.GetOptionalFloat64("TRACING_SAMPLE_RATIO", 1.0),
 	}
 
 	mailer := &Mailer{
-		Region:    env.GetString("SES_REGION"),
-		AccessKey: env.GetString("SES_ACCESS_KEY"),
-		SecretKey: env.GetString("SES_SECRET_KEY"),
-		From:      env.GetString("SES_FROM"),
-		DebugTo:   env.GetString("SES_DEBUG_TO"),
+		Region:          env.GetString("SES_REGION"),
+		AccessKey:       env.GetString("SES_ACCESS_KEY"),
+		SecretKey:       env.GetString("SES_SECRET_KEY"),
+		From:            env.GetString("SES_FROM"),
+		DebugTo:         env.GetString("SES_DEBUG_TO"),
+		WebhookTopicARN: env.GetString("SES_WEBHOOK_TOPIC_ARN"),
 	}
 
+	mailThrottle := newMailThrottle()
+
 	fileUpload := &FileUpload{
-		Region:    env.GetString("S3_REGION"),
-		AccessKey: env.GetString("S3_ACCESS_KEY"),
-		SecretKey: env.GetString("S3_SECRET_KEY"),
-		Bucket:    env.GetString("S3_BUCKET"),
+		Driver:       env.GetOptionalString("STORAGE_DRIVER", StorageDriverS3),
+		Region:       env.GetOptionalString("S3_REGION", ""),
+		AccessKey:    env.GetOptionalString("S3_ACCESS_KEY", ""),
+		SecretKey:    env.GetOptionalString("S3_SECRET_KEY", ""),
+		Bucket:       env.GetOptionalString("S3_BUCKET", ""),
+		Endpoint:     env.GetOptionalString("S3_ENDPOINT", ""),
+		UsePathStyle: env.GetOptionalBool("S3_USE_PATH_STYLE", false),
+		LocalDir:     env.GetOptionalString("STORAGE_LOCAL_DIR", "storage"),
+		SigningKey:   env.GetOptionalString("STORAGE_SIGNING_KEY", ""),
+
+		PresignedUploadDuration:      env.GetOptionalDuration("PRESIGNED_UPLOAD_DURATION", 15*time.Minute),
+		PendingUploadCleanupInterval: env.GetOptionalDuration("PENDING_UPLOAD_CLEANUP_INTERVAL", time.Hour),
+
+		MaxFileSize:            int64(env.GetOptionalInt("MAX_FILE_SIZE_MB", 10)) << 20,
+		UserStorageQuota:       int64(env.GetOptionalInt("USER_STORAGE_QUOTA_MB", 100)) << 20,
+		PrivateFileURLDuration: env.GetOptionalDuration("PRIVATE_FILE_URL_DURATION", 15*time.Minute),
+	}
+
+	fileScanner := &FileScanner{
+		Driver:  env.GetOptionalString("FILE_SCANNER_DRIVER", ScannerDriverNone),
+		Addr:    env.GetOptionalString("CLAMAV_ADDR", "tcp://localhost:3310"),
+		Timeout: env.GetOptionalDuration("CLAMAV_TIMEOUT", 30*time.Second),
 	}
 
+	rateLimit := newRateLimit()
+
 	c := &Container{
-		Application: app,
-		DB:          db,
-		HTTP:        http,
-		Redis:       redis,
-		Token:       token,
-		ErrTracker:  errTracker,
-		Mailer:      mailer,
-		FileUpload:  fileUpload,
+		Application:  app,
+		Log:          log,
+		DB:           db,
+		HTTP:         http,
+		Redis:        redis,
+		Cache:        cache,
+		Token:        token,
+		ErrTracker:   errTracker,
+		Tracing:      tracing,
+		Mailer:       mailer,
+		MailThrottle: mailThrottle,
+		FileUpload:   fileUpload,
+		FileScanner:  fileScanner,
+		RateLimit:    rateLimit,
+		Metrics:      metrics,
+		Health:       health,
 	}
 
 	err := c.validate()
@@ -158,6 +434,23 @@ func (c *Container) validate() error {
 		return fmt.Errorf("invalid environment variable: %s", "ENVIRONMENT")
 	}
 
+	// Log
+	switch c.Log.Level {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		return fmt.Errorf("invalid environment variable: %s", "LOG_LEVEL")
+	}
+
+	if c.Log.Format != LogFormatText && c.Log.Format != LogFormatJSON {
+		return fmt.Errorf("invalid environment variable: %s", "LOG_FORMAT")
+	}
+
+	for route, ratio := range c.Log.Sampling {
+		if route == "" || ratio < 0 || ratio > 1 {
+			return fmt.Errorf("invalid environment variable: %s should list route=ratio pairs with ratios between 0 and 1", "LOG_SAMPLING")
+		}
+	}
+
 	// DB
 	if c.DB.MaxIdleTime < 0 {
 		return fmt.Errorf("invalid environment variable: %s", "DB_MAX_IDLE_TIME")
@@ -180,6 +473,89 @@ func (c *Container) validate() error {
 		return fmt.Errorf("invalid environment variable: %s", "HTTP_PORT")
 	}
 
+	for _, proxy := range c.HTTP.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				return fmt.Errorf("invalid environment variable: %s should list IP addresses or CIDRs", "TRUSTED_PROXIES")
+			}
+		}
+	}
+
+	// Metrics
+	if c.Metrics.Enabled && (c.Metrics.Port <= 0 || c.Metrics.Port == c.HTTP.Port) {
+		return fmt.Errorf("invalid environment variable: %s should be a port other than %s", "METRICS_PORT", "HTTP_PORT")
+	}
+
+	// Health
+	if c.Health.CheckTimeout <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "HEALTH_CHECK_TIMEOUT")
+	}
+
+	if c.Health.CacheTTL < 0 {
+		return fmt.Errorf("invalid environment variable: %s", "HEALTH_CACHE_TTL")
+	}
+
+	// Redis
+	if len(c.Redis.Addrs) == 0 && c.Cache.Driver != CacheDriverMemory {
+		return fmt.Errorf("environment variable %s not set", "REDIS_ADDR")
+	}
+
+	switch c.Redis.Mode {
+	case RedisModeStandalone:
+		if len(c.Redis.Addrs) > 1 {
+			return fmt.Errorf("invalid environment variable: %s", "REDIS_ADDR")
+		}
+	case RedisModeSentinel:
+		if c.Redis.SentinelMaster == "" {
+			return fmt.Errorf("environment variable %s not set", "REDIS_SENTINEL_MASTER")
+		}
+	case RedisModeCluster:
+		if c.Redis.DB != 0 {
+			return fmt.Errorf("invalid environment variable: %s", "REDIS_DB")
+		}
+	default:
+		return fmt.Errorf("invalid environment variable: %s", "REDIS_MODE")
+	}
+
+	if c.Redis.TLSMinVersion == 0 {
+		return fmt.Errorf("invalid environment variable: %s", "REDIS_TLS_MIN_VERSION")
+	}
+
+	if (c.Redis.TLSCertFile == "") != (c.Redis.TLSKeyFile == "") {
+		return fmt.Errorf("environment variables %s and %s must be set together", "REDIS_TLS_CERT_FILE", "REDIS_TLS_KEY_FILE")
+	}
+
+	if c.Redis.BreakerFailureThreshold <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "REDIS_BREAKER_FAILURE_THRESHOLD")
+	}
+
+	if c.Redis.BreakerOpenTimeout <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "REDIS_BREAKER_OPEN_TIMEOUT")
+	}
+
+	// Cache
+	switch c.Cache.Driver {
+	case CacheDriverRedis, CacheDriverMemory, CacheDriverTwoTier:
+	default:
+		return fmt.Errorf("invalid environment variable: %s", "CACHE_DRIVER")
+	}
+
+	if c.Cache.MaxEntries <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "CACHE_MAX_ENTRIES")
+	}
+
+	if c.Cache.MaxSize <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "CACHE_MAX_SIZE_MB")
+	}
+
+	if c.Cache.LocalTTL <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "CACHE_LOCAL_TTL")
+	}
+
+	if c.Cache.LoadLockTimeout < 0 {
+		return fmt.Errorf("invalid environment variable: %s", "CACHE_LOAD_LOCK_TIMEOUT")
+	}
+
 	// Token
 	if c.Token.AccessTokenDuration < 0 {
 		return fmt.Errorf("invalid environment variable: %s", "ACCESS_TOKEN_DURATION")
@@ -190,5 +566,376 @@ func (c *Container) validate() error {
 		return fmt.Errorf("invalid environment variable: %s should be between 0 and 1", "SENTRY_TRACES_SAMPLE_RATE")
 	}
 
+	if len(c.ErrTracker.Drivers) == 0 {
+		return fmt.Errorf("invalid environment variable: %s", "ERROR_TRACKER_DRIVERS")
+	}
+
+	for _, driver := range c.ErrTracker.Drivers {
+		switch driver {
+		case ErrTrackerDriverSentry:
+			if c.ErrTracker.DSN == "" {
+				return fmt.Errorf("environment variable %s not set, it is required by the %s error tracker driver", "SENTRY_DSN", ErrTrackerDriverSentry)
+			}
+		case ErrTrackerDriverLog, ErrTrackerDriverNoop:
+		default:
+			return fmt.Errorf("invalid environment variable: %s should list sentry, log or noop", "ERROR_TRACKER_DRIVERS")
+		}
+	}
+
+	for route := range c.ErrTracker.BodyAllowlist {
+		if route == "" {
+			return fmt.Errorf("invalid environment variable: %s should list route=field|field pairs", "SENTRY_BODY_ALLOWLIST")
+		}
+	}
+
+	if c.ErrTracker.MaxBodySize <= 0 {
+		return fmt.Errorf("invalid environment variable: %s should be positive", "SENTRY_MAX_BODY_SIZE")
+	}
+
+	// Tracing
+	if c.Tracing.Enabled {
+		if c.Tracing.OTLPEndpoint == "" {
+			return fmt.Errorf("environment variable %s not set", "TRACING_OTLP_ENDPOINT")
+		}
+		if c.Tracing.ServiceName == "" {
+			return fmt.Errorf("environment variable %s not set", "TRACING_SERVICE_NAME")
+		}
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1.0 {
+		return fmt.Errorf("invalid environment variable: %s should be between 0 and 1", "TRACING_SAMPLE_RATIO")
+	}
+
+	// FileUpload
+	switch c.FileUpload.Driver {
+	case StorageDriverS3:
+		required := map[string]string{
+			"S3_REGION":     c.FileUpload.Region,
+			"S3_ACCESS_KEY": c.FileUpload.AccessKey,
+			"S3_SECRET_KEY": c.FileUpload.SecretKey,
+			"S3_BUCKET":     c.FileUpload.Bucket,
+		}
+		for key, val := range required {
+			if val == "" {
+				return fmt.Errorf("environment variable %s not set, it is required by the %s storage driver", key, StorageDriverS3)
+			}
+		}
+	case StorageDriverLocal:
+		if c.FileUpload.SigningKey == "" {
+			return fmt.Errorf("environment variable %s not set, it is required by the %s storage driver", "STORAGE_SIGNING_KEY", StorageDriverLocal)
+		}
+	default:
+		return fmt.Errorf("invalid environment variable: %s", "STORAGE_DRIVER")
+	}
+
+	if c.FileUpload.PresignedUploadDuration <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "PRESIGNED_UPLOAD_DURATION")
+	}
+
+	if c.FileUpload.PendingUploadCleanupInterval <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "PENDING_UPLOAD_CLEANUP_INTERVAL")
+	}
+
+	if c.FileUpload.MaxFileSize <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "MAX_FILE_SIZE_MB")
+	}
+
+	if c.FileUpload.UserStorageQuota <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "USER_STORAGE_QUOTA_MB")
+	}
+
+	if c.FileUpload.PrivateFileURLDuration <= 0 {
+		return fmt.Errorf("invalid environment variable: %s", "PRIVATE_FILE_URL_DURATION")
+	}
+
+	// FileScanner
+	switch c.FileScanner.Driver {
+	case ScannerDriverNone:
+	case ScannerDriverClamAV:
+		if !strings.HasPrefix(c.FileScanner.Addr, "tcp://") && !strings.HasPrefix(c.FileScanner.Addr, "unix://") {
+			return fmt.Errorf("invalid environment variable: %s should start with tcp:// or unix://", "CLAMAV_ADDR")
+		}
+		if c.FileScanner.Timeout <= 0 {
+			return fmt.Errorf("invalid environment variable: %s", "CLAMAV_TIMEOUT")
+		}
+	default:
+		return fmt.Errorf("invalid environment variable: %s", "FILE_SCANNER_DRIVER")
+	}
+
+	// RateLimit
+	for _, policy := range c.RateLimit.Policies {
+		prefix := rateLimitPrefix(policy.Name)
+		if len(policy.Routes) == 0 {
+			return fmt.Errorf("environment variable %s not set, it is required by the %s rate limit policy", prefix+"_ROUTES", policy.Name)
+		}
+		if err := checkRoutePatterns(policy.Routes); err != nil {
+			return fmt.Errorf("invalid environment variable: %s: %w", prefix+"_ROUTES", err)
+		}
+		if policy.Limit <= 0 {
+			return fmt.Errorf("invalid environment variable: %s", prefix+"_LIMIT")
+		}
+		if policy.Window < time.Millisecond {
+			return fmt.Errorf("invalid environment variable: %s", prefix+"_WINDOW")
+		}
+		switch policy.Strategy {
+		case RateLimitStrategyFixedWindow, RateLimitStrategySlidingWindowLog, RateLimitStrategySlidingWindowCounter, RateLimitStrategyGCRA:
+		default:
+			return fmt.Errorf("invalid environment variable: %s", prefix+"_STRATEGY")
+		}
+		if len(policy.KeyBy) == 0 {
+			return fmt.Errorf("invalid environment variable: %s", prefix+"_KEY_BY")
+		}
+		for _, key := range policy.KeyBy {
+			if key != RateLimitKeyIP && key != RateLimitKeyUser && key != RateLimitKeyAPIKey {
+				return fmt.Errorf("invalid environment variable: %s should list %s, %s or %s", prefix+"_KEY_BY", RateLimitKeyIP, RateLimitKeyUser, RateLimitKeyAPIKey)
+			}
+		}
+		for role, limit := range policy.RoleLimits {
+			if role == "" || limit <= 0 {
+				return fmt.Errorf("invalid environment variable: %s should list role=limit pairs with positive limits", prefix+"_ROLE_LIMITS")
+			}
+		}
+	}
+
+	// MailThrottle
+	quotas := map[string]MailQuota{"": c.MailThrottle.Default}
+	for template, quota := range c.MailThrottle.Templates {
+		quotas[template] = quota
+	}
+	for template, quota := range quotas {
+		if quota.RecipientHourly < 0 || quota.RecipientDaily < 0 || quota.UserHourly < 0 || quota.UserDaily < 0 {
+			return fmt.Errorf("invalid environment variable: %s quotas should be positive", mailThrottlePrefix(template))
+		}
+	}
+
+	return nil
+}
+
+// newLog reads the settings of the logs. They are written as text in development, and as JSON otherwise.
+// Sampling ratios that cannot be parsed are read as -1, for the validation to reject them.
+func newLog(appEnv string) *Log {
+	format := LogFormatJSON
+	if appEnv == EnvDevelopment {
+		format = LogFormatText
+	}
+
+	sampling := map[string]float64{}
+	for _, pair := range splitList(env.GetOptionalString("LOG_SAMPLING", "GET /livez=0.01,GET /readyz=0.01")) {
+		route, value, _ := strings.Cut(pair, "=")
+		ratio, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			ratio = -1
+		}
+		sampling[strings.TrimSpace(route)] = ratio
+	}
+
+	return &Log{
+		Level:    strings.ToLower(env.GetOptionalString("LOG_LEVEL", LogLevelInfo)),
+		Format:   env.GetOptionalString("LOG_FORMAT", format),
+		Sampling: sampling,
+	}
+}
+
+// newErrTracker reads the settings of the error tracking.
+// The errors are reported to Sentry when its DSN is set, and to the logs otherwise.
+// The allowlist maps each route pattern to its fields separated by "|", a route without fields keeps no body.
+func newErrTracker() *ErrTracker {
+	allowlist := map[string][]string{}
+	defaultAllowlist := "POST /v1/auth/login=username,POST /v1/auth/register=username,POST /v1/auth/password-reset="
+	for _, pair := range splitList(env.GetOptionalString("SENTRY_BODY_ALLOWLIST", defaultAllowlist)) {
+		route, value, _ := strings.Cut(pair, "=")
+		fields := []string{}
+		for _, field := range strings.Split(value, "|") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+		allowlist[strings.TrimSpace(route)] = fields
+	}
+
+	dsn := env.GetOptionalString("SENTRY_DSN", "")
+	driver := ErrTrackerDriverLog
+	if dsn != "" {
+		driver = ErrTrackerDriverSentry
+	}
+
+	return &ErrTracker{
+		Drivers:          splitList(env.GetOptionalString("ERROR_TRACKER_DRIVERS", driver)),
+		DSN:              dsn,
+		TracesSampleRate: env.GetOptionalFloat64("SENTRY_TRACES_SAMPLE_RATE", 1.0),
+		BodyDenylist:     splitList(env.GetOptionalString("SENTRY_BODY_DENYLIST", "password,token,secret,authorization,api_key,card_number,cvv")),
+		BodyAllowlist:    allowlist,
+		MaxBodySize:      env.GetOptionalInt("SENTRY_MAX_BODY_SIZE", 4096),
+	}
+}
+
+// newMailThrottle reads the default email quotas, then the quotas of each template listed in MAIL_THROTTLE_TEMPLATES.
+// The quotas of a template are read from MAIL_THROTTLE_<TEMPLATE>_*, falling back to the default ones.
+func newMailThrottle() *MailThrottle {
+	defaultQuota := readMailQuota("", MailQuota{
+		RecipientHourly: 5,
+		RecipientDaily:  20,
+		UserHourly:      5,
+		UserDaily:       20,
+	})
+
+	templates := map[string]MailQuota{}
+	for _, template := range strings.Split(env.GetOptionalString("MAIL_THROTTLE_TEMPLATES", "verify_email,reset_password"), ",") {
+		template = strings.TrimSpace(template)
+		if template == "" {
+			continue
+		}
+		templates[template] = readMailQuota(template, defaultQuota)
+	}
+
+	return &MailThrottle{
+		Default:   defaultQuota,
+		Templates: templates,
+	}
+}
+
+// readMailQuota reads the quotas of a template, or the default quotas if the template is empty.
+func readMailQuota(template string, defaultQuota MailQuota) MailQuota {
+	prefix := mailThrottlePrefix(template)
+	return MailQuota{
+		RecipientHourly: env.GetOptionalInt(prefix+"_RECIPIENT_HOURLY", defaultQuota.RecipientHourly),
+		RecipientDaily:  env.GetOptionalInt(prefix+"_RECIPIENT_DAILY", defaultQuota.RecipientDaily),
+		UserHourly:      env.GetOptionalInt(prefix+"_USER_HOURLY", defaultQuota.UserHourly),
+		UserDaily:       env.GetOptionalInt(prefix+"_USER_DAILY", defaultQuota.UserDaily),
+	}
+}
+
+// mailThrottlePrefix returns the prefix of the environment variables holding the quotas of a template.
+func mailThrottlePrefix(template string) string {
+	if template == "" {
+		return "MAIL_THROTTLE"
+	}
+	return "MAIL_THROTTLE_" + strings.ToUpper(template)
+}
+
+// Quota returns the email quotas of a template, or the default quotas if the template has none.
+func (mt *MailThrottle) Quota(template string) MailQuota {
+	if quota, ok := mt.Templates[template]; ok {
+		return quota
+	}
+	return mt.Default
+}
+
+// defaultRateLimitPolicies are the rate limit policies applied unless RATE_LIMIT_POLICIES says otherwise,
+// and the default values of the policies of the same name.
+var defaultRateLimitPolicies = map[string]RateLimitPolicy{
+	"global": {
+		Routes:   []string{"/"},
+		Limit:    200,
+		Window:   time.Minute,
+		Strategy: RateLimitStrategyFixedWindow,
+		KeyBy:    []string{RateLimitKeyUser},
+	},
+	"mail": {
+		Routes:   []string{"POST /v1/auth/password-reset", "POST /v1/users/me/verify-email/resend"},
+		Limit:    1,
+		Window:   time.Minute,
+		Strategy: RateLimitStrategyFixedWindow,
+		KeyBy:    []string{RateLimitKeyIP},
+	},
+}
+
+// newRateLimit reads the rate limit policies listed in RATE_LIMIT_POLICIES.
+// The settings of a policy are read from RATE_LIMIT_<POLICY>_*, falling back to the default policy of the same name if any.
+func newRateLimit() *RateLimit {
+	policies := []RateLimitPolicy{}
+	for _, name := range splitList(env.GetOptionalString("RATE_LIMIT_POLICIES", "global,mail")) {
+		policies = append(policies, readRateLimitPolicy(name, defaultRateLimitPolicies[name]))
+	}
+
+	return &RateLimit{
+		Policies: policies,
+		APIKeys:  splitList(env.GetOptionalString("RATE_LIMIT_API_KEYS", "")),
+	}
+}
+
+// readRateLimitPolicy reads the settings of a rate limit policy.
+// Role limits that cannot be parsed are read as zero, for the validation to reject them.
+func readRateLimitPolicy(name string, defaultPolicy RateLimitPolicy) RateLimitPolicy {
+	prefix := rateLimitPrefix(name)
+
+	strategy := defaultPolicy.Strategy
+	if strategy == "" {
+		strategy = RateLimitStrategyFixedWindow
+	}
+	keyBy := strings.Join(defaultPolicy.KeyBy, ",")
+	if keyBy == "" {
+		keyBy = RateLimitKeyIP
+	}
+
+	roleLimits := map[string]int64{}
+	for _, pair := range splitList(env.GetOptionalString(prefix+"_ROLE_LIMITS", "")) {
+		role, value, _ := strings.Cut(pair, "=")
+		limit, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			limit = 0
+		}
+		roleLimits[strings.TrimSpace(role)] = limit
+	}
+
+	return RateLimitPolicy{
+		Name:       name,
+		Routes:     splitList(env.GetOptionalString(prefix+"_ROUTES", strings.Join(defaultPolicy.Routes, ","))),
+		Limit:      int64(env.GetOptionalInt(prefix+"_LIMIT", int(defaultPolicy.Limit))),
+		Window:     env.GetOptionalDuration(prefix+"_WINDOW", defaultPolicy.Window),
+		Strategy:   env.GetOptionalString(prefix+"_STRATEGY", strategy),
+		KeyBy:      splitList(env.GetOptionalString(prefix+"_KEY_BY", keyBy)),
+		RoleLimits: roleLimits,
+	}
+}
+
+// rateLimitPrefix returns the prefix of the environment variables holding the settings of a rate limit policy.
+func rateLimitPrefix(policy string) string {
+	return "RATE_LIMIT_" + strings.ToUpper(policy)
+}
+
+// checkRoutePatterns checks that the route patterns are valid http.ServeMux patterns, none of them listed twice.
+// Returns an error for the first invalid or duplicated pattern.
+func checkRoutePatterns(patterns []string) (err error) {
+	mux := http.NewServeMux()
+	// http.ServeMux panics on an invalid or conflicting pattern
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	seen := make(map[string]bool, len(patterns))
+	for _, pattern := range patterns {
+		if seen[pattern] {
+			return fmt.Errorf("pattern %q is listed twice", pattern)
+		}
+		seen[pattern] = true
+		mux.Handle(pattern, http.NotFoundHandler())
+	}
 	return nil
 }
+
+// parseTLSVersion parses a TLS version such as "1.2".
+// Returns 0 if the version is not supported.
+func parseTLSVersion(version string) uint16 {
+	switch version {
+	case "1.2":
+		return tls.VersionTLS12
+	case "1.3":
+		return tls.VersionTLS13
+	default:
+		return 0
+	}
+}
+
+// splitList splits a comma separated list, trimming the items and dropping the empty ones.
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"go-starter/config"
+	"go-starter/internal/adapters/imaging"
 	"go-starter/internal/adapters/mailer"
+	"go-starter/internal/adapters/metrics"
+	"go-starter/internal/adapters/ratelimiter"
+	"go-starter/internal/adapters/scanner"
 	"go-starter/internal/adapters/storage/cache"
 	"go-starter/internal/adapters/storage/database"
 	"go-starter/internal/adapters/storage/database/migrations"
@@ -12,77 +16,172 @@ import (
 	"go-starter/internal/adapters/storage/fileupload"
 	"go-starter/internal/adapters/timegen"
 	"go-starter/internal/adapters/token"
+	"go-starter/internal/adapters/tracing"
 	"go-starter/internal/domain/ports"
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Adapters holds all repository implementations for the application.
 type Adapters struct {
-	TimeGenerator     ports.TimeGenerator
-	DB                *sql.DB
-	UserRepository    ports.UserRepository
-	TokenRepository   ports.TokenProvider
-	CacheRepository   ports.CacheRepository
-	ErrTrackerAdapter ports.ErrTrackerAdapter
-	MailerAdapter     ports.MailerAdapter
-	FileUploadAdapter ports.FileUploadAdapter
+	TimeGenerator              ports.TimeGenerator
+	DB                         *sql.DB
+	UserRepository             ports.UserRepository
+	EmailSuppressionRepository ports.EmailSuppressionRepository
+	EmailDeliveryRepository    ports.EmailDeliveryRepository
+	PendingUploadRepository    ports.PendingUploadRepository
+	FileRepository             ports.FileRepository
+	TokenRepository            ports.TokenProvider
+	CacheRepository            ports.CacheRepository
+	ErrTrackerAdapter          ports.ErrTrackerAdapter
+	MailerAdapter              ports.MailerAdapter
+	MailerWebhookAdapter       ports.MailerWebhookAdapter
+	MailRateLimiter            ports.RateLimiter
+	FileUploadAdapter          ports.FileUploadAdapter
+	FileServerAdapter          ports.FileServerAdapter
+	ImageProcessor             ports.ImageProcessor
+	FileScanner                ports.FileScanner
+	Metrics                    ports.Metrics
+	MetricsHandler             http.Handler
+	TracerProvider             trace.TracerProvider
 }
 
 // New creates and initializes a new Adapters instance with the provided dependencies.
 func New(ctx context.Context, cfg *config.Container, errTracker ports.ErrTrackerAdapter) *Adapters {
 	timeGenerator := timegen.NewTimeGenerator()
+	tracerProvider := initializeTracing(cfg, errTracker)
 	db := initializeDatabaseAndMigrate(ctx, cfg.DB, errTracker)
+	metricsAdapter, metricsHandler := initializeMetrics(cfg.Metrics, db)
+	cacheRepository := cache.NewInstrumented(initializeCache(ctx, cfg, timeGenerator, errTracker, tracerProvider), metricsAdapter)
+	fileUploadAdapter, fileServerAdapter := initializeFileUpload(cfg, errTracker, tracerProvider)
 
 	return &Adapters{
-		TimeGenerator:     timeGenerator,
-		DB:                db,
-		UserRepository:    repositories.NewUserRepository(db, errTracker),
-		TokenRepository:   token.NewTokenProvider(timeGenerator, errTracker),
-		CacheRepository:   initializeCache(ctx, cfg.Redis, errTracker),
-		ErrTrackerAdapter: errTracker,
-		MailerAdapter:     initializeMailer(cfg.Mailer, errTracker),
-		FileUploadAdapter: initializeFileUpload(cfg.FileUpload, errTracker),
+		TimeGenerator:              timeGenerator,
+		DB:                         db,
+		UserRepository:             repositories.NewUserRepository(db, errTracker, tracerProvider),
+		EmailSuppressionRepository: repositories.NewEmailSuppressionRepository(db, errTracker),
+		EmailDeliveryRepository:    repositories.NewEmailDeliveryRepository(db, errTracker),
+		PendingUploadRepository:    repositories.NewPendingUploadRepository(db, errTracker),
+		FileRepository:             repositories.NewFileRepository(db, errTracker),
+		TokenRepository:            token.NewTokenProvider(timeGenerator, errTracker),
+		CacheRepository:            cacheRepository,
+		ErrTrackerAdapter:          errTracker,
+		MailerAdapter:              initializeMailer(cfg.Mailer, errTracker, tracerProvider),
+		MailerWebhookAdapter:       mailer.NewSNSWebhookAdapter(cfg.Mailer, errTracker),
+		MailRateLimiter:            ratelimiter.New(cacheRepository, "mail_quota"),
+		FileUploadAdapter:          fileUploadAdapter,
+		FileServerAdapter:          fileServerAdapter,
+		ImageProcessor:             imaging.NewProcessor(),
+		FileScanner:                initializeFileScanner(cfg.FileScanner, errTracker),
+		Metrics:                    metricsAdapter,
+		MetricsHandler:             metricsHandler,
+		TracerProvider:             tracerProvider,
 	}
 }
 
 func initializeDatabaseAndMigrate(ctx context.Context, dbCfg *config.DB, errTracker ports.ErrTrackerAdapter) *sql.DB {
 	db, err := database.New(ctx, dbCfg, errTracker)
 	if err != nil {
-		errTracker.CaptureException(err)
+		errTracker.CaptureException(ctx, err)
 		panic(err)
 	}
 
 	err = database.MigrateFS(db, migrations.FS, ".")
 	if err != nil {
-		errTracker.CaptureException(err)
+		errTracker.CaptureException(ctx, err)
 		panic(err)
 	}
 
 	return db
 }
 
-func initializeCache(ctx context.Context, cacheCfg *config.Redis, errTracker ports.ErrTrackerAdapter) ports.CacheRepository {
-	cache, err := cache.New(ctx, cacheCfg, errTracker)
+// initializeTracing creates the tracer provider, exporting the spans if tracing is enabled.
+func initializeTracing(cfg *config.Container, errTracker ports.ErrTrackerAdapter) trace.TracerProvider {
+	tracerProvider, err := tracing.New(cfg.Tracing, cfg.Application)
+	if err != nil {
+		errTracker.CaptureException(context.Background(), err)
+		panic(err)
+	}
+	return tracerProvider
+}
+
+// initializeMetrics creates the metrics adapter and the handler serving its metrics.
+// The handler is nil when the metrics are disabled.
+func initializeMetrics(metricsCfg *config.Metrics, db *sql.DB) (ports.Metrics, http.Handler) {
+	if !metricsCfg.Enabled {
+		return metrics.NewNoopAdapter(), nil
+	}
+
+	prometheus := metrics.NewPrometheusAdapter(db)
+	return prometheus, prometheus.Handler()
+}
+
+// initializeCache creates the cache of the configured driver. Redis is always behind a circuit breaker:
+// the application starts in degraded mode if it is unreachable, and uses it as soon as it is.
+func initializeCache(ctx context.Context, cfg *config.Container, timeGenerator ports.TimeGenerator, errTracker ports.ErrTrackerAdapter, tracerProvider trace.TracerProvider) ports.CacheRepository {
+	if cfg.Cache.Driver == config.CacheDriverMemory {
+		return cache.NewMemory(cfg.Cache.MaxEntries, cfg.Cache.MaxSize, timeGenerator)
+	}
+
+	redisCache, err := cache.NewLazy(cfg.Redis, errTracker, tracerProvider)
 	if err != nil {
-		errTracker.CaptureException(err)
+		errTracker.CaptureException(ctx, err)
 		panic(err)
 	}
-	return cache
+	if err = redisCache.Ping(ctx); err != nil {
+		slog.Warn("redis is unreachable, starting in degraded mode", "error", err)
+	}
+	breaker := cache.NewCircuitBreaker(redisCache, cfg.Redis, timeGenerator)
+
+	if cfg.Cache.Driver == config.CacheDriverTwoTier {
+		local := cache.NewMemory(cfg.Cache.MaxEntries, cfg.Cache.MaxSize, timeGenerator)
+		return cache.NewTwoTier(local, breaker, redisCache, cfg.Cache)
+	}
+	return breaker
 }
 
-func initializeMailer(mailerCfg *config.Mailer, errTracker ports.ErrTrackerAdapter) ports.MailerAdapter {
-	mailer, err := mailer.NewSESAdapter(mailerCfg, errTracker)
+func initializeMailer(mailerCfg *config.Mailer, errTracker ports.ErrTrackerAdapter, tracerProvider trace.TracerProvider) ports.MailerAdapter {
+	mailer, err := mailer.NewSESAdapter(mailerCfg, errTracker, tracerProvider)
 	if err != nil {
-		errTracker.CaptureException(err)
+		errTracker.CaptureException(context.Background(), err)
 		panic(err)
 	}
 	return mailer
 }
 
-func initializeFileUpload(fileUploadCfg *config.FileUpload, errTracker ports.ErrTrackerAdapter) ports.FileUploadAdapter {
-	fileUpload, err := fileupload.NewS3Adapter(fileUploadCfg, errTracker)
+// initializeFileUpload creates the storage selected by the STORAGE_DRIVER option.
+// The file server adapter is nil for storages serving their files themselves.
+func initializeFileUpload(cfg *config.Container, errTracker ports.ErrTrackerAdapter, tracerProvider trace.TracerProvider) (ports.FileUploadAdapter, ports.FileServerAdapter) {
+	if cfg.FileUpload.Driver == config.StorageDriverLocal {
+		local, err := fileupload.NewLocalAdapter(cfg.FileUpload, cfg.Application.BaseURL, errTracker)
+		if err != nil {
+			errTracker.CaptureException(context.Background(), err)
+			panic(err)
+		}
+		return local, local
+	}
+
+	fileUpload, err := fileupload.NewS3Adapter(cfg.FileUpload, errTracker, tracerProvider)
+	if err != nil {
+		errTracker.CaptureException(context.Background(), err)
+		panic(err)
+	}
+	return fileUpload, nil
+}
+
+// initializeFileScanner creates the malware scanner selected by the FILE_SCANNER_DRIVER option.
+// The uploaded files are not scanned when no scanner is selected.
+func initializeFileScanner(scannerCfg *config.FileScanner, errTracker ports.ErrTrackerAdapter) ports.FileScanner {
+	if scannerCfg.Driver != config.ScannerDriverClamAV {
+		return scanner.NewNoopAdapter()
+	}
+
+	clamAV, err := scanner.NewClamAVAdapter(scannerCfg, errTracker)
 	if err != nil {
-		errTracker.CaptureException(err)
+		errTracker.CaptureException(context.Background(), err)
 		panic(err)
 	}
-	return fileUpload
+	return clamAV
 }
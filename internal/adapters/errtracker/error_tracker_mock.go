@@ -1,13 +1,28 @@
 package errtracker
 
 import (
+	"context"
 	"go-starter/internal/domain/ports"
 	"net/http"
+	"sync"
 	"time"
 )
 
+// mockScopeKey is the key the scope of the ErrTrackerAdapterMock is stored with in a context.
+type mockScopeKey struct{}
+
+// mockScope holds the details of a scope of the ErrTrackerAdapterMock.
+type mockScope struct {
+	userID    string
+	traceID   string
+	spanID    string
+	requestID string
+	mu        sync.RWMutex
+}
+
 // ErrTrackerAdapterMock implements the ports.ErrTrackerAdapter interface.
-// It is not implemented and used in local development and tests
+// It is not implemented and used in local development and tests, only the user, trace and request ID
+// of the scopes are kept.
 type ErrTrackerAdapterMock struct{}
 
 // NewErrTrackerAdapterMock creates and returns a new ErrTrackerAdapterMock instance.
@@ -15,33 +30,117 @@ func NewErrTrackerAdapterMock() *ErrTrackerAdapterMock {
 	return &ErrTrackerAdapterMock{}
 }
 
-// Handle wraps the provided http.Handler with a middleware for automatic
-// error tracking and request monitoring.
+// NewScope returns a copy of the context bound to a new scope, cloned from the scope the context is bound to, if any.
+func (mock *ErrTrackerAdapterMock) NewScope(ctx context.Context) context.Context {
+	scope := &mockScope{}
+	if parent := mockScopeFromContext(ctx); parent != nil {
+		parent.mu.RLock()
+		scope.userID, scope.traceID, scope.spanID, scope.requestID = parent.userID, parent.traceID, parent.spanID, parent.requestID
+		parent.mu.RUnlock()
+	}
+	return context.WithValue(ctx, mockScopeKey{}, scope)
+}
+
+// Handle wraps the provided http.Handler with a middleware binding the context of each request to a new scope.
 func (mock *ErrTrackerAdapterMock) Handle(handler http.Handler) http.Handler {
-	return handler
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(mock.NewScope(r.Context())))
+	})
+}
+
+// SetUser keeps the ID of the user in the scope of the context.
+func (mock *ErrTrackerAdapterMock) SetUser(ctx context.Context, id, _ string) {
+	configureMockScope(ctx, func(scope *mockScope) {
+		scope.userID = id
+	})
 }
 
-// SetUser associates the current scope with user information identified by
-// the provided ID and IP address.
-func (mock *ErrTrackerAdapterMock) SetUser(_, _ string) {}
+// User returns the ID of the user set with SetUser in the scope of the context.
+func (mock *ErrTrackerAdapterMock) User(ctx context.Context) string {
+	var userID string
+	readMockScope(ctx, func(scope *mockScope) {
+		userID = scope.userID
+	})
+	return userID
+}
 
 // CaptureException sends an error and returns the event ID as a string.
-func (mock *ErrTrackerAdapterMock) CaptureException(_ error) string {
+func (mock *ErrTrackerAdapterMock) CaptureException(_ context.Context, _ error) string {
 	return ""
 }
 
-// AddBreadcrumb adds a new breadcrumb to the current scope with the specified
+// AddBreadcrumb adds a new breadcrumb to the scope of the context with the specified
 // message and options. Breadcrumbs track the series of events leading up to an error.
-func (mock *ErrTrackerAdapterMock) AddBreadcrumb(_ string, _ ports.BreadCrumbOptions) {}
+func (mock *ErrTrackerAdapterMock) AddBreadcrumb(_ context.Context, _ string, _ ports.BreadCrumbOptions) {
+}
 
-// SetRequest attaches the provided HTTP request to the current scope for
+// SetRequest attaches the provided HTTP request to the scope of the context for
 // additional context in error reports.
-func (mock *ErrTrackerAdapterMock) SetRequest(_ *http.Request) {}
+func (mock *ErrTrackerAdapterMock) SetRequest(_ context.Context, _ *http.Request) {}
 
-// SetBody attaches the provided request body to the current scope for
-// additional context in error reports.
-func (mock *ErrTrackerAdapterMock) SetBody(_ []byte) {}
+// SetBody attaches the provided body of a request of the route with the content type to the scope of the context
+// for additional context in error reports.
+func (mock *ErrTrackerAdapterMock) SetBody(_ context.Context, _, _ string, _ []byte) {}
+
+// SetTrace keeps the IDs of the trace and span of the request in the scope of the context.
+func (mock *ErrTrackerAdapterMock) SetTrace(ctx context.Context, traceID, spanID string) {
+	configureMockScope(ctx, func(scope *mockScope) {
+		scope.traceID = traceID
+		scope.spanID = spanID
+	})
+}
+
+// Trace returns the IDs of the trace and span set with SetTrace in the scope of the context.
+func (mock *ErrTrackerAdapterMock) Trace(ctx context.Context) (string, string) {
+	var traceID, spanID string
+	readMockScope(ctx, func(scope *mockScope) {
+		traceID, spanID = scope.traceID, scope.spanID
+	})
+	return traceID, spanID
+}
+
+// SetRequestID keeps the ID of the request in the scope of the context.
+func (mock *ErrTrackerAdapterMock) SetRequestID(ctx context.Context, requestID string) {
+	configureMockScope(ctx, func(scope *mockScope) {
+		scope.requestID = requestID
+	})
+}
+
+// RequestID returns the ID of the request set with SetRequestID in the scope of the context.
+func (mock *ErrTrackerAdapterMock) RequestID(ctx context.Context) string {
+	var requestID string
+	readMockScope(ctx, func(scope *mockScope) {
+		requestID = scope.requestID
+	})
+	return requestID
+}
 
 // Flush waits for queued events to be sent for the specified duration.
 // It should be called before program termination to ensure all events are sent.
 func (mock *ErrTrackerAdapterMock) Flush(_ time.Duration) {}
+
+// mockScopeFromContext returns the scope the context is bound to, if any.
+func mockScopeFromContext(ctx context.Context) *mockScope {
+	scope, _ := ctx.Value(mockScopeKey{}).(*mockScope)
+	return scope
+}
+
+// configureMockScope calls f with the scope the context is bound to, locked for writing. It does nothing if the context
+// is bound to none.
+func configureMockScope(ctx context.Context, f func(scope *mockScope)) {
+	if scope := mockScopeFromContext(ctx); scope != nil {
+		scope.mu.Lock()
+		defer scope.mu.Unlock()
+		f(scope)
+	}
+}
+
+// readMockScope calls f with the scope the context is bound to, locked for reading. It does nothing if the context
+// is bound to none.
+func readMockScope(ctx context.Context, f func(scope *mockScope)) {
+	if scope := mockScopeFromContext(ctx); scope != nil {
+		scope.mu.RLock()
+		defer scope.mu.RUnlock()
+		f(scope)
+	}
+}
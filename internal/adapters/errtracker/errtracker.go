@@ -0,0 +1,30 @@
+package errtracker
+
+import (
+	"go-starter/config"
+	"go-starter/internal/domain/ports"
+	"log/slog"
+)
+
+// New creates the error tracker reporting to the drivers selected by the ERROR_TRACKER_DRIVERS option,
+// through a FanoutAdapter when several are selected. The log driver reports to the default logger.
+func New(cfg *config.Container) ports.ErrTrackerAdapter {
+	adapters := make([]ports.ErrTrackerAdapter, 0, len(cfg.ErrTracker.Drivers))
+	for _, driver := range cfg.ErrTracker.Drivers {
+		switch driver {
+		case config.ErrTrackerDriverSentry:
+			adapters = append(adapters, NewSentryAdapter(cfg))
+		case config.ErrTrackerDriverLog:
+			adapters = append(adapters, NewLogAdapter(slog.Default()))
+		}
+	}
+
+	switch len(adapters) {
+	case 0:
+		return NewNoopAdapter()
+	case 1:
+		return adapters[0]
+	default:
+		return NewFanoutAdapter(adapters...)
+	}
+}
@@ -0,0 +1,388 @@
+//go:build !integration
+
+package errtracker_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/domain/ports"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// decodeLogRecords decodes the JSON lines logged to the buffer.
+func decodeLogRecords(t *testing.T, logs *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to decode log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// newTestScrubber creates a Scrubber with the default policy of the configuration.
+func newTestScrubber() *errtracker.Scrubber {
+	return errtracker.NewScrubber(&config.ErrTracker{
+		BodyDenylist:  []string{"password", "token", "secret", "authorization", "api_key", "card_number", "cvv"},
+		BodyAllowlist: map[string][]string{"POST /v1/auth/login": {"username"}, "POST /v1/auth/password-reset": {}},
+		MaxBodySize:   64,
+	})
+}
+
+func TestScrubber_ScrubBody(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		route       string
+		contentType string
+		body        string
+		expected    string
+	}{
+		"denied fields should be dropped": {
+			route:       "PATCH /v1/users/me/password",
+			contentType: "application/json",
+			body:        `{"password":"secret123","password_confirmation":"secret123","locale":"fr"}`,
+			expected:    `{"locale":"fr"}`,
+		},
+		"denied nested fields should be dropped": {
+			route:       "POST /v1/orders",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"payment":{"Card-Number":"4242","amount":10},"items":[{"api_key":"k","id":1}]}`,
+			expected:    `{"items":[{"id":1}],"payment":{"amount":10}}`,
+		},
+		"only allowed fields of the route should be kept": {
+			route:       "POST /v1/auth/login",
+			contentType: "application/json",
+			body:        `{"username":"john","password":"secret123","email":"john@example.com"}`,
+			expected:    `{"username":"john"}`,
+		},
+		"route allowing no field should keep an empty body": {
+			route:       "POST /v1/auth/password-reset",
+			contentType: "application/json",
+			body:        `{"email":"john@example.com"}`,
+			expected:    `{}`,
+		},
+		"body without content type should be scrubbed as JSON": {
+			route:    "PATCH /v1/users/me/locale",
+			body:     `{"locale":"fr","reset_token":"abc"}`,
+			expected: `{"locale":"fr"}`,
+		},
+		"form body should be scrubbed": {
+			route:       "POST /v1/auth/login",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "username=john&password=secret123",
+			expected:    "username=john",
+		},
+		"multipart body should be skipped": {
+			route:       "POST /v1/users/me/files",
+			contentType: "multipart/form-data; boundary=xyz",
+			body:        "--xyz\r\nContent-Disposition: form-data; name=\"password\"\r\n\r\nsecret123\r\n--xyz--",
+		},
+		"invalid JSON body should be skipped": {
+			route:       "POST /v1/auth/login",
+			contentType: "application/json",
+			body:        `{"username":"john","password":"secret123"`,
+		},
+		"plain text body should be skipped": {
+			route:       "PUT /v1/files/{key...}",
+			contentType: "text/plain",
+			body:        "password=secret123",
+		},
+		"oversized body should be skipped": {
+			route:       "PATCH /v1/users/me/locale",
+			contentType: "application/json",
+			body:        `{"locale":"` + strings.Repeat("a", 64) + `"}`,
+		},
+		"empty body should be skipped": {
+			route:       "POST /v1/auth/logout",
+			contentType: "application/json",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			scrubber := newTestScrubber()
+
+			// Act
+			scrubbed := scrubber.ScrubBody(tt.route, tt.contentType, []byte(tt.body))
+
+			// Assert
+			if string(scrubbed) != tt.expected {
+				t.Errorf("expected body %q, got %q", tt.expected, scrubbed)
+			}
+		})
+	}
+}
+
+func TestScrubber_BeforeSend(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	scrubber := newTestScrubber()
+	event := &sentry.Event{
+		Request: &sentry.Request{
+			URL:         "https://api.example.com/v1/users/me/password",
+			Method:      "PATCH",
+			Data:        `{"password":"secret123","locale":"fr"}`,
+			QueryString: "page=2&access_token=abc",
+			Cookies:     "session=abc",
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"Authorization": "Bearer abc",
+				"X-Api-Key":     "key",
+				"Cookie":        "session=abc",
+				"Accept":        "application/json",
+			},
+		},
+		Breadcrumbs: []*sentry.Breadcrumb{{
+			Message: "cache miss",
+			Data: map[string]any{
+				"key":    "user:42",
+				"secret": "abc",
+				"user":   map[string]any{"name": "john", "password": "secret123"},
+			},
+		}},
+	}
+
+	// Act
+	event = scrubber.BeforeSend(event, &sentry.EventHint{})
+
+	// Assert
+	if event == nil {
+		t.Fatal("expected the event to be sent")
+	}
+	if event.Request.Data != `{"locale":"fr"}` {
+		t.Errorf("expected body %q, got %q", `{"locale":"fr"}`, event.Request.Data)
+	}
+	if query, _ := url.ParseQuery(event.Request.QueryString); query.Has("access_token") || query.Get("page") != "2" {
+		t.Errorf("expected only the page query parameter, got %q", event.Request.QueryString)
+	}
+	if event.Request.Cookies != "" {
+		t.Errorf("expected no cookies, got %q", event.Request.Cookies)
+	}
+	for _, header := range []string{"Authorization", "X-Api-Key", "Cookie"} {
+		if _, ok := event.Request.Headers[header]; ok {
+			t.Errorf("expected header %s to be dropped", header)
+		}
+	}
+	for _, header := range []string{"Content-Type", "Accept"} {
+		if _, ok := event.Request.Headers[header]; !ok {
+			t.Errorf("expected header %s to be kept", header)
+		}
+	}
+	data := event.Breadcrumbs[0].Data
+	if _, ok := data["secret"]; ok {
+		t.Error("expected breadcrumb field secret to be dropped")
+	}
+	if data["key"] != "user:42" {
+		t.Errorf("expected breadcrumb field key to be kept, got %v", data["key"])
+	}
+	if user, _ := data["user"].(map[string]any); user["password"] != nil || user["name"] != "john" {
+		t.Errorf("expected nested breadcrumb field password to be dropped, got %v", data["user"])
+	}
+}
+
+func TestErrTracker_SelectsDrivers(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		drivers  []string
+		expected ports.ErrTrackerAdapter
+	}{
+		"log driver should report to the logs": {drivers: []string{config.ErrTrackerDriverLog}, expected: &errtracker.LogAdapter{}},
+		"noop driver should report nowhere":    {drivers: []string{config.ErrTrackerDriverNoop}, expected: &errtracker.NoopAdapter{}},
+		"noop driver should be left out":       {drivers: []string{config.ErrTrackerDriverNoop, config.ErrTrackerDriverLog}, expected: &errtracker.LogAdapter{}},
+		"several drivers should be fanned out": {drivers: []string{config.ErrTrackerDriverLog, config.ErrTrackerDriverLog}, expected: &errtracker.FanoutAdapter{}},
+		"no driver should report nowhere":      {drivers: []string{}, expected: &errtracker.NoopAdapter{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			errTracker := errtracker.New(&config.Container{ErrTracker: &config.ErrTracker{Drivers: tt.drivers}})
+
+			// Assert
+			if fmt.Sprintf("%T", errTracker) != fmt.Sprintf("%T", tt.expected) {
+				t.Errorf("expected adapter %T, got %T", tt.expected, errTracker)
+			}
+		})
+	}
+}
+
+func TestLogAdapter_CaptureException(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var logs bytes.Buffer
+	errTracker := errtracker.NewLogAdapter(slog.New(slog.NewJSONHandler(&logs, nil)))
+	err := fmt.Errorf("failed to get user: %w", errors.Join(sql.ErrNoRows, errors.New("retry failed")))
+
+	// Act
+	eventID := errTracker.CaptureException(context.Background(), err)
+
+	// Assert
+	records := decodeLogRecords(t, &logs)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if eventID == "" || record["event_id"] != eventID {
+		t.Errorf("expected event ID %q to be logged, got %v", eventID, record["event_id"])
+	}
+	chain, _ := record["error_chain"].([]any)
+	if len(chain) != 4 {
+		t.Fatalf("expected the 4 errors of the chain, got %v", record["error_chain"])
+	}
+	if last, _ := chain[2].(string); !strings.HasSuffix(last, sql.ErrNoRows.Error()) {
+		t.Errorf("expected the wrapped errors in the chain, got %v", chain)
+	}
+	if stack, _ := record["stacktrace"].(string); !strings.Contains(stack, "TestLogAdapter_CaptureException") {
+		t.Errorf("expected the stack trace of the caller, got %q", stack)
+	}
+}
+
+// recordingTransport is a sentry.Transport keeping the events sent instead of sending them.
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (rt *recordingTransport) Configure(_ sentry.ClientOptions) {}
+
+func (rt *recordingTransport) SendEvent(event *sentry.Event) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.events = append(rt.events, event)
+}
+
+func (rt *recordingTransport) Flush(_ time.Duration) bool { return true }
+
+func (rt *recordingTransport) Close() {}
+
+func TestSentryAdapter_IsolatesConcurrentScopes(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	transport := &recordingTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://public@example.com/1", Transport: transport})
+	if err != nil {
+		t.Fatalf("failed to create the Sentry client: %v", err)
+	}
+	ctx := sentry.SetHubOnContext(context.Background(), sentry.NewHub(client, sentry.NewScope()))
+	errTracker := &errtracker.SentryAdapter{}
+	const requests = 50
+
+	// Act
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userID := fmt.Sprintf("user-%d", i)
+			scopeCtx := errTracker.NewScope(ctx)
+			errTracker.SetUser(scopeCtx, userID, "")
+			errTracker.SetRequestID(scopeCtx, "req-"+userID)
+			runtime.Gosched()
+			errTracker.CaptureException(scopeCtx, errors.New(userID))
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if len(transport.events) != requests {
+		t.Fatalf("expected %d events, got %d", requests, len(transport.events))
+	}
+	for _, event := range transport.events {
+		if len(event.Exception) == 0 {
+			t.Fatalf("expected an exception in event %v", event)
+		}
+		userID := event.Exception[len(event.Exception)-1].Value
+		if event.User.ID != userID || event.Tags["request_id"] != "req-"+userID {
+			t.Errorf("expected the error of %s to be reported with its scope, got user %q and request ID %q",
+				userID, event.User.ID, event.Tags["request_id"])
+		}
+	}
+}
+
+func TestErrTrackerAdapterMock_IsolatesConcurrentScopes(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	errTracker := errtracker.NewErrTrackerAdapterMock()
+	users := make(chan string, 50)
+	handler := errTracker.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		errTracker.SetUser(r.Context(), r.URL.Query().Get("user"), "")
+		runtime.Gosched()
+		users <- r.URL.Query().Get("user") + "=" + errTracker.User(r.Context())
+	}))
+
+	// Act
+	var wg sync.WaitGroup
+	for i := range cap(users) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/users/me?user=user-%d", i), nil))
+		}()
+	}
+	wg.Wait()
+	close(users)
+
+	// Assert
+	for got := range users {
+		if want, have, _ := strings.Cut(got, "="); want != have {
+			t.Errorf("expected the scope of the request of %s to hold its user, got %s", want, have)
+		}
+	}
+}
+
+func TestFanoutAdapter_ForwardsToAdapters(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var logs bytes.Buffer
+	mock := errtracker.NewErrTrackerAdapterMock()
+	errTracker := errtracker.NewFanoutAdapter(errtracker.NewNoopAdapter(), mock, errtracker.NewLogAdapter(slog.New(slog.NewJSONHandler(&logs, nil))))
+	ctx := errTracker.NewScope(context.Background())
+
+	// Act
+	errTracker.SetRequestID(ctx, "req-42")
+	eventID := errTracker.CaptureException(ctx, errors.New("failed"))
+	errTracker.Flush(0)
+
+	// Assert
+	if got := mock.RequestID(ctx); got != "req-42" {
+		t.Errorf("expected the request ID to be set on the scope of the mock, got %q", got)
+	}
+	records := decodeLogRecords(t, &logs)
+	if len(records) != 1 || records[0]["event_id"] != eventID {
+		t.Errorf("expected the event ID of the log adapter, got %q and records %v", eventID, records)
+	}
+}
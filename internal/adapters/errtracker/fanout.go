@@ -0,0 +1,106 @@
+package errtracker
+
+import (
+	"context"
+	"go-starter/internal/domain/ports"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FanoutAdapter implements the ports.ErrTrackerAdapter interface by forwarding every call to several adapters,
+// e.g. to report the errors both to Sentry and to the logs.
+type FanoutAdapter struct {
+	adapters []ports.ErrTrackerAdapter
+}
+
+// NewFanoutAdapter creates and returns a new FanoutAdapter instance forwarding to the adapters.
+func NewFanoutAdapter(adapters ...ports.ErrTrackerAdapter) *FanoutAdapter {
+	return &FanoutAdapter{
+		adapters: adapters,
+	}
+}
+
+// NewScope returns a copy of the context bound to a new scope of each adapter.
+func (fa *FanoutAdapter) NewScope(ctx context.Context) context.Context {
+	for _, adapter := range fa.adapters {
+		ctx = adapter.NewScope(ctx)
+	}
+	return ctx
+}
+
+// Handle wraps the provided http.Handler with the middleware of each adapter, the first adapter's being the innermost.
+func (fa *FanoutAdapter) Handle(handler http.Handler) http.Handler {
+	for _, adapter := range fa.adapters {
+		handler = adapter.Handle(handler)
+	}
+	return handler
+}
+
+// SetUser associates the scope of the context of each adapter with user information identified by
+// the provided ID and IP address.
+func (fa *FanoutAdapter) SetUser(ctx context.Context, id, ipAddr string) {
+	for _, adapter := range fa.adapters {
+		adapter.SetUser(ctx, id, ipAddr)
+	}
+}
+
+// CaptureException sends an error to each adapter and returns the first event ID that is not empty.
+func (fa *FanoutAdapter) CaptureException(ctx context.Context, err error) string {
+	var eventID string
+	for _, adapter := range fa.adapters {
+		if id := adapter.CaptureException(ctx, err); eventID == "" {
+			eventID = id
+		}
+	}
+	return eventID
+}
+
+// AddBreadcrumb adds a new breadcrumb to the scope of the context of each adapter.
+func (fa *FanoutAdapter) AddBreadcrumb(ctx context.Context, message string, options ports.BreadCrumbOptions) {
+	for _, adapter := range fa.adapters {
+		adapter.AddBreadcrumb(ctx, message, options)
+	}
+}
+
+// SetRequest attaches the provided HTTP request to the scope of the context of each adapter.
+func (fa *FanoutAdapter) SetRequest(ctx context.Context, r *http.Request) {
+	for _, adapter := range fa.adapters {
+		adapter.SetRequest(ctx, r)
+	}
+}
+
+// SetBody attaches the provided body of a request of the route with the content type to the scope of the context
+// of each adapter.
+func (fa *FanoutAdapter) SetBody(ctx context.Context, route, contentType string, body []byte) {
+	for _, adapter := range fa.adapters {
+		adapter.SetBody(ctx, route, contentType, body)
+	}
+}
+
+// SetTrace attaches the IDs of the trace and span of the request to the scope of the context of each adapter.
+func (fa *FanoutAdapter) SetTrace(ctx context.Context, traceID, spanID string) {
+	for _, adapter := range fa.adapters {
+		adapter.SetTrace(ctx, traceID, spanID)
+	}
+}
+
+// SetRequestID attaches the ID of the request to the scope of the context of each adapter.
+func (fa *FanoutAdapter) SetRequestID(ctx context.Context, requestID string) {
+	for _, adapter := range fa.adapters {
+		adapter.SetRequestID(ctx, requestID)
+	}
+}
+
+// Flush waits for the queued events of all the adapters to be sent, for the specified duration at most.
+func (fa *FanoutAdapter) Flush(duration time.Duration) {
+	var wg sync.WaitGroup
+	for _, adapter := range fa.adapters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			adapter.Flush(duration)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,75 @@
+package errtracker
+
+import (
+	"context"
+	"go-starter/internal/domain/ports"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LogAdapter implements the ports.ErrTrackerAdapter interface by reporting the errors in the structured logs.
+// The details of the request, such as its ID, route and user, are the attributes of the log context
+// rather than a scope, the methods setting them are no-ops.
+type LogAdapter struct {
+	log *slog.Logger
+}
+
+// NewLogAdapter creates and returns a new LogAdapter instance reporting to the logger.
+func NewLogAdapter(log *slog.Logger) *LogAdapter {
+	return &LogAdapter{
+		log: log,
+	}
+}
+
+// NewScope returns the context as it is, the details of the requests are the attributes of their log context.
+func (la *LogAdapter) NewScope(ctx context.Context) context.Context {
+	return ctx
+}
+
+// Handle returns the provided http.Handler as it is, the requests are logged by the logging middleware.
+func (la *LogAdapter) Handle(handler http.Handler) http.Handler {
+	return handler
+}
+
+// SetUser does nothing, the user and its IP address are attributes of the log context.
+func (la *LogAdapter) SetUser(_ context.Context, _, _ string) {}
+
+// CaptureException logs an error with the attributes of the log context, the stack trace of the caller
+// and the chain of the errors it wraps, and returns the event ID it is logged with.
+func (la *LogAdapter) CaptureException(ctx context.Context, err error) string {
+	eventID := uuid.NewString()
+	la.log.ErrorContext(ctx, "exception captured",
+		"event_id", eventID,
+		"error", err,
+		"error_chain", errorChain(err),
+		"stacktrace", stackTrace(1),
+	)
+	return eventID
+}
+
+// AddBreadcrumb logs the breadcrumb at debug level, with its category and data.
+func (la *LogAdapter) AddBreadcrumb(ctx context.Context, message string, options ports.BreadCrumbOptions) {
+	la.log.DebugContext(ctx, message,
+		"breadcrumb_category", options.Category,
+		"breadcrumb_level", options.Level,
+		"breadcrumb_data", options.Data,
+	)
+}
+
+// SetRequest does nothing, the route of the request is an attribute of the log context.
+func (la *LogAdapter) SetRequest(_ context.Context, _ *http.Request) {}
+
+// SetBody does nothing, the request bodies are not logged.
+func (la *LogAdapter) SetBody(_ context.Context, _, _ string, _ []byte) {}
+
+// SetTrace does nothing, the IDs of the trace and span are added to the records by the log handler.
+func (la *LogAdapter) SetTrace(_ context.Context, _, _ string) {}
+
+// SetRequestID does nothing, the ID of the request is an attribute of the log context.
+func (la *LogAdapter) SetRequestID(_ context.Context, _ string) {}
+
+// Flush does nothing, the logs are written synchronously.
+func (la *LogAdapter) Flush(_ time.Duration) {}
@@ -0,0 +1,52 @@
+package errtracker
+
+import (
+	"context"
+	"go-starter/internal/domain/ports"
+	"net/http"
+	"time"
+)
+
+// NoopAdapter implements the ports.ErrTrackerAdapter interface without reporting the errors anywhere.
+type NoopAdapter struct{}
+
+// NewNoopAdapter creates and returns a new NoopAdapter instance.
+func NewNoopAdapter() *NoopAdapter {
+	return &NoopAdapter{}
+}
+
+// NewScope returns the context as it is.
+func (na *NoopAdapter) NewScope(ctx context.Context) context.Context {
+	return ctx
+}
+
+// Handle returns the provided http.Handler as it is.
+func (na *NoopAdapter) Handle(handler http.Handler) http.Handler {
+	return handler
+}
+
+// SetUser does nothing.
+func (na *NoopAdapter) SetUser(_ context.Context, _, _ string) {}
+
+// CaptureException drops the error and returns an empty event ID.
+func (na *NoopAdapter) CaptureException(_ context.Context, _ error) string {
+	return ""
+}
+
+// AddBreadcrumb does nothing.
+func (na *NoopAdapter) AddBreadcrumb(_ context.Context, _ string, _ ports.BreadCrumbOptions) {}
+
+// SetRequest does nothing.
+func (na *NoopAdapter) SetRequest(_ context.Context, _ *http.Request) {}
+
+// SetBody does nothing.
+func (na *NoopAdapter) SetBody(_ context.Context, _, _ string, _ []byte) {}
+
+// SetTrace does nothing.
+func (na *NoopAdapter) SetTrace(_ context.Context, _, _ string) {}
+
+// SetRequestID does nothing.
+func (na *NoopAdapter) SetRequestID(_ context.Context, _ string) {}
+
+// Flush does nothing.
+func (na *NoopAdapter) Flush(_ time.Duration) {}
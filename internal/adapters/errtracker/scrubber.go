@@ -0,0 +1,175 @@
+package errtracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-starter/config"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// sensitiveHeaders are the headers never reported, in lower case with underscores, in addition to the denylist.
+var sensitiveHeaders = map[string]bool{
+	"cookie":     true,
+	"set_cookie": true,
+}
+
+// Scrubber applies the PII policy of the error reports to the request bodies, query strings, headers and breadcrumbs:
+// the fields whose name contains an item of the denylist are dropped, and the request bodies are further restricted
+// to the fields allowed for their route, if any. Only JSON and form bodies can be scrubbed, the other ones are left out,
+// as well as the ones larger than the maximum size once scrubbed.
+type Scrubber struct {
+	denylist    []string
+	allowlist   map[string]map[string]bool
+	maxBodySize int
+}
+
+// NewScrubber creates a new Scrubber applying the policy of the configuration.
+func NewScrubber(cfg *config.ErrTracker) *Scrubber {
+	denylist := make([]string, len(cfg.BodyDenylist))
+	for i, item := range cfg.BodyDenylist {
+		denylist[i] = normalizeKey(item)
+	}
+
+	allowlist := make(map[string]map[string]bool, len(cfg.BodyAllowlist))
+	for route, fields := range cfg.BodyAllowlist {
+		allowlist[route] = make(map[string]bool, len(fields))
+		for _, field := range fields {
+			allowlist[route][field] = true
+		}
+	}
+
+	return &Scrubber{
+		denylist:    denylist,
+		allowlist:   allowlist,
+		maxBodySize: cfg.MaxBodySize,
+	}
+}
+
+// ScrubBody returns the scrubbed body of a request of the route (e.g., "POST /v1/auth/login") with the content type,
+// or nil if it cannot be reported: multipart bodies, bodies neither JSON nor form encoded, and oversized bodies.
+// The allowlist of the route applies to the top-level fields, the denylist to the fields at any depth.
+func (s *Scrubber) ScrubBody(route, contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if strings.HasPrefix(mediaType, "multipart/") {
+		return nil
+	}
+
+	var scrubbed []byte
+	if mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil
+		}
+		scrubbed = []byte(s.scrubValues(values, s.allowlist[route]).Encode())
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil
+		}
+		var err error
+		if scrubbed, err = json.Marshal(s.scrubValue(value, s.allowlist[route])); err != nil {
+			return nil
+		}
+	}
+
+	if len(scrubbed) > s.maxBodySize {
+		return nil
+	}
+	return scrubbed
+}
+
+// BeforeSend is a Sentry hook scrubbing the events before they are sent: the request body is scrubbed again without
+// its route, which is unknown at this point, the denied query parameters and headers are dropped as well as the cookies,
+// and the denied fields of the breadcrumbs data are dropped.
+func (s *Scrubber) BeforeSend(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+	if request := event.Request; request != nil {
+		request.Data = string(s.ScrubBody("", request.Headers["Content-Type"], []byte(request.Data)))
+		request.QueryString = s.scrubQueryString(request.QueryString)
+		request.Cookies = ""
+		for key := range request.Headers {
+			if sensitiveHeaders[normalizeKey(key)] || s.denied(key) {
+				delete(request.Headers, key)
+			}
+		}
+	}
+
+	for _, breadcrumb := range event.Breadcrumbs {
+		for key, value := range breadcrumb.Data {
+			if s.denied(key) {
+				delete(breadcrumb.Data, key)
+				continue
+			}
+			breadcrumb.Data[key] = s.scrubValue(value, nil)
+		}
+	}
+
+	return event
+}
+
+// scrubValue drops the denied fields of the decoded JSON value at any depth and, if allowed is not nil,
+// the top-level fields it does not list. The values other than objects and arrays are returned as they are.
+func (s *Scrubber) scrubValue(value any, allowed map[string]bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if s.denied(key) || (allowed != nil && !allowed[key]) {
+				delete(v, key)
+				continue
+			}
+			v[key] = s.scrubValue(field, nil)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = s.scrubValue(item, allowed)
+		}
+	}
+	return value
+}
+
+// scrubValues drops the denied form values and, if allowed is not nil, the ones it does not list.
+func (s *Scrubber) scrubValues(values url.Values, allowed map[string]bool) url.Values {
+	for key := range values {
+		if s.denied(key) || (allowed != nil && !allowed[key]) {
+			delete(values, key)
+		}
+	}
+	return values
+}
+
+// scrubQueryString drops the denied parameters of the query string, or the whole query string if it cannot be parsed.
+func (s *Scrubber) scrubQueryString(query string) string {
+	if query == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+	return s.scrubValues(values, nil).Encode()
+}
+
+// denied reports whether the name of the field, parameter or header contains an item of the denylist.
+func (s *Scrubber) denied(key string) bool {
+	key = normalizeKey(key)
+	for _, item := range s.denylist {
+		if strings.Contains(key, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeKey returns the key in lower case with underscores, so that e.g. "X-Api-Key" matches "api_key".
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "-", "_")
+}
@@ -1,6 +1,7 @@
 package errtracker
 
 import (
+	"context"
 	"fmt"
 	"go-starter/config"
 	"go-starter/internal/domain/ports"
@@ -15,34 +16,51 @@ import (
 // SentryAdapter implements ports.ErrTrackerAdapter interface and provides integration
 // with Sentry error monitoring service.
 type SentryAdapter struct {
-	cfg *config.Container
+	cfg      *config.Container
+	scrubber *Scrubber
 }
 
-// New creates a new instance of SentryAdapter.
-func New(cfg *config.Container) *SentryAdapter {
+// NewSentryAdapter creates a new instance of SentryAdapter.
+// The request bodies, headers and breadcrumbs are scrubbed according to the PII policy of the configuration.
+func NewSentryAdapter(cfg *config.Container) *SentryAdapter {
+	scrubber := NewScrubber(cfg.ErrTracker)
+
 	if err := sentry.Init(sentry.ClientOptions{
 		Dsn:              cfg.ErrTracker.DSN,
 		TracesSampleRate: cfg.ErrTracker.TracesSampleRate,
+		Environment:      cfg.Application.Env,
+		BeforeSend:       scrubber.BeforeSend,
 	}); err != nil {
 		slog.Error(fmt.Sprintf("Sentry initialization failed: %v\n", err))
 	}
 
 	return &SentryAdapter{
-		cfg: cfg,
+		cfg:      cfg,
+		scrubber: scrubber,
 	}
 }
 
+// NewScope returns a copy of the context bound to a new Sentry hub, cloned from the hub the context is bound to,
+// or from the global one.
+func (sa *SentryAdapter) NewScope(ctx context.Context) context.Context {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	return sentry.SetHubOnContext(ctx, hub.Clone())
+}
+
 // Handle wraps the provided http.Handler with Sentry middleware for automatic
-// error tracking and request monitoring.
+// error tracking and request monitoring. The middleware binds the context of each request to a clone of the global hub.
 func (sa *SentryAdapter) Handle(handler http.Handler) http.Handler {
 	sentryHandler := sentryhttp.New(sentryhttp.Options{})
 	return sentryHandler.Handle(handler)
 }
 
-// SetUser associates the current scope with user information identified by
+// SetUser associates the scope of the context with user information identified by
 // the provided ID and IP address.
-func (sa *SentryAdapter) SetUser(id, ipAddr string) {
-	sentry.ConfigureScope(func(scope *sentry.Scope) {
+func (sa *SentryAdapter) SetUser(ctx context.Context, id, ipAddr string) {
+	configureScope(ctx, func(scope *sentry.Scope) {
 		scope.SetUser(sentry.User{
 			ID:        id,
 			IPAddress: ipAddr,
@@ -50,48 +68,75 @@ func (sa *SentryAdapter) SetUser(id, ipAddr string) {
 	})
 }
 
-// CaptureException sends an error to Sentry and returns the event ID as a string.
-func (sa *SentryAdapter) CaptureException(err error) string {
-	event := &sentry.Event{
-		Environment: sa.cfg.Application.Env,
-		Exception:   []sentry.Exception{{Value: err.Error()}},
+// CaptureException sends an error to Sentry with the details of the scope of the context, the stack trace
+// of the caller and the chain of the errors it wraps, and returns the event ID as a string.
+// The error is sent through the global hub if the context is bound to none. The event ID is empty if the event is not sent.
+func (sa *SentryAdapter) CaptureException(ctx context.Context, err error) string {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
 	}
 
-	eventID := sentry.CaptureEvent(event)
+	eventID := hub.CaptureException(err)
+	if eventID == nil {
+		return ""
+	}
 
 	return string(*eventID)
 }
 
-// AddBreadcrumb adds a new breadcrumb to the current scope with the specified
+// AddBreadcrumb adds a new breadcrumb to the scope of the context with the specified
 // message and options. Breadcrumbs track the series of events leading up to an error.
-func (sa *SentryAdapter) AddBreadcrumb(message string, options ports.BreadCrumbOptions) {
+func (sa *SentryAdapter) AddBreadcrumb(ctx context.Context, message string, options ports.BreadCrumbOptions) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		return
+	}
+
 	level := sentry.LevelError
 	if options.Level != "" {
 		level = mapDomainSentryLevel(options.Level)
 	}
 
-	sentry.AddBreadcrumb(&sentry.Breadcrumb{
+	hub.AddBreadcrumb(&sentry.Breadcrumb{
 		Timestamp: time.Now(),
 		Message:   message,
 		Level:     level,
 		Category:  options.Category,
 		Data:      options.Data,
-	})
+	}, nil)
 }
 
-// SetRequest attaches the provided HTTP request to the current scope for
+// SetRequest attaches the provided HTTP request to the scope of the context for
 // additional context in error reports.
-func (sa *SentryAdapter) SetRequest(r *http.Request) {
-	sentry.ConfigureScope(func(scope *sentry.Scope) {
+func (sa *SentryAdapter) SetRequest(ctx context.Context, r *http.Request) {
+	configureScope(ctx, func(scope *sentry.Scope) {
 		scope.SetRequest(r)
 	})
 }
 
-// SetBody attaches the provided request body to the current scope for
-// additional context in error reports.
-func (sa *SentryAdapter) SetBody(body []byte) {
-	sentry.ConfigureScope(func(scope *sentry.Scope) {
-		scope.SetRequestBody(body)
+// SetBody attaches the scrubbed body of a request of the route with the content type to the scope of the context
+// for additional context in error reports. It replaces the body buffered by SetRequest, which is not scrubbed.
+func (sa *SentryAdapter) SetBody(ctx context.Context, route, contentType string, body []byte) {
+	scrubbed := sa.scrubber.ScrubBody(route, contentType, body)
+	configureScope(ctx, func(scope *sentry.Scope) {
+		scope.SetRequestBody(scrubbed)
+	})
+}
+
+// SetTrace attaches the IDs of the trace and span of the request to the scope of the context,
+// as tags of the error reports.
+func (sa *SentryAdapter) SetTrace(ctx context.Context, traceID, spanID string) {
+	configureScope(ctx, func(scope *sentry.Scope) {
+		scope.SetTag("trace_id", traceID)
+		scope.SetTag("span_id", spanID)
+	})
+}
+
+// SetRequestID attaches the ID of the request to the scope of the context, as a tag of the error reports.
+func (sa *SentryAdapter) SetRequestID(ctx context.Context, requestID string) {
+	configureScope(ctx, func(scope *sentry.Scope) {
+		scope.SetTag("request_id", requestID)
 	})
 }
 
@@ -101,6 +146,14 @@ func (sa *SentryAdapter) Flush(duration time.Duration) {
 	sentry.Flush(duration)
 }
 
+// configureScope calls f with the scope of the hub the context is bound to. It does nothing if the context
+// is bound to none, so that the details of a request are never set on the global hub shared by all the requests.
+func configureScope(ctx context.Context, f func(scope *sentry.Scope)) {
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		hub.ConfigureScope(f)
+	}
+}
+
 // mapDomainSentryLevel converts internal error tracking levels to corresponding
 // Sentry levels. It defaults to LevelError if the level is not recognized.
 func mapDomainSentryLevel(level ports.ErrTrackerLevel) sentry.Level {
@@ -0,0 +1,60 @@
+package errtracker
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the maximum number of frames of the stack traces.
+const maxStackDepth = 64
+
+// PanicError returns the error of a value recovered from a panic, wrapping it if it is an error itself.
+func PanicError(recovered any) error {
+	if err, ok := recovered.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return fmt.Errorf("panic: %v", recovered)
+}
+
+// errorChain returns the type and message of the error and of every error it wraps, depth first,
+// e.g. ["*fmt.wrapError: failed to get user: sql: no rows", "*errors.errorString: sql: no rows"].
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		chain = append(chain, fmt.Sprintf("%T: %s", err, err.Error()))
+		switch wrapper := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, wrapped := range wrapper.Unwrap() {
+				walk(wrapped)
+			}
+		default:
+			walk(errors.Unwrap(err))
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// stackTrace returns the stack trace of the calling goroutine, skipping the given number of frames
+// above the caller of stackTrace. Called while a panic is recovered, it includes the frames of the panic.
+func stackTrace(skip int) string {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var trace strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&trace, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return trace.String()
+}
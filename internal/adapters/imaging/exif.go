@@ -0,0 +1,137 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+// EXIF orientation values, see the TIFF 6.0 specification.
+const (
+	defaultOrientation = 1
+	maxOrientation     = 8
+	orientationTag     = 0x0112
+)
+
+// JPEG markers.
+const (
+	markerStartOfImage = 0xD8
+	markerEndOfImage   = 0xD9
+	markerStartOfScan  = 0xDA
+	markerAPP1         = 0xE1
+)
+
+// jpegOrientation returns the EXIF orientation of a JPEG image, or the default orientation if it has none.
+// Only the headers preceding the image data are read.
+func jpegOrientation(data []byte) int {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != markerStartOfImage {
+		return defaultOrientation
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return defaultOrientation
+		}
+
+		marker := data[i+1]
+		switch marker {
+		case 0xFF: // fill byte
+			i++
+			continue
+		case markerStartOfScan, markerEndOfImage:
+			return defaultOrientation
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return defaultOrientation
+		}
+
+		if marker == markerAPP1 {
+			if orientation, ok := exifOrientation(data[i+4 : i+2+length]); ok {
+				return orientation
+			}
+		}
+		i += 2 + length
+	}
+
+	return defaultOrientation
+}
+
+// exifOrientation reads the orientation tag from the first IFD of an APP1 EXIF segment.
+func exifOrientation(segment []byte) (int, bool) {
+	tiff, ok := bytes.CutPrefix(segment, []byte("Exif\x00\x00"))
+	if !ok || len(tiff) < 8 {
+		return 0, false
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+
+	offset := int64(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > int64(len(tiff)) {
+		return 0, false
+	}
+	ifd := tiff[offset:]
+
+	count := int(order.Uint16(ifd))
+	for i := range count {
+		entry := 2 + i*12
+		if entry+12 > len(ifd) {
+			return 0, false
+		}
+		if order.Uint16(ifd[entry:]) != orientationTag {
+			continue
+		}
+
+		orientation := int(order.Uint16(ifd[entry+8:]))
+		if orientation < defaultOrientation || orientation > maxOrientation {
+			return 0, false
+		}
+		return orientation, true
+	}
+
+	return 0, false
+}
+
+// orient applies an EXIF orientation to a square image, so that it is displayed upright without its metadata.
+func orient(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= defaultOrientation || orientation > maxOrientation {
+		return img
+	}
+
+	size := img.Bounds().Dx()
+	last := size - 1
+	oriented := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			// Coordinates of the source pixel displayed at (x, y).
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				sx, sy = last-x, y
+			case 3: // rotated 180°
+				sx, sy = last-x, last-y
+			case 4: // mirrored vertically
+				sx, sy = x, last-y
+			case 5: // mirrored along the top-left diagonal
+				sx, sy = y, x
+			case 6: // rotated 90° clockwise
+				sx, sy = y, last-x
+			case 7: // mirrored along the top-right diagonal
+				sx, sy = last-y, last-x
+			case 8: // rotated 90° counterclockwise
+				sx, sy = last-y, x
+			}
+			oriented.SetNRGBA(x, y, img.NRGBAAt(sx, sy))
+		}
+	}
+	return oriented
+}
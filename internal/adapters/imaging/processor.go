@@ -0,0 +1,111 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"image"
+	_ "image/jpeg" // register the JPEG decoder
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register the WebP decoder
+)
+
+// Image limits, checked from the image header before decoding so that decompression bombs are never expanded in memory.
+const (
+	MaxImageBytes     = 10 << 20
+	MaxImageDimension = 8192
+	MaxImagePixels    = 16_000_000
+)
+
+// supportedFormats maps the sniffed content types of the supported images to their decoder name.
+var supportedFormats = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpeg",
+	"image/webp": "webp",
+}
+
+// Processor implements the ports.ImageProcessor interface with the standard library decoders.
+// The variants are encoded as PNG, keeping the transparency of the original image.
+type Processor struct{}
+
+// NewProcessor creates a new Processor instance.
+func NewProcessor() *Processor {
+	return &Processor{}
+}
+
+// Thumbnails decodes an image and returns it cropped to a square and resized to each of the given sizes.
+// The format is detected from the content, the filename extension is not trusted.
+// Returns domain.ErrInvalidFileType if the content is not a PNG, JPEG or WebP image,
+// domain.ErrInvalidImage if it cannot be decoded,
+// or domain.ErrImageTooLarge if its dimensions exceed the limits.
+func (p *Processor) Thumbnails(ctx context.Context, body io.Reader, sizes []int) ([]*ports.Thumbnail, error) {
+	data, err := io.ReadAll(io.LimitReader(body, MaxImageBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > MaxImageBytes {
+		return nil, domain.ErrFileTooLarge
+	}
+
+	format, ok := supportedFormats[http.DetectContentType(data)]
+	if !ok {
+		return nil, domain.ErrInvalidFileType
+	}
+
+	cfg, decodedFormat, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || decodedFormat != format || cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, domain.ErrInvalidImage
+	}
+	if cfg.Width > MaxImageDimension || cfg.Height > MaxImageDimension || cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, domain.ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ErrInvalidImage
+	}
+
+	// The metadata is dropped by the re-encoding, the orientation is applied to the pixels beforehand.
+	orientation := defaultOrientation
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	crop := centerSquare(img.Bounds())
+	thumbnails := make([]*ports.Thumbnail, 0, len(sizes))
+	for _, size := range sizes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		thumbnail := image.NewNRGBA(image.Rect(0, 0, size, size))
+		draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, crop, draw.Src, nil)
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, orient(thumbnail, orientation)); err != nil {
+			return nil, fmt.Errorf("failed to encode %dpx thumbnail: %w", size, err)
+		}
+
+		thumbnails = append(thumbnails, &ports.Thumbnail{
+			Size:      size,
+			Extension: ".png",
+			Content:   buf.Bytes(),
+		})
+	}
+
+	return thumbnails, nil
+}
+
+// centerSquare returns the largest square centered in the bounds.
+func centerSquare(bounds image.Rectangle) image.Rectangle {
+	side := min(bounds.Dx(), bounds.Dy())
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// attrsKey is the key the log attributes are stored with in a context.
+type attrsKey struct{}
+
+// WithAttrs returns a copy of the context holding the attributes, in addition to the ones it already holds.
+// The records logged with the returned context, e.g. with slog.InfoContext, carry them all.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing := attrsFromContext(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// attrsFromContext returns the attributes held by the context, if any.
+func attrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// stringFromContext returns the string value of the last attribute with the key held by the context, if any.
+func stringFromContext(ctx context.Context, key string) string {
+	attrs := attrsFromContext(ctx)
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == key {
+			return attrs[i].Value.String()
+		}
+	}
+	return ""
+}
+
+// ContextHandler is a slog.Handler adding the attributes set in the context with WithAttrs to the records,
+// so that all the logs of a request carry e.g. its request ID without passing it to every call.
+// Only the records logged with a context, e.g. with slog.InfoContext, can carry them.
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler creates a new ContextHandler around the handler.
+func NewContextHandler(handler slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: handler}
+}
+
+// Handle adds the attributes of the context to the record, then handles it.
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := attrsFromContext(ctx); len(attrs) > 0 {
+		record.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a ContextHandler around the handler with the attributes.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewContextHandler(h.Handler.WithAttrs(attrs))
+}
+
+// WithGroup returns a ContextHandler around the handler with the group.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return NewContextHandler(h.Handler.WithGroup(name))
+}
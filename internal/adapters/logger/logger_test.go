@@ -0,0 +1,273 @@
+//go:build !integration
+
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go-starter/config"
+	"go-starter/internal/adapters/logger"
+	"go-starter/internal/domain"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// decodeLogRecords decodes the JSON records written to the buffer.
+func decodeLogRecords(t *testing.T, logs *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to decode log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRedactHandler_RedactsSensitiveValues(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret-token")
+	header.Set("Accept", "application/json")
+
+	tests := map[string]struct {
+		log      func(log *slog.Logger)
+		path     []string
+		expected any
+	}{
+		"password should be redacted": {
+			log:      func(log *slog.Logger) { log.Info("login", "password", "hunter22") },
+			path:     []string{"password"},
+			expected: "[REDACTED]",
+		},
+		"token should be redacted": {
+			log:      func(log *slog.Logger) { log.Info("verify", "reset_token", "abc123") },
+			path:     []string{"reset_token"},
+			expected: "[REDACTED]",
+		},
+		"authorization header should be redacted": {
+			log:      func(log *slog.Logger) { log.Info("request", "headers", header) },
+			path:     []string{"headers", "Authorization"},
+			expected: []any{"[REDACTED]"},
+		},
+		"other headers should be kept": {
+			log:      func(log *slog.Logger) { log.Info("request", "headers", header) },
+			path:     []string{"headers", "Accept"},
+			expected: []any{"application/json"},
+		},
+		"email should be masked": {
+			log:      func(log *slog.Logger) { log.Info("mail sent", "to", "john.doe@example.com") },
+			path:     []string{"to"},
+			expected: "j***@example.com",
+		},
+		"email in message should be masked": {
+			log:      func(log *slog.Logger) { log.Info("mail sent to john.doe@example.com") },
+			path:     []string{"msg"},
+			expected: "mail sent to j***@example.com",
+		},
+		"email in error should be masked": {
+			log:      func(log *slog.Logger) { log.Error("failed", "error", errors.New("bounced: john@example.com")) },
+			path:     []string{"error"},
+			expected: "bounced: j***@example.com",
+		},
+		"password in group should be redacted": {
+			log: func(log *slog.Logger) {
+				log.Info("payload", slog.Group("user", "name", "john", "password", "hunter22"))
+			},
+			path:     []string{"user", "password"},
+			expected: "[REDACTED]",
+		},
+		"password bound to logger should be redacted": {
+			log:      func(log *slog.Logger) { log.With("password", "hunter22").Info("login") },
+			path:     []string{"password"},
+			expected: "[REDACTED]",
+		},
+		"other values should be kept": {
+			log:      func(log *slog.Logger) { log.Info("login", "username", "john") },
+			path:     []string{"username"},
+			expected: "john",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var logs bytes.Buffer
+			log := slog.New(logger.NewRedactHandler(slog.NewJSONHandler(&logs, nil)))
+
+			// Act
+			tt.log(log)
+
+			// Assert
+			var got any = decodeLogRecords(t, &logs)[0]
+			for _, key := range tt.path {
+				object, ok := got.(map[string]any)
+				if !ok {
+					t.Fatalf("expected %s to be an object, got %v", key, got)
+				}
+				got = object[key]
+			}
+			gotJSON, _ := json.Marshal(got)
+			expectedJSON, _ := json.Marshal(tt.expected)
+			if !bytes.Equal(gotJSON, expectedJSON) {
+				t.Errorf("expected %s to be %s, got %s", strings.Join(tt.path, "."), expectedJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestRedactURL_RedactsTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		url      string
+		route    string
+		expected string
+	}{
+		"password reset token should be redacted": {
+			url:      "/v1/auth/password-reset/abc123",
+			route:    "PATCH /v1/auth/password-reset/{token}",
+			expected: "/v1/auth/password-reset/[REDACTED]",
+		},
+		"email verification token should be redacted": {
+			url:      "/v1/users/me/verify-email/abc123",
+			route:    "GET /v1/users/me/verify-email/{token}",
+			expected: "/v1/users/me/verify-email/[REDACTED]",
+		},
+		"signature should be redacted": {
+			url:      "/v1/files/uploads/avatar.png?expires=1700000000&signature=abc123",
+			route:    "GET /v1/files/{key...}",
+			expected: "/v1/files/uploads/avatar.png?expires=1700000000&signature=[REDACTED]",
+		},
+		"other path segments should be kept": {
+			url:      "/v1/users/6b947a32-8919-4974-9ef3-048a556b0b75",
+			route:    "GET /v1/users/{uuid}",
+			expected: "/v1/users/6b947a32-8919-4974-9ef3-048a556b0b75",
+		},
+		"unmatched url should be kept": {
+			url:      "/v1/unknown/abc123?page=2",
+			expected: "/v1/unknown/abc123?page=2",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("failed to parse url: %v", err)
+			}
+
+			// Act
+			got := logger.RedactURL(u, tt.route)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSamplingHandler_SamplesNoisyRoutes(t *testing.T) {
+	t.Parallel()
+
+	ratios := map[string]float64{"GET /readyz": 0, "GET /livez": 1, "GET /v1/admin/health/cache": 0.5}
+
+	tests := map[string]struct {
+		route    string
+		level    slog.Level
+		expected int
+	}{
+		"unsampled route should be kept":             {route: "GET /v1/users/{uuid}", level: slog.LevelInfo, expected: 2},
+		"route with ratio 0 should be dropped":       {route: "GET /readyz", level: slog.LevelInfo, expected: 0},
+		"route with ratio 1 should be kept":          {route: "GET /livez", level: slog.LevelInfo, expected: 2},
+		"warnings of dropped route should be kept":   {route: "GET /readyz", level: slog.LevelWarn, expected: 2},
+		"errors of dropped route should be kept":     {route: "GET /readyz", level: slog.LevelError, expected: 2},
+		"debug records of dropped route are dropped": {route: "GET /readyz", level: slog.LevelDebug, expected: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var logs bytes.Buffer
+			log := slog.New(logger.NewSamplingHandler(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}), ratios))
+			ctx := logger.WithAttrs(context.Background(), slog.String(logger.RequestIDKey, "req-42"), slog.String(logger.RouteKey, tt.route))
+
+			// Act
+			log.Log(ctx, tt.level, "REQUEST")
+			log.Log(ctx, tt.level, "RESPONSE")
+
+			// Assert
+			if got := len(decodeLogRecords(t, &logs)); got != tt.expected {
+				t.Errorf("expected %d records, got %d", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("records of a request should be kept or dropped together", func(t *testing.T) {
+		t.Parallel()
+		var logs bytes.Buffer
+		log := slog.New(logger.NewSamplingHandler(slog.NewJSONHandler(&logs, nil), ratios))
+
+		for i := range 100 {
+			logs.Reset()
+			ctx := logger.WithAttrs(context.Background(),
+				slog.String(logger.RequestIDKey, strings.Repeat("a", i+1)),
+				slog.String(logger.RouteKey, "GET /v1/admin/health/cache"),
+			)
+			log.InfoContext(ctx, "REQUEST")
+			log.InfoContext(ctx, "RESPONSE")
+			if got := len(decodeLogRecords(t, &logs)); got != 0 && got != 2 {
+				t.Fatalf("expected the records of the request to be kept or dropped together, got %d", got)
+			}
+		}
+	})
+}
+
+func TestLevelController_ChangesLevel(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var logs bytes.Buffer
+	handler, levelController := logger.NewHandler(&logs, &config.Log{Level: config.LogLevelInfo, Format: config.LogFormatJSON})
+	log := slog.New(handler)
+
+	// Act
+	log.Debug("hidden")
+	errInvalid := levelController.SetLevel("verbose")
+	errValid := levelController.SetLevel("DEBUG")
+	log.Debug("shown")
+
+	// Assert
+	if !errors.Is(errInvalid, domain.ErrLogLevelInvalid) {
+		t.Errorf("expected error %v, got %v", domain.ErrLogLevelInvalid, errInvalid)
+	}
+	if errValid != nil {
+		t.Errorf("expected no error, got %v", errValid)
+	}
+	if got := levelController.Level(); got != config.LogLevelDebug {
+		t.Errorf("expected level %s, got %s", config.LogLevelDebug, got)
+	}
+	records := decodeLogRecords(t, &logs)
+	if len(records) != 1 || records[0]["msg"] != "shown" {
+		t.Errorf("expected only the debug record logged after the change, got %v", records)
+	}
+}
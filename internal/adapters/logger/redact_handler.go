@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the values of the sensitive attributes.
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the attribute and header keys whose values are always redacted, in lower case with underscores.
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set_cookie":    true,
+	"api_key":       true,
+	"x_api_key":     true,
+}
+
+// sensitiveKeyParts are the parts of the attribute and header keys whose values are always redacted.
+var sensitiveKeyParts = []string{"password", "token", "secret", "signature"}
+
+// emailRegexp matches the email addresses in a string.
+var emailRegexp = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// RedactHandler is a slog.Handler redacting the sensitive values of the records before handling them:
+// the values of the authorization headers, passwords, tokens and secrets are replaced, and the email addresses
+// are masked wherever they appear, e.g. "john@example.com" becomes "j***@example.com".
+// The attributes are matched by key, including in groups and in the http.Header values,
+// and the emails are masked in the string, error and []string values.
+type RedactHandler struct {
+	slog.Handler
+}
+
+// NewRedactHandler creates a new RedactHandler around the handler.
+func NewRedactHandler(handler slog.Handler) *RedactHandler {
+	return &RedactHandler{Handler: handler}
+}
+
+// Handle redacts the message and the attributes of the record, then handles it.
+func (h *RedactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redactedRecord := slog.NewRecord(record.Time, record.Level, maskEmails(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redactedRecord.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.Handler.Handle(ctx, redactedRecord)
+}
+
+// WithAttrs returns a RedactHandler around the handler with the redacted attributes.
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redactedAttrs[i] = redactAttr(attr)
+	}
+	return NewRedactHandler(h.Handler.WithAttrs(redactedAttrs))
+}
+
+// WithGroup returns a RedactHandler around the handler with the group.
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return NewRedactHandler(h.Handler.WithGroup(name))
+}
+
+// redactAttr returns the attribute with its value redacted if its key is sensitive, or with its email addresses masked.
+func redactAttr(attr slog.Attr) slog.Attr {
+	if isSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, maskEmails(value.String()))
+	case slog.KindGroup:
+		groupAttrs := value.Group()
+		redactedAttrs := make([]slog.Attr, len(groupAttrs))
+		for i, groupAttr := range groupAttrs {
+			redactedAttrs[i] = redactAttr(groupAttr)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redactedAttrs...)}
+	case slog.KindAny:
+		switch v := value.Any().(type) {
+		case http.Header:
+			return slog.Any(attr.Key, redactHeader(v))
+		case error:
+			return slog.String(attr.Key, maskEmails(v.Error()))
+		case []string:
+			masked := make([]string, len(v))
+			for i, s := range v {
+				masked[i] = maskEmails(s)
+			}
+			return slog.Any(attr.Key, masked)
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+// redactHeader returns a copy of the header with the values of the sensitive keys redacted and the email addresses masked.
+func redactHeader(header http.Header) http.Header {
+	redactedHeader := make(http.Header, len(header))
+	for key, values := range header {
+		redactedValues := make([]string, len(values))
+		for i, value := range values {
+			if isSensitiveKey(key) {
+				redactedValues[i] = redacted
+			} else {
+				redactedValues[i] = maskEmails(value)
+			}
+		}
+		redactedHeader[key] = redactedValues
+	}
+	return redactedHeader
+}
+
+// isSensitiveKey reports whether the values of the attribute or header key must be redacted.
+func isSensitiveKey(key string) bool {
+	key = strings.ReplaceAll(strings.ToLower(key), "-", "_")
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactURL returns the path and query of the URL with the sensitive values redacted: the path segments matched by
+// a sensitive wildcard of the route pattern, e.g. the {token} of "GET /v1/auth/password-reset/{token}",
+// and the values of the sensitive query parameters, e.g. the signature of a presigned URL.
+func RedactURL(u *url.URL, route string) string {
+	path := u.EscapedPath()
+	if start := strings.Index(route, "/"); start >= 0 {
+		path = redactPath(path, route[start:])
+	}
+	if u.RawQuery == "" {
+		return path
+	}
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if unescapedKey, err := url.QueryUnescape(key); err == nil && isSensitiveKey(unescapedKey) {
+			params[i] = key + "=" + redacted
+		}
+	}
+	return path + "?" + strings.Join(params, "&")
+}
+
+// redactPath returns the path with the segments matched by the sensitive wildcards of the pattern redacted.
+func redactPath(path, pattern string) string {
+	segments := strings.Split(path, "/")
+	for i, patternSegment := range strings.Split(pattern, "/") {
+		if i >= len(segments) {
+			break
+		}
+		name, isWildcard := strings.CutPrefix(patternSegment, "{")
+		name, _ = strings.CutSuffix(name, "}")
+		name, isRest := strings.CutSuffix(name, "...")
+		if !isWildcard || !isSensitiveKey(name) {
+			continue
+		}
+		if isRest {
+			segments = append(segments[:i], redacted)
+			break
+		}
+		segments[i] = redacted
+	}
+	return strings.Join(segments, "/")
+}
+
+// maskEmails masks the email addresses of the string, keeping the first character of their local part and their domain.
+func maskEmails(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	return emailRegexp.ReplaceAllString(s, "$1***@$2")
+}
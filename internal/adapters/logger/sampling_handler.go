@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+)
+
+// SamplingHandler is a slog.Handler keeping only a part of the info and debug records of the noisy routes,
+// e.g. the health checks. The route of a record is the one set in its context with the RouteKey attribute.
+// The records of a request are all kept or all dropped, the decision being derived from its request ID.
+// Warnings and errors are always kept.
+type SamplingHandler struct {
+	slog.Handler
+	ratios map[string]float64
+}
+
+// NewSamplingHandler creates a new SamplingHandler around the handler.
+// The ratios map route patterns (e.g., "GET /readyz") to the ratio of their requests whose records are kept.
+func NewSamplingHandler(handler slog.Handler, ratios map[string]float64) *SamplingHandler {
+	return &SamplingHandler{Handler: handler, ratios: ratios}
+}
+
+// Handle drops the record if its route is sampled and its request is not part of the sample, then handles it.
+func (h *SamplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < slog.LevelWarn && !h.sampled(ctx) {
+		return nil
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// sampled reports whether the records of the request of the context are kept.
+func (h *SamplingHandler) sampled(ctx context.Context) bool {
+	ratio, ok := h.ratios[stringFromContext(ctx, RouteKey)]
+	if !ok || ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+
+	requestID := stringFromContext(ctx, RequestIDKey)
+	if requestID == "" {
+		return rand.Float64() < ratio
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(requestID))
+	return float64(hash.Sum64()) < ratio*math.MaxUint64
+}
+
+// WithAttrs returns a SamplingHandler around the handler with the attributes.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewSamplingHandler(h.Handler.WithAttrs(attrs), h.ratios)
+}
+
+// WithGroup returns a SamplingHandler around the handler with the group.
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return NewSamplingHandler(h.Handler.WithGroup(name), h.ratios)
+}
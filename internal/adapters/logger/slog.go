@@ -2,19 +2,74 @@ package logger
 
 import (
 	"go-starter/config"
+	"go-starter/internal/domain"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 )
 
-// New defines the logger specifications based on the application environment.
-// It initializes a new logger and sets it as the default logger for the application.
-// In production, it uses a JSON format for logging; otherwise, it uses a plain text format.
-func New(appCfg *config.App) {
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+// Keys of the attributes set in the context of a request.
+const (
+	RequestIDKey = "request_id"
+	RouteKey     = "route"
+	ClientIPKey  = "client_ip"
+	UserIDKey    = "user_id"
+)
+
+// New defines the logger specifications based on the log configuration.
+// It initializes a new logger writing to the standard output and sets it as the default logger for the application.
+// Returns the controller of its level, to change it at runtime.
+func New(logCfg *config.Log) *LevelController {
+	handler, levelController := NewHandler(os.Stdout, logCfg)
+	slog.SetDefault(slog.New(handler))
+	return levelController
+}
 
-	if appCfg.Env != config.EnvDevelopment {
-		logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+// NewHandler creates the handler of the logger writing to w in the configured format, along with the controller of its level.
+// The records logged with the context of a request carry its trace and span IDs and the attributes set with WithAttrs,
+// the info and debug ones of the sampled routes are only kept for a part of their requests,
+// and the sensitive values of all of them are redacted.
+func NewHandler(w io.Writer, logCfg *config.Log) (slog.Handler, *LevelController) {
+	levelController := &LevelController{level: &slog.LevelVar{}}
+	if err := levelController.SetLevel(logCfg.Level); err != nil {
+		levelController.level.Set(slog.LevelInfo)
 	}
 
-	slog.SetDefault(logger)
+	opts := &slog.HandlerOptions{Level: levelController.level}
+	var handler slog.Handler = slog.NewTextHandler(w, opts)
+	if logCfg.Format == config.LogFormatJSON {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	return NewSamplingHandler(NewContextHandler(NewTraceHandler(NewRedactHandler(handler))), logCfg.Sampling), levelController
+}
+
+// levels maps the names of the configurable levels to their slog level.
+var levels = map[string]slog.Level{
+	config.LogLevelDebug: slog.LevelDebug,
+	config.LogLevelInfo:  slog.LevelInfo,
+	config.LogLevelWarn:  slog.LevelWarn,
+	config.LogLevelError: slog.LevelError,
+}
+
+// LevelController implements the ports.LogLevelController interface for the level of a logger created by NewHandler.
+type LevelController struct {
+	level *slog.LevelVar
+}
+
+// Level returns the minimum level of the logs: debug, info, warn or error.
+func (lc *LevelController) Level() string {
+	return strings.ToLower(lc.level.Level().String())
+}
+
+// SetLevel changes the minimum level of the logs, taking effect immediately for all the loggers.
+// Returns domain.ErrLogLevelInvalid if the level is not one of debug, info, warn and error.
+func (lc *LevelController) SetLevel(level string) error {
+	l, ok := levels[strings.ToLower(level)]
+	if !ok {
+		return domain.ErrLogLevelInvalid
+	}
+	lc.level.Set(l)
+	return nil
 }
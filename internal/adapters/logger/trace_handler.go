@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceHandler is a slog.Handler adding the IDs of the trace and span of the context to the records,
+// so that the logs of a request can be correlated with its trace.
+// Only the records logged with a context, e.g. with slog.InfoContext, can carry them.
+type TraceHandler struct {
+	slog.Handler
+}
+
+// NewTraceHandler creates a new TraceHandler around the handler.
+func NewTraceHandler(handler slog.Handler) *TraceHandler {
+	return &TraceHandler{Handler: handler}
+}
+
+// Handle adds the trace_id and span_id attributes to the record if the context holds a span, then handles it.
+func (h *TraceHandler) Handle(ctx context.Context, record slog.Record) error {
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanCtx.TraceID().String()),
+			slog.String("span_id", spanCtx.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a TraceHandler around the handler with the attributes.
+func (h *TraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewTraceHandler(h.Handler.WithAttrs(attrs))
+}
+
+// WithGroup returns a TraceHandler around the handler with the group.
+func (h *TraceHandler) WithGroup(name string) slog.Handler {
+	return NewTraceHandler(h.Handler.WithGroup(name))
+}
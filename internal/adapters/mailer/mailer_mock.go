@@ -1,7 +1,9 @@
 package mailer
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"go-starter/internal/domain/ports"
 	"sync"
 )
@@ -9,8 +11,10 @@ import (
 // MailerAdapterMock implements the ports.MailerAdapter interface for testing purposes.
 // It stores sent emails in memory instead of actually sending them.
 type MailerAdapterMock struct {
-	data map[string]ports.EmailMessage
-	mu   sync.RWMutex
+	data    map[string]ports.EmailMessage
+	sendErr error
+	sent    int
+	mu      sync.RWMutex
 }
 
 // NewMailerAdapterMock creates a new instance of MailerAdapterMock.
@@ -25,15 +29,35 @@ func NewMailerAdapterMock() *MailerAdapterMock {
 
 // Send stores the email message in memory instead of sending it.
 // The message is indexed by each recipient's email address.
-func (m *MailerAdapterMock) Send(msg ports.EmailMessage) error {
+// Returns the error set with SetSendError, if any.
+func (m *MailerAdapterMock) Send(_ context.Context, msg ports.EmailMessage) (string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.sendErr != nil {
+		return "", m.sendErr
+	}
+
 	for _, v := range msg.To {
 		m.data[v] = msg
 	}
 
-	return nil
+	m.sent++
+	return fmt.Sprintf("mock-message-%d", m.sent), nil
+}
+
+// Ping returns the error set with SetSendError, if any, the provider being considered down.
+func (m *MailerAdapterMock) Ping(_ context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sendErr
+}
+
+// SetSendError makes the following calls to Send fail with the given error, or succeed again if it is nil.
+func (m *MailerAdapterMock) SetSendError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sendErr = err
 }
 
 // Close implements the Close method of the ports.MailerAdapter interface.
@@ -1,15 +1,19 @@
 package mailer
 
 import (
+	"context"
 	"fmt"
 	"go-starter/config"
+	"go-starter/internal/adapters/tracing"
 	"go-starter/internal/domain/ports"
-	"log/slog"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ses"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SESAdapter is an adapter for the SES service.
@@ -17,17 +21,18 @@ type SESAdapter struct {
 	session    *ses.SES
 	mailerCfg  *config.Mailer
 	errTracker ports.ErrTrackerAdapter
+	tracer     trace.Tracer
 }
 
 // NewSESAdapter creates a new SESAdapter instance.
-func NewSESAdapter(mailerCfg *config.Mailer, errTracker ports.ErrTrackerAdapter) (*SESAdapter, error) {
+func NewSESAdapter(mailerCfg *config.Mailer, errTracker ports.ErrTrackerAdapter, tracerProvider trace.TracerProvider) (*SESAdapter, error) {
 	awsSession, err := session.NewSession(&aws.Config{
 		Region:      aws.String(mailerCfg.Region),
 		Credentials: credentials.NewStaticCredentials(mailerCfg.AccessKey, mailerCfg.SecretKey, ""),
 	})
 
 	if err != nil {
-		errTracker.CaptureException(fmt.Errorf("failed to create SES session: %w", err))
+		errTracker.CaptureException(context.Background(), fmt.Errorf("failed to create SES session: %w", err))
 		return nil, err
 	}
 
@@ -35,12 +40,26 @@ func NewSESAdapter(mailerCfg *config.Mailer, errTracker ports.ErrTrackerAdapter)
 		session:    ses.New(awsSession),
 		mailerCfg:  mailerCfg,
 		errTracker: errTracker,
+		tracer:     tracerProvider.Tracer(tracing.TracerName),
 	}, nil
 }
 
 // Send sends an email message.
-// It takes a ports.EmailMessage and returns an error if the sending fails.
-func (a *SESAdapter) Send(msg ports.EmailMessage) error {
+// It takes a ports.EmailMessage and returns the SES message ID, or an error if the sending fails.
+// The sending is traced in a span, without the recipients.
+func (a *SESAdapter) Send(ctx context.Context, msg ports.EmailMessage) (messageID string, err error) {
+	ctx, span := a.tracer.Start(ctx, "SES.SendEmail",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("aws-api"),
+			semconv.RPCService("SES"),
+			semconv.RPCMethod("SendEmail"),
+			attribute.String("email.template", msg.Template),
+			attribute.Int("email.recipients", len(msg.To)),
+		),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	// Convert []string to []*string for ToAddresses
 	toAddresses := make([]*string, len(msg.To))
 	for i, addr := range msg.To {
@@ -64,13 +83,18 @@ func (a *SESAdapter) Send(msg ports.EmailMessage) error {
 		Source: aws.String(a.mailerCfg.From),
 	}
 
-	msgID, err := a.session.SendEmail(sesInput)
+	output, err := a.session.SendEmailWithContext(ctx, sesInput)
 	if err != nil {
-		a.errTracker.CaptureException(fmt.Errorf("failed to send email: %w", err))
-		return err
+		a.errTracker.CaptureException(ctx, fmt.Errorf("failed to send email: %w", err))
+		return "", err
 	}
 
-	slog.Info("email sent with message ID", "msgID", *msgID)
+	return aws.StringValue(output.MessageId), nil
+}
 
-	return nil
+// Ping checks that SES is reachable with the configured credentials, by retrieving the sending quota.
+// The failures are not captured, they are reported by the health checks.
+func (a *SESAdapter) Ping(ctx context.Context) error {
+	_, err := a.session.GetSendQuotaWithContext(ctx, &ses.GetSendQuotaInput{})
+	return err
 }
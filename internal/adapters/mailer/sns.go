@@ -0,0 +1,326 @@
+package mailer
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"go-starter/config"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SNS message types.
+const (
+	snsTypeNotification             = "Notification"
+	snsTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	snsTypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// snsHostPattern matches the hosts allowed to serve signing certificates and subscription URLs.
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// snsMessage represents the envelope of a message posted by Amazon SNS.
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// sesNotification represents a SES bounce or complaint notification, published in the SNS message.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	Bounce           struct {
+		BounceType        string `json:"bounceType"`
+		BounceSubType     string `json:"bounceSubType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// SNSWebhookAdapter implements the ports.MailerWebhookAdapter interface for SES notifications delivered by SNS.
+// It verifies the SNS signature of every message before decoding it.
+type SNSWebhookAdapter struct {
+	client     *http.Client
+	mailerCfg  *config.Mailer
+	errTracker ports.ErrTrackerAdapter
+	certs      map[string]*x509.Certificate
+	mu         sync.RWMutex
+}
+
+// NewSNSWebhookAdapter creates a new SNSWebhookAdapter instance.
+func NewSNSWebhookAdapter(mailerCfg *config.Mailer, errTracker ports.ErrTrackerAdapter) *SNSWebhookAdapter {
+	return &SNSWebhookAdapter{
+		client:     &http.Client{Timeout: 10 * time.Second},
+		mailerCfg:  mailerCfg,
+		errTracker: errTracker,
+		certs:      map[string]*x509.Certificate{},
+		mu:         sync.RWMutex{},
+	}
+}
+
+// ParseNotification verifies the signature of a raw SNS payload and decodes the SES notification it carries.
+// Returns domain.ErrInvalidWebhookSignature if the payload cannot be authenticated or is not sent by the configured topic.
+func (a *SNSWebhookAdapter) ParseNotification(ctx context.Context, payload []byte) (*ports.MailerNotification, error) {
+	var msg snsMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return nil, fmt.Errorf("failed to decode sns message: %w", err)
+	}
+
+	if msg.TopicArn != a.mailerCfg.WebhookTopicARN {
+		return nil, domain.ErrInvalidWebhookSignature
+	}
+
+	if err := a.verify(ctx, &msg); err != nil {
+		return nil, err
+	}
+
+	switch msg.Type {
+	case snsTypeSubscriptionConfirmation:
+		return &ports.MailerNotification{
+			Type:         ports.MailerNotificationSubscription,
+			SubscribeURL: msg.SubscribeURL,
+		}, nil
+	case snsTypeNotification:
+		return parseSESNotification(msg.Message)
+	default:
+		return &ports.MailerNotification{Type: ports.MailerNotificationOther}, nil
+	}
+}
+
+// ConfirmSubscription confirms the SNS subscription by visiting the subscribe URL of the notification.
+func (a *SNSWebhookAdapter) ConfirmSubscription(ctx context.Context, notification *ports.MailerNotification) error {
+	if err := validateSNSURL(notification.SubscribeURL); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, notification.SubscribeURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		a.errTracker.CaptureException(ctx, fmt.Errorf("failed to confirm sns subscription: %w", err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("failed to confirm sns subscription: unexpected status %d", resp.StatusCode)
+		a.errTracker.CaptureException(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// verify checks the signature of an SNS message against the certificate referenced by the message.
+func (a *SNSWebhookAdapter) verify(ctx context.Context, msg *snsMessage) error {
+	var hash crypto.Hash
+	switch msg.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	stringToSign, err := buildSNSStringToSign(msg)
+	if err != nil {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	cert, err := a.getCertificate(ctx, msg.SigningCertURL)
+	if err != nil {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(stringToSign))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(stringToSign))
+		digest = sum[:]
+	}
+
+	if err := rsa.VerifyPKCS1v15(publicKey, hash, digest, signature); err != nil {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	return nil
+}
+
+// getCertificate returns the signing certificate at the given URL, fetching it on first use.
+func (a *SNSWebhookAdapter) getCertificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	a.mu.RLock()
+	cert, ok := a.certs[certURL]
+	a.mu.RUnlock()
+	if ok {
+		return cert, nil
+	}
+
+	if err := validateSNSURL(certURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		a.errTracker.CaptureException(ctx, fmt.Errorf("failed to fetch sns signing certificate: %w", err))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch sns signing certificate: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, errors.New("invalid sns signing certificate")
+	}
+
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	a.certs[certURL] = cert
+	a.mu.Unlock()
+
+	return cert, nil
+}
+
+// validateSNSURL ensures a URL points to an SNS endpoint over HTTPS, so the signature
+// cannot be verified against a certificate controlled by the sender.
+func validateSNSURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || !snsHostPattern.MatchString(u.Hostname()) {
+		return fmt.Errorf("untrusted sns url: %s", rawURL)
+	}
+	return nil
+}
+
+// buildSNSStringToSign builds the canonical string signed by SNS for the given message.
+func buildSNSStringToSign(msg *snsMessage) (string, error) {
+	var fields [][2]string
+	switch msg.Type {
+	case snsTypeNotification:
+		fields = [][2]string{{"Message", msg.Message}, {"MessageId", msg.MessageID}}
+		if msg.Subject != "" {
+			fields = append(fields, [2]string{"Subject", msg.Subject})
+		}
+		fields = append(fields, [][2]string{{"Timestamp", msg.Timestamp}, {"TopicArn", msg.TopicArn}, {"Type", msg.Type}}...)
+	case snsTypeSubscriptionConfirmation, snsTypeUnsubscribeConfirmation:
+		fields = [][2]string{
+			{"Message", msg.Message},
+			{"MessageId", msg.MessageID},
+			{"SubscribeURL", msg.SubscribeURL},
+			{"Timestamp", msg.Timestamp},
+			{"Token", msg.Token},
+			{"TopicArn", msg.TopicArn},
+			{"Type", msg.Type},
+		}
+	default:
+		return "", fmt.Errorf("unknown sns message type: %s", msg.Type)
+	}
+
+	var sb strings.Builder
+	for _, field := range fields {
+		sb.WriteString(field[0])
+		sb.WriteString("\n")
+		sb.WriteString(field[1])
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// parseSESNotification decodes the SES notification published in an SNS message.
+func parseSESNotification(message string) (*ports.MailerNotification, error) {
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(message), &notification); err != nil {
+		return nil, fmt.Errorf("failed to decode ses notification: %w", err)
+	}
+
+	switch notification.NotificationType {
+	case "Bounce":
+		recipients := make([]string, len(notification.Bounce.BouncedRecipients))
+		for i, r := range notification.Bounce.BouncedRecipients {
+			recipients[i] = r.EmailAddress
+		}
+		return &ports.MailerNotification{
+			Type:       ports.MailerNotificationBounce,
+			Recipients: recipients,
+			Permanent:  notification.Bounce.BounceType == "Permanent",
+			Details:    strings.TrimSpace(notification.Bounce.BounceType + " " + notification.Bounce.BounceSubType),
+		}, nil
+	case "Complaint":
+		recipients := make([]string, len(notification.Complaint.ComplainedRecipients))
+		for i, r := range notification.Complaint.ComplainedRecipients {
+			recipients[i] = r.EmailAddress
+		}
+		return &ports.MailerNotification{
+			Type:       ports.MailerNotificationComplaint,
+			Recipients: recipients,
+			Details:    notification.Complaint.ComplaintFeedbackType,
+		}, nil
+	default:
+		return &ports.MailerNotification{Type: ports.MailerNotificationOther}, nil
+	}
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"sync"
+)
+
+// MailerWebhookAdapterMock implements the ports.MailerWebhookAdapter interface for testing purposes.
+// Payloads are JSON encoded ports.MailerNotification values, no signature is involved.
+type MailerWebhookAdapterMock struct {
+	confirmed int
+	mu        sync.RWMutex
+}
+
+// NewMailerWebhookAdapterMock creates a new instance of MailerWebhookAdapterMock.
+func NewMailerWebhookAdapterMock() *MailerWebhookAdapterMock {
+	return &MailerWebhookAdapterMock{
+		mu: sync.RWMutex{},
+	}
+}
+
+// ParseNotification decodes a JSON encoded ports.MailerNotification.
+// Returns domain.ErrInvalidWebhookSignature if the payload is not valid JSON.
+func (m *MailerWebhookAdapterMock) ParseNotification(_ context.Context, payload []byte) (*ports.MailerNotification, error) {
+	var notification ports.MailerNotification
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		return nil, domain.ErrInvalidWebhookSignature
+	}
+	return &notification, nil
+}
+
+// ConfirmSubscription records the confirmation instead of calling the provider.
+func (m *MailerWebhookAdapterMock) ConfirmSubscription(_ context.Context, _ *ports.MailerNotification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.confirmed++
+	return nil
+}
+
+// ConfirmedSubscriptionsCount returns the number of confirmed subscriptions.
+func (m *MailerWebhookAdapterMock) ConfirmedSubscriptionsCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.confirmed
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsMock is a mock implementation of the ports.Metrics interface.
+// It counts the recorded metrics by name and labels, for the tests to assert on them.
+type MetricsMock struct {
+	counts map[string]int
+	mu     sync.RWMutex
+}
+
+// NewMetricsMock creates a new MetricsMock instance.
+func NewMetricsMock() *MetricsMock {
+	return &MetricsMock{
+		counts: make(map[string]int),
+		mu:     sync.RWMutex{},
+	}
+}
+
+// ObserveHTTPRequest counts the request under "http_requests" with its method, route and status.
+func (m *MetricsMock) ObserveHTTPRequest(method, route string, status int, _ time.Duration) {
+	m.inc("http_requests", method, route, strconv.Itoa(status))
+}
+
+// ObserveCacheOperation counts the operation under "cache_operations" with its result.
+func (m *MetricsMock) ObserveCacheOperation(operation, result string, _ time.Duration) {
+	m.inc("cache_operations", operation, result)
+}
+
+// IncRateLimitRejection counts the rejection under "rate_limit_rejections" with its policy.
+func (m *MetricsMock) IncRateLimitRejection(policy string) {
+	m.inc("rate_limit_rejections", policy)
+}
+
+// IncMailSent counts the email under "mails" with its template and outcome.
+func (m *MetricsMock) IncMailSent(template, outcome string) {
+	m.inc("mails", template, outcome)
+}
+
+// IncDomainEvent counts the event under "domain_events" with its outcome.
+func (m *MetricsMock) IncDomainEvent(event, outcome string) {
+	m.inc("domain_events", event, outcome)
+}
+
+// Count returns how many times the metric was recorded with the labels, in the order of the recording method parameters.
+func (m *MetricsMock) Count(name string, labels ...string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.counts[metricKey(name, labels)]
+}
+
+func (m *MetricsMock) inc(name string, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[metricKey(name, labels)]++
+}
+
+func metricKey(name string, labels []string) string {
+	return name + "{" + strings.Join(labels, ",") + "}"
+}
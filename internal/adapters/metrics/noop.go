@@ -0,0 +1,27 @@
+package metrics
+
+import "time"
+
+// NoopAdapter implements the ports.Metrics interface without recording anything.
+// It is used when the metrics are disabled.
+type NoopAdapter struct{}
+
+// NewNoopAdapter creates a new NoopAdapter instance.
+func NewNoopAdapter() *NoopAdapter {
+	return &NoopAdapter{}
+}
+
+// ObserveHTTPRequest does nothing.
+func (a *NoopAdapter) ObserveHTTPRequest(string, string, int, time.Duration) {}
+
+// ObserveCacheOperation does nothing.
+func (a *NoopAdapter) ObserveCacheOperation(string, string, time.Duration) {}
+
+// IncRateLimitRejection does nothing.
+func (a *NoopAdapter) IncRateLimitRejection(string) {}
+
+// IncMailSent does nothing.
+func (a *NoopAdapter) IncMailSent(string, string) {}
+
+// IncDomainEvent does nothing.
+func (a *NoopAdapter) IncDomainEvent(string, string) {}
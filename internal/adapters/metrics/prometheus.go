@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the names of the metrics of the application.
+const namespace = "go_starter"
+
+// PrometheusAdapter implements the ports.Metrics interface with Prometheus collectors.
+// The metrics are kept in a registry of their own, along with the Go runtime, process and database pool metrics.
+type PrometheusAdapter struct {
+	registry            *prometheus.Registry
+	httpRequests        *prometheus.CounterVec
+	httpDuration        *prometheus.HistogramVec
+	cacheOperations     *prometheus.CounterVec
+	cacheDuration       *prometheus.HistogramVec
+	rateLimitRejections *prometheus.CounterVec
+	mailsSent           *prometheus.CounterVec
+	domainEvents        *prometheus.CounterVec
+}
+
+// NewPrometheusAdapter creates a new PrometheusAdapter instance.
+// The statistics of the connection pool of the database are exported as gauges.
+func NewPrometheusAdapter(db *sql.DB) *PrometheusAdapter {
+	a := &PrometheusAdapter{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests served, by method, route pattern and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests, by method, route pattern and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		cacheOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_operations_total",
+			Help:      "Number of cache operations, by operation and result.",
+		}, []string{"operation", "result"}),
+		cacheDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cache_operation_duration_seconds",
+			Help:      "Duration of the cache operations, by operation.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"operation"}),
+		rateLimitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_rejections_total",
+			Help:      "Number of requests denied by the rate limiter, by policy.",
+		}, []string{"policy"}),
+		mailsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "mails_total",
+			Help:      "Number of emails, by template and delivery outcome.",
+		}, []string{"template", "outcome"}),
+		domainEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "domain_events_total",
+			Help:      "Number of domain events (e.g., logins, registrations), by event and outcome.",
+		}, []string{"event", "outcome"}),
+	}
+
+	a.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, "postgres"),
+		a.httpRequests,
+		a.httpDuration,
+		a.cacheOperations,
+		a.cacheDuration,
+		a.rateLimitRejections,
+		a.mailsSent,
+		a.domainEvents,
+	)
+
+	return a
+}
+
+// Handler returns the HTTP handler exposing the metrics in the Prometheus text format.
+func (a *PrometheusAdapter) Handler() http.Handler {
+	return promhttp.HandlerFor(a.registry, promhttp.HandlerOpts{Registry: a.registry})
+}
+
+// ObserveHTTPRequest records a served HTTP request with the pattern of its route, its status and its duration.
+func (a *PrometheusAdapter) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	a.httpRequests.WithLabelValues(method, route, statusLabel).Inc()
+	a.httpDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// ObserveCacheOperation records a cache operation with its result and its duration.
+func (a *PrometheusAdapter) ObserveCacheOperation(operation, result string, duration time.Duration) {
+	a.cacheOperations.WithLabelValues(operation, result).Inc()
+	a.cacheDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// IncRateLimitRejection counts a request denied by the rate limit policy.
+func (a *PrometheusAdapter) IncRateLimitRejection(policy string) {
+	a.rateLimitRejections.WithLabelValues(policy).Inc()
+}
+
+// IncMailSent counts an email of the template with the outcome of its delivery.
+func (a *PrometheusAdapter) IncMailSent(template, outcome string) {
+	a.mailsSent.WithLabelValues(template, outcome).Inc()
+}
+
+// IncDomainEvent counts a domain event with its outcome.
+func (a *PrometheusAdapter) IncDomainEvent(event, outcome string) {
+	a.domainEvents.WithLabelValues(event, outcome).Inc()
+}
@@ -0,0 +1,94 @@
+package ratelimiter
+
+import (
+	"context"
+	"go-starter/internal/domain/ports"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is the interval between two removals of the clients no longer limited.
+const memorySweepInterval = time.Minute
+
+// MemoryRateLimiter implements the ports.RateLimiter interface in the memory of the process, with the GCRA.
+// It is the fallback of a RateLimiter when the cache is unavailable: the hits are counted per instance
+// of the application, so the limits are only approximately enforced, but a client cannot flood an instance.
+type MemoryRateLimiter struct {
+	timeGenerator ports.TimeGenerator
+	// tats holds the theoretical arrival time of the next hit of each key.
+	tats      map[string]time.Time
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// NewMemoryRateLimiter creates a new instance of MemoryRateLimiter.
+func NewMemoryRateLimiter(timeGenerator ports.TimeGenerator) *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		timeGenerator: timeGenerator,
+		tats:          map[string]time.Time{},
+		lastSweep:     timeGenerator.Now(),
+	}
+}
+
+// Check records a hit for the key and reports whether it is within the limit over the window.
+// The Result has the same semantics as the one of the StrategyGCRA.
+func (m *MemoryRateLimiter) Check(_ context.Context, key string, limit int64, window time.Duration) (*Result, error) {
+	return m.hit(key, limit, window, false), nil
+}
+
+// Peek reports whether a hit for the key would be within the limit over the window, without recording it.
+func (m *MemoryRateLimiter) Peek(_ context.Context, key string, limit int64, window time.Duration) (*Result, error) {
+	return m.hit(key, limit, window, true), nil
+}
+
+// hit computes the outcome of a hit for the key, which is only recorded if it is not a dry run.
+func (m *MemoryRateLimiter) hit(key string, limit int64, window time.Duration, dryRun bool) *Result {
+	if limit <= 0 {
+		return &Result{Allowed: false, Current: 0, Limit: limit, ResetAfter: window}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.timeGenerator.Now()
+	m.sweep(now)
+
+	interval := window / time.Duration(limit)
+	if interval <= 0 {
+		interval = 1
+	}
+
+	tat := m.tats[key]
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-interval * time.Duration(limit))
+	if now.Before(allowAt) {
+		return &Result{Allowed: false, Current: limit, Limit: limit, ResetAfter: allowAt.Sub(now)}
+	}
+
+	if !dryRun {
+		m.tats[key] = newTat
+	}
+	pending := newTat.Sub(now)
+	return &Result{
+		Allowed:    true,
+		Current:    int64((pending + interval - 1) / interval),
+		Limit:      limit,
+		ResetAfter: pending,
+	}
+}
+
+// sweep removes the keys whose hits no longer count, at most once per memorySweepInterval.
+func (m *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, tat := range m.tats {
+		if !tat.After(now) {
+			delete(m.tats, key)
+		}
+	}
+}
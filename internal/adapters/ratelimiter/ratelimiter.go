@@ -2,105 +2,162 @@ package ratelimiter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"go-starter/internal/adapters/timegen"
+	"go-starter/internal/domain"
 	"go-starter/internal/domain/ports"
 	"time"
 )
 
-// fixed window rate limiter lua script
-const rateLimiterScript = `
-local key = KEYS[1]
-local limit = tonumber(ARGV[1])
-local window = tonumber(ARGV[2])
-local current = redis.call('INCR', key)
+// RateLimiter provides rate limiting functionality using the cache repository.
+// It implements the ports.RateLimiter interface.
+// The hits are counted by a Lua script of the chosen strategy, the current time being given by the time generator
+// so that every instance of the application shares the same clock as far as the scripts are concerned.
+// When the cache is unavailable, the hits are counted by the fallback limiter instead, so that the limiter fails open
+// to a limit per instance rather than rejecting every hit.
+type RateLimiter struct {
+	cache         ports.CacheRepository
+	timeGenerator ports.TimeGenerator
+	fallback      ports.RateLimiter
+	strategy      Strategy
+	keyPrefix     string
+	name          string
+}
 
-if current == 1 then
-  redis.call('EXPIRE', key, window)
-end
+// Option configures a RateLimiter.
+type Option func(*RateLimiter)
 
-local ttl = redis.call('TTL', key)
-return {current, limit, ttl}
-`
+// WithStrategy sets the algorithm the hits are counted with, StrategyFixedWindow by default.
+func WithStrategy(strategy Strategy) Option {
+	return func(rl *RateLimiter) {
+		rl.strategy = strategy
+	}
+}
 
-// RateLimiter provides rate limiting functionality using the cache repository
-type RateLimiter struct {
-	cache     ports.CacheRepository
-	keyPrefix string
-	name      string
+// WithTimeGenerator sets the clock the windows are computed with, the system clock by default.
+func WithTimeGenerator(timeGenerator ports.TimeGenerator) Option {
+	return func(rl *RateLimiter) {
+		rl.timeGenerator = timeGenerator
+	}
+}
+
+// WithFallback sets the limiter counting the hits while the cache is unavailable,
+// a MemoryRateLimiter by default.
+func WithFallback(fallback ports.RateLimiter) Option {
+	return func(rl *RateLimiter) {
+		rl.fallback = fallback
+	}
 }
 
 // New creates a new RateLimiter instance
-func New(cache ports.CacheRepository, name string) *RateLimiter {
+func New(cache ports.CacheRepository, name string, opts ...Option) *RateLimiter {
 	keyPrefix := "rate_limit:"
 
-	return &RateLimiter{
-		cache:     cache,
-		keyPrefix: keyPrefix,
-		name:      name,
+	rl := &RateLimiter{
+		cache:         cache,
+		timeGenerator: timegen.NewTimeGenerator(),
+		strategy:      StrategyFixedWindow,
+		keyPrefix:     keyPrefix,
+		name:          name,
+	}
+	for _, opt := range opts {
+		opt(rl)
 	}
+	if rl.fallback == nil {
+		rl.fallback = NewMemoryRateLimiter(rl.timeGenerator)
+	}
+	return rl
 }
 
 // Result represents the outcome of a rate limit check
-type Result struct {
-	Allowed    bool
-	Current    int64
-	Limit      int64
-	ResetAfter time.Duration
-}
+type Result = ports.RateLimitResult
 
 // Check verifies if the request should be allowed based on the rate limit
 func (rl *RateLimiter) Check(ctx context.Context, key string, limit int64, window time.Duration) (*Result, error) {
-	// Format: rate_limit:limiter_name:IP
-	redisKey := fmt.Sprintf("%s%s:%s", rl.keyPrefix, rl.name, key)
+	return rl.run(ctx, key, limit, window, false)
+}
+
+// Peek reports whether a hit for the key would be allowed, without recording it.
+func (rl *RateLimiter) Peek(ctx context.Context, key string, limit int64, window time.Duration) (*Result, error) {
+	return rl.run(ctx, key, limit, window, true)
+}
+
+// run executes the script of the strategy for a hit of the key, which is only recorded if it is not a dry run.
+func (rl *RateLimiter) run(ctx context.Context, key string, limit int64, window time.Duration, dryRun bool) (*Result, error) {
+	script, ok := strategyScripts[rl.strategy]
+	if !ok {
+		return nil, fmt.Errorf("unknown rate limit strategy: %s", rl.strategy)
+	}
+
+	windowMs := window.Milliseconds()
+	if windowMs <= 0 {
+		return nil, fmt.Errorf("invalid rate limit window: %s", window)
+	}
+	if limit <= 0 {
+		return &Result{Allowed: false, Current: 0, Limit: limit, ResetAfter: window}, nil
+	}
+
+	// Format: rate_limit:limiter_name:strategy:{IP}
+	// The braces keep all the keys of a client in the same slot of a Redis cluster.
+	redisKey := fmt.Sprintf("%s%s:%s:{%s}", rl.keyPrefix, rl.name, rl.strategy, key)
+	now := rl.timeGenerator.Now().UnixMilli()
+	keys, args := script.args(redisKey, limit, windowMs, now)
+	// The dry run flag follows the arguments of every script.
+	if dryRun {
+		args = append(args, 1)
+	} else {
+		args = append(args, 0)
+	}
 
 	// Execute the Lua script
-	res, err := rl.cache.Eval(ctx, rateLimiterScript, []string{redisKey}, limit, int(window.Seconds()))
+	res, err := rl.cache.Eval(ctx, script.source, keys, args...)
+	if errors.Is(err, domain.ErrCacheUnavailable) {
+		if dryRun {
+			return rl.fallback.Peek(ctx, redisKey, limit, window)
+		}
+		return rl.fallback.Check(ctx, redisKey, limit, window)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute rate limit script: %w", err)
 	}
 
-	// Parse the result
+	// Parse the result: {allowed, current, reset after in milliseconds}
 	values, ok := res.([]interface{})
 	if !ok || len(values) != 3 {
 		return nil, fmt.Errorf("unexpected result format from rate limit script")
 	}
 
-	// Extract current count and limit
-	current, ok := values[0].(int64)
-	if !ok {
-		// Try to convert from float64 (Redis sometimes returns numbers as float64)
-		if floatVal, isFloat := values[0].(float64); isFloat {
-			current = int64(floatVal)
-		} else {
-			return nil, fmt.Errorf("unexpected type for current count")
-		}
+	allowed, err := toInt64(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected type for allowed flag: %w", err)
 	}
-
-	limitVal, ok := values[1].(int64)
-	if !ok {
-		// Try to convert from float64
-		if floatVal, isFloat := values[1].(float64); isFloat {
-			limitVal = int64(floatVal)
-		} else {
-			return nil, fmt.Errorf("unexpected type for limit value")
-		}
+	current, err := toInt64(values[1])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected type for current count: %w", err)
 	}
-
-	// Extract TTL
-	ttlVal, ok := values[2].(int64)
-	if !ok {
-		if floatVal, isFloat := values[2].(float64); isFloat {
-			ttlVal = int64(floatVal)
-		} else {
-			ttlVal = int64(window.Seconds()) // Fallback
-		}
+	resetAfterMs, err := toInt64(values[2])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected type for reset duration: %w", err)
 	}
-	resetAfter := time.Duration(ttlVal) * time.Second
 
 	return &Result{
-		Allowed:    current <= limitVal,
+		Allowed:    allowed == 1,
 		Current:    current,
-		Limit:      limitVal,
-		ResetAfter: resetAfter,
+		Limit:      limit,
+		ResetAfter: time.Duration(resetAfterMs) * time.Millisecond,
 	}, nil
 }
+
+// toInt64 converts a number returned by a script.
+// Redis returns integers, float64 values are accepted as some clients decode numbers as such.
+func toInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("%T", value)
+	}
+}
@@ -0,0 +1,57 @@
+package ratelimiter
+
+import (
+	"context"
+	"go-starter/internal/domain/ports"
+	"sync"
+	"time"
+)
+
+// RateLimiterMock implements the ports.RateLimiter interface with in-memory fixed windows for testing purposes.
+// Windows are aligned on the time generator clock so tests can move from one window to the next.
+type RateLimiterMock struct {
+	timeGenerator ports.TimeGenerator
+	counters      map[string]int64
+	mu            sync.Mutex
+}
+
+// NewRateLimiterMock creates a new instance of RateLimiterMock.
+func NewRateLimiterMock(timeGenerator ports.TimeGenerator) *RateLimiterMock {
+	return &RateLimiterMock{
+		timeGenerator: timeGenerator,
+		counters:      map[string]int64{},
+		mu:            sync.Mutex{},
+	}
+}
+
+// Check records a hit for the key in the current window and reports whether it is within the limit.
+func (m *RateLimiterMock) Check(_ context.Context, key string, limit int64, window time.Duration) (*Result, error) {
+	return m.hit(key, limit, window, false), nil
+}
+
+// Peek reports whether a hit for the key in the current window would be within the limit, without recording it.
+func (m *RateLimiterMock) Peek(_ context.Context, key string, limit int64, window time.Duration) (*Result, error) {
+	return m.hit(key, limit, window, true), nil
+}
+
+// hit computes the outcome of a hit for the key in the current window, which is only recorded if it is not a dry run.
+func (m *RateLimiterMock) hit(key string, limit int64, window time.Duration, dryRun bool) *Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.timeGenerator.Now()
+	start := now.Truncate(window)
+	windowKey := key + "@" + start.String()
+
+	current := m.counters[windowKey] + 1
+	if !dryRun {
+		m.counters[windowKey] = current
+	}
+
+	return &Result{
+		Allowed:    current <= limit,
+		Current:    current,
+		Limit:      limit,
+		ResetAfter: start.Add(window).Sub(now),
+	}
+}
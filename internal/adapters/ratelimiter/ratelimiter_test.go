@@ -0,0 +1,254 @@
+//go:build !integration
+
+package ratelimiter_test
+
+import (
+	"context"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/ratelimiter"
+	"go-starter/internal/adapters/storage/cache"
+	"go-starter/internal/adapters/timegen"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Rate limit used by the conformance suite.
+const (
+	conformanceLimit  = 5
+	conformanceWindow = time.Second
+)
+
+// rateLimiterHarness runs a rate limiter against an in-memory Redis server, both driven by a simulated clock.
+type rateLimiterHarness struct {
+	limiter *ratelimiter.RateLimiter
+	// other is a limiter of another name, sharing the same server.
+	other *ratelimiter.RateLimiter
+	clock *timegen.TimeGeneratorMock
+	redis *miniredis.Miniredis
+}
+
+func newRateLimiterHarness(t *testing.T, strategy ratelimiter.Strategy) *rateLimiterHarness {
+	t.Helper()
+	redis := miniredis.RunT(t)
+	cacheRepo, err := cache.New(context.Background(), &config.Redis{Addrs: []string{redis.Addr()}}, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	t.Cleanup(func() { _ = cacheRepo.Close() })
+
+	// 300ms into a window, so that the strategies aligned on windows are not checked on a boundary only.
+	clock := timegen.NewTimeGeneratorMock(time.UnixMilli(1_700_000_000_300))
+	return &rateLimiterHarness{
+		limiter: ratelimiter.New(cacheRepo, "conformance", ratelimiter.WithStrategy(strategy), ratelimiter.WithTimeGenerator(clock)),
+		other:   ratelimiter.New(cacheRepo, "other", ratelimiter.WithStrategy(strategy), ratelimiter.WithTimeGenerator(clock)),
+		clock:   clock,
+		redis:   redis,
+	}
+}
+
+// advance moves the clock of the limiter and of the Redis server, expiring the keys.
+func (h *rateLimiterHarness) advance(d time.Duration) {
+	h.clock.Advance(d)
+	h.redis.FastForward(d)
+}
+
+func (h *rateLimiterHarness) check(t *testing.T, key string) *ratelimiter.Result {
+	t.Helper()
+	result, err := h.limiter.Check(context.Background(), key, conformanceLimit, conformanceWindow)
+	if err != nil {
+		t.Fatalf("failed to check rate limit: %v", err)
+	}
+	if result.Limit != conformanceLimit {
+		t.Errorf("expected limit %d, got %d", conformanceLimit, result.Limit)
+	}
+	return result
+}
+
+// burst checks the limit number of hits, expecting them all to be allowed.
+func (h *rateLimiterHarness) burst(t *testing.T, key string) {
+	t.Helper()
+	for i := range conformanceLimit {
+		if result := h.check(t, key); !result.Allowed {
+			t.Fatalf("expected hit %d to be allowed", i+1)
+		}
+	}
+}
+
+func TestRateLimiter_Conformance(t *testing.T) {
+	t.Parallel()
+
+	strategies := map[ratelimiter.Strategy]struct {
+		allowsBoundaryBurst bool
+	}{
+		ratelimiter.StrategyFixedWindow:          {allowsBoundaryBurst: true},
+		ratelimiter.StrategySlidingWindowLog:     {},
+		ratelimiter.StrategySlidingWindowCounter: {},
+		ratelimiter.StrategyGCRA:                 {},
+	}
+
+	for strategy, tt := range strategies {
+		t.Run(strategy.String(), func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("hits up to the limit should be allowed", func(t *testing.T) {
+				t.Parallel()
+				h := newRateLimiterHarness(t, strategy)
+
+				for i := int64(1); i <= conformanceLimit; i++ {
+					result := h.check(t, "client")
+					if !result.Allowed {
+						t.Fatalf("expected hit %d to be allowed", i)
+					}
+					if result.Current != i {
+						t.Errorf("expected hit %d to be counted as %d, got %d", i, i, result.Current)
+					}
+					if result.ResetAfter <= 0 || result.ResetAfter > 2*conformanceWindow {
+						t.Errorf("expected hit %d to reset within two windows, got %v", i, result.ResetAfter)
+					}
+				}
+
+				result := h.check(t, "client")
+				if result.Allowed {
+					t.Fatal("expected the hit over the limit to be denied")
+				}
+				if result.Current != conformanceLimit {
+					t.Errorf("expected the denied hit to report %d, got %d", conformanceLimit, result.Current)
+				}
+			})
+
+			t.Run("denied hit should be allowed once reset", func(t *testing.T) {
+				t.Parallel()
+				h := newRateLimiterHarness(t, strategy)
+
+				h.burst(t, "client")
+				result := h.check(t, "client")
+				if result.Allowed || result.ResetAfter <= 0 {
+					t.Fatalf("expected the hit over the limit to be denied with a reset, got %+v", result)
+				}
+
+				h.advance(result.ResetAfter - time.Millisecond)
+				if h.check(t, "client").Allowed {
+					t.Fatalf("expected the hit %v before the reset to be denied", time.Millisecond)
+				}
+				h.advance(time.Millisecond)
+				if !h.check(t, "client").Allowed {
+					t.Fatal("expected the hit at the reset to be allowed")
+				}
+			})
+
+			t.Run("limit should be recovered after idling", func(t *testing.T) {
+				t.Parallel()
+				h := newRateLimiterHarness(t, strategy)
+
+				h.burst(t, "client")
+				h.advance(2 * conformanceWindow)
+				h.burst(t, "client")
+			})
+
+			t.Run("keys should be limited independently", func(t *testing.T) {
+				t.Parallel()
+				h := newRateLimiterHarness(t, strategy)
+
+				h.burst(t, "client")
+				h.burst(t, "other client")
+				if h.check(t, "client").Allowed {
+					t.Error("expected the hit over the limit to be denied")
+				}
+			})
+
+			t.Run("limiters of another name should be limited independently", func(t *testing.T) {
+				t.Parallel()
+				h := newRateLimiterHarness(t, strategy)
+
+				h.burst(t, "client")
+				result, err := h.other.Check(context.Background(), "client", conformanceLimit, conformanceWindow)
+				if err != nil {
+					t.Fatalf("failed to check rate limit: %v", err)
+				}
+				if !result.Allowed || result.Current != 1 {
+					t.Errorf("expected the first hit of the other limiter to be allowed, got %+v", result)
+				}
+			})
+
+			t.Run("peeked hits should not be recorded", func(t *testing.T) {
+				t.Parallel()
+				h := newRateLimiterHarness(t, strategy)
+
+				for i := range 2 * conformanceLimit {
+					result, err := h.limiter.Peek(context.Background(), "client", conformanceLimit, conformanceWindow)
+					if err != nil {
+						t.Fatalf("failed to peek rate limit: %v", err)
+					}
+					if !result.Allowed || result.Current != 1 {
+						t.Fatalf("expected peek %d to report the first hit as allowed, got %+v", i+1, result)
+					}
+				}
+				h.burst(t, "client")
+				result, err := h.limiter.Peek(context.Background(), "client", conformanceLimit, conformanceWindow)
+				if err != nil {
+					t.Fatalf("failed to peek rate limit: %v", err)
+				}
+				if result.Allowed {
+					t.Error("expected the peek over the limit to be denied")
+				}
+			})
+
+			t.Run("burst across a window boundary", func(t *testing.T) {
+				t.Parallel()
+				h := newRateLimiterHarness(t, strategy)
+
+				// The burst ends a window, the next hit starts the next one.
+				h.advance(conformanceWindow - 300*time.Millisecond - time.Millisecond)
+				h.burst(t, "client")
+				h.advance(time.Millisecond)
+
+				if allowed := h.check(t, "client").Allowed; allowed != tt.allowsBoundaryBurst {
+					t.Errorf("expected the hit after the boundary to be allowed: %t, got %t", tt.allowsBoundaryBurst, allowed)
+				}
+			})
+		})
+	}
+}
+
+func TestRateLimiter_FailsOpen(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	redis := miniredis.RunT(t)
+	redisCfg := &config.Redis{Addrs: []string{redis.Addr()}, BreakerFailureThreshold: 1, BreakerOpenTimeout: time.Minute}
+	cacheRepo, err := cache.New(ctx, redisCfg, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	t.Cleanup(func() { _ = cacheRepo.Close() })
+
+	clock := timegen.NewTimeGeneratorMock(time.Now())
+	breaker := cache.NewCircuitBreaker(cacheRepo, redisCfg, clock)
+	limiter := ratelimiter.New(breaker, "degraded", ratelimiter.WithTimeGenerator(clock))
+	redis.Close()
+
+	// Act
+	results := make([]*ratelimiter.Result, 0, 3)
+	for range 3 {
+		result, err := limiter.Check(ctx, "client", 2, time.Minute)
+		if err != nil {
+			t.Fatalf("expected the limiter to fail open to the in-process limiter, got %v", err)
+		}
+		results = append(results, result)
+	}
+
+	// Assert
+	for i, result := range results[:2] {
+		if !result.Allowed {
+			t.Errorf("expected hit %d to be allowed", i+1)
+		}
+	}
+	if result := results[2]; result.Allowed || result.ResetAfter <= 0 {
+		t.Errorf("expected the hit over the limit to be denied with a reset, got %+v", result)
+	}
+}
@@ -0,0 +1,176 @@
+package ratelimiter
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Strategy is the algorithm a RateLimiter counts the hits with.
+// Every strategy reports the same Result:
+//   - Current is the number of hits counted against the limit, never more than the limit;
+//     denied hits are not counted and report the limit.
+//   - ResetAfter is, for a denied hit, how long to wait before a hit is allowed again,
+//     and for an allowed hit, how long until the hits counted so far no longer count.
+type Strategy string
+
+// Rate limit strategies.
+const (
+	// StrategyFixedWindow counts the hits in windows aligned on the clock.
+	// It is the cheapest strategy, but allows up to twice the limit across the boundary of two windows.
+	StrategyFixedWindow Strategy = "fixed_window"
+	// StrategySlidingWindowLog records every allowed hit, and counts those of the last window.
+	// It is exact, at the cost of storing up to limit entries per client.
+	StrategySlidingWindowLog Strategy = "sliding_window_log"
+	// StrategySlidingWindowCounter counts the hits of the current fixed window,
+	// plus those of the previous one in proportion to its part still covered by the sliding window.
+	// It approximates the sliding window log with two counters per client.
+	StrategySlidingWindowCounter Strategy = "sliding_window_counter"
+	// StrategyGCRA is the generic cell rate algorithm, a token bucket of limit tokens refilled over the window.
+	// A burst of limit hits is allowed, then one hit every window/limit.
+	StrategyGCRA Strategy = "gcra"
+)
+
+// String converts the Strategy to its string representation.
+func (s Strategy) String() string {
+	return string(s)
+}
+
+// strategyScript is the Lua script of a strategy, and how its keys and arguments are built.
+// Every script returns {allowed (0 or 1), current, reset after in milliseconds}.
+// The last argument of every script is the dry run flag: when it is 1, an allowed hit is reported without being recorded.
+type strategyScript struct {
+	source string
+	args   func(key string, limit, windowMs, nowMs int64) ([]string, []interface{})
+}
+
+var strategyScripts = map[Strategy]strategyScript{
+	StrategyFixedWindow:          {source: fixedWindowScript, args: fixedWindowArgs},
+	StrategySlidingWindowLog:     {source: slidingWindowLogScript, args: slidingWindowLogArgs},
+	StrategySlidingWindowCounter: {source: slidingWindowCounterScript, args: slidingWindowCounterArgs},
+	StrategyGCRA:                 {source: gcraScript, args: gcraArgs},
+}
+
+// fixed window rate limiter lua script
+// KEYS[1]: counter of the current window
+// ARGV: limit, time left in the window (ms), dry run
+const fixedWindowScript = `
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local reset = tonumber(ARGV[2])
+
+local current = tonumber(redis.call('GET', key) or '0')
+if current >= limit then
+  return {0, limit, reset}
+end
+if ARGV[3] == '1' then
+  return {1, current + 1, reset}
+end
+
+current = redis.call('INCR', key)
+if current == 1 then
+  redis.call('PEXPIRE', key, reset)
+end
+return {1, current, reset}
+`
+
+func fixedWindowArgs(key string, limit, windowMs, nowMs int64) ([]string, []interface{}) {
+	start := nowMs - nowMs%windowMs
+	return []string{key + ":" + strconv.FormatInt(start, 10)}, []interface{}{limit, start + windowMs - nowMs}
+}
+
+// sliding window log rate limiter lua script
+// KEYS[1]: sorted set of the allowed hits, scored by time
+// ARGV: limit, window (ms), now (ms), unique member of the hit, dry run
+const slidingWindowLogScript = `
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+if count >= limit then
+  local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+  return {0, limit, tonumber(oldest[2]) + window - now}
+end
+if ARGV[5] == '1' then
+  return {1, count + 1, window}
+end
+
+redis.call('ZADD', key, now, ARGV[4])
+redis.call('PEXPIRE', key, window)
+return {1, count + 1, window}
+`
+
+func slidingWindowLogArgs(key string, limit, windowMs, nowMs int64) ([]string, []interface{}) {
+	return []string{key}, []interface{}{limit, windowMs, nowMs, strconv.FormatInt(nowMs, 10) + ":" + uuid.NewString()}
+}
+
+// sliding window counter rate limiter lua script
+// KEYS[1]: counter of the current window, KEYS[2]: counter of the previous window
+// ARGV: limit, window (ms), time elapsed in the current window (ms), dry run
+// The hits are weighted by the window length to keep the arithmetic exact.
+const slidingWindowCounterScript = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+
+local curr = tonumber(redis.call('GET', KEYS[1]) or '0')
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+
+local weighted = prev * (window - elapsed) + curr * window
+if weighted + window > limit * window then
+  local wait
+  if curr < limit then
+    -- A hit fits once the previous window weighs little enough.
+    wait = window - math.floor((limit - 1 - curr) * window / prev) - elapsed
+  else
+    -- A hit fits in the next window, once the current one weighs little enough.
+    wait = window - elapsed + window - math.floor((limit - 1) * window / curr)
+  end
+  return {0, limit, wait}
+end
+if ARGV[4] == '1' then
+  return {1, math.ceil((weighted + window) / window), 2 * window - elapsed}
+end
+
+curr = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], 2 * window - elapsed)
+return {1, math.ceil((prev * (window - elapsed) + curr * window) / window), 2 * window - elapsed}
+`
+
+func slidingWindowCounterArgs(key string, limit, windowMs, nowMs int64) ([]string, []interface{}) {
+	start := nowMs - nowMs%windowMs
+	keys := []string{key + ":" + strconv.FormatInt(start, 10), key + ":" + strconv.FormatInt(start-windowMs, 10)}
+	return keys, []interface{}{limit, windowMs, nowMs - start}
+}
+
+// GCRA rate limiter lua script
+// KEYS[1]: theoretical arrival time of the next hit (µs)
+// ARGV: limit, window (ms), now (ms), dry run
+// Times are in microseconds, for the emission interval to be precise enough.
+const gcraScript = `
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local now = tonumber(ARGV[3]) * 1000
+local interval = math.ceil(tonumber(ARGV[2]) * 1000 / limit)
+local tolerance = interval * limit
+
+local tat = math.max(tonumber(redis.call('GET', key) or now), now)
+local new_tat = tat + interval
+local allow_at = new_tat - tolerance
+if now < allow_at then
+  return {0, limit, math.ceil((allow_at - now) / 1000)}
+end
+if ARGV[4] == '1' then
+  return {1, math.ceil((new_tat - now) / interval), math.ceil((new_tat - now) / 1000)}
+end
+
+redis.call('SET', key, string.format('%d', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.ceil((new_tat - now) / interval), math.ceil((new_tat - now) / 1000)}
+`
+
+func gcraArgs(key string, limit, windowMs, nowMs int64) ([]string, []interface{}) {
+	return []string{key}, []interface{}{limit, windowMs, nowMs}
+}
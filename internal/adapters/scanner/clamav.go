@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-starter/config"
+	"go-starter/internal/domain/ports"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize is the size of the chunks the files are streamed to clamd in.
+const clamdChunkSize = 64 << 10
+
+// ClamAVAdapter implements the ports.FileScanner interface with a clamd daemon, over TCP or a unix socket.
+// Files are streamed with the INSTREAM command, clamd must accept streams as large as the largest uploaded file (StreamMaxLength).
+type ClamAVAdapter struct {
+	network    string
+	address    string
+	timeout    time.Duration
+	errTracker ports.ErrTrackerAdapter
+}
+
+// NewClamAVAdapter creates a new ClamAVAdapter instance.
+// The address of clamd is either tcp://host:port or unix:///path/to/clamd.sock.
+func NewClamAVAdapter(scannerCfg *config.FileScanner, errTracker ports.ErrTrackerAdapter) (*ClamAVAdapter, error) {
+	network, address, ok := strings.Cut(scannerCfg.Addr, "://")
+	if !ok || (network != "tcp" && network != "unix") || address == "" {
+		err := fmt.Errorf("invalid clamd address: %s", scannerCfg.Addr)
+		errTracker.CaptureException(context.Background(), err)
+		return nil, err
+	}
+
+	return &ClamAVAdapter{
+		network:    network,
+		address:    address,
+		timeout:    scannerCfg.Timeout,
+		errTracker: errTracker,
+	}, nil
+}
+
+// Scan streams a file to clamd and returns its verdict.
+// Returns an error if clamd cannot be reached, times out or fails to scan the file.
+func (a *ClamAVAdapter) Scan(ctx context.Context, body io.Reader) (*ports.ScanResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	result, err := a.scan(ctx, body)
+	if err != nil {
+		err = fmt.Errorf("failed to scan file with clamd: %w", err)
+		a.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (a *ClamAVAdapter) scan(ctx context.Context, body io.Reader) (*ports.ScanResult, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, a.network, a.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, err
+	}
+
+	// Each chunk is prefixed with its length, a zero length chunk ends the stream.
+	chunk := make([]byte, 4+clamdChunkSize)
+	for {
+		n, readErr := io.ReadFull(body, chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if _, err := conn.Write(chunk[:4+n]); err != nil {
+				return nil, err
+			}
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil {
+		return nil, err
+	}
+	return parseClamdReply(string(bytes.TrimRight(reply, "\x00")))
+}
+
+// parseClamdReply parses the reply to an INSTREAM command:
+// "stream: OK", "stream: <signature> FOUND" or "<message> ERROR".
+func parseClamdReply(reply string) (*ports.ScanResult, error) {
+	reply = strings.TrimSpace(reply)
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(reply, " FOUND")
+		signature = strings.TrimPrefix(signature, "stream: ")
+		return &ports.ScanResult{Infected: true, Signature: signature}, nil
+	case strings.HasSuffix(reply, ": OK"):
+		return &ports.ScanResult{}, nil
+	default:
+		return nil, fmt.Errorf("unexpected clamd reply: %s", reply)
+	}
+}
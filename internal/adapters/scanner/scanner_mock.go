@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"go-starter/internal/domain/ports"
+	"io"
+	"sync"
+)
+
+// EICARTestFile is the EICAR anti-malware test file, detected as infected by the scanners.
+const EICARTestFile = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// EICARSignature is the signature reported for the EICAR test file.
+const EICARSignature = "Eicar-Test-Signature"
+
+// FileScannerMock is a mock implementation of the ports.FileScanner interface.
+// Files containing the EICAR test file are reported infected.
+type FileScannerMock struct {
+	err   error
+	scans int
+	mu    sync.RWMutex
+}
+
+// NewFileScannerMock creates a new FileScannerMock instance.
+func NewFileScannerMock() *FileScannerMock {
+	return &FileScannerMock{
+		mu: sync.RWMutex{},
+	}
+}
+
+// Scan reads a file and reports it infected if it contains the EICAR test file.
+// Returns the error set with SetError, if any.
+func (m *FileScannerMock) Scan(_ context.Context, body io.Reader) (*ports.ScanResult, error) {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scans++
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if bytes.Contains(content, []byte(EICARTestFile)) {
+		return &ports.ScanResult{Infected: true, Signature: EICARSignature}, nil
+	}
+	return &ports.ScanResult{}, nil
+}
+
+// SetError makes the next scans fail with err, or succeed again if err is nil.
+func (m *FileScannerMock) SetError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// Scans returns the number of files scanned so far.
+func (m *FileScannerMock) Scans() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.scans
+}
@@ -8,20 +8,36 @@ import (
 // DomainHttpErrMap maps domain-specific error types to their corresponding HTTP status codes.
 var DomainHttpErrMap = map[error]int{
 	// Generic errors
-	domain.ErrInternal:     http.StatusInternalServerError,
-	domain.ErrForbidden:    http.StatusForbidden,
-	domain.ErrUnauthorized: http.StatusUnauthorized,
-	domain.ErrBadRequest:   http.StatusBadRequest,
+	domain.ErrInternal:           http.StatusInternalServerError,
+	domain.ErrForbidden:          http.StatusForbidden,
+	domain.ErrUnauthorized:       http.StatusUnauthorized,
+	domain.ErrBadRequest:         http.StatusBadRequest,
+	domain.ErrInvalidJSON:        http.StatusBadRequest,
+	domain.ErrServiceUnavailable: http.StatusServiceUnavailable,
 
 	// Auth errors
 	domain.ErrInvalidToken:       http.StatusUnauthorized,
 	domain.ErrInvalidCredentials: http.StatusUnauthorized,
 
 	// File upload errors
-	domain.ErrFileTooLarge:         http.StatusRequestEntityTooLarge,
-	domain.ErrMissingBoundary:      http.StatusBadRequest,
-	domain.ErrInvalidMultipartForm: http.StatusBadRequest,
-	domain.ErrInvalidFileType:      http.StatusBadRequest,
+	domain.ErrFileTooLarge:          http.StatusRequestEntityTooLarge,
+	domain.ErrMissingBoundary:       http.StatusBadRequest,
+	domain.ErrInvalidMultipartForm:  http.StatusBadRequest,
+	domain.ErrInvalidFileType:       http.StatusBadRequest,
+	domain.ErrFileNotFound:          http.StatusNotFound,
+	domain.ErrInvalidFileSignature:  http.StatusForbidden,
+	domain.ErrInvalidImage:          http.StatusBadRequest,
+	domain.ErrImageTooLarge:         http.StatusRequestEntityTooLarge,
+	domain.ErrInvalidUploadID:       http.StatusBadRequest,
+	domain.ErrUploadNotFound:        http.StatusNotFound,
+	domain.ErrUploadNotCompleted:    http.StatusConflict,
+	domain.ErrUploadMismatch:        http.StatusUnprocessableEntity,
+	domain.ErrInvalidFileID:         http.StatusBadRequest,
+	domain.ErrInvalidFileVisibility: http.StatusBadRequest,
+	domain.ErrStorageQuotaExceeded:  http.StatusRequestEntityTooLarge,
+	domain.ErrFileInfected:          http.StatusUnprocessableEntity,
+	domain.ErrFileScanFailed:        http.StatusServiceUnavailable,
+	domain.ErrFileQuarantined:       http.StatusConflict,
 
 	// User errors
 	domain.ErrInvalidUserId:        http.StatusBadRequest,
@@ -30,6 +46,17 @@ var DomainHttpErrMap = map[error]int{
 	domain.ErrEmailConflict:        http.StatusConflict,
 	domain.ErrEmailAlreadyVerified: http.StatusConflict,
 
+	// Mailer errors
+	domain.ErrEmailSuppressed:            http.StatusUnprocessableEntity,
+	domain.ErrEmailSuppressionNotFound:   http.StatusNotFound,
+	domain.ErrInvalidWebhookSignature:    http.StatusForbidden,
+	domain.ErrInvalidEmailDeliveryID:     http.StatusBadRequest,
+	domain.ErrInvalidEmailDeliveryStatus: http.StatusBadRequest,
+	domain.ErrEmailDeliveryNotFound:      http.StatusNotFound,
+	domain.ErrEmailDeliveryNotResendable: http.StatusConflict,
+	domain.ErrEmailDeliveryRedacted:      http.StatusConflict,
+	domain.ErrEmailThrottled:             http.StatusTooManyRequests,
+
 	// Validation errors
 
 	// Users
@@ -43,4 +70,10 @@ var DomainHttpErrMap = map[error]int{
 	domain.ErrPasswordsNotMatch:            http.StatusUnprocessableEntity,
 	domain.ErrPasswordTooShort:             http.StatusUnprocessableEntity,
 	domain.ErrPasswordConfirmationRequired: http.StatusUnprocessableEntity,
+	domain.ErrLocaleRequired:               http.StatusUnprocessableEntity,
+	domain.ErrContentTypeRequired:          http.StatusUnprocessableEntity,
+	domain.ErrFileSizeRequired:             http.StatusUnprocessableEntity,
+	domain.ErrLocaleInvalid:                http.StatusUnprocessableEntity,
+	domain.ErrLogLevelRequired:             http.StatusUnprocessableEntity,
+	domain.ErrLogLevelInvalid:              http.StatusUnprocessableEntity,
 }
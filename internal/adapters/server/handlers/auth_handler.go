@@ -49,14 +49,14 @@ func (ah *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	var payload loginRequest
 	if err := validator.ValidateRequest(w, r, &payload); err != nil {
-		responses.HandleValidationError(w, err)
+		responses.HandleValidationError(w, r, err)
 		return
 	}
 
 	payload.Username = strings.TrimSpace(payload.Username)
 	user, accessToken, err := ah.svc.Login(ctx, payload.Username, payload.Password)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -75,10 +75,11 @@ type registerRequest struct {
 // Register godoc
 //
 //	@Summary		Register a new user
-//	@Description	Create a new user account
+//	@Description	Create a new user account, the negotiated Accept-Language becomes the user's preferred locale
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
+//	@Param			Accept-Language	header	string	false	"Preferred languages"
 //	@Param			registerRequest	body registerRequest true "Register request"
 //	@Success		201	{object}	responses.Response[responses.LoginResponse]	"Created user"
 //	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
@@ -91,7 +92,7 @@ func (ah *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var payload registerRequest
 
 	if err := validator.ValidateRequest(w, r, &payload); err != nil {
-		responses.HandleValidationError(w, err)
+		responses.HandleValidationError(w, r, err)
 		return
 	}
 
@@ -103,17 +104,18 @@ func (ah *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Username: payload.Username,
 		Password: payload.Password,
 		Email:    payload.Email,
+		Locale:   helpers.GetLocaleFromContext(ctx),
 	}
 
 	created, err := ah.svc.Register(ctx, user)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
 	authenticatedUser, authTokens, err := ah.svc.Login(ctx, created.Username, payload.Password)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -138,13 +140,13 @@ func (ah *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 
 	accessToken, err := helpers.ExtractTokenFromHeader(r)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
 	err = ah.svc.Logout(ctx, accessToken)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -168,13 +170,13 @@ func (ah *AuthHandler) SendPasswordResetEmail(w http.ResponseWriter, r *http.Req
 
 	email := r.URL.Query().Get("email")
 	if email == "" {
-		responses.HandleError(w, domain.ErrBadRequest)
+		responses.HandleError(w, r, domain.ErrBadRequest)
 		return
 	}
 
 	err := ah.svc.SendPasswordResetEmail(ctx, email)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -199,13 +201,13 @@ func (ah *AuthHandler) VerifyPasswordResetToken(w http.ResponseWriter, r *http.R
 
 	token := r.PathValue("token")
 	if token == "" {
-		responses.HandleError(w, domain.ErrBadRequest)
+		responses.HandleError(w, r, domain.ErrBadRequest)
 		return
 	}
 
 	err := ah.svc.VerifyPasswordResetToken(ctx, token)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -238,19 +240,19 @@ func (ah *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 
 	token := r.PathValue("token")
 	if token == "" {
-		responses.HandleError(w, domain.ErrBadRequest)
+		responses.HandleError(w, r, domain.ErrBadRequest)
 		return
 	}
 
 	var payload resetPasswordRequest
 	if err := validator.ValidateRequest(w, r, &payload); err != nil {
-		responses.HandleValidationError(w, err)
+		responses.HandleValidationError(w, r, err)
 		return
 	}
 
 	err := ah.svc.ResetPassword(ctx, token, payload.Password, payload.PasswordConfirmation)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
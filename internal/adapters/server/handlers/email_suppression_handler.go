@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"go-starter/internal/adapters/server/responses"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// maxWebhookBodySize is the maximum size of a delivery notification body (SNS messages are at most 256 KB).
+const maxWebhookBodySize = 256 << 10
+
+// EmailSuppressionHandler represents the HTTP handler for email delivery notifications and the suppression list.
+type EmailSuppressionHandler struct {
+	svc ports.EmailSuppressionService
+}
+
+// NewEmailSuppressionHandler creates and returns a new EmailSuppressionHandler instance.
+func NewEmailSuppressionHandler(svc ports.EmailSuppressionService) *EmailSuppressionHandler {
+	return &EmailSuppressionHandler{
+		svc: svc,
+	}
+}
+
+// HandleSESNotification godoc
+//
+//	@Summary		Receive SES delivery notifications
+//	@Description	Endpoint subscribed to the SNS topic receiving SES bounce and complaint notifications
+//	@Tags			Mail
+//	@Accept			plain
+//	@Produce		json
+//	@Success		200	{object}	responses.EmptyResponse	"Success"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		403	{object}	responses.ErrorResponse	"Invalid signature"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/webhooks/ses [post]
+func (eh *EmailSuppressionHandler) HandleSESNotification(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		responses.HandleError(w, r, domain.ErrBadRequest)
+		return
+	}
+
+	err = eh.svc.HandleNotification(r.Context(), payload)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	responses.HandleSuccess(w, http.StatusOK, nil)
+}
+
+// List godoc
+//
+//	@Summary		List suppressed email addresses
+//	@Description	List the email addresses that hard bounced or complained, most recent first
+//	@Tags			Mail
+//	@Produce		json
+//	@Param			limit	query		int		false	"Maximum number of results (default 20, max 100)"
+//	@Param			offset	query		int		false	"Number of results to skip"
+//	@Success		200	{object}	responses.Response[[]responses.EmailSuppressionResponse]	"Suppressions displayed"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	responses.ErrorResponse	"Forbidden error"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/admin/email-suppressions [get]
+//	@Security		BearerAuth
+func (eh *EmailSuppressionHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseOptionalIntQuery(r, "limit")
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	offset, err := parseOptionalIntQuery(r, "offset")
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	suppressions, err := eh.svc.List(r.Context(), limit, offset)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	response := responses.NewEmailSuppressionsResponse(suppressions)
+	responses.HandleSuccess(w, http.StatusOK, response)
+}
+
+// Delete godoc
+//
+//	@Summary		Remove a suppressed email address
+//	@Description	Remove an email address from the suppression list so it can receive emails again
+//	@Tags			Mail
+//	@Produce		json
+//	@Param			email	path		string		true	"Email address"
+//	@Success		200	{object}	responses.EmptyResponse	"Success"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	responses.ErrorResponse	"Forbidden error"
+//	@Failure		404	{object}	responses.ErrorResponse	"Data not found error"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/admin/email-suppressions/{email} [delete]
+//	@Security		BearerAuth
+func (eh *EmailSuppressionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	err := eh.svc.Delete(r.Context(), r.PathValue("email"))
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	responses.HandleSuccess(w, http.StatusOK, nil)
+}
+
+// parseOptionalIntQuery parses an optional integer query parameter, returning 0 when it is absent.
+// Returns domain.ErrBadRequest if the value is not an integer.
+func parseOptionalIntQuery(r *http.Request, key string) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, domain.ErrBadRequest
+	}
+	return n, nil
+}
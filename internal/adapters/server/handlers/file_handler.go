@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"go-starter/config"
+	"go-starter/internal/adapters/server/helpers"
+	"go-starter/internal/adapters/server/responses"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+	"net/http"
+)
+
+// multipartOverhead is the size allowed for the form fields and part headers of a multipart upload, besides the file.
+const multipartOverhead = 1 << 20
+
+// FileHandler represents the HTTP handler for the files of the users, and for the files stored by the application.
+type FileHandler struct {
+	cfg        *config.FileUpload
+	svc        ports.FileUploadService
+	fileSvc    ports.FileService
+	errTracker ports.ErrTrackerAdapter
+}
+
+// NewFileHandler creates and returns a new FileHandler instance.
+func NewFileHandler(cfg *config.FileUpload, svc ports.FileUploadService, fileSvc ports.FileService, errTracker ports.ErrTrackerAdapter) *FileHandler {
+	return &FileHandler{
+		cfg:        cfg,
+		svc:        svc,
+		fileSvc:    fileSvc,
+		errTracker: errTracker,
+	}
+}
+
+// ServeFile godoc
+//
+//	@Summary		Download a stored file
+//	@Description	Serve a file stored on the local disk from its signed URL, only with the local storage driver
+//	@Tags			Files
+//	@Produce		octet-stream
+//	@Param			key			path		string	true	"File key"
+//	@Param			signature	query		string	true	"URL signature"
+//	@Param			expires		query		int		false	"URL expiration (unix timestamp)"
+//	@Success		200	{file}		binary	"File content"
+//	@Failure		403	{object}	responses.ErrorResponse	"Invalid or expired signature"
+//	@Failure		404	{object}	responses.ErrorResponse	"Data not found error"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/files/{key} [get]
+func (fh *FileHandler) ServeFile(w http.ResponseWriter, r *http.Request) {
+	file, err := fh.svc.OpenFile(r.Context(), r.PathValue("key"), r.URL.Query())
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+	defer file.Content.Close()
+
+	// Uploaded files are never rendered as active content.
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	http.ServeContent(w, r, file.Name, file.ModTime, file.Content)
+}
+
+// UploadFile godoc
+//
+//	@Summary		Upload a file to a presigned URL
+//	@Description	Store a file uploaded directly to the local disk from a presigned URL, only with the local storage driver
+//	@Tags			Files
+//	@Accept			octet-stream
+//	@Produce		json
+//	@Param			key			path		string	true	"File key"
+//	@Param			signature	query		string	true	"URL signature"
+//	@Param			expires		query		int		true	"URL expiration (unix timestamp)"
+//	@Param			size		query		int		true	"Maximum file size"
+//	@Success		200	{object}	responses.EmptyResponse	"Success"
+//	@Failure		403	{object}	responses.ErrorResponse	"Invalid or expired signature"
+//	@Failure		404	{object}	responses.ErrorResponse	"Data not found error"
+//	@Failure		413	{object}	responses.ErrorResponse	"File too large"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/files/{key} [put]
+func (fh *FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
+	err := fh.svc.StoreFile(r.Context(), r.PathValue("key"), r.URL.Query(), r.Header.Get("Content-Type"), r.Body)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	responses.HandleSuccess(w, http.StatusOK, nil)
+}
+
+// Upload godoc
+//
+//	@Summary		Upload a file
+//	@Description	Store a file for the current user, within its storage quota. Private files are downloaded from signed expiring URLs.
+//	@Tags			Files
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file		formData	file	true	"File"
+//	@Param			visibility	formData	string	false	"File visibility (default private)"	Enums(public, private)
+//	@Success		201	{object}	responses.Response[responses.FileResponse]	"Success"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		413	{object}	responses.ErrorResponse	"File too large or storage quota exceeded"
+//	@Failure		422	{object}	responses.ErrorResponse	"File rejected by the malware scan"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Failure		503	{object}	responses.ErrorResponse	"File could not be scanned"
+//	@Router			/v1/users/me/files [post]
+//	@Security		BearerAuth
+func (fh *FileHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	// The body is bounded before being parsed, a larger file is rejected without being stored on disk.
+	r.Body = http.MaxBytesReader(w, r.Body, fh.cfg.MaxFileSize+multipartOverhead)
+	parser := helpers.NewMultipartFormParser(5<<20, nil)
+	if err := parser.Parse(r); err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	// The quota is enforced by the service.
+	file, header, err := parser.GetFile(r, "file", fh.cfg.MaxFileSize)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	defer func() {
+		if err := (*file).Close(); err != nil {
+			fh.errTracker.CaptureException(r.Context(), err)
+		}
+	}()
+
+	visibility := entities.FileVisibilityPrivate
+	if value := r.FormValue("visibility"); value != "" {
+		visibility, err = entities.ParseFileVisibility(value)
+		if err != nil {
+			responses.HandleError(w, r, err)
+			return
+		}
+	}
+
+	ctx := r.Context()
+
+	userID, err := helpers.GetUserIDFromContext(ctx)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	stored, err := fh.fileSvc.Upload(ctx, userID, header.Filename, visibility, *file)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	response := responses.NewFileResponse(stored)
+	responses.HandleSuccess(w, http.StatusCreated, response)
+}
+
+// List godoc
+//
+//	@Summary		List files
+//	@Description	List the files of the current user, most recent first, with its storage usage
+//	@Tags			Files
+//	@Produce		json
+//	@Param			limit	query		int		false	"Maximum number of results (default 20, max 100)"
+//	@Param			offset	query		int		false	"Number of results to skip"
+//	@Success		200	{object}	responses.Response[responses.FilesResponse]	"Success"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/users/me/files [get]
+//	@Security		BearerAuth
+func (fh *FileHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := parseOptionalIntQuery(r, "limit")
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	offset, err := parseOptionalIntQuery(r, "offset")
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	userID, err := helpers.GetUserIDFromContext(ctx)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	files, err := fh.fileSvc.List(ctx, userID, limit, offset)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	usage, err := fh.fileSvc.Usage(ctx, userID)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	response := responses.NewFilesResponse(files, usage)
+	responses.HandleSuccess(w, http.StatusOK, response)
+}
+
+// Download godoc
+//
+//	@Summary		Download a file
+//	@Description	Return a file of the current user and the URL it is downloaded from, signed and expiring for a private file
+//	@Tags			Files
+//	@Produce		json
+//	@Param			id	path		string		true	"File ID" format(uuid)
+//	@Success		200	{object}	responses.Response[responses.FileDownloadResponse]	"Success"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		404	{object}	responses.ErrorResponse	"Data not found error"
+//	@Failure		409	{object}	responses.ErrorResponse	"File being scanned"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/users/me/files/{id} [get]
+//	@Security		BearerAuth
+func (fh *FileHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	fileID, err := entities.ParseFileID(r.PathValue("id"))
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	userID, err := helpers.GetUserIDFromContext(ctx)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	file, link, err := fh.fileSvc.Download(ctx, userID, fileID)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	response := responses.NewFileDownloadResponse(file, link)
+	responses.HandleSuccess(w, http.StatusOK, response)
+}
+
+// Delete godoc
+//
+//	@Summary		Delete a file
+//	@Description	Delete a file of the current user
+//	@Tags			Files
+//	@Produce		json
+//	@Param			id	path		string		true	"File ID" format(uuid)
+//	@Success		200	{object}	responses.EmptyResponse	"Success"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		404	{object}	responses.ErrorResponse	"Data not found error"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/users/me/files/{id} [delete]
+//	@Security		BearerAuth
+func (fh *FileHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	fileID, err := entities.ParseFileID(r.PathValue("id"))
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	userID, err := helpers.GetUserIDFromContext(ctx)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	err = fh.fileSvc.Delete(ctx, userID, fileID)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	responses.HandleSuccess(w, http.StatusOK, nil)
+}
@@ -1,24 +1,31 @@
 package handlers
 
 import (
+	"go-starter/config"
 	"go-starter/internal/domain/ports"
 	"go-starter/internal/domain/services"
 )
 
 // Handlers holds all handler implementations for the application.
 type Handlers struct {
-	HealthHandler *HealthHandler
-	AuthHandler   *AuthHandler
-	UserHandler   *UserHandler
-	MailerHandler *MailerHandler
+	HealthHandler           *HealthHandler
+	AuthHandler             *AuthHandler
+	UserHandler             *UserHandler
+	MailerHandler           *MailerHandler
+	EmailSuppressionHandler *EmailSuppressionHandler
+	FileHandler             *FileHandler
+	LogHandler              *LogHandler
 }
 
 // New creates and initializes a new Handlers instance with the provided dependencies.
-func New(s *services.Services, errTracker ports.ErrTrackerAdapter) *Handlers {
+func New(cfg *config.Container, s *services.Services, errTracker ports.ErrTrackerAdapter, logLevel ports.LogLevelController) *Handlers {
 	return &Handlers{
-		HealthHandler: NewHealthHandler(),
-		AuthHandler:   NewAuthHandler(s.AuthService),
-		UserHandler:   NewUserHandler(s.UserService, errTracker),
-		MailerHandler: NewMailerHandler(s.MailerService),
+		HealthHandler:           NewHealthHandler(s.HealthChecker, s.CacheService),
+		AuthHandler:             NewAuthHandler(s.AuthService),
+		UserHandler:             NewUserHandler(s.UserService, errTracker),
+		MailerHandler:           NewMailerHandler(s.MailerService),
+		EmailSuppressionHandler: NewEmailSuppressionHandler(s.EmailSuppressionService),
+		FileHandler:             NewFileHandler(cfg.FileUpload, s.FileUploadService, s.FileService, errTracker),
+		LogHandler:              NewLogHandler(logLevel),
 	}
 }
@@ -1,42 +1,126 @@
 package handlers
 
 import (
-	"encoding/json"
+	"fmt"
 	"go-starter/internal/adapters/server/responses"
-	"go-starter/internal/adapters/storage/database"
-	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
 	"net/http"
+	"strings"
 )
 
-// HealthHandler is responsible for handling HTTP requests related to the health status of the database.
-type HealthHandler struct{}
+// HealthHandler is responsible for handling HTTP requests related to the health status of the application and its dependencies.
+type HealthHandler struct {
+	healthChecker ports.HealthChecker
+	cacheSvc      ports.CacheService
+}
 
 // NewHealthHandler initializes and returns a new instance of HealthHandler.
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(healthChecker ports.HealthChecker, cacheSvc ports.CacheService) *HealthHandler {
+	return &HealthHandler{
+		healthChecker: healthChecker,
+		cacheSvc:      cacheSvc,
+	}
 }
 
-// PostgresHealth godoc
+// Livez godoc
 //
-//	@Summary		Get database health information
-//	@Description	Get database health information
+//	@Summary		Get liveness information
+//	@Description	Report that the server is running, in the format of the Kubernetes probes. The dependencies are not checked, a failing one must not get the server restarted. Add the verbose query parameter to list the checks.
+//	@Tags			Health
+//	@Produce		plain
+//	@Param			verbose	query		string	false	"List the checks"
+//	@Success		200		{string}	string	"ok"
+//	@Router			/livez [get]
+func (hh *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	writeProbe(w, r, "livez", []ports.HealthCheckResult{{Name: "ping", Status: ports.HealthCheckStatusUp}}, true)
+}
+
+// Readyz godoc
+//
+//	@Summary		Get readiness information
+//	@Description	Check the dependencies, in the format of the Kubernetes probes. The server stays ready, though degraded, while only non-critical dependencies are down. Add the verbose query parameter to list the checks, their failures being withheld.
+//	@Tags			Health
+//	@Produce		plain
+//	@Param			verbose	query		string	false	"List the checks"
+//	@Success		200		{string}	string	"ok"
+//	@Failure		503		{string}	string	"readyz check failed"
+//	@Router			/readyz [get]
+func (hh *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := hh.healthChecker.Check(r.Context())
+	writeProbe(w, r, "readyz", report.Checks, report.Ready())
+}
+
+// Health godoc
+//
+//	@Summary		Get detailed health information
+//	@Description	Get the status, duration and failure of the check of each dependency. The report is cached for a few seconds.
+//	@Tags			Health
+//	@Produce		json
+//	@Success		200	{object}	responses.Response[responses.HealthReportResponse]	"Service ready or degraded"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	responses.ErrorResponse	"Forbidden error"
+//	@Failure		503	{object}	responses.Response[responses.HealthReportResponse]	"Service unavailable"
+//	@Router			/v1/admin/health [get]
+//	@Security		BearerAuth
+func (hh *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
+	report := hh.healthChecker.Check(r.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	responses.HandleSuccess(w, status, responses.NewHealthReportResponse(report))
+}
+
+// CacheStats godoc
+//
+//	@Summary		Get cache statistics
+//	@Description	Get the hits, misses and evictions of each layer of the cache since the application started. Redis alone keeps no statistics.
 //	@Tags			Health
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	responses.HealthResponse	"Postgres health information"
-//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
-//	@Router			/v1/health/postgres [get]
-func (hh *HealthHandler) PostgresHealth(w http.ResponseWriter, _ *http.Request) {
-	resp, err := json.Marshal(database.Health())
-	if err != nil {
-		responses.HandleError(w, domain.ErrInternal)
-		return
+//	@Success		200	{object}	responses.Response[[]responses.CacheStatsResponse]	"Cache statistics"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	responses.ErrorResponse	"Forbidden error"
+//	@Router			/v1/admin/health/cache [get]
+//	@Security		BearerAuth
+func (hh *HealthHandler) CacheStats(w http.ResponseWriter, r *http.Request) {
+	responses.HandleSuccess(w, http.StatusOK, responses.NewCacheStatsResponse(hh.cacheSvc.Stats()))
+}
+
+// writeProbe writes the result of a probe in the plain text format of the Kubernetes health endpoints:
+// "ok" when it passes, or one "[+]name ok" or "[-]name failed" line per check followed by the result of the probe
+// when it fails or the verbose query parameter is set. The reasons of the failures are withheld, they are only
+// given to the admins.
+func writeProbe(w http.ResponseWriter, r *http.Request, probe string, checks []ports.HealthCheckResult, passed bool) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	status := http.StatusOK
+	if !passed {
+		status = http.StatusServiceUnavailable
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
 
-	if _, err := w.Write(resp); err != nil {
-		responses.HandleError(w, domain.ErrInternal)
+	if passed && !r.URL.Query().Has("verbose") {
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, "ok")
 		return
 	}
+
+	var b strings.Builder
+	for _, check := range checks {
+		if check.Status == ports.HealthCheckStatusUp {
+			fmt.Fprintf(&b, "[+]%s ok\n", check.Name)
+		} else {
+			fmt.Fprintf(&b, "[-]%s failed: reason withheld\n", check.Name)
+		}
+	}
+	if passed {
+		fmt.Fprintf(&b, "%s check passed\n", probe)
+	} else {
+		fmt.Fprintf(&b, "%s check failed\n", probe)
+	}
+
+	w.WriteHeader(status)
+	_, _ = fmt.Fprint(w, b.String())
 }
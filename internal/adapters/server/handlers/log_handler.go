@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"go-starter/internal/adapters/server/responses"
+	"go-starter/internal/adapters/validator"
+	"go-starter/internal/domain/ports"
+	"log/slog"
+	"net/http"
+)
+
+// LogHandler represents the HTTP handler for the settings of the logs.
+type LogHandler struct {
+	levelController ports.LogLevelController
+}
+
+// NewLogHandler creates and returns a new LogHandler instance.
+func NewLogHandler(levelController ports.LogLevelController) *LogHandler {
+	return &LogHandler{
+		levelController: levelController,
+	}
+}
+
+// GetLevel godoc
+//
+//	@Summary		Get the log level
+//	@Description	Get the minimum level of the logs
+//	@Tags			Logs
+//	@Produce		json
+//	@Success		200	{object}	responses.Response[responses.LogLevelResponse]	"Log level displayed"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	responses.ErrorResponse	"Forbidden error"
+//	@Router			/v1/admin/log-level [get]
+//	@Security		BearerAuth
+func (lh *LogHandler) GetLevel(w http.ResponseWriter, r *http.Request) {
+	responses.HandleSuccess(w, http.StatusOK, responses.LogLevelResponse{Level: lh.levelController.Level()})
+}
+
+// updateLogLevelRequest represents the structure of the request body used for changing the log level.
+type updateLogLevelRequest struct {
+	Level string `json:"level" validate:"required" example:"debug"`
+}
+
+// UpdateLevel godoc
+//
+//	@Summary		Update the log level
+//	@Description	Change the minimum level of the logs until the application restarts
+//	@Tags			Logs
+//	@Accept			json
+//	@Produce		json
+//	@Param			updateLogLevelRequest	body updateLogLevelRequest true "Update log level request"
+//	@Success		200	{object}	responses.Response[responses.LogLevelResponse]	"Log level updated"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	responses.ErrorResponse	"Forbidden error"
+//	@Failure		422	{object}	responses.ErrorResponse	"Validation error"
+//	@Router			/v1/admin/log-level [put]
+//	@Security		BearerAuth
+func (lh *LogHandler) UpdateLevel(w http.ResponseWriter, r *http.Request) {
+	var payload updateLogLevelRequest
+	if err := validator.ValidateRequest(w, r, &payload); err != nil {
+		responses.HandleValidationError(w, r, err)
+		return
+	}
+
+	previous := lh.levelController.Level()
+	if err := lh.levelController.SetLevel(payload.Level); err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+	slog.WarnContext(r.Context(), "log level changed", "from", previous, "to", lh.levelController.Level())
+
+	responses.HandleSuccess(w, http.StatusOK, responses.LogLevelResponse{Level: lh.levelController.Level()})
+}
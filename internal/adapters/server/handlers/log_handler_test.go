@@ -0,0 +1,65 @@
+//go:build !integration
+
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-starter/config"
+	"go-starter/internal/adapters/logger"
+	"go-starter/internal/adapters/server/handlers"
+	"go-starter/internal/adapters/server/responses"
+	"go-starter/internal/domain/i18n"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogHandler_UpdateLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		body           string
+		expectedStatus int
+		expectedKey    string
+		expectedLevel  string
+	}{
+		"valid level should be set":      {body: `{"level":"warn"}`, expectedStatus: http.StatusOK, expectedLevel: config.LogLevelWarn},
+		"missing level should fail":      {body: `{}`, expectedStatus: http.StatusUnprocessableEntity, expectedKey: i18n.ErrKeyLogLevelRequired, expectedLevel: config.LogLevelInfo},
+		"unknown level should fail":      {body: `{"level":"verbose"}`, expectedStatus: http.StatusUnprocessableEntity, expectedKey: i18n.ErrKeyLogLevelInvalid, expectedLevel: config.LogLevelInfo},
+		"invalid JSON should be refused": {body: `{`, expectedStatus: http.StatusBadRequest, expectedLevel: config.LogLevelInfo},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var logs bytes.Buffer
+			_, levelController := logger.NewHandler(&logs, &config.Log{Level: config.LogLevelInfo, Format: config.LogFormatJSON})
+			handler := handlers.NewLogHandler(levelController)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			handler.UpdateLevel(recorder, httptest.NewRequest(http.MethodPut, "/v1/admin/log-level", strings.NewReader(tt.body)))
+
+			// Assert
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, recorder.Code)
+			}
+			if got := levelController.Level(); got != tt.expectedLevel {
+				t.Errorf("expected level %s, got %s", tt.expectedLevel, got)
+			}
+			if tt.expectedKey != "" {
+				var body responses.ErrorResponse
+				if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode error response: %v", err)
+				}
+				if len(body.Errors) != 1 || body.Errors[0].Key != tt.expectedKey {
+					t.Errorf("expected error key %s, got %v", tt.expectedKey, body.Errors)
+				}
+			}
+		})
+	}
+}
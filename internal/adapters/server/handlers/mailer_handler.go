@@ -2,12 +2,12 @@ package handlers
 
 import (
 	"go-starter/internal/adapters/server/responses"
-	"go-starter/internal/domain/mailtemplates"
+	"go-starter/internal/domain/entities"
 	"go-starter/internal/domain/ports"
 	"net/http"
 )
 
-// MailerHandler is responsible for sending a test email.
+// MailerHandler represents the HTTP handler for the email delivery log.
 type MailerHandler struct {
 	mailerSvc ports.MailerService
 }
@@ -19,29 +19,93 @@ func NewMailerHandler(mailerSvc ports.MailerService) *MailerHandler {
 	}
 }
 
-// SendEmail godoc
+// ListEmails godoc
 //
-//	@Summary		Send an example email
-//	@Description	Send an example email
+//	@Summary		List sent emails
+//	@Description	List the email delivery log, most recent first
 //	@Tags			Mail
-//	@Accept			json
 //	@Produce		json
-//	@Success		200		"Success"
+//	@Param			status		query		string	false	"Delivery status"	Enums(pending, sent, failed, suppressed, throttled)
+//	@Param			template	query		string	false	"Template name"	example(verify_email)
+//	@Param			recipient	query		string	false	"Recipient email address"
+//	@Param			limit		query		int		false	"Maximum number of results (default 20, max 100)"
+//	@Param			offset		query		int		false	"Number of results to skip"
+//	@Success		200	{object}	responses.Response[[]responses.EmailDeliveryResponse]	"Emails displayed"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
 //	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
 //	@Failure		403	{object}	responses.ErrorResponse	"Forbidden error"
 //	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
-//	@Router			/v1/mailer [get]
+//	@Router			/v1/admin/emails [get]
 //	@Security		BearerAuth
-func (mh *MailerHandler) SendEmail(w http.ResponseWriter, _ *http.Request) {
-	err := mh.mailerSvc.Send(&ports.EmailMessage{
-		To:      []string{"example@example.com"},
-		Subject: "Subject",
-		Body:    mailtemplates.Hello("John Doe"),
-	})
+func (mh *MailerHandler) ListEmails(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := entities.EmailDeliveryFilter{
+		Template:  query.Get("template"),
+		Recipient: query.Get("recipient"),
+	}
+
+	if status := query.Get("status"); status != "" {
+		parsedStatus, err := entities.ParseEmailDeliveryStatus(status)
+		if err != nil {
+			responses.HandleError(w, r, err)
+			return
+		}
+		filter.Status = parsedStatus
+	}
+
+	var err error
+	filter.Limit, err = parseOptionalIntQuery(r, "limit")
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	filter.Offset, err = parseOptionalIntQuery(r, "offset")
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	deliveries, err := mh.mailerSvc.ListDeliveries(r.Context(), filter)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	response := responses.NewEmailDeliveriesResponse(deliveries)
+	responses.HandleSuccess(w, http.StatusOK, response)
+}
+
+// ResendEmail godoc
+//
+//	@Summary		Resend a failed email
+//	@Description	Send again an email whose delivery failed. The emails with one-time tokens cannot be resent, they are not kept.
+//	@Tags			Mail
+//	@Produce		json
+//	@Param			id	path		int		true	"Email delivery ID"
+//	@Success		200	{object}	responses.Response[responses.EmailDeliveryResponse]	"Email resent"
+//	@Failure		400	{object}	responses.ErrorResponse	"Incorrect email delivery ID"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	responses.ErrorResponse	"Forbidden error"
+//	@Failure		404	{object}	responses.ErrorResponse	"Data not found error"
+//	@Failure		409	{object}	responses.ErrorResponse	"Email did not fail or holds one-time tokens"
+//	@Failure		422	{object}	responses.ErrorResponse	"Recipients suppressed"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/admin/emails/{id}/resend [post]
+//	@Security		BearerAuth
+func (mh *MailerHandler) ResendEmail(w http.ResponseWriter, r *http.Request) {
+	id, err := entities.ParseEmailDeliveryID(r.PathValue("id"))
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	delivery, err := mh.mailerSvc.Resend(r.Context(), id)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	response := responses.NewEmailDeliveryResponse(delivery)
+	responses.HandleSuccess(w, http.StatusOK, response)
 }
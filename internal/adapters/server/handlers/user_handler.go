@@ -40,13 +40,13 @@ func (uh *UserHandler) Me(w http.ResponseWriter, r *http.Request) {
 
 	userID, err := helpers.GetUserIDFromContext(ctx)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
 	user, err := uh.svc.GetByID(ctx, userID)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -74,13 +74,13 @@ func (uh *UserHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 	userID, err := entities.ParseUserID(id)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
 	user, err := uh.svc.GetByID(ctx, userID)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -114,7 +114,7 @@ func (uh *UserHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 
 	var payload updatePasswordRequest
 	if err := validator.ValidateRequest(w, r, &payload); err != nil {
-		responses.HandleValidationError(w, err)
+		responses.HandleValidationError(w, r, err)
 		return
 	}
 
@@ -125,13 +125,57 @@ func (uh *UserHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 
 	userID, err := helpers.GetUserIDFromContext(ctx)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
 	err = uh.svc.UpdatePassword(ctx, userID, updateUserParams)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	responses.HandleSuccess(w, http.StatusOK, nil)
+}
+
+// updateLocaleRequest represents the structure of the request body used for updating a user preferred locale.
+type updateLocaleRequest struct {
+	Locale string `json:"locale" validate:"required" example:"fr"`
+}
+
+// UpdateLocale godoc
+//
+//	@Summary		Update user preferred locale
+//	@Description	Update the locale used for the emails sent to the user
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			updateLocaleRequest	body updateLocaleRequest true "Update user locale request"
+//	@Success		200	{object}	responses.EmptyResponse	"Success"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		422	{object}	responses.ErrorResponse	"Validation error"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/users/me/locale [patch]
+//	@Security		BearerAuth
+func (uh *UserHandler) UpdateLocale(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload updateLocaleRequest
+	if err := validator.ValidateRequest(w, r, &payload); err != nil {
+		responses.HandleValidationError(w, r, err)
+		return
+	}
+
+	userID, err := helpers.GetUserIDFromContext(ctx)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	err = uh.svc.UpdateLocale(ctx, userID, payload.Locale)
+	if err != nil {
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -156,13 +200,13 @@ func (uh *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 
 	token := r.PathValue("token")
 	if token == "" {
-		responses.HandleError(w, domain.ErrBadRequest)
+		responses.HandleError(w, r, domain.ErrBadRequest)
 		return
 	}
 
 	err := uh.svc.VerifyEmail(ctx, token)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -178,6 +222,7 @@ func (uh *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 //	@Success		200	{object}	responses.EmptyResponse	"Success"
 //	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
 //	@Failure		409	{object}	responses.ErrorResponse	"Conflict error / already verified"
+//	@Failure		429	{object}	responses.ErrorResponse	"Too many emails sent"
 //	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
 //	@Router			/v1/users/me/verify-email/resend [post]
 //	@Security		BearerAuth
@@ -186,13 +231,13 @@ func (uh *UserHandler) ResendEmailVerification(w http.ResponseWriter, r *http.Re
 
 	userID, err := helpers.GetUserIDFromContext(ctx)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
 	err = uh.svc.ResendEmailVerification(ctx, userID)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
@@ -202,7 +247,7 @@ func (uh *UserHandler) ResendEmailVerification(w http.ResponseWriter, r *http.Re
 // UploadAvatar godoc
 //
 //	@Summary		Upload user avatar
-//	@Description	Upload user avatar
+//	@Description	Upload user avatar. The image (PNG, JPEG or WebP) is stripped of its metadata and resized to square variants.
 //	@Tags			Users
 //	@Accept			multipart/form-data
 //	@Produce		json
@@ -210,26 +255,28 @@ func (uh *UserHandler) ResendEmailVerification(w http.ResponseWriter, r *http.Re
 //	@Success		200	{object}	responses.Response[responses.UploadAvatarResponse]	"Success"
 //	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
 //	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
-//	@Failure		413	{object}	responses.ErrorResponse	"File too large"
+//	@Failure		413	{object}	responses.ErrorResponse	"File or image dimensions too large"
+//	@Failure		422	{object}	responses.ErrorResponse	"File rejected by the malware scan"
 //	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Failure		503	{object}	responses.ErrorResponse	"File could not be scanned"
 //	@Router			/v1/users/me/avatar [post]
 //	@Security		BearerAuth
 func (uh *UserHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
 	parser := helpers.NewMultipartFormParser(5<<20, helpers.ImageExtensions)
 	if err := parser.Parse(r); err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
-	file, header, err := parser.GetFile(r, "avatar", 3<<20)
+	file, _, err := parser.GetFile(r, "avatar", 3<<20)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
 	defer func() {
 		if err := (*file).Close(); err != nil {
-			uh.errTracker.CaptureException(err)
+			uh.errTracker.CaptureException(r.Context(), err)
 		}
 	}()
 
@@ -237,17 +284,107 @@ func (uh *UserHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
 
 	userID, err := helpers.GetUserIDFromContext(ctx)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	avatarURLs, err := uh.svc.UpdateAvatar(ctx, userID, *file)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	response := responses.NewUploadAvatarResponse(avatarURLs)
+	responses.HandleSuccess(w, http.StatusOK, response)
+}
+
+// presignAvatarUploadRequest represents the structure of the request body used for requesting a direct avatar upload.
+type presignAvatarUploadRequest struct {
+	ContentType string `json:"content_type" validate:"required" example:"image/png"`
+	Size        int64  `json:"size" validate:"required,gt=0" example:"204800"`
+}
+
+// PresignAvatarUpload godoc
+//
+//	@Summary		Request a direct avatar upload
+//	@Description	Return a short-lived URL the avatar is uploaded to, directly to the storage. The upload must then be confirmed.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			presignAvatarUploadRequest	body presignAvatarUploadRequest true "Presign avatar upload request"
+//	@Success		201	{object}	responses.Response[responses.PresignedUploadResponse]	"Success"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		413	{object}	responses.ErrorResponse	"File too large"
+//	@Failure		422	{object}	responses.ErrorResponse	"Validation error"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/v1/users/me/avatar/uploads [post]
+//	@Security		BearerAuth
+func (uh *UserHandler) PresignAvatarUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload presignAvatarUploadRequest
+	if err := validator.ValidateRequest(w, r, &payload); err != nil {
+		responses.HandleValidationError(w, r, err)
+		return
+	}
+
+	userID, err := helpers.GetUserIDFromContext(ctx)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	upload, presigned, err := uh.svc.PresignAvatarUpload(ctx, userID, payload.ContentType, payload.Size)
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	response := responses.NewPresignedUploadResponse(upload, presigned)
+	responses.HandleSuccess(w, http.StatusCreated, response)
+}
+
+// ConfirmAvatarUpload godoc
+//
+//	@Summary		Confirm a direct avatar upload
+//	@Description	Check the avatar uploaded to a presigned URL, then process it like an avatar uploaded through the API
+//	@Tags			Users
+//	@Produce		json
+//	@Param			id	path		string		true	"Upload ID" format(uuid)
+//	@Success		200	{object}	responses.Response[responses.UploadAvatarResponse]	"Success"
+//	@Failure		400	{object}	responses.ErrorResponse	"Bad request error"
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized error"
+//	@Failure		404	{object}	responses.ErrorResponse	"Upload not found or expired"
+//	@Failure		409	{object}	responses.ErrorResponse	"File not uploaded yet"
+//	@Failure		413	{object}	responses.ErrorResponse	"Image dimensions too large"
+//	@Failure		422	{object}	responses.ErrorResponse	"Uploaded file does not match the upload request or rejected by the malware scan"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Failure		503	{object}	responses.ErrorResponse	"File could not be scanned"
+//	@Router			/v1/users/me/avatar/uploads/{id}/confirm [post]
+//	@Security		BearerAuth
+func (uh *UserHandler) ConfirmAvatarUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	uploadID, err := entities.ParseUploadID(r.PathValue("id"))
+	if err != nil {
+		responses.HandleError(w, r, err)
+		return
+	}
+
+	userID, err := helpers.GetUserIDFromContext(ctx)
+	if err != nil {
+		responses.HandleError(w, r, err)
 		return
 	}
 
-	avatarURL, err := uh.svc.UpdateAvatar(ctx, userID, header.Filename, *file)
+	avatarURLs, err := uh.svc.ConfirmAvatarUpload(ctx, userID, uploadID)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
-	response := responses.NewUploadAvatarResponse(avatarURL)
+	response := responses.NewUploadAvatarResponse(avatarURLs)
 	responses.HandleSuccess(w, http.StatusOK, response)
 }
 
@@ -267,13 +404,13 @@ func (uh *UserHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
 
 	userID, err := helpers.GetUserIDFromContext(ctx)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
 	err = uh.svc.DeleteAvatar(ctx, userID)
 	if err != nil {
-		responses.HandleError(w, err)
+		responses.HandleError(w, r, err)
 		return
 	}
 
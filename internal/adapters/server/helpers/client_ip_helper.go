@@ -0,0 +1,140 @@
+package helpers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+const (
+	// ForwardedHeaderKey defines the key used to retrieve the standard forwarding header (RFC 7239) from the HTTP request.
+	ForwardedHeaderKey = "Forwarded"
+	// XForwardedForHeaderKey defines the key used to retrieve the de facto forwarding header from the HTTP request.
+	XForwardedForHeaderKey = "X-Forwarded-For"
+	// XRealIPHeaderKey defines the key used to retrieve the client IP address set by a proxy from the HTTP request.
+	XRealIPHeaderKey = "X-Real-IP"
+	// ClientIPPayloadKey defines the key used to store and retrieve the resolved client IP address from the context.
+	ClientIPPayloadKey = "client_ip_payload"
+)
+
+// ClientIPResolver resolves the IP address of the client of an HTTP request.
+// The forwarding headers are only trusted when the request comes from a trusted proxy, so that a client
+// cannot choose the address it is identified by.
+type ClientIPResolver struct {
+	trustedProxies []netip.Prefix
+}
+
+// NewClientIPResolver creates a new ClientIPResolver trusting the given IP addresses or CIDRs.
+// Invalid entries are ignored, the configuration being validated beforehand.
+func NewClientIPResolver(trustedProxies []string) *ClientIPResolver {
+	prefixes := make([]netip.Prefix, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		}
+	}
+	return &ClientIPResolver{trustedProxies: prefixes}
+}
+
+// Resolve returns the IP address of the client of the HTTP request, without port.
+// When the peer is a trusted proxy, the forwarding headers are read in order of preference:
+// Forwarded, X-Forwarded-For, then X-Real-IP. The chain of addresses is walked from the nearest hop,
+// skipping the trusted proxies, and the first untrusted address is the client.
+// An invalid or obfuscated address stops the walk at the last valid one, which did set it.
+func (cr *ClientIPResolver) Resolve(r *http.Request) string {
+	peer, ok := parseIP(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !cr.isTrusted(peer) {
+		return peer.String()
+	}
+
+	var chain []string
+	if values := r.Header.Values(ForwardedHeaderKey); len(values) > 0 {
+		chain = forwardedFor(values)
+	} else if values := r.Header.Values(XForwardedForHeaderKey); len(values) > 0 {
+		chain = splitHeaderList(values)
+	} else if value := r.Header.Get(XRealIPHeaderKey); value != "" {
+		chain = []string{strings.TrimSpace(value)}
+	}
+
+	client := peer
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr, ok := parseIP(chain[i])
+		if !ok {
+			break
+		}
+		client = addr
+		if !cr.isTrusted(addr) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// isTrusted reports whether the address is one of a trusted proxy.
+func (cr *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range cr.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetClientIPFromContext retrieves the client IP address from the context of the HTTP request.
+// Returns an empty string if the address has not been resolved.
+func GetClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ClientIPPayloadKey).(string)
+	return ip
+}
+
+// parseIP parses an IP address, with or without port, IPv6 addresses being possibly bracketed.
+// IPv4-mapped IPv6 addresses are returned as IPv4 addresses.
+func parseIP(value string) (netip.Addr, bool) {
+	value = strings.Trim(strings.TrimSpace(value), `"`)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap().WithZone(""), true
+}
+
+// forwardedFor returns the "for" parameters of the elements of the Forwarded headers, from the farthest hop.
+// Elements without a "for" parameter are returned as empty, for the walk to stop on them.
+func forwardedFor(values []string) []string {
+	elements := splitHeaderList(values)
+	chain := make([]string, 0, len(elements))
+	for _, element := range elements {
+		forValue := ""
+		for _, pair := range strings.Split(element, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			if strings.EqualFold(key, "for") {
+				forValue = value
+			}
+		}
+		chain = append(chain, forValue)
+	}
+	return chain
+}
+
+// splitHeaderList splits the comma separated values of a header, repeated or not.
+func splitHeaderList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+	}
+	return items
+}
@@ -0,0 +1,92 @@
+//go:build !integration
+
+package helpers_test
+
+import (
+	"go-starter/internal/adapters/server/helpers"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIPResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	resolver := helpers.NewClientIPResolver([]string{"10.0.0.0/8", "2001:db8::1"})
+
+	tests := map[string]struct {
+		remoteAddr string
+		headers    map[string]string
+		expectedIP string
+	}{
+		"direct client should be identified by its address without port": {
+			remoteAddr: "203.0.113.7:51234",
+			expectedIP: "203.0.113.7",
+		},
+		"untrusted peer should not be able to spoof X-Forwarded-For": {
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			expectedIP: "203.0.113.7",
+		},
+		"untrusted peer should not be able to spoof Forwarded": {
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string]string{"Forwarded": "for=198.51.100.1"},
+			expectedIP: "203.0.113.7",
+		},
+		"trusted proxy should be looked through with X-Forwarded-For": {
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			expectedIP: "198.51.100.1",
+		},
+		"entries set by the client before the trusted proxies should be ignored": {
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.1, 10.0.0.3"},
+			expectedIP: "198.51.100.1",
+		},
+		"trusted proxy should be looked through with Forwarded": {
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string]string{"Forwarded": `for=1.2.3.4, for="[2001:db8:cafe::17]:4711";proto=https, for=10.0.0.3`},
+			expectedIP: "2001:db8:cafe::17",
+		},
+		"Forwarded should be preferred over X-Forwarded-For": {
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string]string{"Forwarded": "for=198.51.100.1", "X-Forwarded-For": "198.51.100.2"},
+			expectedIP: "198.51.100.1",
+		},
+		"trusted proxy should be looked through with X-Real-IP": {
+			remoteAddr: "[2001:db8::1]:443",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.1"},
+			expectedIP: "198.51.100.1",
+		},
+		"obfuscated address should stop at the proxy which set it": {
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string]string{"Forwarded": "for=198.51.100.1, for=_hidden, for=10.0.0.3"},
+			expectedIP: "10.0.0.3",
+		},
+		"invalid address should stop at the trusted proxy": {
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string]string{"X-Forwarded-For": "not an ip"},
+			expectedIP: "10.0.0.2",
+		},
+		"chain of trusted proxies only should resolve to the farthest one": {
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.4, 10.0.0.3"},
+			expectedIP: "10.0.0.4",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			if ip := resolver.Resolve(r); ip != tt.expectedIP {
+				t.Errorf("expected client IP %q, got %q", tt.expectedIP, ip)
+			}
+		})
+	}
+}
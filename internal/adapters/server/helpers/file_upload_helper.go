@@ -13,7 +13,7 @@ import (
 
 // ImageExtensions is a list of allowed image file extensions.
 var (
-	ImageExtensions = []string{".png", ".jpg", ".jpeg"}
+	ImageExtensions = []string{".png", ".jpg", ".jpeg", ".webp"}
 )
 
 // MultipartFormParser is a helper struct for parsing multipart form data.
@@ -35,9 +35,13 @@ func NewMultipartFormParser(maxMemory int64, allowedExtensions []string) *Multip
 }
 
 // Parse parses the multipart form data from the request.
+// Returns domain.ErrFileTooLarge if the body exceeds the limit of its http.MaxBytesReader.
 func (p *MultipartFormParser) Parse(r *http.Request) error {
 	if err := r.ParseMultipartForm(p.maxMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
 		switch {
+		case errors.As(err, &maxBytesErr):
+			return domain.ErrFileTooLarge
 		case errors.Is(err, http.ErrNotMultipart):
 			return domain.ErrInvalidMultipartForm
 		case errors.Is(err, http.ErrMissingBoundary):
@@ -49,27 +53,31 @@ func (p *MultipartFormParser) Parse(r *http.Request) error {
 	return nil
 }
 
+// GetFile returns a file of the multipart form, which must be closed by the caller.
+// Returns domain.ErrFileTooLarge if the file exceeds maxFileSize, or domain.ErrInvalidFileType if its extension is not allowed.
 func (p *MultipartFormParser) GetFile(r *http.Request, fieldName string, maxFileSize int64) (*multipart.File, *multipart.FileHeader, error) {
 	file, header, err := r.FormFile(fieldName)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	defer func() {
-		if err := file.Close(); err != nil {
-			slog.Error(err.Error())
+	// The file is only closed when rejected, large files are stored on disk and cannot be read once closed.
+	reject := func(err error) (*multipart.File, *multipart.FileHeader, error) {
+		if closeErr := file.Close(); closeErr != nil {
+			slog.ErrorContext(r.Context(), "failed to close rejected file", "error", closeErr)
 		}
-	}()
+		return nil, nil, err
+	}
 
 	if header.Size > maxFileSize {
-		return nil, nil, domain.ErrFileTooLarge
+		return reject(domain.ErrFileTooLarge)
 	}
 
 	ext := strings.ToLower(filepath.Ext(header.Filename))
 	ext = strings.TrimPrefix(ext, ".")
 
 	if !p.isExtensionAllowed(ext) {
-		return nil, nil, domain.ErrInvalidFileType
+		return reject(domain.ErrInvalidFileType)
 	}
 
 	return &file, header, nil
@@ -82,18 +90,3 @@ func (p *MultipartFormParser) isExtensionAllowed(ext string) bool {
 	}
 	return slices.Contains(p.allowedExtensions, ext)
 }
-
-// GenerateFileKey generates a file key for the file.
-func GenerateFileKey(filePrefix string, filename string) string {
-	return filePrefix + "." + getFileExtension(filename)
-}
-
-// getFileExtension returns the file extension from the filename.
-// Returns an empty string if no extension is found.
-func getFileExtension(filename string) string {
-	parts := strings.Split(filename, ".")
-	if len(parts) <= 1 {
-		return ""
-	}
-	return parts[len(parts)-1]
-}
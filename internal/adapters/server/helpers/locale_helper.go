@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"context"
+	"go-starter/internal/domain/i18n"
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	// AcceptLanguageHeaderKey defines the key used to retrieve the preferred languages from the HTTP request.
+	AcceptLanguageHeaderKey = "Accept-Language"
+	// LocalePayloadKey defines the key used to store and retrieve the negotiated locale from the context.
+	LocalePayloadKey = "locale_payload"
+)
+
+// localeMatcher matches the languages accepted by a client against the supported locales.
+var localeMatcher = newLocaleMatcher()
+
+// newLocaleMatcher creates a language matcher for the supported locales.
+// The default locale comes first, so it is used when nothing matches.
+func newLocaleMatcher() language.Matcher {
+	tags := make([]language.Tag, len(i18n.SupportedLocales))
+	for i, locale := range i18n.SupportedLocales {
+		tags[i] = language.Make(locale.String())
+	}
+	return language.NewMatcher(tags)
+}
+
+// NegotiateLocale selects the best supported locale from the Accept-Language header of the HTTP request.
+// Returns the default locale if the header is missing, invalid or does not match any supported locale.
+func NegotiateLocale(r *http.Request) i18n.Locale {
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get(AcceptLanguageHeaderKey))
+	if err != nil || len(tags) == 0 {
+		return i18n.DefaultLocale
+	}
+
+	_, index, confidence := localeMatcher.Match(tags...)
+	if confidence == language.No {
+		return i18n.DefaultLocale
+	}
+
+	return i18n.SupportedLocales[index]
+}
+
+// GetLocaleFromContext retrieves the negotiated locale from the context of the HTTP request.
+// Returns the default locale if no locale was negotiated.
+func GetLocaleFromContext(ctx context.Context) i18n.Locale {
+	locale, ok := ctx.Value(LocalePayloadKey).(i18n.Locale)
+	if !ok {
+		return i18n.DefaultLocale
+	}
+	return locale
+}
@@ -0,0 +1,50 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeaderKey defines the key used to retrieve the request ID from the HTTP request and send it back in the response.
+	RequestIDHeaderKey = "X-Request-ID"
+	// RequestIDPayloadKey defines the key used to store and retrieve the request ID from the context.
+	RequestIDPayloadKey = "request_id_payload"
+	// maxRequestIDLength is the maximum length of a request ID accepted from a client.
+	maxRequestIDLength = 128
+)
+
+// ResolveRequestID returns the request ID of the X-Request-ID header of the HTTP request, so that the ID set
+// by a client or a proxy is kept across services. A new UUID is generated if the header is missing or invalid.
+func ResolveRequestID(r *http.Request) string {
+	if requestID := r.Header.Get(RequestIDHeaderKey); isValidRequestID(requestID) {
+		return requestID
+	}
+	return uuid.NewString()
+}
+
+// isValidRequestID reports whether the request ID is not empty, at most maxRequestIDLength long and only made of
+// letters, digits and the characters "-", "_", ".", ":" and "/", so that it is safe to log and send back.
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// GetRequestIDFromContext returns the request ID set in the context by the request ID middleware.
+// Returns an empty string if the context holds none.
+func GetRequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDPayloadKey).(string)
+	return requestID
+}
@@ -2,120 +2,157 @@ package middleware
 
 import (
 	"bytes"
-	"encoding/json"
-	"go-starter/internal/adapters/ratelimiter"
+	"context"
+	"fmt"
+	"go-starter/config"
+	"go-starter/internal/adapters"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/logger"
+	"go-starter/internal/adapters/server/helpers"
+	"go-starter/internal/adapters/server/responses"
+	"go-starter/internal/adapters/tracing"
+	"go-starter/internal/domain"
 	"go-starter/internal/domain/ports"
+	"go-starter/internal/domain/services"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // GlobalMiddleware is a middleware that applies global middleware functions to the HTTP request pipeline.
 type GlobalMiddleware struct {
+	ClientIP    HandlerMiddleware
 	ErrTracking HandlerMiddleware
+	Recovery    HandlerMiddleware
 	Logging     HandlerMiddleware
 	Security    HandlerMiddleware
 	Cors        HandlerMiddleware
 	RateLimiter HandlerMiddleware
+	Locale      HandlerMiddleware
+	Metrics     HandlerMiddleware
+	Tracing     HandlerMiddleware
+	RequestID   HandlerMiddleware
 }
 
 // NewGlobalMiddleware creates a new GlobalMiddleware instance.
-// It initializes the middleware components with the provided error tracker and cache repository.
-func NewGlobalMiddleware(errTracker ports.ErrTrackerAdapter, cache ports.CacheRepository) *GlobalMiddleware {
-	globalLimiter := ratelimiter.New(cache, "global")
+// It initializes the middleware components with the provided configuration, services and adapters.
+// The routes are the ones the requests are served by, their patterns label the HTTP metrics, spans and logs.
+func NewGlobalMiddleware(cfg *config.Container, s *services.Services, a *adapters.Adapters, routes *http.ServeMux) *GlobalMiddleware {
+	errTracker := a.ErrTrackerAdapter
 
 	return &GlobalMiddleware{
-		ErrTracking: ErrTrackingMiddleware(errTracker),
-		Logging:     LoggingMiddleware(),
+		ClientIP:    ClientIPMiddleware(helpers.NewClientIPResolver(cfg.HTTP.TrustedProxies)),
+		ErrTracking: ErrTrackingMiddleware(errTracker, routes, cfg.ErrTracker.MaxBodySize),
+		Recovery:    RecoveryMiddleware(errTracker),
+		Logging:     LoggingMiddleware(routes),
 		Security:    SecurityHeadersMiddleware(),
 		Cors:        CorsMiddleware(),
-		RateLimiter: GlobalRateLimitMiddleware(globalLimiter, errTracker),
+		RateLimiter: RateLimitMiddleware(cfg.RateLimit, a.CacheRepository, s.TokenService, s.UserService, a.Metrics, errTracker),
+		Locale:      LocaleMiddleware(),
+		Metrics:     MetricsMiddleware(a.Metrics, routes),
+		Tracing:     TracingMiddleware(a.TracerProvider, routes, errTracker),
+		RequestID:   RequestIDMiddleware(routes, errTracker),
 	}
 }
 
-// GlobalRateLimitMiddleware creates a middleware that globally limits the request rate.
-func GlobalRateLimitMiddleware(limiter *ratelimiter.RateLimiter, errTracker ports.ErrTrackerAdapter) HandlerMiddleware {
-	// Configuration du rate limiter global
-	const (
-		globalLimit  = 200
-		globalWindow = time.Minute
-	)
-
+// RequestIDMiddleware identifies each HTTP request with the ID of its X-Request-ID header, or a generated one,
+// and sends it back in the X-Request-ID response header. The ID is set in the context of the request and attached
+// to the error tracker scope, so that a report can be looked up from the ID of a response.
+// The request ID and route pattern are set as log attributes of the context, it must run before any middleware logging.
+func RequestIDMiddleware(routes *http.ServeMux, errTracker ports.ErrTrackerAdapter) HandlerMiddleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Use the IP address as the identifier
-			identifier := r.RemoteAddr
-
-			// Check if we have an X-Forwarded-For header (case of a proxy)
-			if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
-				ips := strings.Split(forwardedFor, ",")
-				if len(ips) > 0 {
-					identifier = strings.TrimSpace(ips[0])
-				}
-			}
+			requestID := helpers.ResolveRequestID(r)
+			w.Header().Set(helpers.RequestIDHeaderKey, requestID)
+			errTracker.SetRequestID(r.Context(), requestID)
 
-			result, err := limiter.Check(r.Context(), identifier, globalLimit, globalWindow)
-			if err != nil {
-				errTracker.CaptureException(err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
+			attrs := []slog.Attr{slog.String(logger.RequestIDKey, requestID)}
+			if _, route := routes.Handler(r); route != "" {
+				attrs = append(attrs, slog.String(logger.RouteKey, route))
 			}
 
-			w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
-			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(result.Limit-result.Current, 10))
-			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(int64(result.ResetAfter.Seconds()), 10))
-
-			if !result.Allowed {
-				w.Header().Set("Retry-After", strconv.FormatInt(int64(result.ResetAfter.Seconds()), 10))
-				w.WriteHeader(http.StatusTooManyRequests)
-
-				response := map[string]interface{}{
-					"error":       "Rate limit exceeded",
-					"retry_after": result.ResetAfter.Seconds(),
-				}
-
-				json.NewEncoder(w).Encode(response)
-				return
-			}
+			ctx := context.WithValue(r.Context(), helpers.RequestIDPayloadKey, requestID)
+			ctx = logger.WithAttrs(ctx, attrs...)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
 
-			next.ServeHTTP(w, r)
+// ClientIPMiddleware resolves the IP address of the client and sets it in the context of the HTTP request,
+// as well as a log attribute of the context. It must run before any middleware identifying the client.
+func ClientIPMiddleware(resolver *helpers.ClientIPResolver) HandlerMiddleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientIP := resolver.Resolve(r)
+			ctx := context.WithValue(r.Context(), helpers.ClientIPPayloadKey, clientIP)
+			ctx = logger.WithAttrs(ctx, slog.String(logger.ClientIPKey, clientIP))
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
 // ErrTrackingMiddleware creates a middleware that integrates error tracking functionality
-// into the HTTP request pipeline. It captures request details and bodies for error monitoring.
-func ErrTrackingMiddleware(errTracker ports.ErrTrackerAdapter) HandlerMiddleware {
+// into the HTTP request pipeline. It captures request details and bodies for error monitoring,
+// the bodies being scrubbed by the error tracker according to the route pattern they are served by.
+// Only JSON and form encoded bodies are captured, and only if they are not larger than maxBodySize bytes:
+// at most maxBodySize + 1 bytes are read ahead of the next handlers, which stream the rest of the body.
+func ErrTrackingMiddleware(errTracker ports.ErrTrackerAdapter, routes *http.ServeMux, maxBodySize int) HandlerMiddleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Identify the client by its IP address until it is authenticated
+			errTracker.SetUser(r.Context(), "", helpers.GetClientIPFromContext(r.Context()))
+
 			// Skip body reading for GET/HEAD requests
 			if r.Method == http.MethodGet || r.Method == http.MethodHead {
-				errTracker.SetRequest(r)
+				errTracker.SetRequest(r.Context(), r)
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			body, err := io.ReadAll(r.Body)
+			_, route := routes.Handler(r)
+			contentType := r.Header.Get("Content-Type")
+			errTracker.SetRequest(r.Context(), r)
+
+			// Skip body reading for the bodies that cannot be reported, such as file uploads,
+			// the body buffered by SetRequest is dropped
+			if !isReportableBody(contentType) {
+				errTracker.SetBody(r.Context(), route, contentType, nil)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			prefix, err := io.ReadAll(io.LimitReader(r.Body, int64(maxBodySize)+1))
 			if err != nil {
-				slog.Error("failed to read request body",
+				slog.ErrorContext(r.Context(), "failed to read request body",
 					"error", err,
 					"path", r.URL.Path,
 					"method", r.Method,
 				)
-				errTracker.CaptureException(err)
+				errTracker.CaptureException(r.Context(), err)
 				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 				return
 			}
 
-			// Replace the body for downstream handlers
-			r.Body = io.NopCloser(bytes.NewBuffer(body))
+			// Replace the body for downstream handlers, the part not read yet follows the prefix
+			r.Body = readCloser{
+				Reader: io.MultiReader(bytes.NewReader(prefix), r.Body),
+				Closer: r.Body,
+			}
 
-			// Track request with body
-			errTracker.SetRequest(r)
-			errTracker.SetBody(body)
+			// Track request with body, an oversized one is dropped
+			if len(prefix) > maxBodySize {
+				prefix = nil
+			}
+			errTracker.SetBody(r.Context(), route, contentType, prefix)
 
 			// Call the next handler
 			next.ServeHTTP(w, r)
@@ -123,6 +160,64 @@ func ErrTrackingMiddleware(errTracker ports.ErrTrackerAdapter) HandlerMiddleware
 	}
 }
 
+// readCloser combines a reader with the closer of another one.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// isReportableBody reports whether a body with the content type can be reported to the error tracker,
+// i.e. whether it is JSON or form encoded.
+func isReportableBody(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/x-www-form-urlencoded"
+}
+
+// RecoveryMiddleware recovers from the panics of the next handlers and reports them to the error tracker,
+// with the stack trace of the panic. The client gets an internal error, unless the panic is http.ErrAbortHandler
+// which is raised again to abort the response.
+func RecoveryMiddleware(errTracker ports.ErrTrackerAdapter) HandlerMiddleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				err := errtracker.PanicError(recovered)
+				errTracker.CaptureException(r.Context(), err)
+				responses.HandleError(w, r, fmt.Errorf("%w: %w", domain.ErrInternal, err))
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// LocaleMiddleware negotiates the locale of the response from the Accept-Language header.
+// It sets the locale in the context of the HTTP request and the Content-Language response header.
+func LocaleMiddleware() HandlerMiddleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := helpers.NegotiateLocale(r)
+
+			w.Header().Set("Content-Language", locale.String())
+			w.Header().Add("Vary", helpers.AcceptLanguageHeaderKey)
+
+			ctx := context.WithValue(r.Context(), helpers.LocalePayloadKey, locale)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // CorsMiddleware defines CORS specifications.
 func CorsMiddleware() HandlerMiddleware {
 	return func(next http.Handler) http.Handler {
@@ -130,7 +225,8 @@ func CorsMiddleware() HandlerMiddleware {
 			// Set CORS headers
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token")
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Accept-Language, Authorization, Content-Type, X-API-Key, X-CSRF-Token, X-Request-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 			w.Header().Set("Access-Control-Allow-Credentials", "false") // Set to "true" if credentials are required
 
 			// Handle preflight OPTIONS requests
@@ -162,16 +258,20 @@ func SecurityHeadersMiddleware() HandlerMiddleware {
 	}
 }
 
-// LoggingMiddleware logs each HTTP request with method, URL, and response time
-func LoggingMiddleware() HandlerMiddleware {
+// LoggingMiddleware logs each HTTP request with method, URL, and response time.
+// Both lines carry the log attributes of the context, e.g. the request ID, to be correlated.
+// The tokens and signatures of the URL are redacted, the route pattern tells which path segments hold them.
+func LoggingMiddleware(routes *http.ServeMux) HandlerMiddleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Capture the start time
 			start := time.Now()
+			_, route := routes.Handler(r)
+			url := logger.RedactURL(r.URL, route)
 
 			// Log the incoming request details
-			slog.Info("REQUEST",
-				"url", r.URL.String(),
+			slog.InfoContext(r.Context(), "REQUEST",
+				"url", url,
 				"method", r.Method,
 			)
 
@@ -183,8 +283,8 @@ func LoggingMiddleware() HandlerMiddleware {
 
 			// Log the response details
 			duration := time.Since(start)
-			slog.Info("RESPONSE",
-				"url", r.URL.String(),
+			slog.InfoContext(r.Context(), "RESPONSE",
+				"url", url,
 				"method", r.Method,
 				"status", wrappedWriter.statusCode,
 				"duration_ms", duration.Milliseconds(),
@@ -193,6 +293,61 @@ func LoggingMiddleware() HandlerMiddleware {
 	}
 }
 
+// MetricsMiddleware records the method, route pattern, status and duration of each HTTP request.
+// The pattern is looked up in the routes rather than taken from the request, so that the requests answered
+// before reaching them (e.g., rate limited ones) are labeled too; requests matching no route are labeled "unmatched".
+func MetricsMiddleware(metrics ports.Metrics, routes *http.ServeMux) HandlerMiddleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Wrap the ResponseWriter to capture the status code
+			wrappedWriter := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrappedWriter, r)
+
+			_, route := routes.Handler(r)
+			if route == "" {
+				route = "unmatched"
+			}
+			metrics.ObserveHTTPRequest(r.Method, route, wrappedWriter.statusCode, time.Since(start))
+		})
+	}
+}
+
+// TracingMiddleware traces each HTTP request in a server span, named after the pattern of its route.
+// The span continues the trace of the W3C trace context headers of the request, if any,
+// and its IDs are attached to the error tracker scope. Requests answered with a 5xx status are marked failed.
+func TracingMiddleware(tracerProvider trace.TracerProvider, routes *http.ServeMux, errTracker ports.ErrTrackerAdapter) HandlerMiddleware {
+	tracer := tracerProvider.Tracer(tracing.TracerName)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := tracing.Propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			name := r.Method
+			attrs := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(r.Method), semconv.URLPath(r.URL.Path)}
+			if _, pattern := routes.Handler(r); pattern != "" {
+				name = pattern
+				_, route, _ := strings.Cut(pattern, " ")
+				attrs = append(attrs, semconv.HTTPRoute(route))
+			}
+
+			ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+			defer span.End()
+			errTracker.SetTrace(ctx, span.SpanContext().TraceID().String(), span.SpanContext().SpanID().String())
+
+			// Wrap the ResponseWriter to capture the status code
+			wrappedWriter := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrappedWriter, r.WithContext(ctx))
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(wrappedWriter.statusCode))
+			if wrappedWriter.statusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(wrappedWriter.statusCode))
+			}
+		})
+	}
+}
+
 // responseWriter is a wrapper around http.ResponseWriter to capture the status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -0,0 +1,424 @@
+//go:build !integration
+
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/logger"
+	"go-starter/internal/adapters/metrics"
+	"go-starter/internal/adapters/server/helpers"
+	"go-starter/internal/adapters/server/middleware"
+	"go-starter/internal/adapters/storage/cache"
+	"go-starter/internal/adapters/tracing"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/i18n"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// decodeLogRecords decodes the JSON lines logged to the buffer.
+func decodeLogRecords(t *testing.T, logs *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to decode log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRequestIDMiddleware_ResolvesRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		header    string
+		expected  string
+		generated bool
+	}{
+		"valid header should be kept":            {header: "req-42_a.b:c/d", expected: "req-42_a.b:c/d"},
+		"missing header should be generated":     {header: "", generated: true},
+		"header with spaces should be replaced":  {header: "req 42", generated: true},
+		"header with newline should be replaced": {header: "req\n42", generated: true},
+		"too long header should be replaced":     {header: strings.Repeat("a", 129), generated: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			errTracker := errtracker.NewErrTrackerAdapterMock()
+			var fromContext string
+			var scopeCtx context.Context
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /v1/users/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+				fromContext = helpers.GetRequestIDFromContext(r.Context())
+				scopeCtx = r.Context()
+			})
+			handler := errTracker.Handle(middleware.RequestIDMiddleware(mux, errTracker)(mux))
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/users/42", nil)
+			if tt.header != "" {
+				request.Header.Set(helpers.RequestIDHeaderKey, tt.header)
+			}
+			recorder := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(recorder, request)
+
+			// Assert
+			got := recorder.Header().Get(helpers.RequestIDHeaderKey)
+			if tt.generated {
+				if _, err := uuid.Parse(got); err != nil {
+					t.Errorf("expected a generated UUID request ID, got %q", got)
+				}
+			} else if got != tt.expected {
+				t.Errorf("expected request ID %q, got %q", tt.expected, got)
+			}
+			if fromContext != got {
+				t.Errorf("expected the context to hold request ID %q, got %q", got, fromContext)
+			}
+			if errTracker.RequestID(scopeCtx) != got {
+				t.Errorf("expected the error tracker scope to hold request ID %q, got %q", got, errTracker.RequestID(scopeCtx))
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware_CorrelatesLogs(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	tokenSvc := newTokenService()
+	errTracker := errtracker.NewErrTrackerAdapterMock()
+	userID := entities.UserID(uuid.New())
+	token, err := tokenSvc.GenerateAuthToken(ctx, userID)
+	if err != nil {
+		t.Fatalf("failed to generate auth token: %v", err)
+	}
+
+	var logs bytes.Buffer
+	log := slog.New(logger.NewContextHandler(slog.NewJSONHandler(&logs, nil)))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/users/{uuid}", middleware.AuthMiddleware(tokenSvc, errTracker)(
+		func(w http.ResponseWriter, r *http.Request) {
+			log.InfoContext(r.Context(), "handling request")
+		},
+	))
+	handler := middleware.ChainHandlerFunc(mux,
+		middleware.ClientIPMiddleware(helpers.NewClientIPResolver(nil)),
+		middleware.RequestIDMiddleware(mux, errTracker),
+	)
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/users/42", nil)
+	request.RemoteAddr = "192.0.2.10:4321"
+	request.Header.Set(helpers.RequestIDHeaderKey, "req-42")
+	request.Header.Set(helpers.AuthorizationHeaderKey, "Bearer "+token)
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	// Assert
+	var record map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("failed to decode log record: %v", err)
+	}
+
+	tests := map[string]struct {
+		key      string
+		expected string
+	}{
+		"request ID should be logged": {key: "request_id", expected: "req-42"},
+		"route should be logged":      {key: "route", expected: "GET /v1/users/{uuid}"},
+		"client IP should be logged":  {key: "client_ip", expected: "192.0.2.10"},
+		"user ID should be logged":    {key: "user_id", expected: userID.String()},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := record[tt.key]; got != tt.expected {
+				t.Errorf("expected %s %q, got %v", tt.key, tt.expected, got)
+			}
+		})
+	}
+}
+
+// bodyRecorder is an error tracker keeping the last body set with SetBody.
+type bodyRecorder struct {
+	errtracker.NoopAdapter
+	body []byte
+}
+
+func (br *bodyRecorder) SetBody(_ context.Context, _, _ string, body []byte) {
+	br.body = body
+}
+
+func TestErrTrackingMiddleware_BoundsBodyReads(t *testing.T) {
+	t.Parallel()
+
+	oversized := `{"name":"` + strings.Repeat("a", 64) + `"}`
+	tests := map[string]struct {
+		contentType  string
+		body         string
+		expectedBody string
+	}{
+		"JSON body should be reported":                     {contentType: "application/json", body: `{"name":"john"}`, expectedBody: `{"name":"john"}`},
+		"form body should be reported":                     {contentType: "application/x-www-form-urlencoded", body: "name=john", expectedBody: "name=john"},
+		"oversized JSON body should be dropped":            {contentType: "application/json", body: oversized},
+		"multipart body should not be reported":            {contentType: "multipart/form-data; boundary=x", body: "--x--"},
+		"octet-stream body should not be reported":         {contentType: "application/octet-stream", body: "binary"},
+		"body without content type should not be reported": {body: `{"name":"john"}`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			errTracker := &bodyRecorder{}
+			var received string
+			handler := middleware.ErrTrackingMiddleware(errTracker, http.NewServeMux(), 32)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+			}))
+			request := httptest.NewRequest(http.MethodPost, "/v1/users/me/files", strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", tt.contentType)
+
+			// Act
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			// Assert
+			if received != tt.body {
+				t.Errorf("expected the handler to read the whole body %q, got %q", tt.body, received)
+			}
+			if string(errTracker.body) != tt.expectedBody {
+				t.Errorf("expected the reported body %q, got %q", tt.expectedBody, errTracker.body)
+			}
+		})
+	}
+}
+
+func TestRecoveryMiddleware_RecoversPanics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panic should be reported and answered with an internal error", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logs bytes.Buffer
+		errTracker := errtracker.NewLogAdapter(slog.New(slog.NewJSONHandler(&logs, nil)))
+		handler := middleware.RecoveryMiddleware(errTracker)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("nil map")
+		}))
+		recorder := httptest.NewRecorder()
+
+		// Act
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/users/me", nil))
+
+		// Assert
+		if recorder.Code != http.StatusInternalServerError {
+			t.Errorf("expected status %d, got %d", http.StatusInternalServerError, recorder.Code)
+		}
+		if body := recorder.Body.String(); !strings.Contains(body, i18n.ErrorKey(domain.ErrInternal)) || strings.Contains(body, "nil map") {
+			t.Errorf("expected an internal error without the panic value, got %s", body)
+		}
+		records := decodeLogRecords(t, &logs)
+		if len(records) != 1 || records[0]["error"] != "panic: nil map" {
+			t.Fatalf("expected the panic to be reported, got %v", records)
+		}
+		if stack, _ := records[0]["stacktrace"].(string); !strings.Contains(stack, "TestRecoveryMiddleware_RecoversPanics") {
+			t.Errorf("expected the stack trace of the panic, got %q", stack)
+		}
+	})
+
+	t.Run("aborted handler should panic again", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := middleware.RecoveryMiddleware(errtracker.NewNoopAdapter())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		// Act
+		defer func() {
+			// Assert
+			if recovered := recover(); recovered != http.ErrAbortHandler {
+				t.Errorf("expected panic %v, got %v", http.ErrAbortHandler, recovered)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/users/me", nil))
+	})
+}
+
+// TestLoggingMiddleware_RedactsTokens replaces the default logger, so it must not run in parallel.
+func TestLoggingMiddleware_RedactsTokens(t *testing.T) {
+	// Arrange
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /v1/auth/password-reset/{token}", func(w http.ResponseWriter, r *http.Request) {})
+	handler := middleware.LoggingMiddleware(mux)(mux)
+	req := httptest.NewRequest(http.MethodPatch, "/v1/auth/password-reset/abc123", nil)
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	if strings.Contains(logs.String(), "abc123") {
+		t.Errorf("expected the token to be redacted, got %s", logs.String())
+	}
+	records := decodeLogRecords(t, &logs)
+	if len(records) != 2 {
+		t.Fatalf("expected the request and response to be logged, got %d records", len(records))
+	}
+	for _, record := range records {
+		if record["url"] != "/v1/auth/password-reset/[REDACTED]" {
+			t.Errorf("expected the url to be redacted, got %v", record["url"])
+		}
+	}
+}
+
+func TestMetricsMiddleware_RecordsRoutesAndRejections(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	redis := miniredis.RunT(t)
+	cacheRepo, err := cache.New(context.Background(), &config.Redis{Addrs: []string{redis.Addr()}}, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	t.Cleanup(func() { _ = cacheRepo.Close() })
+
+	metricsMock := metrics.NewMetricsMock()
+	rateLimitCfg := &config.RateLimit{Policies: []config.RateLimitPolicy{{
+		Name:     "login",
+		Routes:   []string{"POST /v1/auth/login"},
+		Limit:    1,
+		Window:   time.Minute,
+		Strategy: config.RateLimitStrategyFixedWindow,
+		KeyBy:    []string{config.RateLimitKeyIP},
+	}}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/auth/login", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("GET /v1/users/{uuid}", func(http.ResponseWriter, *http.Request) {})
+	handler := middleware.ChainHandlerFunc(mux,
+		// The policy keys by IP address only, the token and user services are never called.
+		middleware.RateLimitMiddleware(rateLimitCfg, cacheRepo, nil, nil, metricsMock, errtracker.NewErrTrackerAdapterMock()),
+		middleware.MetricsMiddleware(metricsMock, mux),
+	)
+
+	// Act
+	for _, target := range []string{"POST /v1/auth/login", "POST /v1/auth/login", "GET /v1/users/42", "GET /v1/unknown"} {
+		method, path, _ := strings.Cut(target, " ")
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+	}
+
+	// Assert
+	tests := map[string]struct {
+		name   string
+		labels []string
+	}{
+		"allowed request should be labeled with its route":   {name: "http_requests", labels: []string{"POST", "POST /v1/auth/login", "200"}},
+		"denied request should be labeled with its route":    {name: "http_requests", labels: []string{"POST", "POST /v1/auth/login", "429"}},
+		"route with wildcard should be labeled with pattern": {name: "http_requests", labels: []string{"GET", "GET /v1/users/{uuid}", "200"}},
+		"unknown route should be labeled unmatched":          {name: "http_requests", labels: []string{"GET", "unmatched", "404"}},
+		"denied request should be counted by policy":         {name: "rate_limit_rejections", labels: []string{"login"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := metricsMock.Count(tt.name, tt.labels...); got != 1 {
+				t.Errorf("expected 1 %s%v, got %d", tt.name, tt.labels, got)
+			}
+		})
+	}
+}
+
+func TestTracingMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+	tracerProvider := tracing.NewTracerProviderMock()
+	errTracker := errtracker.NewErrTrackerAdapterMock()
+
+	var logs bytes.Buffer
+	log := slog.New(logger.NewTraceHandler(slog.NewJSONHandler(&logs, nil)))
+
+	var scopeCtx context.Context
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/users/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		log.InfoContext(r.Context(), "handling request")
+		scopeCtx = r.Context()
+	})
+	handler := errTracker.Handle(middleware.TracingMiddleware(tracerProvider, mux, errTracker)(mux))
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/users/42", nil)
+	request.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	// Assert
+	span, ok := tracerProvider.Span("GET /v1/users/{uuid}")
+	if !ok {
+		t.Fatalf("no span named %q recorded", "GET /v1/users/{uuid}")
+	}
+	spanID := span.SpanContext().SpanID().String()
+
+	if got := span.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("expected the span to continue trace %s, got %s", traceID, got)
+	}
+	if got := span.Parent().SpanID().String(); got != parentSpanID {
+		t.Errorf("expected the span to be a child of %s, got %s", parentSpanID, got)
+	}
+	if got := tracing.SpanAttribute(span, "http.route"); got != "/v1/users/{uuid}" {
+		t.Errorf("expected route /v1/users/{uuid}, got %q", got)
+	}
+	if got := tracing.SpanAttribute(span, "http.response.status_code"); got != "200" {
+		t.Errorf("expected status code 200, got %q", got)
+	}
+
+	if gotTraceID, gotSpanID := errTracker.Trace(scopeCtx); gotTraceID != traceID || gotSpanID != spanID {
+		t.Errorf("expected the error tracker scope to hold trace %s and span %s, got %s and %s", traceID, spanID, gotTraceID, gotSpanID)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("failed to decode log record: %v", err)
+	}
+	if record["trace_id"] != traceID || record["span_id"] != spanID {
+		t.Errorf("expected the log record to hold trace %s and span %s, got %v and %v", traceID, spanID, record["trace_id"], record["span_id"])
+	}
+}
@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go-starter/config"
+	"go-starter/internal/adapters/ratelimiter"
+	"go-starter/internal/adapters/server/helpers"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// APIKeyHeaderKey is the header the clients keyed by API key are identified with.
+const APIKeyHeaderKey = "X-API-Key"
+
+// rateLimitPolicy is a rate limit policy with the limiter counting its hits and the routes it applies to.
+type rateLimitPolicy struct {
+	config.RateLimitPolicy
+	limiter *ratelimiter.RateLimiter
+	routes  *http.ServeMux
+}
+
+// matches reports whether the request is to one of the routes of the policy.
+func (p *rateLimitPolicy) matches(r *http.Request) bool {
+	_, pattern := p.routes.Handler(r)
+	return pattern != ""
+}
+
+// rateLimitClient identifies the client of a request.
+// The user and their role are only resolved when a policy needs them.
+type rateLimitClient struct {
+	r        *http.Request
+	tokenSvc ports.TokenService
+	userSvc  ports.UserService
+	apiKeys  map[string]bool
+
+	userResolved bool
+	userID       entities.UserID
+	roleResolved bool
+	role         string
+}
+
+// user returns the ID of the authenticated user, or false if the request is anonymous or its token invalid.
+func (c *rateLimitClient) user() (entities.UserID, bool) {
+	if !c.userResolved {
+		c.userResolved = true
+		if token, err := helpers.ExtractTokenFromHeader(c.r); err == nil {
+			if userID, err := c.tokenSvc.VerifyAuthToken(c.r.Context(), token); err == nil {
+				c.userID = userID
+			}
+		}
+	}
+	return c.userID, c.userID != entities.NilUserID
+}
+
+// roleName returns the name of the role of the authenticated user, or false if it is unknown.
+func (c *rateLimitClient) roleName() (string, bool) {
+	if !c.roleResolved {
+		c.roleResolved = true
+		if userID, ok := c.user(); ok {
+			if user, err := c.userSvc.GetByID(c.r.Context(), userID); err == nil {
+				c.role = user.RoleID.String()
+			}
+		}
+	}
+	return c.role, c.role != ""
+}
+
+// apiKey returns the hash of the API key of the request, or false if the request has none or an unknown one.
+// API keys are hashed not to be stored as is.
+func (c *rateLimitClient) apiKey() (string, bool) {
+	apiKey := c.r.Header.Get(APIKeyHeaderKey)
+	if apiKey == "" {
+		return "", false
+	}
+	hash := hashAPIKey(apiKey)
+	return hash, c.apiKeys[hash]
+}
+
+// key builds the key the client is counted by for the policy.
+// The parts missing from the request are replaced by the IP address, as well as the unknown API keys,
+// so that a client cannot get a new bucket by sending a new key.
+func (c *rateLimitClient) key(policy *rateLimitPolicy) string {
+	parts := make([]string, 0, len(policy.KeyBy))
+	for _, keyBy := range policy.KeyBy {
+		part := "ip:" + helpers.GetClientIPFromContext(c.r.Context())
+		switch keyBy {
+		case config.RateLimitKeyUser:
+			if userID, ok := c.user(); ok {
+				part = "user:" + userID.String()
+			}
+		case config.RateLimitKeyAPIKey:
+			if hash, ok := c.apiKey(); ok {
+				part = "api_key:" + hash
+			}
+		}
+		if !slices.Contains(parts, part) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// limit returns the limit of the policy for the client, the one of the role of the authenticated user if any.
+func (c *rateLimitClient) limit(policy *rateLimitPolicy) int64 {
+	if len(policy.RoleLimits) == 0 {
+		return policy.Limit
+	}
+	if role, ok := c.roleName(); ok {
+		if limit, ok := policy.RoleLimits[role]; ok {
+			return limit
+		}
+	}
+	return policy.Limit
+}
+
+// RateLimitMiddleware creates a middleware that limits the request rate according to the rate limit policies.
+// A request is counted against every policy matching its route, and is denied as soon as one of them is exceeded.
+// The rate limit headers describe the policy with the fewest requests remaining, and denied requests are counted
+// in the metrics by the policy denying them.
+func RateLimitMiddleware(cfg *config.RateLimit, cache ports.CacheRepository, tokenSvc ports.TokenService, userSvc ports.UserService, metrics ports.Metrics, errTracker ports.ErrTrackerAdapter) HandlerMiddleware {
+	apiKeys := make(map[string]bool, len(cfg.APIKeys))
+	for _, apiKey := range cfg.APIKeys {
+		apiKeys[hashAPIKey(apiKey)] = true
+	}
+
+	policies := make([]*rateLimitPolicy, 0, len(cfg.Policies))
+	for _, policyCfg := range cfg.Policies {
+		policy := &rateLimitPolicy{
+			RateLimitPolicy: policyCfg,
+			limiter:         ratelimiter.New(cache, policyCfg.Name, ratelimiter.WithStrategy(ratelimiter.Strategy(policyCfg.Strategy))),
+			routes:          http.NewServeMux(),
+		}
+		for _, route := range policyCfg.Routes {
+			policy.routes.Handle(route, http.NotFoundHandler())
+		}
+		policies = append(policies, policy)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := &rateLimitClient{r: r, tokenSvc: tokenSvc, userSvc: userSvc, apiKeys: apiKeys}
+
+			var tightest *ratelimiter.Result
+			var deniedBy string
+			for _, policy := range policies {
+				if !policy.matches(r) {
+					continue
+				}
+
+				result, err := policy.limiter.Check(r.Context(), client.key(policy), client.limit(policy), policy.Window)
+				if err != nil {
+					errTracker.CaptureException(r.Context(), err)
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					return
+				}
+
+				if tightest == nil || !result.Allowed || result.Limit-result.Current < tightest.Limit-tightest.Current {
+					tightest = result
+				}
+				if !result.Allowed {
+					deniedBy = policy.Name
+					break
+				}
+			}
+
+			if tightest == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(tightest.Limit, 10))
+			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(tightest.Limit-tightest.Current, 10))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(int64(tightest.ResetAfter.Seconds()), 10))
+
+			if !tightest.Allowed {
+				metrics.IncRateLimitRejection(deniedBy)
+				w.Header().Set("Retry-After", strconv.FormatInt(int64(tightest.ResetAfter.Seconds()), 10))
+				w.WriteHeader(http.StatusTooManyRequests)
+
+				response := map[string]interface{}{
+					"error":       "Rate limit exceeded",
+					"retry_after": tightest.ResetAfter.Seconds(),
+				}
+
+				json.NewEncoder(w).Encode(response)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hashAPIKey returns the truncated SHA-256 hash of an API key.
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:16])
+}
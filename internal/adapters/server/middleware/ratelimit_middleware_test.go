@@ -0,0 +1,76 @@
+//go:build !integration
+
+package middleware_test
+
+import (
+	"context"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/metrics"
+	"go-starter/internal/adapters/server/middleware"
+	"go-starter/internal/adapters/storage/cache"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestRateLimitMiddleware_KeysByKnownAPIKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		apiKeys  []string
+		expected []int
+	}{
+		"known API key should be limited":                   {apiKeys: []string{"known", "known"}, expected: []int{http.StatusOK, http.StatusTooManyRequests}},
+		"unknown API keys should be limited by IP address":  {apiKeys: []string{"random-1", "random-2"}, expected: []int{http.StatusOK, http.StatusTooManyRequests}},
+		"known API key should not share the IP address one": {apiKeys: []string{"random-1", "known"}, expected: []int{http.StatusOK, http.StatusOK}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			redis := miniredis.RunT(t)
+			cacheRepo, err := cache.New(context.Background(), &config.Redis{Addrs: []string{redis.Addr()}}, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+			if err != nil {
+				t.Fatalf("failed to connect to redis: %v", err)
+			}
+			t.Cleanup(func() { _ = cacheRepo.Close() })
+
+			rateLimitCfg := &config.RateLimit{
+				Policies: []config.RateLimitPolicy{{
+					Name:     "api",
+					Routes:   []string{"/"},
+					Limit:    1,
+					Window:   time.Minute,
+					Strategy: config.RateLimitStrategyFixedWindow,
+					KeyBy:    []string{config.RateLimitKeyAPIKey},
+				}},
+				APIKeys: []string{"known"},
+			}
+			// The policy keys by API key or IP address only, the token and user services are never called.
+			handler := middleware.RateLimitMiddleware(rateLimitCfg, cacheRepo, nil, nil, metrics.NewMetricsMock(), errtracker.NewErrTrackerAdapterMock())(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+			// Act
+			statuses := make([]int, 0, len(tt.apiKeys))
+			for _, apiKey := range tt.apiKeys {
+				request := httptest.NewRequest(http.MethodGet, "/v1/files", nil)
+				request.Header.Set(middleware.APIKeyHeaderKey, apiKey)
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+				statuses = append(statuses, recorder.Code)
+			}
+
+			// Assert
+			if !slices.Equal(statuses, tt.expected) {
+				t.Errorf("expected statuses %v, got %v", tt.expected, statuses)
+			}
+		})
+	}
+}
@@ -2,92 +2,31 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
 	"go-starter/internal/adapters"
-	"go-starter/internal/adapters/ratelimiter"
+	"go-starter/internal/adapters/logger"
 	"go-starter/internal/adapters/server/helpers"
 	"go-starter/internal/adapters/server/responses"
 	"go-starter/internal/domain"
 	"go-starter/internal/domain/entities"
 	"go-starter/internal/domain/ports"
 	"go-starter/internal/domain/services"
+	"log/slog"
 	"net/http"
 	"slices"
-	"strconv"
-	"strings"
-	"time"
 )
 
 // RouteMiddleware is a middleware that applies route-specific middleware functions to the HTTP request pipeline.
 type RouteMiddleware struct {
-	MailLimiter Middleware
-	Auth        Middleware
-	Admin       Middleware
+	Auth  Middleware
+	Admin Middleware
 }
 
 // NewRouteMiddleware creates a new RouteMiddleware instance.
 // It initializes the middleware components with the provided services and adapters.
 func NewRouteMiddleware(s *services.Services, a *adapters.Adapters) *RouteMiddleware {
-	mailLimiter := ratelimiter.New(a.CacheRepository, "mail")
-	mailLimiterMiddleware := RateLimitMiddleware(mailLimiter, RateLimitConfig{
-		Limit:  1,
-		Window: time.Minute,
-	}, a.ErrTrackerAdapter)
-
 	return &RouteMiddleware{
-		MailLimiter: mailLimiterMiddleware,
-		Auth:        AuthMiddleware(s.TokenService, a.ErrTrackerAdapter),
-		Admin:       RoleMiddleware(s.UserService, AuthMiddleware(s.TokenService, a.ErrTrackerAdapter), entities.RoleAdmin),
-	}
-}
-
-// RateLimitConfig defines configuration for rate limiting
-type RateLimitConfig struct {
-	Limit  int64
-	Window time.Duration
-}
-
-// RateLimitMiddleware creates a middleware that limits request rates
-func RateLimitMiddleware(limiter *ratelimiter.RateLimiter, config RateLimitConfig, errTracker ports.ErrTrackerAdapter) Middleware {
-	return func(next http.HandlerFunc) http.HandlerFunc {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			identifier := r.RemoteAddr
-
-			// Check if we have an X-Forwarded-For header (case of a proxy)
-			if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
-				ips := strings.Split(forwardedFor, ",")
-				if len(ips) > 0 {
-					identifier = strings.TrimSpace(ips[0])
-				}
-			}
-
-			result, err := limiter.Check(r.Context(), identifier, config.Limit, config.Window)
-			if err != nil {
-				errTracker.CaptureException(err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
-
-			w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
-			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(result.Limit-result.Current, 10))
-			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(int64(result.ResetAfter.Seconds()), 10))
-
-			if !result.Allowed {
-				w.Header().Set("Retry-After", strconv.FormatInt(int64(result.ResetAfter.Seconds()), 10))
-				w.WriteHeader(http.StatusTooManyRequests)
-
-				response := map[string]interface{}{
-					"error":       "Rate limit exceeded",
-					"retry_after": result.ResetAfter.Seconds(),
-				}
-
-				json.NewEncoder(w).Encode(response)
-				return
-			}
-
-			// Continue to the next handler if rate limit not exceeded
-			next.ServeHTTP(w, r)
-		})
+		Auth:  AuthMiddleware(s.TokenService, a.ErrTrackerAdapter),
+		Admin: RoleMiddleware(s.UserService, AuthMiddleware(s.TokenService, a.ErrTrackerAdapter), entities.RoleAdmin),
 	}
 }
 
@@ -98,20 +37,20 @@ func RoleMiddleware(userSvc ports.UserService, authMiddleware Middleware, roleID
 		handlerWithAuth := authMiddleware(func(w http.ResponseWriter, r *http.Request) {
 			userID, err := helpers.GetUserIDFromContext(r.Context())
 			if err != nil {
-				responses.HandleError(w, domain.ErrUnauthorized)
+				responses.HandleError(w, r, domain.ErrUnauthorized)
 				return
 			}
 
 			user, err := userSvc.GetByID(r.Context(), userID)
 			if err != nil {
-				responses.HandleError(w, domain.ErrUnauthorized)
+				responses.HandleError(w, r, domain.ErrUnauthorized)
 				return
 			}
 
 			hasValidRole := slices.Contains(roleIDs, user.RoleID)
 
 			if !hasValidRole {
-				responses.HandleError(w, domain.ErrForbidden)
+				responses.HandleError(w, r, domain.ErrForbidden)
 				return
 			}
 
@@ -123,24 +62,25 @@ func RoleMiddleware(userSvc ports.UserService, authMiddleware Middleware, roleID
 }
 
 // AuthMiddleware is a middleware function that validates the authorization token from the incoming HTTP request.
-// It sets the user ID in the context of the HTTP request.
+// It sets the user ID in the context of the HTTP request, as well as a log attribute of the context.
 func AuthMiddleware(tokenSvc ports.TokenService, errTracker ports.ErrTrackerAdapter) Middleware {
 	return func(f http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			accessToken, err := helpers.ExtractTokenFromHeader(r)
 			if err != nil {
-				responses.HandleError(w, err)
+				responses.HandleError(w, r, err)
 				return
 			}
 
 			userID, err := tokenSvc.VerifyAuthToken(r.Context(), accessToken)
 			if err != nil {
-				responses.HandleError(w, err)
+				responses.HandleError(w, r, err)
 				return
 			}
 
-			errTracker.SetUser(userID.String(), r.RemoteAddr)
+			errTracker.SetUser(r.Context(), userID.String(), helpers.GetClientIPFromContext(r.Context()))
 			ctx := context.WithValue(r.Context(), helpers.AuthorizationPayloadKey, userID.String())
+			ctx = logger.WithAttrs(ctx, slog.String(logger.UserIDKey, userID.String()))
 			r = r.WithContext(ctx)
 
 			f(w, r)
@@ -0,0 +1,22 @@
+//go:build !integration
+
+package middleware_test
+
+import (
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/storage/cache"
+	"go-starter/internal/adapters/timegen"
+	"go-starter/internal/adapters/token"
+	"go-starter/internal/domain/ports"
+	"go-starter/internal/domain/services"
+	"time"
+)
+
+// newTokenService creates a token service keeping its tokens in a cache mock.
+func newTokenService() ports.TokenService {
+	timeGenerator := timegen.NewTimeGenerator()
+	cacheSvc := services.NewCacheService(cache.NewCacheRepositoryMock(timeGenerator), timeGenerator)
+	tokenProvider := token.NewTokenProvider(timeGenerator, errtracker.NewErrTrackerAdapterMock())
+	return services.NewTokenService(&config.Token{AccessTokenDuration: time.Hour}, tokenProvider, cacheSvc)
+}
@@ -0,0 +1,48 @@
+package responses
+
+import (
+	"go-starter/internal/domain/entities"
+	"time"
+)
+
+// EmailDeliveryResponse represents the structure of a response body containing an email delivery.
+// The body is left out, it may contain secrets such as verification tokens.
+type EmailDeliveryResponse struct {
+	ID                string    `json:"id" example:"42"`
+	Template          string    `json:"template" example:"verify_email"`
+	Recipients        []string  `json:"recipients" example:"john@example.com"`
+	Subject           string    `json:"subject" example:"Verify your email!"`
+	Redacted          bool      `json:"redacted" example:"true"`
+	Status            string    `json:"status" example:"sent"`
+	ProviderMessageID string    `json:"provider_message_id" example:"010201234abcd-5678efgh-0000-000000"`
+	Error             string    `json:"error" example:""`
+	Attempts          int       `json:"attempts" example:"1"`
+	CreatedAt         time.Time `json:"created_at" example:"2024-08-15T16:23:33.455225Z"`
+	UpdatedAt         time.Time `json:"updated_at" example:"2024-08-15T16:23:34.455225Z"`
+}
+
+// NewEmailDeliveryResponse is a helper function that creates an EmailDeliveryResponse from an email delivery entity.
+func NewEmailDeliveryResponse(delivery *entities.EmailDelivery) EmailDeliveryResponse {
+	return EmailDeliveryResponse{
+		ID:                delivery.ID.String(),
+		Template:          delivery.Template,
+		Recipients:        delivery.Recipients,
+		Subject:           delivery.Subject,
+		Redacted:          delivery.Redacted,
+		Status:            delivery.Status.String(),
+		ProviderMessageID: delivery.ProviderMessageID,
+		Error:             delivery.Error,
+		Attempts:          delivery.Attempts,
+		CreatedAt:         delivery.CreatedAt,
+		UpdatedAt:         delivery.UpdatedAt,
+	}
+}
+
+// NewEmailDeliveriesResponse is a helper function that creates a list of EmailDeliveryResponse from email delivery entities.
+func NewEmailDeliveriesResponse(deliveries []*entities.EmailDelivery) []EmailDeliveryResponse {
+	response := make([]EmailDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, NewEmailDeliveryResponse(delivery))
+	}
+	return response
+}
@@ -0,0 +1,28 @@
+package responses
+
+import (
+	"go-starter/internal/domain/entities"
+	"time"
+)
+
+// EmailSuppressionResponse represents the structure of a response body containing a suppressed email address.
+type EmailSuppressionResponse struct {
+	Email     string    `json:"email" example:"john@example.com"`
+	Reason    string    `json:"reason" example:"bounce"`
+	Details   string    `json:"details" example:"Permanent/General"`
+	CreatedAt time.Time `json:"created_at" example:"2024-08-15T16:23:33.455225Z"`
+}
+
+// NewEmailSuppressionsResponse is a helper function that creates a list of EmailSuppressionResponse from email suppression entities.
+func NewEmailSuppressionsResponse(suppressions []*entities.EmailSuppression) []EmailSuppressionResponse {
+	response := make([]EmailSuppressionResponse, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		response = append(response, EmailSuppressionResponse{
+			Email:     suppression.Email,
+			Reason:    string(suppression.Reason),
+			Details:   suppression.Details,
+			CreatedAt: suppression.CreatedAt,
+		})
+	}
+	return response
+}
@@ -0,0 +1,106 @@
+package responses
+
+import (
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+	"time"
+)
+
+// PresignedUploadResponse represents the structure of a response body containing the request to send to upload a file directly to the storage.
+type PresignedUploadResponse struct {
+	ID        string            `json:"id" example:"0b0f3a4e-3a0e-4d7c-9f43-05b5a1c8d6a2"`
+	URL       string            `json:"url" example:"https://bucket.s3.amazonaws.com/uploads/6b947a32-8919-4974-9ef3-048a556b0b75/0b0f3a4e-3a0e-4d7c-9f43-05b5a1c8d6a2?X-Amz-Signature=..."`
+	Method    string            `json:"method" example:"PUT"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at" example:"2024-08-15T16:38:33Z"`
+}
+
+// NewPresignedUploadResponse is a helper function that creates a PresignedUploadResponse from a pending upload and its presigned request.
+func NewPresignedUploadResponse(upload *entities.PendingUpload, presigned *ports.PresignedUpload) PresignedUploadResponse {
+	headers := make(map[string]string, len(presigned.Headers))
+	for name := range presigned.Headers {
+		headers[name] = presigned.Headers.Get(name)
+	}
+
+	return PresignedUploadResponse{
+		ID:        upload.ID.String(),
+		URL:       presigned.URL,
+		Method:    presigned.Method,
+		Headers:   headers,
+		ExpiresAt: presigned.ExpiresAt,
+	}
+}
+
+// FileResponse represents the structure of a response body containing a stored file.
+// URL is only set for available public files, private ones are downloaded from a signed URL.
+// Status is quarantined until the malware scan of the file passes.
+type FileResponse struct {
+	ID          string    `json:"id" example:"3f1c2a9e-8b7d-4c6e-a5f4-2d1e0b9c8a7f"`
+	Name        string    `json:"name" example:"report.pdf"`
+	ContentType string    `json:"content_type" example:"application/pdf"`
+	Size        int64     `json:"size" example:"204800"`
+	Checksum    string    `json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Visibility  string    `json:"visibility" example:"private"`
+	Status      string    `json:"status" example:"available"`
+	URL         string    `json:"url,omitempty" example:"https://bucket.s3.amazonaws.com/files/6b947a32-8919-4974-9ef3-048a556b0b75/3f1c2a9e-8b7d-4c6e-a5f4-2d1e0b9c8a7f.pdf"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-08-15T16:23:33.455225Z"`
+}
+
+// NewFileResponse is a helper function that creates a FileResponse from a file entity.
+func NewFileResponse(file *entities.File) FileResponse {
+	return FileResponse{
+		ID:          file.ID.String(),
+		Name:        file.Name,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+		Checksum:    file.Checksum,
+		Visibility:  file.Visibility.String(),
+		Status:      file.Status.String(),
+		URL:         file.URL,
+		CreatedAt:   file.CreatedAt,
+	}
+}
+
+// FilesResponse represents the structure of a response body containing a page of the files of a user, and its storage usage.
+type FilesResponse struct {
+	Files []FileResponse       `json:"files"`
+	Usage StorageUsageResponse `json:"usage"`
+}
+
+// StorageUsageResponse represents the storage used by a user and its quota, in bytes.
+type StorageUsageResponse struct {
+	Used  int64 `json:"used" example:"1048576"`
+	Quota int64 `json:"quota" example:"104857600"`
+}
+
+// NewFilesResponse is a helper function that creates a FilesResponse from file entities and the storage usage.
+func NewFilesResponse(files []*entities.File, usage *entities.StorageUsage) FilesResponse {
+	response := FilesResponse{
+		Files: make([]FileResponse, 0, len(files)),
+		Usage: StorageUsageResponse{Used: usage.Used, Quota: usage.Quota},
+	}
+	for _, file := range files {
+		response.Files = append(response.Files, NewFileResponse(file))
+	}
+	return response
+}
+
+// FileDownloadResponse represents the structure of a response body containing a file and the URL it is downloaded from.
+// ExpiresAt is only set for private files, whose URL is signed.
+type FileDownloadResponse struct {
+	File      FileResponse `json:"file"`
+	URL       string       `json:"url" example:"https://bucket.s3.amazonaws.com/private/6b947a32-8919-4974-9ef3-048a556b0b75/3f1c2a9e-8b7d-4c6e-a5f4-2d1e0b9c8a7f.pdf?X-Amz-Signature=..."`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty" example:"2024-08-15T16:38:33Z"`
+}
+
+// NewFileDownloadResponse is a helper function that creates a FileDownloadResponse from a file entity and its link.
+func NewFileDownloadResponse(file *entities.File, link *ports.FileLink) FileDownloadResponse {
+	response := FileDownloadResponse{
+		File: NewFileResponse(file),
+		URL:  link.URL,
+	}
+	if !link.ExpiresAt.IsZero() {
+		response.ExpiresAt = &link.ExpiresAt
+	}
+	return response
+}
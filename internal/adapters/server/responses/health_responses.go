@@ -1,14 +1,75 @@
 package responses
 
-// HealthResponse represents the structure of the health check response.
-type HealthResponse struct {
-	Idle              string `json:"idle" example:"1"`
-	InUse             string `json:"in_use" example:"0"`
-	MaxIdleClosed     string `json:"max_idle_closed" example:"0"`
-	MaxLifetimeClosed string `json:"max_lifetime_closed" example:"0"`
-	Message           string `json:"message" example:"It's healthy'"`
-	OpenConnections   string `json:"open_connections" example:"1"`
-	Status            string `json:"status" example:"up"`
-	WaitCount         string `json:"wait_count" example:"0"`
-	WaitDuration      string `json:"wait_duration" example:"0s"`
+import (
+	"go-starter/internal/domain/ports"
+	"time"
+)
+
+// HealthReportResponse represents the structure of the detailed health check response.
+// The application is degraded when a non-critical dependency is down, and unavailable when a critical one is.
+type HealthReportResponse struct {
+	Status    string                `json:"status" example:"ready"`
+	CheckedAt time.Time             `json:"checked_at" example:"2024-08-15T16:23:33.455225Z"`
+	Checks    []HealthCheckResponse `json:"checks"`
+}
+
+// HealthCheckResponse represents the result of the check of a dependency.
+type HealthCheckResponse struct {
+	Name       string `json:"name" example:"postgres"`
+	Critical   bool   `json:"critical" example:"true"`
+	Status     string `json:"status" example:"up"`
+	Error      string `json:"error,omitempty" example:"dial tcp 127.0.0.1:5432: connect: connection refused"`
+	DurationMs int64  `json:"duration_ms" example:"3"`
+}
+
+// NewHealthReportResponse creates the detailed health check response from the report of the checks.
+func NewHealthReportResponse(report ports.HealthReport) HealthReportResponse {
+	checks := make([]HealthCheckResponse, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, HealthCheckResponse{
+			Name:       check.Name,
+			Critical:   check.Critical,
+			Status:     check.Status,
+			Error:      check.Error,
+			DurationMs: check.Duration.Milliseconds(),
+		})
+	}
+	return HealthReportResponse{
+		Status:    report.Status,
+		CheckedAt: report.CheckedAt,
+		Checks:    checks,
+	}
+}
+
+// CacheStatsResponse represents the statistics of a layer of the cache since the application started.
+// Evictions, Entries and Size (in bytes) are only known for the layers held in memory.
+type CacheStatsResponse struct {
+	Layer     string  `json:"layer" example:"l1"`
+	Hits      int64   `json:"hits" example:"950"`
+	Misses    int64   `json:"misses" example:"50"`
+	HitRatio  float64 `json:"hit_ratio" example:"0.95"`
+	Evictions int64   `json:"evictions" example:"0"`
+	Entries   int64   `json:"entries" example:"120"`
+	Size      int64   `json:"size" example:"48000"`
+}
+
+// NewCacheStatsResponse creates the statistics response of each layer of the cache.
+func NewCacheStatsResponse(stats []ports.CacheStats) []CacheStatsResponse {
+	rsp := make([]CacheStatsResponse, 0, len(stats))
+	for _, s := range stats {
+		var hitRatio float64
+		if total := s.Hits + s.Misses; total > 0 {
+			hitRatio = float64(s.Hits) / float64(total)
+		}
+		rsp = append(rsp, CacheStatsResponse{
+			Layer:     s.Layer,
+			Hits:      s.Hits,
+			Misses:    s.Misses,
+			HitRatio:  hitRatio,
+			Evictions: s.Evictions,
+			Entries:   s.Entries,
+			Size:      s.Size,
+		})
+	}
+	return rsp
 }
@@ -0,0 +1,6 @@
+package responses
+
+// LogLevelResponse represents the structure of a response body containing the minimum level of the logs.
+type LogLevelResponse struct {
+	Level string `json:"level" example:"info"`
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"go-starter/internal/adapters/server/apierrors"
+	"go-starter/internal/adapters/server/helpers"
 	"go-starter/internal/adapters/validator"
+	"go-starter/internal/domain/i18n"
+	"log/slog"
 	"net/http"
 )
 
@@ -24,19 +27,25 @@ func NewSuccessResponse[T any](data T) Response[T] {
 
 // HandleError sends an error response to the client.
 // It determines the appropriate HTTP status code based on the provided error
-// and returns a standardized error response format.
-func HandleError(w http.ResponseWriter, err error) {
+// and returns a standardized error response format, translated in the negotiated locale.
+// Errors answered with a 5xx status are logged with the context of the request, to be correlated with its ID.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
 	status, ok := apierrors.DomainHttpErrMap[err]
 	if !ok {
 		status = http.StatusInternalServerError
 	}
 
+	if status >= http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "request failed", "error", err, "status", status)
+	}
+
 	if status == http.StatusUnprocessableEntity {
-		HandleValidationError(w, []error{err})
+		HandleValidationError(w, r, []error{err})
 		return
 	}
 
-	errResp := NewErrorResponse([]error{err})
+	errResp := NewErrorResponse(helpers.GetLocaleFromContext(r.Context()), []error{err})
+	errResp.RequestID = helpers.GetRequestIDFromContext(r.Context())
 	encoder := json.NewEncoder(w)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -50,20 +59,36 @@ type EmptyResponse struct {
 }
 
 // ErrorResponse represents the format of an error response body.
+// The request ID is the one of the X-Request-ID response header, to be given to the support.
 type ErrorResponse struct {
-	Success  bool     `json:"success" example:"false"`
-	Messages []string `json:"messages" example:"Error message 1,Error message 2"`
+	Success   bool           `json:"success" example:"false"`
+	Messages  []string       `json:"messages" example:"Error message 1,Error message 2"`
+	Errors    []ErrorMessage `json:"errors"`
+	RequestID string         `json:"request_id,omitempty" example:"0b9c6a8e-5f0e-4c1e-9d6a-2f8b7c1d3e4f"`
+}
+
+// ErrorMessage represents a translated error message along with its stable key.
+type ErrorMessage struct {
+	Key     string `json:"key" example:"user_not_found"`
+	Message string `json:"message" example:"user not found"`
 }
 
-// NewErrorResponse is a helper function that creates an error response body from a slice of error messages.
-func NewErrorResponse(errs []error) ErrorResponse {
+// NewErrorResponse is a helper function that creates an error response body from a slice of errors.
+// Messages are translated in the given locale, keys stay the same whatever the locale.
+func NewErrorResponse(locale i18n.Locale, errs []error) ErrorResponse {
 	errsStr := make([]string, len(errs))
+	errMessages := make([]ErrorMessage, len(errs))
 	for i, err := range errs {
-		errsStr[i] = err.Error()
+		errsStr[i] = i18n.TranslateError(locale, err)
+		errMessages[i] = ErrorMessage{
+			Key:     i18n.ErrorKey(err),
+			Message: errsStr[i],
+		}
 	}
 	return ErrorResponse{
 		Success:  false,
 		Messages: errsStr,
+		Errors:   errMessages,
 	}
 }
 
@@ -79,9 +104,10 @@ func HandleSuccess(w http.ResponseWriter, statusCode int, data any) {
 
 // HandleValidationError sends an error response specifically for request validation errors.
 // It sets the appropriate HTTP status code based on the type of validation error.
-func HandleValidationError(w http.ResponseWriter, errs []error) {
+func HandleValidationError(w http.ResponseWriter, r *http.Request, errs []error) {
 	w.Header().Set("Content-Type", "application/json")
-	errRsp := NewErrorResponse(errs)
+	errRsp := NewErrorResponse(helpers.GetLocaleFromContext(r.Context()), errs)
+	errRsp.RequestID = helpers.GetRequestIDFromContext(r.Context())
 
 	if errors.Is(errs[0], validator.ErrInvalidJSON) {
 		w.WriteHeader(http.StatusBadRequest)
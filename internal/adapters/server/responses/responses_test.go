@@ -0,0 +1,57 @@
+//go:build !integration
+
+package responses_test
+
+import (
+	"encoding/json"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/server/helpers"
+	"go-starter/internal/adapters/server/middleware"
+	"go-starter/internal/adapters/server/responses"
+	"go-starter/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleError_IncludesRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err    error
+		status int
+	}{
+		"domain error should carry the request ID":     {err: domain.ErrUserNotFound, status: http.StatusNotFound},
+		"validation error should carry the request ID": {err: domain.ErrUsernameRequired, status: http.StatusUnprocessableEntity},
+		"internal error should carry the request ID":   {err: domain.ErrInternal, status: http.StatusInternalServerError},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /v1/users/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+				responses.HandleError(w, r, tt.err)
+			})
+			handler := middleware.RequestIDMiddleware(mux, errtracker.NewErrTrackerAdapterMock())(mux)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/users/42", nil))
+
+			// Assert
+			if recorder.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, recorder.Code)
+			}
+			var body responses.ErrorResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if expected := recorder.Header().Get(helpers.RequestIDHeaderKey); body.RequestID == "" || body.RequestID != expected {
+				t.Errorf("expected the body to hold request ID %q, got %q", expected, body.RequestID)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"go-starter/config"
 	"go-starter/internal/adapters"
 	"go-starter/internal/adapters/server/handlers"
 	m "go-starter/internal/adapters/server/middleware"
@@ -10,17 +11,23 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func SetupRoutes(h *handlers.Handlers, s *services.Services, a *adapters.Adapters) http.Handler {
+func SetupRoutes(cfg *config.Container, h *handlers.Handlers, s *services.Services, a *adapters.Adapters) http.Handler {
 	mux := http.NewServeMux()
 
 	// Global middleware
-	gm := m.NewGlobalMiddleware(a.ErrTrackerAdapter, a.CacheRepository)
+	gm := m.NewGlobalMiddleware(cfg, s, a, mux)
 	handler := m.ChainHandlerFunc(mux,
 		gm.ErrTracking,
+		gm.Recovery,
 		gm.Logging,
 		gm.RateLimiter,
+		gm.Locale,
 		gm.Security,
 		gm.Cors,
+		gm.ClientIP,
+		gm.Metrics,
+		gm.Tracing,
+		gm.RequestID,
 	)
 	handler = a.ErrTrackerAdapter.Handle(handler)
 
@@ -29,14 +36,31 @@ func SetupRoutes(h *handlers.Handlers, s *services.Services, a *adapters.Adapter
 
 	// Global routes
 	mux.HandleFunc("GET /v1/swagger/", httpSwagger.WrapHandler)
-	mux.HandleFunc("GET /v1/health/postgres", m.Chain(h.HealthHandler.PostgresHealth))
-	mux.HandleFunc("GET /v1/mailer", m.Chain(h.MailerHandler.SendEmail, rm.Admin))
+	mux.HandleFunc("GET /livez", h.HealthHandler.Livez)
+	mux.HandleFunc("GET /readyz", h.HealthHandler.Readyz)
+
+	// File routes
+	mux.HandleFunc("GET /v1/files/{key...}", h.FileHandler.ServeFile)
+	mux.HandleFunc("PUT /v1/files/{key...}", h.FileHandler.UploadFile)
+
+	// Webhook routes
+	mux.HandleFunc("POST /v1/webhooks/ses", h.EmailSuppressionHandler.HandleSESNotification)
+
+	// Admin routes
+	mux.HandleFunc("GET /v1/admin/health", m.Chain(h.HealthHandler.Health, rm.Admin))
+	mux.HandleFunc("GET /v1/admin/health/cache", m.Chain(h.HealthHandler.CacheStats, rm.Admin))
+	mux.HandleFunc("GET /v1/admin/log-level", m.Chain(h.LogHandler.GetLevel, rm.Admin))
+	mux.HandleFunc("PUT /v1/admin/log-level", m.Chain(h.LogHandler.UpdateLevel, rm.Admin))
+	mux.HandleFunc("GET /v1/admin/emails", m.Chain(h.MailerHandler.ListEmails, rm.Admin))
+	mux.HandleFunc("POST /v1/admin/emails/{id}/resend", m.Chain(h.MailerHandler.ResendEmail, rm.Admin))
+	mux.HandleFunc("GET /v1/admin/email-suppressions", m.Chain(h.EmailSuppressionHandler.List, rm.Admin))
+	mux.HandleFunc("DELETE /v1/admin/email-suppressions/{email}", m.Chain(h.EmailSuppressionHandler.Delete, rm.Admin))
 
 	// Auth routes
 	mux.HandleFunc("POST /v1/auth/login", h.AuthHandler.Login)
 	mux.HandleFunc("POST /v1/auth/register", h.AuthHandler.Register)
 	mux.HandleFunc("DELETE /v1/auth/logout", m.Chain(h.AuthHandler.Logout, rm.Auth))
-	mux.HandleFunc("POST /v1/auth/password-reset", m.Chain(h.AuthHandler.SendPasswordResetEmail, rm.MailLimiter))
+	mux.HandleFunc("POST /v1/auth/password-reset", h.AuthHandler.SendPasswordResetEmail)
 	mux.HandleFunc("GET /v1/auth/password-reset/{token}", h.AuthHandler.VerifyPasswordResetToken)
 	mux.HandleFunc("PATCH /v1/auth/password-reset/{token}", h.AuthHandler.ResetPassword)
 
@@ -44,9 +68,16 @@ func SetupRoutes(h *handlers.Handlers, s *services.Services, a *adapters.Adapter
 	mux.HandleFunc("GET /v1/users/me", m.Chain(h.UserHandler.Me, rm.Auth))
 	mux.HandleFunc("POST /v1/users/me/avatar", m.Chain(h.UserHandler.UploadAvatar, rm.Auth))
 	mux.HandleFunc("DELETE /v1/users/me/avatar", m.Chain(h.UserHandler.DeleteAvatar, rm.Auth))
+	mux.HandleFunc("POST /v1/users/me/avatar/uploads", m.Chain(h.UserHandler.PresignAvatarUpload, rm.Auth))
+	mux.HandleFunc("POST /v1/users/me/avatar/uploads/{id}/confirm", m.Chain(h.UserHandler.ConfirmAvatarUpload, rm.Auth))
+	mux.HandleFunc("GET /v1/users/me/files", m.Chain(h.FileHandler.List, rm.Auth))
+	mux.HandleFunc("POST /v1/users/me/files", m.Chain(h.FileHandler.Upload, rm.Auth))
+	mux.HandleFunc("GET /v1/users/me/files/{id}", m.Chain(h.FileHandler.Download, rm.Auth))
+	mux.HandleFunc("DELETE /v1/users/me/files/{id}", m.Chain(h.FileHandler.Delete, rm.Auth))
 	mux.HandleFunc("PATCH /v1/users/me/password", m.Chain(h.UserHandler.UpdatePassword, rm.Auth))
+	mux.HandleFunc("PATCH /v1/users/me/locale", m.Chain(h.UserHandler.UpdateLocale, rm.Auth))
 	mux.HandleFunc("GET /v1/users/me/verify-email/{token}", h.UserHandler.VerifyEmail)
-	mux.HandleFunc("POST /v1/users/me/verify-email/resend", m.Chain(h.UserHandler.ResendEmailVerification, rm.Auth, rm.MailLimiter))
+	mux.HandleFunc("POST /v1/users/me/verify-email/resend", m.Chain(h.UserHandler.ResendEmailVerification, rm.Auth))
 	mux.HandleFunc("GET /v1/users/{uuid}", h.UserHandler.GetByID)
 
 	return handler
@@ -33,8 +33,24 @@ func New(
 	return server
 }
 
+// NewMetrics creates and initializes the HTTP server of the metrics, listening on the admin port.
+func NewMetrics(metricsConfig *config.Metrics, handler http.Handler) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", handler)
+
+	return &Server{
+		Server: &http.Server{
+			Addr:         fmt.Sprintf(":%d", metricsConfig.Port),
+			Handler:      mux,
+			IdleTimeout:  time.Minute,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 30 * time.Second,
+		},
+	}
+}
+
 // Serve starts the HTTP server and listens for incoming requests.
 func (s *Server) Serve() error {
-	slog.Info("starting HTTP server")
+	slog.Info("starting HTTP server", "addr", s.Addr)
 	return s.ListenAndServe()
 }
@@ -15,6 +15,7 @@ import (
 type CacheRepositoryMock struct {
 	data          map[string][]byte
 	timer         map[string]time.Time
+	tags          map[string]map[string]struct{}
 	mu            sync.RWMutex
 	timeGenerator ports.TimeGenerator
 }
@@ -24,18 +25,26 @@ func NewCacheRepositoryMock(timeGenerator ports.TimeGenerator) ports.CacheReposi
 	return &CacheRepositoryMock{
 		data:          make(map[string][]byte),
 		timer:         make(map[string]time.Time),
+		tags:          make(map[string]map[string]struct{}),
 		mu:            sync.RWMutex{},
 		timeGenerator: timeGenerator,
 	}
 }
 
 // Set stores the value in the cache with a specified key and time-to-live (TTL).
+// The key is attached to the tags, so that InvalidateTags removes it.
 // Returns an error if the operation fails (e.g., if the cache is unreachable).
-func (cm *CacheRepositoryMock) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+func (cm *CacheRepositoryMock) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 	cm.data[key] = value
 	cm.timer[key] = cm.timeGenerator.Now().Add(ttl)
+	for _, tag := range tags {
+		if cm.tags[tag] == nil {
+			cm.tags[tag] = make(map[string]struct{})
+		}
+		cm.tags[tag][key] = struct{}{}
+	}
 	return nil
 }
 
@@ -53,6 +62,18 @@ func (cm *CacheRepositoryMock) Get(_ context.Context, key string) ([]byte, error
 	return nil, domain.ErrCacheNotFound
 }
 
+// Touch extends the time-to-live (TTL) of the key if it still exists, without changing its value.
+// Returns domain.ErrCacheNotFound if the key is not found.
+func (cm *CacheRepositoryMock) Touch(_ context.Context, key string, ttl time.Duration, _ ...string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if expiresAt, ok := cm.timer[key]; !ok || !expiresAt.After(cm.timeGenerator.Now()) {
+		return domain.ErrCacheNotFound
+	}
+	cm.timer[key] = cm.timeGenerator.Now().Add(ttl)
+	return nil
+}
+
 // Delete removes the value associated with the specified key from the cache.
 // Returns an error if the operation fails (e.g., if there are issues accessing the cache).
 func (cm *CacheRepositoryMock) Delete(_ context.Context, key string) error {
@@ -82,6 +103,28 @@ func (cm *CacheRepositoryMock) DeleteByPrefix(_ context.Context, prefix string)
 	return nil
 }
 
+// InvalidateTags removes all values from the cache attached to one of the tags.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (cm *CacheRepositoryMock) InvalidateTags(_ context.Context, tags ...string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range cm.tags[tag] {
+			delete(cm.data, key)
+			delete(cm.timer, key)
+		}
+		delete(cm.tags, tag)
+	}
+	return nil
+}
+
+// Ping checks that the cache server is reachable.
+// The in-memory cache is always reachable.
+func (cm *CacheRepositoryMock) Ping(_ context.Context) error {
+	return nil
+}
+
 // Close closes the connection to the cache server, ensuring that all resources are freed.
 // Returns an error if the operation fails (e.g., if there are issues closing the connection).
 func (cm *CacheRepositoryMock) Close() error {
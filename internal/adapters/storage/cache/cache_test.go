@@ -0,0 +1,527 @@
+//go:build !integration
+
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/ratelimiter"
+	"go-starter/internal/adapters/storage/cache"
+	"go-starter/internal/adapters/timegen"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// invalidationTimeout is the maximum time waited for an invalidation published over Redis pub/sub.
+const invalidationTimeout = 5 * time.Second
+
+// newTwoTierCache creates a two-tier cache in front of the Redis server, once its invalidations are received.
+func newTwoTierCache(t *testing.T, redis *miniredis.Miniredis) *cache.TwoTier {
+	t.Helper()
+	redisCfg := &config.Redis{Addrs: []string{redis.Addr()}, BreakerFailureThreshold: 5, BreakerOpenTimeout: time.Minute}
+	cacheCfg := &config.Cache{MaxEntries: 100, MaxSize: 1 << 20, LocalTTL: time.Minute, LocalPrefixes: []string{"user:"}}
+
+	redisCache, err := cache.New(context.Background(), redisCfg, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	local := cache.NewMemory(cacheCfg.MaxEntries, cacheCfg.MaxSize, timegen.NewTimeGenerator())
+	twoTier := cache.NewTwoTier(local, redisCache, redisCache, cacheCfg)
+	t.Cleanup(func() { _ = twoTier.Close() })
+
+	deadline := time.Now().Add(invalidationTimeout)
+	for !twoTier.Synced() {
+		if time.Now().After(deadline) {
+			t.Fatal("the invalidations were never received")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return twoTier
+}
+
+// cacheLayerStats returns the statistics of a layer of the cache.
+func cacheLayerStats(t *testing.T, reporter ports.CacheStatsReporter, layer string) ports.CacheStats {
+	t.Helper()
+	for _, stats := range reporter.Stats() {
+		if stats.Layer == layer {
+			return stats
+		}
+	}
+	t.Fatalf("the cache has no %s layer", layer)
+	return ports.CacheStats{}
+}
+
+func TestMemoryCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		maxEntries int
+		maxSize    int64
+		run        func(t *testing.T, memory *cache.Memory, timeGenerator *timegen.TimeGeneratorMock)
+	}{
+		"should evict the least recently used entry when full": {
+			maxEntries: 2,
+			maxSize:    1 << 10,
+			run: func(t *testing.T, memory *cache.Memory, _ *timegen.TimeGeneratorMock) {
+				_ = memory.Set(ctx, "a", []byte("1"), time.Minute)
+				_ = memory.Set(ctx, "b", []byte("2"), time.Minute)
+				_, _ = memory.Get(ctx, "a")
+				_ = memory.Set(ctx, "c", []byte("3"), time.Minute)
+
+				if _, err := memory.Get(ctx, "b"); !errors.Is(err, domain.ErrCacheNotFound) {
+					t.Errorf("expected b to be evicted, got %v", err)
+				}
+				if _, err := memory.Get(ctx, "a"); err != nil {
+					t.Errorf("expected a to be kept, got %v", err)
+				}
+				if stats := cacheLayerStats(t, memory, "memory"); stats.Evictions != 1 || stats.Entries != 2 {
+					t.Errorf("expected 1 eviction and 2 entries, got %+v", stats)
+				}
+			},
+		},
+		"should evict entries to stay within the size": {
+			maxEntries: 100,
+			maxSize:    10,
+			run: func(t *testing.T, memory *cache.Memory, _ *timegen.TimeGeneratorMock) {
+				_ = memory.Set(ctx, "a", []byte("1111"), time.Minute)
+				_ = memory.Set(ctx, "b", []byte("2222"), time.Minute)
+				_ = memory.Set(ctx, "c", []byte("3333"), time.Minute)
+
+				if _, err := memory.Get(ctx, "a"); !errors.Is(err, domain.ErrCacheNotFound) {
+					t.Errorf("expected a to be evicted, got %v", err)
+				}
+				if stats := cacheLayerStats(t, memory, "memory"); stats.Size != 10 {
+					t.Errorf("expected a size of 10 bytes, got %d", stats.Size)
+				}
+			},
+		},
+		"should not store a value larger than the cache": {
+			maxEntries: 100,
+			maxSize:    10,
+			run: func(t *testing.T, memory *cache.Memory, _ *timegen.TimeGeneratorMock) {
+				_ = memory.Set(ctx, "a", []byte("1111"), time.Minute)
+				_ = memory.Set(ctx, "b", bytes.Repeat([]byte("2"), 20), time.Minute)
+
+				if _, err := memory.Get(ctx, "b"); !errors.Is(err, domain.ErrCacheNotFound) {
+					t.Errorf("expected b not to be stored, got %v", err)
+				}
+				if _, err := memory.Get(ctx, "a"); err != nil {
+					t.Errorf("expected a to be kept, got %v", err)
+				}
+			},
+		},
+		"should expire entries after their TTL": {
+			maxEntries: 100,
+			maxSize:    1 << 10,
+			run: func(t *testing.T, memory *cache.Memory, timeGenerator *timegen.TimeGeneratorMock) {
+				_ = memory.Set(ctx, "a", []byte("1"), time.Minute)
+				timeGenerator.Advance(time.Minute)
+
+				if _, err := memory.Get(ctx, "a"); !errors.Is(err, domain.ErrCacheNotFound) {
+					t.Errorf("expected a to be expired, got %v", err)
+				}
+			},
+		},
+		"should delete the entries of a prefix": {
+			maxEntries: 100,
+			maxSize:    1 << 10,
+			run: func(t *testing.T, memory *cache.Memory, _ *timegen.TimeGeneratorMock) {
+				_ = memory.Set(ctx, "user:1", []byte("1"), time.Minute)
+				_ = memory.Set(ctx, "user:2", []byte("2"), time.Minute)
+				_ = memory.Set(ctx, "token:1", []byte("3"), time.Minute)
+				_ = memory.DeleteByPrefix(ctx, "user:")
+
+				if stats := cacheLayerStats(t, memory, "memory"); stats.Entries != 1 {
+					t.Errorf("expected 1 entry left, got %d", stats.Entries)
+				}
+				if _, err := memory.Get(ctx, "token:1"); err != nil {
+					t.Errorf("expected token:1 to be kept, got %v", err)
+				}
+			},
+		},
+		"should count hits and misses": {
+			maxEntries: 100,
+			maxSize:    1 << 10,
+			run: func(t *testing.T, memory *cache.Memory, _ *timegen.TimeGeneratorMock) {
+				_ = memory.Set(ctx, "a", []byte("1"), time.Minute)
+				_, _ = memory.Get(ctx, "a")
+				_, _ = memory.Get(ctx, "a")
+				_, _ = memory.Get(ctx, "b")
+
+				if stats := cacheLayerStats(t, memory, "memory"); stats.Hits != 2 || stats.Misses != 1 {
+					t.Errorf("expected 2 hits and 1 miss, got %+v", stats)
+				}
+			},
+		},
+		"should delete the entries of a tag": {
+			maxEntries: 100,
+			maxSize:    1 << 10,
+			run: func(t *testing.T, memory *cache.Memory, _ *timegen.TimeGeneratorMock) {
+				_ = memory.Set(ctx, "profile", []byte("1"), time.Minute, "user:1")
+				_ = memory.Set(ctx, "session", []byte("2"), time.Minute, "user:1", "sessions")
+				_ = memory.Set(ctx, "other", []byte("3"), time.Minute, "user:2")
+				_ = memory.InvalidateTags(ctx, "user:1")
+
+				if stats := cacheLayerStats(t, memory, "memory"); stats.Entries != 1 {
+					t.Errorf("expected 1 entry left, got %d", stats.Entries)
+				}
+				if _, err := memory.Get(ctx, "other"); err != nil {
+					t.Errorf("expected other to be kept, got %v", err)
+				}
+			},
+		},
+		"should extend the TTL of an existing entry only": {
+			maxEntries: 100,
+			maxSize:    1 << 10,
+			run: func(t *testing.T, memory *cache.Memory, timeGenerator *timegen.TimeGeneratorMock) {
+				_ = memory.Set(ctx, "session", []byte("1"), time.Minute, "user:1")
+				timeGenerator.Advance(30 * time.Second)
+				if err := memory.Touch(ctx, "session", time.Minute, "user:1"); err != nil {
+					t.Fatalf("failed to touch session: %v", err)
+				}
+				timeGenerator.Advance(45 * time.Second)
+
+				if _, err := memory.Get(ctx, "session"); err != nil {
+					t.Errorf("expected session to be extended, got %v", err)
+				}
+				if err := memory.Touch(ctx, "other", time.Minute); !errors.Is(err, domain.ErrCacheNotFound) {
+					t.Errorf("expected error %v, got %v", domain.ErrCacheNotFound, err)
+				}
+			},
+		},
+		"should report lua scripts as unavailable": {
+			maxEntries: 100,
+			maxSize:    1 << 10,
+			run: func(t *testing.T, memory *cache.Memory, _ *timegen.TimeGeneratorMock) {
+				if _, err := memory.Eval(ctx, "return 1", nil); !errors.Is(err, domain.ErrCacheUnavailable) {
+					t.Errorf("expected error %v, got %v", domain.ErrCacheUnavailable, err)
+				}
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			timeGenerator := timegen.NewTimeGeneratorMock(time.Now())
+			tt.run(t, cache.NewMemory(tt.maxEntries, tt.maxSize, timeGenerator), timeGenerator)
+		})
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	newRedisCache := func(t *testing.T) (*cache.Redis, *miniredis.Miniredis) {
+		t.Helper()
+		redis := miniredis.RunT(t)
+		redisCache, err := cache.New(ctx, &config.Redis{Addrs: []string{redis.Addr()}}, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+		if err != nil {
+			t.Fatalf("failed to connect to redis: %v", err)
+		}
+		t.Cleanup(func() { _ = redisCache.Close() })
+		return redisCache, redis
+	}
+
+	t.Run("prefix deletion should delete every batch", func(t *testing.T) {
+		t.Parallel()
+		redisCache, redis := newRedisCache(t)
+		for i := range 1200 {
+			_ = redis.Set(fmt.Sprintf("user:%d", i), "1")
+		}
+		_ = redis.Set("token:1", "1")
+
+		if err := redisCache.DeleteByPrefix(ctx, "user:"); err != nil {
+			t.Fatalf("failed to delete by prefix: %v", err)
+		}
+		if keys := redis.Keys(); len(keys) != 1 || keys[0] != "token:1" {
+			t.Errorf("expected only token:1 to be kept, got %d keys", len(keys))
+		}
+	})
+
+	t.Run("tag invalidation should delete the tagged keys only", func(t *testing.T) {
+		t.Parallel()
+		redisCache, redis := newRedisCache(t)
+		_ = redisCache.Set(ctx, "profile", []byte("1"), time.Hour, "user:1")
+		_ = redisCache.Set(ctx, "session", []byte("2"), time.Minute, "user:1", "sessions")
+		_ = redisCache.Set(ctx, "other", []byte("3"), time.Hour, "user:2")
+
+		if ttl := redis.TTL("tag:user:1"); ttl <= time.Hour {
+			t.Errorf("expected the tag to outlive its longest-lived key, got a TTL of %v", ttl)
+		}
+		if err := redisCache.InvalidateTags(ctx, "user:1"); err != nil {
+			t.Fatalf("failed to invalidate tags: %v", err)
+		}
+
+		for _, key := range []string{"profile", "session"} {
+			if redis.Exists(key) {
+				t.Errorf("expected %s to be deleted", key)
+			}
+		}
+		if !redis.Exists("other") {
+			t.Error("expected other to be kept")
+		}
+	})
+
+	t.Run("touch should not bring back an invalidated key", func(t *testing.T) {
+		t.Parallel()
+		redisCache, redis := newRedisCache(t)
+		_ = redisCache.Set(ctx, "session", []byte("1"), time.Minute, "user:1")
+
+		if err := redisCache.Touch(ctx, "session", time.Hour, "user:1"); err != nil {
+			t.Fatalf("failed to touch session: %v", err)
+		}
+		if ttl := redis.TTL("session"); ttl != time.Hour {
+			t.Errorf("expected the TTL to be extended to %v, got %v", time.Hour, ttl)
+		}
+		if ttl := redis.TTL("tag:user:1"); ttl <= time.Hour {
+			t.Errorf("expected the tag to outlive the touched key, got a TTL of %v", ttl)
+		}
+
+		_ = redisCache.InvalidateTags(ctx, "user:1")
+		if err := redisCache.Touch(ctx, "session", time.Hour, "user:1"); !errors.Is(err, domain.ErrCacheNotFound) {
+			t.Errorf("expected error %v, got %v", domain.ErrCacheNotFound, err)
+		}
+		if redis.Exists("session") || redis.Exists("tag:user:1") {
+			t.Error("expected the invalidated session not to be brought back")
+		}
+	})
+}
+
+// newTLSCertificate creates a self-signed certificate for localhost, and writes it as a CA bundle in a temporary file.
+func newTLSCertificate(t *testing.T) (tls.Certificate, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err = os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write CA bundle: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+func TestRedisConnectionModes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("cluster mode should run every operation", func(t *testing.T) {
+		t.Parallel()
+		redis := miniredis.RunT(t)
+		redisCfg := &config.Redis{Mode: config.RedisModeCluster, Addrs: []string{redis.Addr()}}
+		redisCache, err := cache.New(ctx, redisCfg, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+		if err != nil {
+			t.Fatalf("failed to connect to the cluster: %v", err)
+		}
+		t.Cleanup(func() { _ = redisCache.Close() })
+
+		_ = redisCache.Set(ctx, "user:1", []byte("alice"), time.Hour, "user:1")
+		_ = redisCache.Set(ctx, "user:2", []byte("bob"), time.Hour)
+		if value, err := redisCache.Get(ctx, "user:1"); err != nil || string(value) != "alice" {
+			t.Fatalf("expected alice, got %q (%v)", value, err)
+		}
+
+		limiter := ratelimiter.New(redisCache, "cluster", ratelimiter.WithStrategy(ratelimiter.StrategySlidingWindowCounter))
+		if result, err := limiter.Check(ctx, "client", 1, time.Minute); err != nil || !result.Allowed {
+			t.Fatalf("expected the first hit to be allowed, got %+v (%v)", result, err)
+		}
+
+		if err = redisCache.InvalidateTags(ctx, "user:1"); err != nil {
+			t.Fatalf("failed to invalidate tags: %v", err)
+		}
+		if err = redisCache.DeleteByPrefix(ctx, "user:"); err != nil {
+			t.Fatalf("failed to delete by prefix: %v", err)
+		}
+		for _, key := range []string{"user:1", "user:2"} {
+			if redis.Exists(key) {
+				t.Errorf("expected %s to be deleted", key)
+			}
+		}
+	})
+
+	t.Run("tls should verify the server against the CA bundle", func(t *testing.T) {
+		t.Parallel()
+		cert, caFile := newTLSCertificate(t)
+		redis, err := miniredis.RunTLS(&tls.Config{Certificates: []tls.Certificate{cert}})
+		if err != nil {
+			t.Fatalf("failed to start redis: %v", err)
+		}
+		t.Cleanup(redis.Close)
+
+		redisCfg := &config.Redis{
+			Addrs:         []string{redis.Addr()},
+			TLSEnabled:    true,
+			TLSCAFile:     caFile,
+			TLSServerName: "localhost",
+			TLSMinVersion: tls.VersionTLS12,
+		}
+		redisCache, err := cache.New(ctx, redisCfg, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+		if err != nil {
+			t.Fatalf("failed to connect over tls: %v", err)
+		}
+		_ = redisCache.Close()
+
+		redisCfg.TLSServerName = "redis.example.com"
+		if _, err = cache.New(ctx, redisCfg, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider()); err == nil {
+			t.Error("expected a server name mismatch to be rejected")
+		}
+	})
+
+	t.Run("tls should reject an invalid CA bundle", func(t *testing.T) {
+		t.Parallel()
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+			t.Fatalf("failed to write CA bundle: %v", err)
+		}
+
+		redisCfg := &config.Redis{Addrs: []string{"localhost:6379"}, TLSEnabled: true, TLSCAFile: caFile}
+		if _, err := cache.NewLazy(redisCfg, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider()); err == nil {
+			t.Error("expected the invalid CA bundle to be rejected")
+		}
+	})
+}
+
+func TestTwoTierCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("local hits should skip redis", func(t *testing.T) {
+		t.Parallel()
+		twoTier := newTwoTierCache(t, miniredis.RunT(t))
+
+		_ = twoTier.Set(ctx, "user:1", []byte("alice"), time.Hour)
+		for range 3 {
+			if _, err := twoTier.Get(ctx, "user:1"); err != nil {
+				t.Fatalf("expected user:1 to be found, got %v", err)
+			}
+		}
+
+		if stats := cacheLayerStats(t, twoTier, "l1"); stats.Hits != 3 {
+			t.Errorf("expected 3 local hits, got %d", stats.Hits)
+		}
+		if stats := cacheLayerStats(t, twoTier, "l2"); stats.Hits != 0 {
+			t.Errorf("expected no redis hit, got %d", stats.Hits)
+		}
+	})
+
+	t.Run("keys without a local prefix should be read from redis", func(t *testing.T) {
+		t.Parallel()
+		twoTier := newTwoTierCache(t, miniredis.RunT(t))
+
+		_ = twoTier.Set(ctx, "token:1", []byte("1"), time.Hour)
+		_, _ = twoTier.Get(ctx, "token:1")
+
+		if stats := cacheLayerStats(t, twoTier, "l1"); stats.Entries != 0 {
+			t.Errorf("expected no local entry, got %d", stats.Entries)
+		}
+		if stats := cacheLayerStats(t, twoTier, "l2"); stats.Hits != 1 {
+			t.Errorf("expected 1 redis hit, got %d", stats.Hits)
+		}
+	})
+
+	t.Run("writes should invalidate the local cache of the other instances", func(t *testing.T) {
+		t.Parallel()
+		redis := miniredis.RunT(t)
+		writer, reader := newTwoTierCache(t, redis), newTwoTierCache(t, redis)
+
+		_ = writer.Set(ctx, "user:1", []byte("alice"), time.Hour)
+		if value, err := reader.Get(ctx, "user:1"); err != nil || string(value) != "alice" {
+			t.Fatalf("expected alice, got %q (%v)", value, err)
+		}
+		_ = writer.Set(ctx, "user:1", []byte("bob"), time.Hour)
+
+		deadline := time.Now().Add(invalidationTimeout)
+		for {
+			value, err := reader.Get(ctx, "user:1")
+			if err == nil && string(value) == "bob" {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the reader to see bob, got %q (%v)", value, err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("tag invalidations should clear the local cache of the other instances", func(t *testing.T) {
+		t.Parallel()
+		redis := miniredis.RunT(t)
+		writer, reader := newTwoTierCache(t, redis), newTwoTierCache(t, redis)
+
+		_ = writer.Set(ctx, "user:1", []byte("alice"), time.Hour, "user:1")
+		_, _ = reader.Get(ctx, "user:1")
+		_ = writer.InvalidateTags(ctx, "user:1")
+
+		deadline := time.Now().Add(invalidationTimeout)
+		for {
+			_, err := reader.Get(ctx, "user:1")
+			if errors.Is(err, domain.ErrCacheNotFound) {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected user:1 to be deleted from the reader, got %v", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("prefix deletions should invalidate the local cache of the other instances", func(t *testing.T) {
+		t.Parallel()
+		redis := miniredis.RunT(t)
+		writer, reader := newTwoTierCache(t, redis), newTwoTierCache(t, redis)
+
+		_ = writer.Set(ctx, "user:1", []byte("alice"), time.Hour)
+		_, _ = reader.Get(ctx, "user:1")
+		_ = writer.DeleteByPrefix(ctx, "user:")
+
+		deadline := time.Now().Add(invalidationTimeout)
+		for {
+			_, err := reader.Get(ctx, "user:1")
+			if errors.Is(err, domain.ErrCacheNotFound) {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected user:1 to be deleted from the reader, got %v", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
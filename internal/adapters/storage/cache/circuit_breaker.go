@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-starter/config"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState string
+
+// Circuit breaker states.
+const (
+	// BreakerClosed lets every call through, counting the consecutive failures.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects every call until the open timeout elapses.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single call through, closing the breaker if it succeeds and opening it again otherwise.
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// CircuitBreaker implements the ports.CacheRepository interface around another cache repository.
+// It stops calling the cache server once it is considered down, failing fast with domain.ErrCacheUnavailable,
+// and probes it periodically so that the application recovers on its own when the server returns.
+// Only the errors wrapping domain.ErrCacheUnavailable are counted as failures.
+type CircuitBreaker struct {
+	repo             ports.CacheRepository
+	timeGenerator    ports.TimeGenerator
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// errBreakerOpen is returned while the breaker is open.
+var errBreakerOpen = fmt.Errorf("%w: circuit breaker open", domain.ErrCacheUnavailable)
+
+// NewCircuitBreaker creates a new instance of CircuitBreaker around the cache repository, initially closed.
+func NewCircuitBreaker(repo ports.CacheRepository, redisCfg *config.Redis, timeGenerator ports.TimeGenerator) *CircuitBreaker {
+	return &CircuitBreaker{
+		repo:             repo,
+		timeGenerator:    timeGenerator,
+		failureThreshold: redisCfg.BreakerFailureThreshold,
+		openTimeout:      redisCfg.BreakerOpenTimeout,
+		state:            BreakerClosed,
+	}
+}
+
+// allow reports whether a call can go through, and whether it is the probe of a half-open breaker.
+func (cb *CircuitBreaker) allow() (bool, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case BreakerOpen:
+		if cb.timeGenerator.Now().Sub(cb.openedAt) < cb.openTimeout {
+			return false, false
+		}
+		cb.state = BreakerHalfOpen
+		slog.Info("cache circuit breaker half-open, probing the cache server")
+		fallthrough
+	case BreakerHalfOpen:
+		if cb.probing {
+			return false, false
+		}
+		cb.probing = true
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+// record updates the state of the breaker with the outcome of a call.
+func (cb *CircuitBreaker) record(err error, probe bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if probe {
+		cb.probing = false
+	}
+
+	// A call canceled by its caller tells nothing about the server.
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if !errors.Is(err, domain.ErrCacheUnavailable) {
+		if cb.state != BreakerClosed {
+			slog.Info("cache circuit breaker closed, the cache server is reachable again")
+		}
+		cb.state = BreakerClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == BreakerHalfOpen || cb.failures >= cb.failureThreshold {
+		if cb.state == BreakerClosed {
+			slog.Warn("cache circuit breaker open, the cache server is unreachable", "error", err)
+		}
+		cb.state = BreakerOpen
+		cb.openedAt = cb.timeGenerator.Now()
+	}
+}
+
+// do runs the call through the breaker.
+func (cb *CircuitBreaker) do(call func() error) error {
+	allowed, probe := cb.allow()
+	if !allowed {
+		return errBreakerOpen
+	}
+
+	err := call()
+	cb.record(err, probe)
+	return err
+}
+
+// Set stores the value in the cache with a specified key and time-to-live (TTL).
+// The key is attached to the tags, so that InvalidateTags removes it.
+// Returns an error if the operation fails (e.g., if the cache is unreachable).
+func (cb *CircuitBreaker) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	return cb.do(func() error {
+		return cb.repo.Set(ctx, key, value, ttl, tags...)
+	})
+}
+
+// Get retrieves the value associated with the specified key from the cache.
+// Returns the value as a byte slice and an error if the key is not found
+// or if there are issues accessing the cache.
+func (cb *CircuitBreaker) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := cb.do(func() error {
+		var err error
+		value, err = cb.repo.Get(ctx, key)
+		return err
+	})
+	return value, err
+}
+
+// Touch extends the time-to-live (TTL) of the key if it still exists, without changing its value.
+// Returns domain.ErrCacheNotFound if the key is not found or if there are issues accessing the cache.
+func (cb *CircuitBreaker) Touch(ctx context.Context, key string, ttl time.Duration, tags ...string) error {
+	return cb.do(func() error {
+		return cb.repo.Touch(ctx, key, ttl, tags...)
+	})
+}
+
+// Delete removes the value associated with the specified key from the cache.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (cb *CircuitBreaker) Delete(ctx context.Context, key string) error {
+	return cb.do(func() error {
+		return cb.repo.Delete(ctx, key)
+	})
+}
+
+// DeleteByPrefix removes all values from the cache that match the given prefix.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (cb *CircuitBreaker) DeleteByPrefix(ctx context.Context, prefix string) error {
+	return cb.do(func() error {
+		return cb.repo.DeleteByPrefix(ctx, prefix)
+	})
+}
+
+// InvalidateTags removes all values from the cache attached to one of the tags.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (cb *CircuitBreaker) InvalidateTags(ctx context.Context, tags ...string) error {
+	return cb.do(func() error {
+		return cb.repo.InvalidateTags(ctx, tags...)
+	})
+}
+
+// Ping checks that the cache server is reachable.
+// Returns an error wrapping domain.ErrCacheUnavailable if it is not, without calling it while the breaker is open.
+func (cb *CircuitBreaker) Ping(ctx context.Context) error {
+	return cb.do(func() error {
+		return cb.repo.Ping(ctx)
+	})
+}
+
+// Close closes the connection to the cache server, ensuring that all resources are freed.
+// Returns an error if the operation fails (e.g., if there are issues closing the connection).
+func (cb *CircuitBreaker) Close() error {
+	return cb.repo.Close()
+}
+
+// Eval executes a Lua script on the cache server.
+// Returns the result of the script and an error if the operation fails (e.g., if there are issues executing the script).
+func (cb *CircuitBreaker) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	var result interface{}
+	err := cb.do(func() error {
+		var err error
+		result, err = cb.repo.Eval(ctx, script, keys, args...)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,85 @@
+//go:build !integration
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/storage/cache"
+	"go-starter/internal/adapters/timegen"
+	"go-starter/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Circuit breaker settings used in tests.
+const (
+	breakerFailureThreshold = 2
+	breakerOpenTimeout      = 10 * time.Second
+)
+
+// newOpenCircuitBreaker creates a circuit breaker in front of the Redis server, then stops the server
+// and fails enough calls for the breaker to open.
+func newOpenCircuitBreaker(t *testing.T) (*cache.CircuitBreaker, *miniredis.Miniredis, *timegen.TimeGeneratorMock) {
+	t.Helper()
+	redis := miniredis.RunT(t)
+	redisCfg := &config.Redis{
+		Addrs:                   []string{redis.Addr()},
+		BreakerFailureThreshold: breakerFailureThreshold,
+		BreakerOpenTimeout:      breakerOpenTimeout,
+	}
+	redisCache, err := cache.New(context.Background(), redisCfg, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	t.Cleanup(func() { _ = redisCache.Close() })
+
+	timeGenerator := timegen.NewTimeGeneratorMock(time.Now())
+	breaker := cache.NewCircuitBreaker(redisCache, redisCfg, timeGenerator)
+
+	redis.Close()
+	for range breakerFailureThreshold {
+		if err := breaker.Ping(context.Background()); !errors.Is(err, domain.ErrCacheUnavailable) {
+			t.Fatalf("expected the cache to be unavailable, got %v", err)
+		}
+	}
+	return breaker, redis, timeGenerator
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("open breaker should fail fast until its timeout", func(t *testing.T) {
+		t.Parallel()
+		breaker, redis, _ := newOpenCircuitBreaker(t)
+
+		if err := redis.Restart(); err != nil {
+			t.Fatalf("failed to restart redis: %v", err)
+		}
+		if err := breaker.Ping(context.Background()); !errors.Is(err, domain.ErrCacheUnavailable) {
+			t.Errorf("expected the open breaker to fail fast with %v, got %v", domain.ErrCacheUnavailable, err)
+		}
+	})
+
+	t.Run("failed probe should keep the breaker open", func(t *testing.T) {
+		t.Parallel()
+		breaker, redis, timeGenerator := newOpenCircuitBreaker(t)
+
+		timeGenerator.Advance(breakerOpenTimeout)
+		if err := breaker.Ping(context.Background()); !errors.Is(err, domain.ErrCacheUnavailable) {
+			t.Fatalf("expected the probe to fail, got %v", err)
+		}
+
+		if err := redis.Restart(); err != nil {
+			t.Fatalf("failed to restart redis: %v", err)
+		}
+		if err := breaker.Ping(context.Background()); !errors.Is(err, domain.ErrCacheUnavailable) {
+			t.Errorf("expected the breaker to be open again after the failed probe, got %v", err)
+		}
+	})
+}
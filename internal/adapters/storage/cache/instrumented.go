@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"time"
+)
+
+// Instrumented implements the ports.CacheRepository interface around another cache repository.
+// It records the result and the duration of every operation in the metrics.
+type Instrumented struct {
+	repo    ports.CacheRepository
+	metrics ports.Metrics
+}
+
+// NewInstrumented creates a new instance of Instrumented around the cache repository.
+func NewInstrumented(repo ports.CacheRepository, metrics ports.Metrics) *Instrumented {
+	return &Instrumented{
+		repo:    repo,
+		metrics: metrics,
+	}
+}
+
+// observe records the operation started at start, a missing key being a miss rather than an error.
+func (i *Instrumented) observe(operation string, start time.Time, err error) {
+	result := ports.CacheResultOK
+	switch {
+	case errors.Is(err, domain.ErrCacheNotFound):
+		result = ports.CacheResultMiss
+	case err != nil:
+		result = ports.CacheResultError
+	case operation == "get":
+		result = ports.CacheResultHit
+	}
+	i.metrics.ObserveCacheOperation(operation, result, time.Since(start))
+}
+
+// Set stores the value in the cache with a specified key and time-to-live (TTL).
+// The key is attached to the tags, so that InvalidateTags removes it.
+// Returns an error if the operation fails (e.g., if the cache is unreachable).
+func (i *Instrumented) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	start := time.Now()
+	err := i.repo.Set(ctx, key, value, ttl, tags...)
+	i.observe("set", start, err)
+	return err
+}
+
+// Get retrieves the value associated with the specified key from the cache.
+// Returns the value as a byte slice and an error if the key is not found
+// or if there are issues accessing the cache.
+func (i *Instrumented) Get(ctx context.Context, key string) ([]byte, error) {
+	start := time.Now()
+	value, err := i.repo.Get(ctx, key)
+	i.observe("get", start, err)
+	return value, err
+}
+
+// Touch extends the time-to-live (TTL) of the key if it still exists, without changing its value.
+// Returns domain.ErrCacheNotFound if the key is not found or if there are issues accessing the cache.
+func (i *Instrumented) Touch(ctx context.Context, key string, ttl time.Duration, tags ...string) error {
+	start := time.Now()
+	err := i.repo.Touch(ctx, key, ttl, tags...)
+	i.observe("touch", start, err)
+	return err
+}
+
+// Delete removes the value associated with the specified key from the cache.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (i *Instrumented) Delete(ctx context.Context, key string) error {
+	start := time.Now()
+	err := i.repo.Delete(ctx, key)
+	i.observe("delete", start, err)
+	return err
+}
+
+// DeleteByPrefix removes all values from the cache that match the given prefix.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (i *Instrumented) DeleteByPrefix(ctx context.Context, prefix string) error {
+	start := time.Now()
+	err := i.repo.DeleteByPrefix(ctx, prefix)
+	i.observe("delete_by_prefix", start, err)
+	return err
+}
+
+// InvalidateTags removes all values from the cache attached to one of the tags.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (i *Instrumented) InvalidateTags(ctx context.Context, tags ...string) error {
+	start := time.Now()
+	err := i.repo.InvalidateTags(ctx, tags...)
+	i.observe("invalidate_tags", start, err)
+	return err
+}
+
+// Ping checks that the cache server is reachable.
+// Health checks are not recorded, they would skew the latency of the operations.
+func (i *Instrumented) Ping(ctx context.Context) error {
+	return i.repo.Ping(ctx)
+}
+
+// Close closes the connection to the cache server, ensuring that all resources are freed.
+// Returns an error if the operation fails (e.g., if there are issues closing the connection).
+func (i *Instrumented) Close() error {
+	return i.repo.Close()
+}
+
+// Eval executes a Lua script on the cache server.
+// Returns the result of the script and an error if the operation fails (e.g., if there are issues executing the script).
+func (i *Instrumented) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	start := time.Now()
+	result, err := i.repo.Eval(ctx, script, keys, args...)
+	i.observe("eval", start, err)
+	return result, err
+}
+
+// Stats returns the hit and miss statistics of the wrapped cache repository, or nil if it does not keep any.
+func (i *Instrumented) Stats() []ports.CacheStats {
+	if reporter, ok := i.repo.(ports.CacheStatsReporter); ok {
+		return reporter.Stats()
+	}
+	return nil
+}
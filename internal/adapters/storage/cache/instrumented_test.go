@@ -0,0 +1,54 @@
+//go:build !integration
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"go-starter/internal/adapters/metrics"
+	"go-starter/internal/adapters/storage/cache"
+	"go-starter/internal/adapters/timegen"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"testing"
+	"time"
+)
+
+func TestInstrumentedCache_RecordsOperations(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	metricsMock := metrics.NewMetricsMock()
+	repo := cache.NewInstrumented(cache.NewCacheRepositoryMock(timegen.NewTimeGenerator()), metricsMock)
+
+	// Act
+	if _, err := repo.Get(ctx, "key"); !errors.Is(err, domain.ErrCacheNotFound) {
+		t.Fatalf("expected error %v, got %v", domain.ErrCacheNotFound, err)
+	}
+	if err := repo.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("failed to set key: %v", err)
+	}
+	if _, err := repo.Get(ctx, "key"); err != nil {
+		t.Fatalf("failed to get key: %v", err)
+	}
+
+	// Assert
+	tests := map[string]struct {
+		operation string
+		result    string
+	}{
+		"missing key should be a miss": {operation: "get", result: ports.CacheResultMiss},
+		"stored value should be a hit": {operation: "get", result: ports.CacheResultHit},
+		"successful set should be ok":  {operation: "set", result: ports.CacheResultOK},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := metricsMock.Count("cache_operations", tt.operation, tt.result); got != 1 {
+				t.Errorf("expected 1 %s operation with result %s, got %d", tt.operation, tt.result, got)
+			}
+		})
+	}
+}
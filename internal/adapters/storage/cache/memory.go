@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Memory implements the ports.CacheRepository interface in the memory of the process.
+// It is a least recently used cache bounded by a number of entries and a size in bytes, each entry having a TTL.
+// It suits deployments running a single instance of the application, and is the local layer of the TwoTier cache.
+// Lua scripts need Redis: Eval reports the cache as unavailable, so the rate limiters count the hits in memory.
+type Memory struct {
+	timeGenerator ports.TimeGenerator
+	maxEntries    int
+	maxSize       int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru holds the entries from the most to the least recently used.
+	lru  *list.List
+	size int64
+	// tags holds the keys attached to each tag.
+	tags map[string]map[string]struct{}
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+// memoryEntry is an entry of the Memory cache.
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+	tags      []string
+}
+
+// size returns the number of bytes the entry is accounted for.
+func (e *memoryEntry) size() int64 {
+	return int64(len(e.key) + len(e.value))
+}
+
+// errEvalUnsupported is returned by Memory.Eval.
+var errEvalUnsupported = fmt.Errorf("%w: lua scripts are not supported by the in-memory cache", domain.ErrCacheUnavailable)
+
+// NewMemory creates a new instance of Memory holding at most maxEntries entries and maxSize bytes of keys and values.
+func NewMemory(maxEntries int, maxSize int64, timeGenerator ports.TimeGenerator) *Memory {
+	return &Memory{
+		timeGenerator: timeGenerator,
+		maxEntries:    maxEntries,
+		maxSize:       maxSize,
+		entries:       map[string]*list.Element{},
+		lru:           list.New(),
+		tags:          map[string]map[string]struct{}{},
+	}
+}
+
+// Set stores the value in the cache with a specified key and time-to-live (TTL).
+// The least recently used entries are evicted to make room for it, a value larger than the cache is not stored.
+// The key is attached to the tags, so that InvalidateTags removes it.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	entry := &memoryEntry{
+		key:       key,
+		value:     append([]byte(nil), value...),
+		expiresAt: m.timeGenerator.Now().Add(ttl),
+		tags:      tags,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		m.remove(elem)
+	}
+	if entry.size() > m.maxSize {
+		return nil
+	}
+
+	for m.lru.Len() > 0 && (len(m.entries) >= m.maxEntries || m.size+entry.size() > m.maxSize) {
+		m.remove(m.lru.Back())
+		m.evictions.Add(1)
+	}
+
+	m.entries[key] = m.lru.PushFront(entry)
+	m.size += entry.size()
+	for _, tag := range tags {
+		if m.tags[tag] == nil {
+			m.tags[tag] = map[string]struct{}{}
+		}
+		m.tags[tag][key] = struct{}{}
+	}
+	return nil
+}
+
+// Get retrieves the value associated with the specified key from the cache.
+// Returns domain.ErrCacheNotFound if the key is not found or expired.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		m.misses.Add(1)
+		return nil, domain.ErrCacheNotFound
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expiresAt.After(m.timeGenerator.Now()) {
+		m.remove(elem)
+		m.misses.Add(1)
+		return nil, domain.ErrCacheNotFound
+	}
+
+	m.lru.MoveToFront(elem)
+	m.hits.Add(1)
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Touch extends the time-to-live (TTL) of the key if it still exists, without changing its value.
+// Returns domain.ErrCacheNotFound if the key is not found or expired.
+func (m *Memory) Touch(_ context.Context, key string, ttl time.Duration, _ ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return domain.ErrCacheNotFound
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	now := m.timeGenerator.Now()
+	if !entry.expiresAt.After(now) {
+		m.remove(elem)
+		return domain.ErrCacheNotFound
+	}
+
+	entry.expiresAt = now.Add(ttl)
+	m.lru.MoveToFront(elem)
+	return nil
+}
+
+// Delete removes the value associated with the specified key from the cache.
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		m.remove(elem)
+	}
+	return nil
+}
+
+// DeleteByPrefix removes all values from the cache that match the given prefix.
+func (m *Memory) DeleteByPrefix(_ context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, elem := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(elem)
+		}
+	}
+	return nil
+}
+
+// InvalidateTags removes all values from the cache attached to one of the tags.
+func (m *Memory) InvalidateTags(_ context.Context, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range m.tags[tag] {
+			m.remove(m.entries[key])
+		}
+	}
+	return nil
+}
+
+// Clear removes all values from the cache.
+func (m *Memory) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = map[string]*list.Element{}
+	m.lru.Init()
+	m.size = 0
+	m.tags = map[string]map[string]struct{}{}
+}
+
+// remove removes an entry and detaches it from its tags, the lock being held.
+func (m *Memory) remove(elem *list.Element) {
+	entry := m.lru.Remove(elem).(*memoryEntry)
+	delete(m.entries, entry.key)
+	m.size -= entry.size()
+	for _, tag := range entry.tags {
+		delete(m.tags[tag], entry.key)
+		if len(m.tags[tag]) == 0 {
+			delete(m.tags, tag)
+		}
+	}
+}
+
+// Ping checks that the cache is reachable, which it always is.
+func (m *Memory) Ping(_ context.Context) error {
+	return nil
+}
+
+// Close frees the resources of the cache, which holds none.
+func (m *Memory) Close() error {
+	return nil
+}
+
+// Eval executes a Lua script, which the in-memory cache does not support.
+// Returns an error wrapping domain.ErrCacheUnavailable.
+func (m *Memory) Eval(_ context.Context, _ string, _ []string, _ ...interface{}) (interface{}, error) {
+	return nil, errEvalUnsupported
+}
+
+// Stats returns the statistics of the cache.
+func (m *Memory) Stats() []ports.CacheStats {
+	return []ports.CacheStats{m.stats("memory")}
+}
+
+// stats returns the statistics of the cache under the given layer name.
+func (m *Memory) stats(layer string) ports.CacheStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return ports.CacheStats{
+		Layer:     layer,
+		Hits:      m.hits.Load(),
+		Misses:    m.misses.Load(),
+		Evictions: m.evictions.Load(),
+		Entries:   int64(len(m.entries)),
+		Size:      m.size,
+	}
+}
@@ -2,48 +2,150 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"go-starter/config"
 	"go-starter/internal/domain"
 	"go-starter/internal/domain/ports"
+	"os"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tagPrefix is the prefix of the sets holding the keys attached to a tag.
+const tagPrefix = "tag:"
+
+// deleteBatchSize is the number of keys scanned or unlinked per round trip.
+const deleteBatchSize = 500
+
 // Redis implements the ports.CacheRepository interface and provides access to the Redis library.
+// The errors of an unreachable server wrap domain.ErrCacheUnavailable, unlike the errors replied by the server.
 type Redis struct {
-	client     *redis.Client
+	client     redis.UniversalClient
 	errTracker ports.ErrTrackerAdapter
 }
 
-// New creates a new instance of Redis.
-func New(ctx context.Context, redisCfg *config.Redis, errTracker ports.ErrTrackerAdapter) (ports.CacheRepository, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     redisCfg.Addr,
-		Password: redisCfg.Password,
-		DB:       redisCfg.DB,
-		// TLSConfig: &tls.Config{
-		// 	MinVersion: tls.VersionTLS12, // TODO: Uncomment this when we have a valid certificate
-		// },
-	})
+// New creates a new instance of Redis, checking that the server is reachable.
+func New(ctx context.Context, redisCfg *config.Redis, errTracker ports.ErrTrackerAdapter, tracerProvider trace.TracerProvider) (*Redis, error) {
+	r, err := NewLazy(redisCfg, errTracker, tracerProvider)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := client.Ping(ctx).Result()
+	err = r.Ping(ctx)
 	if err != nil {
-		errTracker.CaptureException(fmt.Errorf("failed to ping redis: %w", err))
+		_ = r.client.Close()
 		return nil, err
 	}
 
+	return r, nil
+}
+
+// NewLazy creates a new instance of Redis in the configured mode without checking that the server is reachable,
+// the connections being established on first use. Every command is traced in a span of its own.
+// Returns an error if the TLS files cannot be loaded.
+func NewLazy(redisCfg *config.Redis, errTracker ports.ErrTrackerAdapter, tracerProvider trace.TracerProvider) (*Redis, error) {
+	tlsConfig, err := newTLSConfig(redisCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:            redisCfg.Addrs,
+		Username:         redisCfg.Username,
+		Password:         redisCfg.Password,
+		DB:               redisCfg.DB,
+		MasterName:       redisCfg.SentinelMaster,
+		SentinelPassword: redisCfg.SentinelPassword,
+		TLSConfig:        tlsConfig,
+	}
+
+	var client redis.UniversalClient
+	switch redisCfg.Mode {
+	case config.RedisModeSentinel:
+		client = redis.NewFailoverClient(opts.Failover())
+	case config.RedisModeCluster:
+		client = redis.NewClusterClient(opts.Cluster())
+	default:
+		client = redis.NewClient(opts.Simple())
+	}
+	client.AddHook(newTracingHook(tracerProvider))
+
 	return &Redis{client: client, errTracker: errTracker}, nil
 }
 
+// newTLSConfig creates the TLS configuration of the connections to the server.
+// Returns nil if TLS is disabled, or an error if the certificates cannot be loaded.
+func newTLSConfig(redisCfg *config.Redis) (*tls.Config, error) {
+	if !redisCfg.TLSEnabled {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: redisCfg.TLSMinVersion,
+		ServerName: redisCfg.TLSServerName,
+	}
+
+	if redisCfg.TLSCAFile != "" {
+		caBundle, err := os.ReadFile(redisCfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redis CA bundle: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("failed to parse redis CA bundle: no certificate found in %s", redisCfg.TLSCAFile)
+		}
+	}
+
+	if redisCfg.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(redisCfg.TLSCertFile, redisCfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load redis client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// Ping checks that the server is reachable.
+// Returns an error wrapping domain.ErrCacheUnavailable if it is not.
+func (r *Redis) Ping(ctx context.Context) error {
+	err := r.client.Ping(ctx).Err()
+	if err != nil {
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to ping redis: %w", err))
+		return err
+	}
+	return nil
+}
+
 // Set stores the value in the cache with a specified key and time-to-live (TTL).
+// The key is added to the set of each tag, which lives as long as its longest-lived key (Redis 7 or later).
 // Returns an error if the operation fails (e.g., if the cache is unreachable).
-func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
-	err := r.client.Set(ctx, key, value, ttl).Err()
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, value, ttl)
+		for _, tag := range tags {
+			tagKey := tagSetKey(tag)
+			pipe.SAdd(ctx, tagKey, key)
+			if ttl > 0 {
+				// A new set has no expiration for ExpireGT to compare with.
+				pipe.ExpireNX(ctx, tagKey, ttl+time.Second)
+				pipe.ExpireGT(ctx, tagKey, ttl+time.Second)
+			} else {
+				pipe.Persist(ctx, tagKey)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		r.errTracker.CaptureException(fmt.Errorf("failed to set value in redis: %w", err))
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to set value in redis: %w", err))
 		return err
 	}
 	return nil
@@ -58,58 +160,175 @@ func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
 		if errors.Is(err, redis.Nil) {
 			return nil, domain.ErrCacheNotFound
 		}
-		r.errTracker.CaptureException(fmt.Errorf("failed to get value from redis: %w", err))
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to get value from redis: %w", err))
 		return nil, err
 	}
 	return []byte(res), nil
 }
 
+// Touch extends the time-to-live (TTL) of the key if it still exists, without changing its value.
+// The key is not added to the sets of the tags again, their expiration is only extended to outlive it (Redis 7 or later),
+// so that a key removed by InvalidateTags meanwhile is not brought back.
+// Returns domain.ErrCacheNotFound if the key is not found or if there are issues accessing the cache.
+func (r *Redis) Touch(ctx context.Context, key string, ttl time.Duration, tags ...string) error {
+	var exists *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		exists = pipe.Exists(ctx, key)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		} else {
+			pipe.Persist(ctx, key)
+		}
+		for _, tag := range tags {
+			tagKey := tagSetKey(tag)
+			if ttl > 0 {
+				pipe.ExpireGT(ctx, tagKey, ttl+time.Second)
+			} else {
+				pipe.Persist(ctx, tagKey)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to touch value in redis: %w", err))
+		return err
+	}
+	if exists.Val() == 0 {
+		return domain.ErrCacheNotFound
+	}
+	return nil
+}
+
 // Delete removes the value associated with the specified key from the cache.
 // Returns an error if the operation fails (e.g., if there are issues accessing the cache).
 func (r *Redis) Delete(ctx context.Context, key string) error {
 	err := r.client.Del(ctx, key).Err()
 	if err != nil {
-		r.errTracker.CaptureException(fmt.Errorf("failed to delete value from redis: %w", err))
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to delete value from redis: %w", err))
 		return err
 	}
 	return nil
 }
 
 // DeleteByPrefix removes all values from the cache that match the given prefix.
+// The keys are scanned and unlinked in batches, their memory being freed in the background by the server.
+// In cluster mode, the keys of every master are scanned.
 // Returns an error if the operation fails (e.g., if there are issues accessing the cache).
 func (r *Redis) DeleteByPrefix(ctx context.Context, prefix string) error {
+	var err error
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return r.deleteByPrefix(ctx, node, prefix)
+		})
+	} else {
+		err = r.deleteByPrefix(ctx, r.client, prefix)
+	}
+
+	if err != nil {
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to delete values by prefix from redis: %w", err))
+		return err
+	}
+	return nil
+}
+
+// deleteByPrefix scans the keys of a node matching the prefix, and unlinks them in batches.
+func (r *Redis) deleteByPrefix(ctx context.Context, node redis.Cmdable, prefix string) error {
 	var cursor uint64
-	prefix = prefix + "*"
 	for {
-		var err error
-		keys, cursor, err := r.client.Scan(ctx, cursor, prefix, 100).Result()
+		keys, next, err := node.Scan(ctx, cursor, prefix+"*", deleteBatchSize).Result()
 		if err != nil {
-			r.errTracker.CaptureException(fmt.Errorf("failed to scan values from redis: %w", err))
 			return err
 		}
 
-		for _, key := range keys {
-			err := r.client.Del(ctx, key).Err()
-			if err != nil {
-				r.errTracker.CaptureException(fmt.Errorf("failed to delete value from redis: %w", err))
-				return err
-			}
+		err = r.unlink(ctx, keys)
+		if err != nil {
+			return err
 		}
 
+		cursor = next
 		if cursor == 0 {
-			break
+			return nil
 		}
 	}
+}
+
+// InvalidateTags removes all values from the cache attached to one of the tags.
+// The members of the tag sets are read, then unlinked and removed from the sets, in one round trip each.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (r *Redis) InvalidateTags(ctx context.Context, tags ...string) error {
+	members := make([]*redis.StringSliceCmd, len(tags))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, tag := range tags {
+			members[i] = pipe.SMembers(ctx, tagSetKey(tag))
+		}
+		return nil
+	})
+	if err != nil {
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to read tags from redis: %w", err))
+		return err
+	}
 
+	// The keys attached to the tags since they were read stay in the sets.
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, tag := range tags {
+			keys := members[i].Val()
+			for start := 0; start < len(keys); start += deleteBatchSize {
+				batch := keys[start:min(start+deleteBatchSize, len(keys))]
+				for _, key := range batch {
+					pipe.Unlink(ctx, key)
+				}
+				pipe.SRem(ctx, tagSetKey(tag), toInterfaces(batch)...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to invalidate tags in redis: %w", err))
+		return err
+	}
 	return nil
 }
 
+// unlink unlinks the keys in one round trip, one command per key so that they can live in different cluster slots.
+func (r *Redis) unlink(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Unlink(ctx, key)
+		}
+		return nil
+	})
+	return err
+}
+
+// tagSetKey returns the key of the set holding the keys attached to a tag.
+func tagSetKey(tag string) string {
+	return tagPrefix + tag
+}
+
+// toInterfaces converts the keys to the arguments of a command.
+func toInterfaces(keys []string) []interface{} {
+	args := make([]interface{}, len(keys))
+	for i, key := range keys {
+		args[i] = key
+	}
+	return args
+}
+
 // Close closes the connection to the cache server, ensuring that all resources are freed.
 // Returns an error if the operation fails (e.g., if there are issues closing the connection).
 func (r *Redis) Close() error {
 	err := r.client.Close()
 	if err != nil {
-		r.errTracker.CaptureException(fmt.Errorf("failed to close redis connection: %w", err))
+		r.errTracker.CaptureException(context.Background(), fmt.Errorf("failed to close redis connection: %w", err))
 		return err
 	}
 	return nil
@@ -120,8 +339,37 @@ func (r *Redis) Close() error {
 func (r *Redis) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
 	result, err := r.client.Eval(ctx, script, keys, args...).Result()
 	if err != nil {
-		r.errTracker.CaptureException(fmt.Errorf("failed to execute Lua script in redis: %w", err))
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to execute Lua script in redis: %w", err))
 		return nil, err
 	}
 	return result, nil
 }
+
+// Publish sends a message to the subscribers of a channel.
+// Returns an error wrapping domain.ErrCacheUnavailable if the server is unreachable.
+func (r *Redis) Publish(ctx context.Context, channel, message string) error {
+	err := r.client.Publish(ctx, channel, message).Err()
+	if err != nil {
+		err = wrapError(err)
+		r.errTracker.CaptureException(ctx, fmt.Errorf("failed to publish message in redis: %w", err))
+		return err
+	}
+	return nil
+}
+
+// Subscribe subscribes to a channel. The subscription reconnects on its own when the connection is lost.
+// The caller must close it.
+func (r *Redis) Subscribe(ctx context.Context, channel string) *redis.PubSub {
+	return r.client.Subscribe(ctx, channel)
+}
+
+// wrapError wraps the error of an unreachable server with domain.ErrCacheUnavailable.
+// The errors replied by the server, and the cancellation of the request by the caller, are returned as is.
+func wrapError(err error) error {
+	var replyErr redis.Error
+	if errors.As(err, &replyErr) || errors.Is(err, context.Canceled) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", domain.ErrCacheUnavailable, err)
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"go-starter/internal/adapters/tracing"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingHook is a redis.Hook starting a span around every command and pipeline sent to the server.
+// A missing key is not an error of the span.
+type tracingHook struct {
+	tracer trace.Tracer
+}
+
+// newTracingHook creates a new tracingHook with the tracer of the provider.
+func newTracingHook(tracerProvider trace.TracerProvider) *tracingHook {
+	return &tracingHook{tracer: tracerProvider.Tracer(tracing.TracerName)}
+}
+
+// DialHook leaves the connections to the server untraced.
+func (h *tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook traces a command in a span named after it, e.g. "GET".
+func (h *tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		operation := strings.ToUpper(cmd.Name())
+		ctx, span := h.tracer.Start(ctx, operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationName(operation)),
+		)
+		err := next(ctx, cmd)
+		tracing.EndSpan(span, commandError(err))
+		return err
+	}
+}
+
+// ProcessPipelineHook traces the commands of a pipeline in a single span.
+func (h *tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := h.tracer.Start(ctx, "PIPELINE",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemRedis,
+				semconv.DBOperationName("PIPELINE"),
+				attribute.Int("db.operation.batch.size", len(cmds)),
+			),
+		)
+		err := next(ctx, cmds)
+		tracing.EndSpan(span, commandError(err))
+		return err
+	}
+}
+
+// commandError returns the error of a command, nil if the key is missing.
+func commandError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
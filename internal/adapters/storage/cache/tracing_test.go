@@ -0,0 +1,69 @@
+//go:build !integration
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/storage/cache"
+	"go-starter/internal/adapters/tracing"
+	"go-starter/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestRedisCache_TracesCommands(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	tracerProvider := tracing.NewTracerProviderMock()
+	redis := miniredis.RunT(t)
+	redisCache, err := cache.New(ctx, &config.Redis{Addrs: []string{redis.Addr()}}, errtracker.NewErrTrackerAdapterMock(), tracerProvider)
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	t.Cleanup(func() { _ = redisCache.Close() })
+
+	// Act
+	if err := redisCache.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("failed to set key: %v", err)
+	}
+	if _, err := redisCache.Get(ctx, "missing"); !errors.Is(err, domain.ErrCacheNotFound) {
+		t.Fatalf("expected error %v, got %v", domain.ErrCacheNotFound, err)
+	}
+
+	// Assert
+	tests := map[string]struct {
+		name      string
+		operation string
+	}{
+		"pipeline should be traced":         {name: "PIPELINE", operation: "PIPELINE"},
+		"command should be traced":          {name: "GET", operation: "GET"},
+		"connection check should be traced": {name: "PING", operation: "PING"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			span, ok := tracerProvider.Span(tt.name)
+			if !ok {
+				t.Fatalf("no span named %q recorded", tt.name)
+			}
+			if got := tracing.SpanAttribute(span, "db.system"); got != "redis" {
+				t.Errorf("expected db.system redis, got %q", got)
+			}
+			if got := tracing.SpanAttribute(span, "db.operation.name"); got != tt.operation {
+				t.Errorf("expected db.operation.name %s, got %q", tt.operation, got)
+			}
+			if span.Status().Code == codes.Error {
+				t.Errorf("expected the span not to fail, got %v", span.Status())
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"go-starter/config"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"log/slog"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidationChannel is the Redis channel the instances publish the keys to invalidate on.
+const invalidationChannel = "cache:invalidations"
+
+// Invalidation operations.
+const (
+	invalidateKey    = "key"
+	invalidatePrefix = "prefix"
+	invalidateAll    = "all"
+)
+
+// Intervals of the invalidation listener.
+const (
+	// invalidationHealthCheckInterval is the idle time after which the subscription is checked with a ping.
+	invalidationHealthCheckInterval = 30 * time.Second
+	// invalidationRetryInterval is the time waited before receiving again once the subscription is lost.
+	invalidationRetryInterval = time.Second
+)
+
+// TwoTier implements the ports.CacheRepository interface with a local Memory cache (L1) in front of a remote one (L2).
+// The values of the keys with a local prefix are kept in L1 for at most the local TTL, so that the hot keys skip the network.
+// The writes go to L2 first, then every instance is told to drop the key from its L1 over Redis pub/sub.
+// L1 is bypassed, and cleared, whenever the invalidations may have been missed, so that it is never staler than
+// the local TTL. The Lua scripts always run on L2.
+type TwoTier struct {
+	local      *Memory
+	remote     ports.CacheRepository
+	bus        *Redis
+	cacheCfg   *config.Cache
+	instanceID string
+	pubsub     *redis.PubSub
+
+	// synced reports whether the invalidations are received.
+	synced       atomic.Bool
+	remoteHits   atomic.Int64
+	remoteMisses atomic.Int64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTwoTier creates a new instance of TwoTier, and starts listening to the invalidations published on the bus.
+// L1 is bypassed until the subscription is confirmed.
+func NewTwoTier(local *Memory, remote ports.CacheRepository, bus *Redis, cacheCfg *config.Cache) *TwoTier {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &TwoTier{
+		local:      local,
+		remote:     remote,
+		bus:        bus,
+		cacheCfg:   cacheCfg,
+		instanceID: uuid.NewString(),
+		pubsub:     bus.Subscribe(ctx, invalidationChannel),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	go t.listen(ctx)
+	return t
+}
+
+// listen applies the invalidations published by the other instances until the context is canceled.
+// The subscription reconnects on its own; L1 is cleared once it is back, as invalidations may have been missed.
+func (t *TwoTier) listen(ctx context.Context) {
+	defer close(t.done)
+
+	for {
+		msg, err := t.pubsub.ReceiveTimeout(ctx, invalidationHealthCheckInterval)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				if err = t.pubsub.Ping(ctx); err == nil {
+					continue
+				}
+			}
+
+			if t.synced.Swap(false) {
+				slog.Warn("cache invalidations lost, bypassing the local cache", "error", err)
+			}
+			t.local.Clear()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(invalidationRetryInterval):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			t.local.Clear()
+			if !t.synced.Swap(true) {
+				slog.Info("cache invalidations received, using the local cache")
+			}
+		case *redis.Message:
+			t.applyInvalidation(msg.Payload)
+		}
+	}
+}
+
+// applyInvalidation drops the keys of an invalidation published by another instance from L1.
+// Payload format: "<instance ID> <operation> <key or prefix>".
+func (t *TwoTier) applyInvalidation(payload string) {
+	parts := strings.SplitN(payload, " ", 3)
+	if len(parts) != 3 || parts[0] == t.instanceID {
+		return
+	}
+
+	switch parts[1] {
+	case invalidateKey:
+		_ = t.local.Delete(context.Background(), parts[2])
+	case invalidatePrefix:
+		_ = t.local.DeleteByPrefix(context.Background(), parts[2])
+	case invalidateAll:
+		t.local.Clear()
+	}
+}
+
+// Synced reports whether the invalidations published by the other instances are received, L1 being bypassed otherwise.
+func (t *TwoTier) Synced() bool {
+	return t.synced.Load()
+}
+
+// useLocal reports whether L1 can be used for the key: its invalidations must be received and its prefix local.
+func (t *TwoTier) useLocal(key string) bool {
+	if !t.synced.Load() {
+		return false
+	}
+	for _, prefix := range t.cacheCfg.LocalPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// publishInvalidation tells the other instances to drop a key or the keys of a prefix from their L1.
+// A failure only leaves their L1 stale until the local TTL, the error being tracked by the bus.
+func (t *TwoTier) publishInvalidation(ctx context.Context, operation, key string) {
+	_ = t.bus.Publish(ctx, invalidationChannel, t.instanceID+" "+operation+" "+key)
+}
+
+// Set stores the value in the cache with a specified key and time-to-live (TTL).
+// The key is attached to the tags in L2, so that InvalidateTags removes it.
+// Returns an error if the operation fails (e.g., if the cache is unreachable).
+func (t *TwoTier) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	err := t.remote.Set(ctx, key, value, ttl, tags...)
+	if err != nil {
+		_ = t.local.Delete(ctx, key)
+		return err
+	}
+
+	if t.useLocal(key) {
+		_ = t.local.Set(ctx, key, value, min(ttl, t.cacheCfg.LocalTTL))
+	}
+	t.publishInvalidation(ctx, invalidateKey, key)
+	return nil
+}
+
+// Get retrieves the value associated with the specified key from L1, or from L2 on a miss.
+// Returns the value as a byte slice and an error if the key is not found
+// or if there are issues accessing the cache.
+func (t *TwoTier) Get(ctx context.Context, key string) ([]byte, error) {
+	useLocal := t.useLocal(key)
+	if useLocal {
+		if value, err := t.local.Get(ctx, key); err == nil {
+			return value, nil
+		}
+	}
+
+	value, err := t.remote.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, domain.ErrCacheNotFound) {
+			t.remoteMisses.Add(1)
+		}
+		return nil, err
+	}
+	t.remoteHits.Add(1)
+
+	if useLocal {
+		_ = t.local.Set(ctx, key, value, t.cacheCfg.LocalTTL)
+	}
+	return value, nil
+}
+
+// Touch extends the time-to-live (TTL) of the key in L2 if it still exists, without changing its value.
+// The value held in L1 keeps its local TTL, which is not longer than the one of L2.
+// Returns domain.ErrCacheNotFound if the key is not found or if there are issues accessing the cache.
+func (t *TwoTier) Touch(ctx context.Context, key string, ttl time.Duration, tags ...string) error {
+	err := t.remote.Touch(ctx, key, ttl, tags...)
+	if err != nil {
+		_ = t.local.Delete(ctx, key)
+		return err
+	}
+	return nil
+}
+
+// Delete removes the value associated with the specified key from the cache.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (t *TwoTier) Delete(ctx context.Context, key string) error {
+	_ = t.local.Delete(ctx, key)
+	err := t.remote.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	t.publishInvalidation(ctx, invalidateKey, key)
+	return nil
+}
+
+// DeleteByPrefix removes all values from the cache that match the given prefix.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (t *TwoTier) DeleteByPrefix(ctx context.Context, prefix string) error {
+	_ = t.local.DeleteByPrefix(ctx, prefix)
+	err := t.remote.DeleteByPrefix(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	t.publishInvalidation(ctx, invalidatePrefix, prefix)
+	return nil
+}
+
+// InvalidateTags removes all values from the cache attached to one of the tags.
+// The keys of the tags are only known by L2, so every L1 is cleared.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (t *TwoTier) InvalidateTags(ctx context.Context, tags ...string) error {
+	t.local.Clear()
+	err := t.remote.InvalidateTags(ctx, tags...)
+	if err != nil {
+		return err
+	}
+
+	t.publishInvalidation(ctx, invalidateAll, "*")
+	return nil
+}
+
+// Ping checks that L2 is reachable.
+// Returns an error wrapping domain.ErrCacheUnavailable if it is not.
+func (t *TwoTier) Ping(ctx context.Context) error {
+	return t.remote.Ping(ctx)
+}
+
+// Close stops listening to the invalidations and closes the connection to L2.
+// Returns an error if the operation fails (e.g., if there are issues closing the connection).
+func (t *TwoTier) Close() error {
+	t.cancel()
+	// Closing the subscription interrupts the listener waiting for a message.
+	_ = t.pubsub.Close()
+	<-t.done
+	return t.remote.Close()
+}
+
+// Eval executes a Lua script on L2.
+// Returns the result of the script and an error if the operation fails (e.g., if there are issues executing the script).
+func (t *TwoTier) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return t.remote.Eval(ctx, script, keys, args...)
+}
+
+// Stats returns the statistics of L1, then the hits and misses of L2.
+func (t *TwoTier) Stats() []ports.CacheStats {
+	return []ports.CacheStats{
+		t.local.stats("l1"),
+		{Layer: "l2", Hits: t.remoteHits.Load(), Misses: t.remoteMisses.Load()},
+	}
+}
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE email_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    template VARCHAR(50) NOT NULL DEFAULT '',
+    recipients TEXT[] NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    provider_message_id VARCHAR(255) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_email_deliveries_created_at
+    ON email_deliveries (created_at DESC);
+
+CREATE INDEX idx_email_deliveries_status
+    ON email_deliveries (status);
+
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE ON email_deliveries
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS set_updated_at ON email_deliveries;
+DROP INDEX IF EXISTS idx_email_deliveries_status;
+DROP INDEX IF EXISTS idx_email_deliveries_created_at;
+DROP TABLE IF EXISTS email_deliveries;
+-- +goose StatementEnd
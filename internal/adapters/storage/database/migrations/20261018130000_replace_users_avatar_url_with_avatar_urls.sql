@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN avatar_urls JSONB;
+UPDATE users SET avatar_urls = jsonb_build_object('original', avatar_url) WHERE avatar_url IS NOT NULL;
+ALTER TABLE users DROP COLUMN avatar_url;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN avatar_url VARCHAR(255);
+UPDATE users SET avatar_url = COALESCE(avatar_urls->>'512', avatar_urls->>'original') WHERE avatar_urls IS NOT NULL;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_urls;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE files (
+    id UUID PRIMARY KEY,
+    owner_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    key VARCHAR(255) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    visibility VARCHAR(10) NOT NULL CHECK (visibility IN ('public', 'private')),
+    url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_files_owner_id_created_at
+    ON files (owner_id, created_at DESC);
+
+ALTER TABLE users ADD COLUMN avatar_file_ids UUID[] NOT NULL DEFAULT '{}';
+
+-- The existing avatars are recorded as public files, their keys are found in their URLs.
+-- Their size and checksum are unknown, they do not count against the storage quotas.
+INSERT INTO files (id, owner_id, key, name, content_type, size, checksum, visibility, url)
+SELECT gen_random_uuid(), avatar.owner_id, avatar.key, regexp_replace(avatar.key, '^.*/', ''), 'application/octet-stream', 0, '', 'public', avatar.url
+FROM (
+    SELECT users.id AS owner_id, substring(avatar_url.value FROM '(avatars/[^?#]+)') AS key, avatar_url.value AS url
+    FROM users, jsonb_each_text(users.avatar_urls) AS avatar_url
+) AS avatar
+WHERE avatar.key IS NOT NULL
+ON CONFLICT (key) DO NOTHING;
+
+UPDATE users SET avatar_file_ids = ARRAY(
+    SELECT files.id FROM files WHERE files.owner_id = users.id AND files.key LIKE 'avatars/%'
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_file_ids;
+DROP INDEX IF EXISTS idx_files_owner_id_created_at;
+DROP TABLE IF EXISTS files;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The existing files are available, the new ones are held in quarantine until their malware scan passes.
+ALTER TABLE files ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'available' CHECK (status IN ('quarantined', 'available'));
+ALTER TABLE files ALTER COLUMN status SET DEFAULT 'quarantined';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE files DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
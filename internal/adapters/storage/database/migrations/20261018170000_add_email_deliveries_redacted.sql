@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The bodies of the emails sent so far with one-time tokens are dropped, their tokens are not kept.
+ALTER TABLE email_deliveries ADD COLUMN redacted BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE email_deliveries SET body = '', redacted = TRUE WHERE template IN ('reset_password', 'verify_email');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE email_deliveries DROP COLUMN IF EXISTS redacted;
+-- +goose StatementEnd
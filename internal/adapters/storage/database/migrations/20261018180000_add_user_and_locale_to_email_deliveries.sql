@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE email_deliveries ADD COLUMN user_id UUID REFERENCES users (id) ON DELETE SET NULL;
+ALTER TABLE email_deliveries ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT '';
+-- The redacted emails sent so far are assigned to the user of their recipient, so that they can be rendered again.
+UPDATE email_deliveries AS d SET user_id = u.id, locale = u.locale
+FROM users AS u
+WHERE d.redacted AND lower(u.email) = lower(d.recipients[1]);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE email_deliveries DROP COLUMN IF EXISTS locale;
+ALTER TABLE email_deliveries DROP COLUMN IF EXISTS user_id;
+-- +goose StatementEnd
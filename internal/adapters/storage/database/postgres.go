@@ -7,8 +7,6 @@ import (
 	"fmt"
 	"go-starter/config"
 	"go-starter/internal/domain/ports"
-	"log"
-	"strconv"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -27,7 +25,7 @@ func New(ctx context.Context, dbCfg *config.DB, errTracker ports.ErrTrackerAdapt
 
 	db, err := createConnection(ctx, dbCfg, errTracker)
 	if err != nil {
-		errTracker.CaptureException(fmt.Errorf("failed to create database connection: %w", err))
+		errTracker.CaptureException(ctx, fmt.Errorf("failed to create database connection: %w", err))
 		return nil, err
 	}
 
@@ -62,7 +60,7 @@ func Migrate(db *sql.DB, dir string) error {
 func createConnection(c context.Context, dbCfg *config.DB, errTracker ports.ErrTrackerAdapter) (*sql.DB, error) {
 	db, err := sql.Open("postgres", dbCfg.Addr)
 	if err != nil {
-		errTracker.CaptureException(fmt.Errorf("failed to create database connection: %w", err))
+		errTracker.CaptureException(c, fmt.Errorf("failed to create database connection: %w", err))
 		return nil, err
 	}
 	db.SetMaxOpenConns(dbCfg.MaxOpenConns)
@@ -72,66 +70,8 @@ func createConnection(c context.Context, dbCfg *config.DB, errTracker ports.ErrT
 	ctx, cancel := context.WithTimeout(c, 5*time.Second)
 	defer cancel()
 	if err := db.PingContext(ctx); err != nil {
-		errTracker.CaptureException(fmt.Errorf("failed to ping database: %w", err))
+		errTracker.CaptureException(ctx, fmt.Errorf("failed to ping database: %w", err))
 		return nil, err
 	}
 	return db, nil
 }
-
-// Health checks the health status of the database.
-func Health() map[string]string {
-	stats := make(map[string]string)
-
-	if dbInstance == nil {
-		stats["status"] = "down"
-		stats["error"] = "database instance is nil"
-		log.Fatal("database instance is nil")
-		return stats
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-	defer cancel()
-
-	// Ping the database
-	err := dbInstance.PingContext(ctx)
-	if err != nil {
-		stats["status"] = "down"
-		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf("db down: %v", err) // Log the error and terminate the program
-		return stats
-	}
-
-	// Database is up, add more statistics
-	stats["status"] = "up"
-	stats["message"] = "It's healthy"
-
-	// Get database stats (like open connections, in use, idle, etc.)
-	dbStats := dbInstance.Stats()
-
-	stats["open_connections"] = strconv.Itoa(dbStats.OpenConnections)
-	stats["in_use"] = strconv.Itoa(dbStats.InUse)
-	stats["idle"] = strconv.Itoa(dbStats.Idle)
-	stats["wait_count"] = strconv.FormatInt(dbStats.WaitCount, 10)
-	stats["wait_duration"] = dbStats.WaitDuration.String()
-	stats["max_idle_closed"] = strconv.FormatInt(dbStats.MaxIdleClosed, 10)
-	stats["max_lifetime_closed"] = strconv.FormatInt(dbStats.MaxLifetimeClosed, 10)
-
-	// Evaluate stats to provide a health message
-	if dbStats.OpenConnections > 40 { // Assuming 50 is the max for this example
-		stats["message"] = "The database is experiencing heavy load."
-	}
-
-	if dbStats.WaitCount > 1000 {
-		stats["message"] = "The database has a high number of wait events, indicating potential bottlenecks."
-	}
-
-	if dbStats.MaxIdleClosed > int64(dbStats.OpenConnections)/2 {
-		stats["message"] = "Many idle connections are being closed, consider revising the connection pool settings."
-	}
-
-	if dbStats.MaxLifetimeClosed > int64(dbStats.OpenConnections)/2 {
-		stats["message"] = "Many connections are being closed due to max lifetime, consider increasing max lifetime or revising the connection usage pattern."
-	}
-
-	return stats
-}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// EmailDeliveryRepository implements the ports.EmailDeliveryRepository interface and provides access to the database.
+type EmailDeliveryRepository struct {
+	executor   QueryExecutor
+	errTracker ports.ErrTrackerAdapter
+}
+
+// NewEmailDeliveryRepository creates and returns a new EmailDeliveryRepository instance.
+func NewEmailDeliveryRepository(db *sql.DB, errTracker ports.ErrTrackerAdapter) *EmailDeliveryRepository {
+	return &EmailDeliveryRepository{
+		executor:   db,
+		errTracker: errTracker,
+	}
+}
+
+// EmailDeliveryRepository queries
+const (
+	createEmailDeliveryQuery  = `INSERT INTO email_deliveries (template, user_id, locale, recipients, subject, body, redacted, status, provider_message_id, error, attempts) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at`
+	updateEmailDeliveryQuery  = `UPDATE email_deliveries SET recipients = $1, status = $2, provider_message_id = $3, error = $4, attempts = $5 WHERE id = $6 RETURNING updated_at`
+	getEmailDeliveryByIDQuery = `SELECT template, user_id, locale, recipients, subject, body, redacted, status, provider_message_id, error, attempts, created_at, updated_at FROM email_deliveries WHERE id = $1`
+	listEmailDeliveriesQuery  = `SELECT id, template, user_id, locale, recipients, subject, body, redacted, status, provider_message_id, error, attempts, created_at, updated_at FROM email_deliveries
+		WHERE ($1 = '' OR status = $1)
+		AND ($2 = '' OR template = $2)
+		AND ($3 = '' OR EXISTS (SELECT 1 FROM unnest(recipients) AS recipient WHERE lower(recipient) = lower($3)))
+		ORDER BY created_at DESC, id DESC LIMIT $4 OFFSET $5`
+)
+
+// Create inserts an email delivery and sets its ID and timestamps.
+func (r *EmailDeliveryRepository) Create(ctx context.Context, delivery *entities.EmailDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := r.executor.QueryRowContext(ctx, createEmailDeliveryQuery,
+		delivery.Template,
+		uuid.NullUUID{UUID: delivery.UserID.UUID(), Valid: delivery.UserID != entities.NilUserID},
+		delivery.Locale,
+		pq.Array(delivery.Recipients),
+		delivery.Subject,
+		delivery.Body,
+		delivery.Redacted,
+		delivery.Status.String(),
+		delivery.ProviderMessageID,
+		delivery.Error,
+		delivery.Attempts,
+	).Scan(&delivery.ID, &delivery.CreatedAt, &delivery.UpdatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to create email delivery: %w", err)
+		r.errTracker.CaptureException(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// Update saves the recipients, status, provider message ID, error and attempt count of an email delivery.
+// Returns domain.ErrEmailDeliveryNotFound if the delivery does not exist.
+func (r *EmailDeliveryRepository) Update(ctx context.Context, delivery *entities.EmailDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := r.executor.QueryRowContext(ctx, updateEmailDeliveryQuery,
+		pq.Array(delivery.Recipients),
+		delivery.Status.String(),
+		delivery.ProviderMessageID,
+		delivery.Error,
+		delivery.Attempts,
+		int64(delivery.ID),
+	).Scan(&delivery.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return domain.ErrEmailDeliveryNotFound
+		default:
+			err = fmt.Errorf("failed to update email delivery %s: %w", delivery.ID, err)
+			r.errTracker.CaptureException(ctx, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByID retrieves an email delivery by its ID.
+// Returns domain.ErrEmailDeliveryNotFound if the delivery does not exist.
+func (r *EmailDeliveryRepository) GetByID(ctx context.Context, id entities.EmailDeliveryID) (*entities.EmailDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	delivery := &entities.EmailDelivery{ID: id}
+	var userID uuid.NullUUID
+	err := r.executor.QueryRowContext(ctx, getEmailDeliveryByIDQuery, int64(id)).Scan(
+		&delivery.Template,
+		&userID,
+		&delivery.Locale,
+		pq.Array(&delivery.Recipients),
+		&delivery.Subject,
+		&delivery.Body,
+		&delivery.Redacted,
+		&delivery.Status,
+		&delivery.ProviderMessageID,
+		&delivery.Error,
+		&delivery.Attempts,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrEmailDeliveryNotFound
+		default:
+			err = fmt.Errorf("failed to get email delivery %s: %w", id, err)
+			r.errTracker.CaptureException(ctx, err)
+			return nil, err
+		}
+	}
+	delivery.UserID = entities.UserID(userID.UUID)
+	return delivery, nil
+}
+
+// List returns the email deliveries matching the filter, most recent first.
+func (r *EmailDeliveryRepository) List(ctx context.Context, filter entities.EmailDeliveryFilter) ([]*entities.EmailDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := r.executor.QueryContext(ctx, listEmailDeliveriesQuery,
+		filter.Status.String(),
+		filter.Template,
+		filter.Recipient,
+		filter.Limit,
+		filter.Offset,
+	)
+	if err != nil {
+		err = fmt.Errorf("failed to list email deliveries: %w", err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*entities.EmailDelivery, 0, filter.Limit)
+	for rows.Next() {
+		delivery := &entities.EmailDelivery{}
+		var userID uuid.NullUUID
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.Template,
+			&userID,
+			&delivery.Locale,
+			pq.Array(&delivery.Recipients),
+			&delivery.Subject,
+			&delivery.Body,
+			&delivery.Redacted,
+			&delivery.Status,
+			&delivery.ProviderMessageID,
+			&delivery.Error,
+			&delivery.Attempts,
+			&delivery.CreatedAt,
+			&delivery.UpdatedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to scan email delivery: %w", err)
+			r.errTracker.CaptureException(ctx, err)
+			return nil, err
+		}
+		delivery.UserID = entities.UserID(userID.UUID)
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to iterate email deliveries: %w", err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"cmp"
+	"context"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EmailDeliveryRepositoryMock implements the ports.EmailDeliveryRepository interface with an in-memory store.
+type EmailDeliveryRepositoryMock struct {
+	data   map[entities.EmailDeliveryID]*entities.EmailDelivery
+	lastID entities.EmailDeliveryID
+	mu     sync.RWMutex
+}
+
+// NewEmailDeliveryRepositoryMock creates and returns a new mock instance of an email delivery repository.
+func NewEmailDeliveryRepositoryMock() *EmailDeliveryRepositoryMock {
+	return &EmailDeliveryRepositoryMock{
+		data: map[entities.EmailDeliveryID]*entities.EmailDelivery{},
+		mu:   sync.RWMutex{},
+	}
+}
+
+// Create inserts an email delivery and sets its ID and timestamps.
+func (r *EmailDeliveryRepositoryMock) Create(_ context.Context, delivery *entities.EmailDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	now := time.Now()
+	delivery.ID = r.lastID
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+	r.data[delivery.ID] = copyEmailDelivery(delivery)
+	return nil
+}
+
+// Update saves the recipients, status, provider message ID, error and attempt count of an email delivery.
+// Returns domain.ErrEmailDeliveryNotFound if the delivery does not exist.
+func (r *EmailDeliveryRepositoryMock) Update(_ context.Context, delivery *entities.EmailDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.data[delivery.ID]
+	if !ok {
+		return domain.ErrEmailDeliveryNotFound
+	}
+
+	delivery.UpdatedAt = time.Now()
+	existing.Recipients = slices.Clone(delivery.Recipients)
+	existing.Status = delivery.Status
+	existing.ProviderMessageID = delivery.ProviderMessageID
+	existing.Error = delivery.Error
+	existing.Attempts = delivery.Attempts
+	existing.UpdatedAt = delivery.UpdatedAt
+	return nil
+}
+
+// GetByID retrieves an email delivery by its ID.
+// Returns domain.ErrEmailDeliveryNotFound if the delivery does not exist.
+func (r *EmailDeliveryRepositoryMock) GetByID(_ context.Context, id entities.EmailDeliveryID) (*entities.EmailDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	delivery, ok := r.data[id]
+	if !ok {
+		return nil, domain.ErrEmailDeliveryNotFound
+	}
+	return copyEmailDelivery(delivery), nil
+}
+
+// List returns the email deliveries matching the filter, most recent first.
+func (r *EmailDeliveryRepositoryMock) List(_ context.Context, filter entities.EmailDeliveryFilter) ([]*entities.EmailDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := make([]*entities.EmailDelivery, 0, len(r.data))
+	for _, v := range r.data {
+		if filter.Status != "" && v.Status != filter.Status {
+			continue
+		}
+		if filter.Template != "" && v.Template != filter.Template {
+			continue
+		}
+		if filter.Recipient != "" && !slices.ContainsFunc(v.Recipients, func(recipient string) bool {
+			return strings.EqualFold(recipient, filter.Recipient)
+		}) {
+			continue
+		}
+		deliveries = append(deliveries, copyEmailDelivery(v))
+	}
+	slices.SortFunc(deliveries, func(a, b *entities.EmailDelivery) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	if filter.Offset >= len(deliveries) {
+		return []*entities.EmailDelivery{}, nil
+	}
+	return deliveries[filter.Offset:min(filter.Offset+filter.Limit, len(deliveries))], nil
+}
+
+// copyEmailDelivery returns a copy of the delivery so that callers cannot alter the stored value.
+func copyEmailDelivery(delivery *entities.EmailDelivery) *entities.EmailDelivery {
+	c := *delivery
+	c.Recipients = slices.Clone(delivery.Recipients)
+	return &c
+}
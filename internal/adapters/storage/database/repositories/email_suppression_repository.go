@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// EmailSuppressionRepository implements the ports.EmailSuppressionRepository interface and provides access to the database.
+type EmailSuppressionRepository struct {
+	executor   QueryExecutor
+	errTracker ports.ErrTrackerAdapter
+}
+
+// NewEmailSuppressionRepository creates and returns a new EmailSuppressionRepository instance.
+func NewEmailSuppressionRepository(db *sql.DB, errTracker ports.ErrTrackerAdapter) *EmailSuppressionRepository {
+	return &EmailSuppressionRepository{
+		executor:   db,
+		errTracker: errTracker,
+	}
+}
+
+// EmailSuppressionRepository queries
+const (
+	upsertEmailSuppressionQuery = `INSERT INTO email_suppressions (email, reason, details) VALUES ($1, $2, $3) ON CONFLICT (email) DO UPDATE SET reason = EXCLUDED.reason, details = EXCLUDED.details`
+	getSuppressedEmailsQuery    = `SELECT email FROM email_suppressions WHERE email = ANY($1)`
+	listEmailSuppressionsQuery  = `SELECT email, reason, details, created_at FROM email_suppressions ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	deleteEmailSuppressionQuery = `DELETE FROM email_suppressions WHERE email = $1`
+)
+
+// Upsert inserts an email suppression, or updates the reason and details if the address is already suppressed.
+func (r *EmailSuppressionRepository) Upsert(ctx context.Context, suppression *entities.EmailSuppression) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := r.executor.ExecContext(ctx, upsertEmailSuppressionQuery, suppression.Email, suppression.Reason.String(), suppression.Details)
+	if err != nil {
+		err = fmt.Errorf("failed to upsert email suppression for %s: %w", suppression.Email, err)
+		r.errTracker.CaptureException(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// GetSuppressed returns the subset of the given email addresses that are suppressed.
+// The comparison is case-insensitive, the addresses are returned as given.
+func (r *EmailSuppressionRepository) GetSuppressed(ctx context.Context, emails []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	byNormalized := make(map[string][]string, len(emails))
+	normalized := make([]string, 0, len(emails))
+	for _, email := range emails {
+		key := strings.ToLower(strings.TrimSpace(email))
+		if _, ok := byNormalized[key]; !ok {
+			normalized = append(normalized, key)
+		}
+		byNormalized[key] = append(byNormalized[key], email)
+	}
+
+	rows, err := r.executor.QueryContext(ctx, getSuppressedEmailsQuery, pq.Array(normalized))
+	if err != nil {
+		err = fmt.Errorf("failed to get suppressed emails: %w", err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suppressed []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			err = fmt.Errorf("failed to scan suppressed email: %w", err)
+			r.errTracker.CaptureException(ctx, err)
+			return nil, err
+		}
+		suppressed = append(suppressed, byNormalized[email]...)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to iterate suppressed emails: %w", err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	return suppressed, nil
+}
+
+// List returns the suppressed email addresses, most recent first.
+func (r *EmailSuppressionRepository) List(ctx context.Context, limit, offset int) ([]*entities.EmailSuppression, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := r.executor.QueryContext(ctx, listEmailSuppressionsQuery, limit, offset)
+	if err != nil {
+		err = fmt.Errorf("failed to list email suppressions: %w", err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppressions := make([]*entities.EmailSuppression, 0, limit)
+	for rows.Next() {
+		suppression := &entities.EmailSuppression{}
+		if err := rows.Scan(&suppression.Email, &suppression.Reason, &suppression.Details, &suppression.CreatedAt); err != nil {
+			err = fmt.Errorf("failed to scan email suppression: %w", err)
+			r.errTracker.CaptureException(ctx, err)
+			return nil, err
+		}
+		suppressions = append(suppressions, suppression)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to iterate email suppressions: %w", err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	return suppressions, nil
+}
+
+// Delete removes an email address from the suppression list.
+// Returns domain.ErrEmailSuppressionNotFound if the address is not suppressed.
+func (r *EmailSuppressionRepository) Delete(ctx context.Context, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	result, err := r.executor.ExecContext(ctx, deleteEmailSuppressionQuery, email)
+	if err != nil {
+		err = fmt.Errorf("failed to delete email suppression for %s: %w", email, err)
+		r.errTracker.CaptureException(ctx, err)
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("failed to get affected rows for email suppression %s: %w", email, err)
+		r.errTracker.CaptureException(ctx, err)
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrEmailSuppressionNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EmailSuppressionRepositoryMock implements the ports.EmailSuppressionRepository interface with an in-memory store.
+type EmailSuppressionRepositoryMock struct {
+	data map[string]*entities.EmailSuppression
+	mu   sync.RWMutex
+}
+
+// NewEmailSuppressionRepositoryMock creates and returns a new mock instance of an email suppression repository.
+func NewEmailSuppressionRepositoryMock() *EmailSuppressionRepositoryMock {
+	return &EmailSuppressionRepositoryMock{
+		data: map[string]*entities.EmailSuppression{},
+		mu:   sync.RWMutex{},
+	}
+}
+
+// Upsert inserts an email suppression, or updates the reason and details if the address is already suppressed.
+func (r *EmailSuppressionRepositoryMock) Upsert(_ context.Context, suppression *entities.EmailSuppression) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.data[suppression.Email]; ok {
+		existing.Reason = suppression.Reason
+		existing.Details = suppression.Details
+		return nil
+	}
+
+	r.data[suppression.Email] = &entities.EmailSuppression{
+		Email:     suppression.Email,
+		Reason:    suppression.Reason,
+		Details:   suppression.Details,
+		CreatedAt: time.Now(),
+	}
+	return nil
+}
+
+// GetSuppressed returns the subset of the given email addresses that are suppressed.
+func (r *EmailSuppressionRepositoryMock) GetSuppressed(_ context.Context, emails []string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var suppressed []string
+	for _, email := range emails {
+		if _, ok := r.data[strings.ToLower(strings.TrimSpace(email))]; ok {
+			suppressed = append(suppressed, email)
+		}
+	}
+	return suppressed, nil
+}
+
+// List returns the suppressed email addresses, most recent first.
+func (r *EmailSuppressionRepositoryMock) List(_ context.Context, limit, offset int) ([]*entities.EmailSuppression, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	suppressions := make([]*entities.EmailSuppression, 0, len(r.data))
+	for _, v := range r.data {
+		suppressions = append(suppressions, v)
+	}
+	slices.SortFunc(suppressions, func(a, b *entities.EmailSuppression) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	if offset >= len(suppressions) {
+		return []*entities.EmailSuppression{}, nil
+	}
+	return suppressions[offset:min(offset+limit, len(suppressions))], nil
+}
+
+// Delete removes an email address from the suppression list.
+// Returns domain.ErrEmailSuppressionNotFound if the address is not suppressed.
+func (r *EmailSuppressionRepositoryMock) Delete(_ context.Context, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.data[email]; !ok {
+		return domain.ErrEmailSuppressionNotFound
+	}
+	delete(r.data, email)
+	return nil
+}
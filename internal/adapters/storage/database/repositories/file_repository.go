@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+
+	"github.com/google/uuid"
+)
+
+// FileRepository implements the ports.FileRepository interface and provides access to the database.
+type FileRepository struct {
+	executor   QueryExecutor
+	errTracker ports.ErrTrackerAdapter
+}
+
+// NewFileRepository creates and returns a new FileRepository instance.
+func NewFileRepository(db *sql.DB, errTracker ports.ErrTrackerAdapter) *FileRepository {
+	return &FileRepository{
+		executor:   db,
+		errTracker: errTracker,
+	}
+}
+
+// FileRepository queries
+const (
+	lockFileOwnerQuery = `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`
+	createFileQuery    = `INSERT INTO files (id, owner_id, key, name, content_type, size, checksum, visibility, status, url)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		WHERE (SELECT COALESCE(SUM(size), 0) FROM files WHERE owner_id = $2) + $6 <= $11
+		RETURNING created_at`
+	getFileByIDQuery       = `SELECT owner_id, key, name, content_type, size, checksum, visibility, status, url, created_at FROM files WHERE id = $1`
+	listFilesByOwnerQuery  = `SELECT id, owner_id, key, name, content_type, size, checksum, visibility, status, url, created_at FROM files WHERE owner_id = $1 ORDER BY created_at DESC, id LIMIT $2 OFFSET $3`
+	getFileOwnerUsageQuery = `SELECT COALESCE(SUM(size), 0) FROM files WHERE owner_id = $1`
+	markFileAvailableQuery = `UPDATE files SET status = 'available', url = $2 WHERE id = $1`
+	deleteFileQuery        = `DELETE FROM files WHERE id = $1`
+)
+
+// Create inserts a file, unless the total size of the files of its owner would then exceed quota bytes.
+// The owner is locked during the insertion, so that concurrent uploads cannot exceed the quota together.
+func (r *FileRepository) Create(ctx context.Context, file *entities.File, quota int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(r.executor.(*sql.DB), ctx, r.errTracker, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, lockFileOwnerQuery, file.OwnerID.String())
+		if err != nil {
+			err = fmt.Errorf("failed to lock owner of file %s: %w", file.Key, err)
+			r.errTracker.CaptureException(ctx, err)
+			return err
+		}
+
+		err = tx.QueryRowContext(
+			ctx,
+			createFileQuery,
+			file.ID.String(),
+			file.OwnerID.String(),
+			file.Key,
+			file.Name,
+			file.ContentType,
+			file.Size,
+			file.Checksum,
+			file.Visibility.String(),
+			file.Status.String(),
+			file.URL,
+			quota,
+		).Scan(&file.CreatedAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrStorageQuotaExceeded
+			}
+			err = fmt.Errorf("failed to insert file %s: %w", file.Key, err)
+			r.errTracker.CaptureException(ctx, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetByID returns a file.
+// Returns domain.ErrFileNotFound if the file does not exist.
+func (r *FileRepository) GetByID(ctx context.Context, id entities.FileID) (*entities.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	file := &entities.File{ID: id}
+	var ownerID uuid.UUID
+	err := r.executor.QueryRowContext(ctx, getFileByIDQuery, id.String()).Scan(
+		&ownerID,
+		&file.Key,
+		&file.Name,
+		&file.ContentType,
+		&file.Size,
+		&file.Checksum,
+		&file.Visibility,
+		&file.Status,
+		&file.URL,
+		&file.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrFileNotFound
+		}
+		err = fmt.Errorf("failed to get file %s: %w", id.String(), err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	file.OwnerID = entities.UserID(ownerID)
+	return file, nil
+}
+
+// ListByOwner returns the files of a user, most recent first.
+func (r *FileRepository) ListByOwner(ctx context.Context, ownerID entities.UserID, limit, offset int) ([]*entities.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := r.executor.QueryContext(ctx, listFilesByOwnerQuery, ownerID.String(), limit, offset)
+	if err != nil {
+		err = fmt.Errorf("failed to list files of user %s: %w", ownerID.String(), err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := []*entities.File{}
+	for rows.Next() {
+		file := &entities.File{}
+		var id, fileOwnerID uuid.UUID
+		err := rows.Scan(
+			&id,
+			&fileOwnerID,
+			&file.Key,
+			&file.Name,
+			&file.ContentType,
+			&file.Size,
+			&file.Checksum,
+			&file.Visibility,
+			&file.Status,
+			&file.URL,
+			&file.CreatedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to scan file: %w", err)
+			r.errTracker.CaptureException(ctx, err)
+			return nil, err
+		}
+		file.ID = entities.FileID(id)
+		file.OwnerID = entities.UserID(fileOwnerID)
+		files = append(files, file)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to list files of user %s: %w", ownerID.String(), err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+	return files, nil
+}
+
+// GetUsage returns the total size, in bytes, of the files of a user.
+func (r *FileRepository) GetUsage(ctx context.Context, ownerID entities.UserID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var used int64
+	err := r.executor.QueryRowContext(ctx, getFileOwnerUsageQuery, ownerID.String()).Scan(&used)
+	if err != nil {
+		err = fmt.Errorf("failed to get storage usage of user %s: %w", ownerID.String(), err)
+		r.errTracker.CaptureException(ctx, err)
+		return 0, err
+	}
+	return used, nil
+}
+
+// MarkAvailable sets a quarantined file available, once stored and served from the given URL.
+func (r *FileRepository) MarkAvailable(ctx context.Context, id entities.FileID, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := r.executor.ExecContext(ctx, markFileAvailableQuery, id.String(), url)
+	if err != nil {
+		err = fmt.Errorf("failed to mark file %s available: %w", id.String(), err)
+		r.errTracker.CaptureException(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// Delete removes a file.
+func (r *FileRepository) Delete(ctx context.Context, id entities.FileID) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := r.executor.ExecContext(ctx, deleteFileQuery, id.String())
+	if err != nil {
+		err = fmt.Errorf("failed to delete file %s: %w", id.String(), err)
+		r.errTracker.CaptureException(ctx, err)
+		return err
+	}
+	return nil
+}
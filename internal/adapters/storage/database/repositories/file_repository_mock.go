@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FileRepositoryMock implements the ports.FileRepository interface with an in-memory store.
+type FileRepositoryMock struct {
+	data map[entities.FileID]*entities.File
+	mu   sync.RWMutex
+}
+
+// NewFileRepositoryMock creates and returns a new mock instance of a file repository.
+func NewFileRepositoryMock() *FileRepositoryMock {
+	return &FileRepositoryMock{
+		data: map[entities.FileID]*entities.File{},
+		mu:   sync.RWMutex{},
+	}
+}
+
+// Create inserts a file, unless the total size of the files of its owner would then exceed quota bytes.
+// Returns domain.ErrStorageQuotaExceeded if the file does not fit in the quota.
+func (r *FileRepositoryMock) Create(_ context.Context, file *entities.File, quota int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.usage(file.OwnerID)+file.Size > quota {
+		return domain.ErrStorageQuotaExceeded
+	}
+
+	file.CreatedAt = time.Now()
+	stored := *file
+	r.data[file.ID] = &stored
+	return nil
+}
+
+// GetByID returns a file.
+// Returns domain.ErrFileNotFound if the file does not exist.
+func (r *FileRepositoryMock) GetByID(_ context.Context, id entities.FileID) (*entities.File, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	file, ok := r.data[id]
+	if !ok {
+		return nil, domain.ErrFileNotFound
+	}
+	found := *file
+	return &found, nil
+}
+
+// ListByOwner returns the files of a user, most recent first.
+func (r *FileRepositoryMock) ListByOwner(_ context.Context, ownerID entities.UserID, limit, offset int) ([]*entities.File, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files := []*entities.File{}
+	for _, file := range r.data {
+		if file.OwnerID == ownerID {
+			found := *file
+			files = append(files, &found)
+		}
+	}
+	slices.SortFunc(files, func(a, b *entities.File) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+
+	offset = min(offset, len(files))
+	return files[offset:min(offset+limit, len(files))], nil
+}
+
+// GetUsage returns the total size, in bytes, of the files of a user.
+func (r *FileRepositoryMock) GetUsage(_ context.Context, ownerID entities.UserID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.usage(ownerID), nil
+}
+
+// MarkAvailable sets a quarantined file available, once stored and served from the given URL.
+func (r *FileRepositoryMock) MarkAvailable(_ context.Context, id entities.FileID, url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if file, ok := r.data[id]; ok {
+		file.Status = entities.FileStatusAvailable
+		file.URL = url
+	}
+	return nil
+}
+
+// Delete removes a file.
+func (r *FileRepositoryMock) Delete(_ context.Context, id entities.FileID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.data, id)
+	return nil
+}
+
+// usage returns the total size of the files of a user, the lock must be held by the caller.
+func (r *FileRepositoryMock) usage(ownerID entities.UserID) int64 {
+	var used int64
+	for _, file := range r.data {
+		if file.OwnerID == ownerID {
+			used += file.Size
+		}
+	}
+	return used
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PendingUploadRepository implements the ports.PendingUploadRepository interface and provides access to the database.
+type PendingUploadRepository struct {
+	executor   QueryExecutor
+	errTracker ports.ErrTrackerAdapter
+}
+
+// NewPendingUploadRepository creates and returns a new PendingUploadRepository instance.
+func NewPendingUploadRepository(db *sql.DB, errTracker ports.ErrTrackerAdapter) *PendingUploadRepository {
+	return &PendingUploadRepository{
+		executor:   db,
+		errTracker: errTracker,
+	}
+}
+
+// PendingUploadRepository queries
+const (
+	createPendingUploadQuery       = `INSERT INTO pending_uploads (id, user_id, key, content_type, size, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at`
+	getPendingUploadByIDQuery      = `SELECT user_id, key, content_type, size, expires_at, created_at FROM pending_uploads WHERE id = $1`
+	deletePendingUploadQuery       = `DELETE FROM pending_uploads WHERE id = $1`
+	listExpiredPendingUploadsQuery = `SELECT id, user_id, key, content_type, size, expires_at, created_at FROM pending_uploads WHERE expires_at < $1 ORDER BY expires_at LIMIT $2`
+)
+
+// Create inserts a pending upload.
+func (r *PendingUploadRepository) Create(ctx context.Context, upload *entities.PendingUpload) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := r.executor.QueryRowContext(
+		ctx,
+		createPendingUploadQuery,
+		upload.ID.String(),
+		upload.UserID.String(),
+		upload.Key,
+		upload.ContentType,
+		upload.Size,
+		upload.ExpiresAt,
+	).Scan(&upload.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to insert pending upload %s: %w", upload.Key, err)
+		r.errTracker.CaptureException(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// GetByID returns a pending upload.
+// Returns domain.ErrUploadNotFound if the upload does not exist.
+func (r *PendingUploadRepository) GetByID(ctx context.Context, id entities.UploadID) (*entities.PendingUpload, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	upload := &entities.PendingUpload{ID: id}
+	var userID uuid.UUID
+	err := r.executor.QueryRowContext(ctx, getPendingUploadByIDQuery, id.String()).Scan(
+		&userID,
+		&upload.Key,
+		&upload.ContentType,
+		&upload.Size,
+		&upload.ExpiresAt,
+		&upload.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUploadNotFound
+		}
+		err = fmt.Errorf("failed to get pending upload %s: %w", id, err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+	upload.UserID = entities.UserID(userID)
+
+	return upload, nil
+}
+
+// Delete removes a pending upload.
+func (r *PendingUploadRepository) Delete(ctx context.Context, id entities.UploadID) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := r.executor.ExecContext(ctx, deletePendingUploadQuery, id.String())
+	if err != nil {
+		err = fmt.Errorf("failed to delete pending upload %s: %w", id, err)
+		r.errTracker.CaptureException(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// ListExpired returns at most limit pending uploads that expired before the given time, oldest first.
+func (r *PendingUploadRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*entities.PendingUpload, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := r.executor.QueryContext(ctx, listExpiredPendingUploadsQuery, before, limit)
+	if err != nil {
+		err = fmt.Errorf("failed to list expired pending uploads: %w", err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	uploads := make([]*entities.PendingUpload, 0, limit)
+	for rows.Next() {
+		upload := &entities.PendingUpload{}
+		var id, userID uuid.UUID
+		if err := rows.Scan(&id, &userID, &upload.Key, &upload.ContentType, &upload.Size, &upload.ExpiresAt, &upload.CreatedAt); err != nil {
+			err = fmt.Errorf("failed to scan pending upload: %w", err)
+			r.errTracker.CaptureException(ctx, err)
+			return nil, err
+		}
+		upload.ID = entities.UploadID(id)
+		upload.UserID = entities.UserID(userID)
+		uploads = append(uploads, upload)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to iterate pending uploads: %w", err)
+		r.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	return uploads, nil
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"slices"
+	"sync"
+	"time"
+)
+
+// PendingUploadRepositoryMock implements the ports.PendingUploadRepository interface with an in-memory store.
+type PendingUploadRepositoryMock struct {
+	data map[entities.UploadID]*entities.PendingUpload
+	mu   sync.RWMutex
+}
+
+// NewPendingUploadRepositoryMock creates and returns a new mock instance of a pending upload repository.
+func NewPendingUploadRepositoryMock() *PendingUploadRepositoryMock {
+	return &PendingUploadRepositoryMock{
+		data: map[entities.UploadID]*entities.PendingUpload{},
+		mu:   sync.RWMutex{},
+	}
+}
+
+// Create inserts a pending upload.
+func (r *PendingUploadRepositoryMock) Create(_ context.Context, upload *entities.PendingUpload) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	upload.CreatedAt = time.Now()
+	stored := *upload
+	r.data[upload.ID] = &stored
+	return nil
+}
+
+// GetByID returns a pending upload.
+// Returns domain.ErrUploadNotFound if the upload does not exist.
+func (r *PendingUploadRepositoryMock) GetByID(_ context.Context, id entities.UploadID) (*entities.PendingUpload, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	upload, ok := r.data[id]
+	if !ok {
+		return nil, domain.ErrUploadNotFound
+	}
+	found := *upload
+	return &found, nil
+}
+
+// Delete removes a pending upload.
+func (r *PendingUploadRepositoryMock) Delete(_ context.Context, id entities.UploadID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.data, id)
+	return nil
+}
+
+// ListExpired returns at most limit pending uploads that expired before the given time, oldest first.
+func (r *PendingUploadRepositoryMock) ListExpired(_ context.Context, before time.Time, limit int) ([]*entities.PendingUpload, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var uploads []*entities.PendingUpload
+	for _, upload := range r.data {
+		if upload.ExpiresAt.Before(before) {
+			expired := *upload
+			uploads = append(uploads, &expired)
+		}
+	}
+	slices.SortFunc(uploads, func(a, b *entities.PendingUpload) int {
+		return a.ExpiresAt.Compare(b.ExpiresAt)
+	})
+
+	return uploads[:min(limit, len(uploads))], nil
+}
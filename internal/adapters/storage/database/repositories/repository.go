@@ -3,9 +3,13 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"go-starter/internal/domain/ports"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const (
@@ -28,7 +32,7 @@ func withTx(db *sql.DB, ctx context.Context, errTracker ports.ErrTrackerAdapter,
 		ReadOnly:  false,
 	})
 	if err != nil {
-		errTracker.CaptureException(err)
+		errTracker.CaptureException(ctx, err)
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
@@ -41,16 +45,60 @@ func withTx(db *sql.DB, ctx context.Context, errTracker ports.ErrTrackerAdapter,
 
 	if err := fn(tx); err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
-			errTracker.CaptureException(rbErr)
+			errTracker.CaptureException(ctx, rbErr)
 			return fmt.Errorf("failed to rollback transaction: %v (original error: %w)", rbErr, err)
 		}
 		return err
 	}
 
 	if err := tx.Commit(); err != nil {
-		errTracker.CaptureException(err)
+		errTracker.CaptureException(ctx, err)
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
+
+// jsonColumn is a sql.Scanner decoding a nullable JSON column into its destination.
+// A NULL value leaves the destination untouched.
+type jsonColumn[T any] struct {
+	dest *T
+}
+
+// Scan implements the sql.Scanner interface.
+func (c jsonColumn[T]) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(value, c.dest)
+	case string:
+		return json.Unmarshal([]byte(value), c.dest)
+	default:
+		return fmt.Errorf("unsupported type %T for a JSON column", src)
+	}
+}
+
+// uuidArrayColumn is a sql.Scanner decoding a UUID array column into a slice of identifiers based on UUID.
+type uuidArrayColumn[T ~[16]byte] struct {
+	dest *[]T
+}
+
+// Scan implements the sql.Scanner interface.
+func (c uuidArrayColumn[T]) Scan(src any) error {
+	var values pq.StringArray
+	if err := values.Scan(src); err != nil {
+		return err
+	}
+
+	ids := make([]T, 0, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid uuid %q in array column: %w", value, err)
+		}
+		ids = append(ids, T(id))
+	}
+	*c.dest = ids
+	return nil
+}
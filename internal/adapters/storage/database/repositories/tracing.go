@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"go-starter/internal/adapters/tracing"
+	"strings"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedExecutor is a QueryExecutor starting a span around every query of another executor.
+type tracedExecutor struct {
+	executor QueryExecutor
+	tracer   trace.Tracer
+	table    string
+}
+
+// newTracedExecutor creates a tracedExecutor around the executor, for the queries to the table.
+func newTracedExecutor(executor QueryExecutor, tracerProvider trace.TracerProvider, table string) *tracedExecutor {
+	return &tracedExecutor{
+		executor: executor,
+		tracer:   tracerProvider.Tracer(tracing.TracerName),
+		table:    table,
+	}
+}
+
+// start starts the span of the query, named after its operation and table, e.g. "SELECT users".
+func (te *tracedExecutor) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	return te.tracer.Start(ctx, operation+" "+te.table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBCollectionName(te.table),
+			semconv.DBQueryText(query),
+		),
+	)
+}
+
+// QueryRowContext executes a query returning at most one row.
+// The error of the query, if any, is recorded in the span; the ones of the scan are not.
+func (te *tracedExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := te.start(ctx, query)
+	row := te.executor.QueryRowContext(ctx, query, args...)
+	tracing.EndSpan(span, row.Err())
+	return row
+}
+
+// QueryContext executes a query returning rows.
+func (te *tracedExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := te.start(ctx, query)
+	rows, err := te.executor.QueryContext(ctx, query, args...)
+	tracing.EndSpan(span, err)
+	return rows, err
+}
+
+// ExecContext executes a query without returning any rows.
+func (te *tracedExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := te.start(ctx, query)
+	result, err := te.executor.ExecContext(ctx, query, args...)
+	tracing.EndSpan(span, err)
+	return result, err
+}
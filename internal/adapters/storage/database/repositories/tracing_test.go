@@ -0,0 +1,51 @@
+//go:build !integration
+
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/storage/database/repositories"
+	"go-starter/internal/adapters/tracing"
+	"go-starter/internal/domain/entities"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestUserRepository_TracesQueries(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	tracerProvider := tracing.NewTracerProviderMock()
+	// Nothing listens on port 1, the query fails without a database server.
+	db, err := sql.Open("postgres", "postgres://user@127.0.0.1:1/starter?sslmode=disable&connect_timeout=1")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	repo := repositories.NewUserRepository(db, errtracker.NewErrTrackerAdapterMock(), tracerProvider)
+
+	// Act
+	if _, err := repo.GetByID(context.Background(), entities.UserID(uuid.New())); err == nil {
+		t.Fatal("expected the query to fail")
+	}
+
+	// Assert
+	span, ok := tracerProvider.Span("SELECT users")
+	if !ok {
+		t.Fatalf("no span named %q recorded", "SELECT users")
+	}
+	if got := tracing.SpanAttribute(span, "db.system"); got != "postgresql" {
+		t.Errorf("expected db.system postgresql, got %q", got)
+	}
+	if got := tracing.SpanAttribute(span, "db.query.text"); !strings.HasPrefix(got, "SELECT created_at") {
+		t.Errorf("expected the query text to be recorded, got %q", got)
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected the span of the failed query to fail, got %v", span.Status())
+	}
+}
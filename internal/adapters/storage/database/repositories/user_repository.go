@@ -3,26 +3,37 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go-starter/internal/domain"
 	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/i18n"
 	"go-starter/internal/domain/ports"
 
 	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// usersTable is the table of the users, naming the spans of their queries.
+const usersTable = "users"
+
 // UserRepository implements the ports.UserRepository interface and provides access to the database.
+// Every query is traced in a span of its own.
 type UserRepository struct {
-	executor   QueryExecutor
-	errTracker ports.ErrTrackerAdapter
+	db             *sql.DB
+	executor       QueryExecutor
+	errTracker     ports.ErrTrackerAdapter
+	tracerProvider trace.TracerProvider
 }
 
 // NewUserRepository creates and returns a new UserRepository instance.
-func NewUserRepository(db *sql.DB, errTracker ports.ErrTrackerAdapter) *UserRepository {
+func NewUserRepository(db *sql.DB, errTracker ports.ErrTrackerAdapter, tracerProvider trace.TracerProvider) *UserRepository {
 	return &UserRepository{
-		executor:   db,
-		errTracker: errTracker,
+		db:             db,
+		executor:       newTracedExecutor(db, tracerProvider, usersTable),
+		errTracker:     errTracker,
+		tracerProvider: tracerProvider,
 	}
 }
 
@@ -36,15 +47,16 @@ func NewUserRepositoryWithExecutor(executor QueryExecutor, errTracker ports.ErrT
 
 // UserRepository queries
 const (
-	getByIDQuery                = `SELECT created_at, updated_at, name, username, email, is_email_verified, role_id, avatar_url FROM users WHERE id = $1`
-	getByUsernameQuery          = `SELECT id, created_at, updated_at, name, username, password, email, is_email_verified, role_id, avatar_url FROM users WHERE username = $1`
+	getByIDQuery                = `SELECT created_at, updated_at, name, username, email, is_email_verified, role_id, avatar_urls, avatar_file_ids, locale FROM users WHERE id = $1`
+	getByUsernameQuery          = `SELECT id, created_at, updated_at, name, username, password, email, is_email_verified, role_id, avatar_urls, avatar_file_ids, locale FROM users WHERE username = $1`
 	getIDByVerifiedEmailQuery   = `SELECT id FROM users WHERE email = $1 AND is_email_verified = true`
 	checkEmailAvailabilityQuery = `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND is_email_verified = true)`
-	createUserQuery             = `INSERT INTO users (name, username, password, email) VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at, is_email_verified, role_id, avatar_url`
+	createUserQuery             = `INSERT INTO users (name, username, password, email, locale) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, is_email_verified, role_id, avatar_urls, avatar_file_ids, locale`
 	updatePasswordQuery         = `UPDATE users SET password = $1 WHERE id = $2 `
 	verifyEmailQuery            = `UPDATE users SET is_email_verified = true WHERE id = $1 `
-	updateAvatarQuery           = `UPDATE users SET avatar_url = $1 WHERE id = $2 `
-	deleteAvatarQuery           = `UPDATE users SET avatar_url = NULL WHERE id = $1 `
+	updateAvatarQuery           = `UPDATE users SET avatar_urls = $1, avatar_file_ids = $2 WHERE id = $3 `
+	deleteAvatarQuery           = `UPDATE users SET avatar_urls = NULL, avatar_file_ids = '{}' WHERE id = $1 `
+	updateLocaleQuery           = `UPDATE users SET locale = $1 WHERE id = $2 `
 )
 
 // GetByID selects a user by their unique identifier from the database.
@@ -54,14 +66,14 @@ func (ur *UserRepository) GetByID(ctx context.Context, id entities.UserID) (*ent
 	defer cancel()
 	user := &entities.User{}
 
-	err := ur.executor.QueryRowContext(ctx, getByIDQuery, id.String()).Scan(&user.CreatedAt, &user.UpdatedAt, &user.Name, &user.Username, &user.Email, &user.IsEmailVerified, &user.RoleID, &user.AvatarURL)
+	err := ur.executor.QueryRowContext(ctx, getByIDQuery, id.String()).Scan(&user.CreatedAt, &user.UpdatedAt, &user.Name, &user.Username, &user.Email, &user.IsEmailVerified, &user.RoleID, jsonColumn[map[string]string]{dest: &user.AvatarURLs}, uuidArrayColumn[entities.FileID]{dest: &user.AvatarFileIDs}, &user.Locale)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return nil, fmt.Errorf("%w: id=%s", domain.ErrUserNotFound, id)
+			return nil, domain.ErrUserNotFound
 		default:
 			err = fmt.Errorf("failed to get user %s: %w", id.String(), err)
-			ur.errTracker.CaptureException(err)
+			ur.errTracker.CaptureException(ctx, err)
 			return nil, err
 		}
 	}
@@ -77,14 +89,14 @@ func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (*
 	defer cancel()
 	user := &entities.User{}
 	var uuidStr string
-	err := ur.executor.QueryRowContext(ctx, getByUsernameQuery, username).Scan(&uuidStr, &user.CreatedAt, &user.UpdatedAt, &user.Name, &user.Username, &user.Password, &user.Email, &user.IsEmailVerified, &user.RoleID, &user.AvatarURL)
+	err := ur.executor.QueryRowContext(ctx, getByUsernameQuery, username).Scan(&uuidStr, &user.CreatedAt, &user.UpdatedAt, &user.Name, &user.Username, &user.Password, &user.Email, &user.IsEmailVerified, &user.RoleID, jsonColumn[map[string]string]{dest: &user.AvatarURLs}, uuidArrayColumn[entities.FileID]{dest: &user.AvatarFileIDs}, &user.Locale)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return nil, domain.ErrUserNotFound
 		default:
 			err = fmt.Errorf("failed to get user %s: %w", username, err)
-			ur.errTracker.CaptureException(err)
+			ur.errTracker.CaptureException(ctx, err)
 			return nil, err
 		}
 	}
@@ -92,7 +104,7 @@ func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (*
 	parsedID, err := entities.ParseUserID(uuidStr)
 	if err != nil {
 		err = fmt.Errorf("failed to parse user id %s: %w", uuidStr, err)
-		ur.errTracker.CaptureException(err)
+		ur.errTracker.CaptureException(ctx, err)
 		return nil, err
 	}
 	user.ID = parsedID
@@ -113,7 +125,7 @@ func (ur *UserRepository) GetIDByVerifiedEmail(ctx context.Context, email string
 			return entities.NilUserID, domain.ErrUserNotFound
 		default:
 			err = fmt.Errorf("failed to get user %s: %w", email, err)
-			ur.errTracker.CaptureException(err)
+			ur.errTracker.CaptureException(ctx, err)
 			return entities.NilUserID, err
 		}
 	}
@@ -121,7 +133,7 @@ func (ur *UserRepository) GetIDByVerifiedEmail(ctx context.Context, email string
 	parsedID, err := entities.ParseUserID(uuidStr)
 	if err != nil {
 		err = fmt.Errorf("failed to parse user id %s: %w", uuidStr, err)
-		ur.errTracker.CaptureException(err)
+		ur.errTracker.CaptureException(ctx, err)
 		return entities.NilUserID, err
 	}
 
@@ -136,7 +148,7 @@ func (ur *UserRepository) CheckEmailAvailability(ctx context.Context, email stri
 	var exists bool
 	if err := ur.executor.QueryRowContext(ctx, checkEmailAvailabilityQuery, email).Scan(&exists); err != nil {
 		err = fmt.Errorf("failed to check email verification status: %w", err)
-		ur.errTracker.CaptureException(err)
+		ur.errTracker.CaptureException(ctx, err)
 		return err
 	}
 
@@ -164,13 +176,16 @@ func (ur *UserRepository) Create(ctx context.Context, user *entities.User) (*ent
 		user.Username,
 		user.Password,
 		user.Email,
+		user.Locale.OrDefault().String(),
 	).Scan(
 		&uuidStr,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.IsEmailVerified,
 		&user.RoleID,
-		&user.AvatarURL,
+		jsonColumn[map[string]string]{dest: &user.AvatarURLs},
+		uuidArrayColumn[entities.FileID]{dest: &user.AvatarFileIDs},
+		&user.Locale,
 	)
 
 	if err != nil {
@@ -184,14 +199,14 @@ func (ur *UserRepository) Create(ctx context.Context, user *entities.User) (*ent
 			}
 		}
 		err = fmt.Errorf("failed to insert user %s: %w", user.Username, err)
-		ur.errTracker.CaptureException(err)
+		ur.errTracker.CaptureException(ctx, err)
 		return nil, err
 	}
 
 	parsedID, err := entities.ParseUserID(uuidStr)
 	if err != nil {
 		err = fmt.Errorf("failed to parse user id %s: %w", uuidStr, err)
-		ur.errTracker.CaptureException(err)
+		ur.errTracker.CaptureException(ctx, err)
 		return nil, err
 	}
 	user.ID = parsedID
@@ -208,7 +223,7 @@ func (ur *UserRepository) UpdatePassword(ctx context.Context, userID entities.Us
 	_, err := ur.executor.ExecContext(ctx, updatePasswordQuery, newPassword, userID.String())
 	if err != nil {
 		err = fmt.Errorf("failed to update user password for user %s: %w", userID.String(), err)
-		ur.errTracker.CaptureException(err)
+		ur.errTracker.CaptureException(ctx, err)
 		return err
 	}
 
@@ -221,8 +236,8 @@ func (ur *UserRepository) VerifyEmail(ctx context.Context, userID entities.UserI
 	defer cancel()
 
 	var returnedUser *entities.User
-	return returnedUser, withTx(ur.executor.(*sql.DB), ctx, ur.errTracker, func(tx *sql.Tx) error {
-		txRepo := NewUserRepositoryWithExecutor(tx, ur.errTracker)
+	return returnedUser, withTx(ur.db, ctx, ur.errTracker, func(tx *sql.Tx) error {
+		txRepo := NewUserRepositoryWithExecutor(newTracedExecutor(tx, ur.tracerProvider, usersTable), ur.errTracker)
 
 		user, err := txRepo.GetByID(ctx, userID)
 		if err != nil {
@@ -234,10 +249,10 @@ func (ur *UserRepository) VerifyEmail(ctx context.Context, userID entities.UserI
 			return domain.ErrEmailAlreadyVerified
 		}
 
-		_, err = tx.ExecContext(ctx, verifyEmailQuery, userID.String())
+		_, err = txRepo.executor.ExecContext(ctx, verifyEmailQuery, userID.String())
 		if err != nil {
 			err = fmt.Errorf("failed to update email verification status: %w", err)
-			ur.errTracker.CaptureException(err)
+			ur.errTracker.CaptureException(ctx, err)
 			return err
 		}
 
@@ -247,15 +262,27 @@ func (ur *UserRepository) VerifyEmail(ctx context.Context, userID entities.UserI
 	})
 }
 
-// UpdateAvatar updates a user avatar.
-func (ur *UserRepository) UpdateAvatar(ctx context.Context, userID entities.UserID, avatarURL string) error {
+// UpdateAvatar updates the URLs of a user avatar variants, and the files storing them.
+func (ur *UserRepository) UpdateAvatar(ctx context.Context, userID entities.UserID, avatarURLs map[string]string, avatarFileIDs []entities.FileID) error {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	_, err := ur.executor.ExecContext(ctx, updateAvatarQuery, avatarURL, userID.String())
+	encodedAvatarURLs, err := json.Marshal(avatarURLs)
+	if err != nil {
+		err = fmt.Errorf("failed to encode user avatar for user %s: %w", userID.String(), err)
+		ur.errTracker.CaptureException(ctx, err)
+		return err
+	}
+
+	fileIDs := make([]string, len(avatarFileIDs))
+	for i, id := range avatarFileIDs {
+		fileIDs[i] = id.String()
+	}
+
+	_, err = ur.executor.ExecContext(ctx, updateAvatarQuery, encodedAvatarURLs, pq.Array(fileIDs), userID.String())
 	if err != nil {
 		err = fmt.Errorf("failed to update user avatar for user %s: %w", userID.String(), err)
-		ur.errTracker.CaptureException(err)
+		ur.errTracker.CaptureException(ctx, err)
 		return err
 	}
 	return nil
@@ -269,7 +296,21 @@ func (ur *UserRepository) DeleteAvatar(ctx context.Context, userID entities.User
 	_, err := ur.executor.ExecContext(ctx, deleteAvatarQuery, userID.String())
 	if err != nil {
 		err = fmt.Errorf("failed to delete user avatar for user %s: %w", userID.String(), err)
-		ur.errTracker.CaptureException(err)
+		ur.errTracker.CaptureException(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// UpdateLocale updates a user preferred locale.
+func (ur *UserRepository) UpdateLocale(ctx context.Context, userID entities.UserID, locale i18n.Locale) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := ur.executor.ExecContext(ctx, updateLocaleQuery, locale.String(), userID.String())
+	if err != nil {
+		err = fmt.Errorf("failed to update user locale for user %s: %w", userID.String(), err)
+		ur.errTracker.CaptureException(ctx, err)
 		return err
 	}
 	return nil
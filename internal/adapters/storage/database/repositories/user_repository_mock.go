@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"go-starter/internal/domain"
 	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/i18n"
+	"maps"
+	"slices"
 	"sync"
 
 	"github.com/google/uuid"
@@ -89,6 +92,7 @@ func (ur *UserRepositoryMock) Create(_ context.Context, user *entities.User) (*e
 		Password:        user.Password,
 		Email:           user.Email,
 		IsEmailVerified: false,
+		Locale:          user.Locale.OrDefault(),
 	}
 	ur.db.data[newUser.ID] = newUser
 
@@ -135,12 +139,13 @@ func (ur *UserRepositoryMock) CheckEmailAvailability(_ context.Context, email st
 	return nil
 }
 
-// UpdateAvatar updates a user avatar.
-func (ur *UserRepositoryMock) UpdateAvatar(_ context.Context, userID entities.UserID, avatarURL string) error {
+// UpdateAvatar updates the URLs of a user avatar variants, and the files storing them.
+func (ur *UserRepositoryMock) UpdateAvatar(_ context.Context, userID entities.UserID, avatarURLs map[string]string, avatarFileIDs []entities.FileID) error {
 	ur.db.mu.Lock()
 	defer ur.db.mu.Unlock()
 
-	ur.db.data[userID].AvatarURL = &avatarURL
+	ur.db.data[userID].AvatarURLs = maps.Clone(avatarURLs)
+	ur.db.data[userID].AvatarFileIDs = slices.Clone(avatarFileIDs)
 	return nil
 }
 
@@ -149,7 +154,17 @@ func (ur *UserRepositoryMock) DeleteAvatar(_ context.Context, userID entities.Us
 	ur.db.mu.Lock()
 	defer ur.db.mu.Unlock()
 
-	ur.db.data[userID].AvatarURL = nil
+	ur.db.data[userID].AvatarURLs = nil
+	ur.db.data[userID].AvatarFileIDs = nil
+	return nil
+}
+
+// UpdateLocale updates a user preferred locale.
+func (ur *UserRepositoryMock) UpdateLocale(_ context.Context, userID entities.UserID, locale i18n.Locale) error {
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	ur.db.data[userID].Locale = locale
 	return nil
 }
 
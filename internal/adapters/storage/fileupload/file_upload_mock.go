@@ -1,24 +1,121 @@
 package fileupload
 
 import (
+	"bytes"
 	"context"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
 	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
 )
 
 // FileUploadAdapterMock is a mock implementation of the ports.FileUploadAdapter interface.
-type FileUploadAdapterMock struct{}
+// Files are kept in memory, with their content type.
+type FileUploadAdapterMock struct {
+	files        map[string][]byte
+	contentTypes map[string]string
+	mu           sync.RWMutex
+}
 
 // NewFileUploadAdapterMock creates a new FileUploadAdapterMock instance.
 func NewFileUploadAdapterMock() *FileUploadAdapterMock {
-	return &FileUploadAdapterMock{}
+	return &FileUploadAdapterMock{
+		files:        map[string][]byte{},
+		contentTypes: map[string]string{},
+	}
 }
 
 // Upload uploads a file to the file upload service.
-func (f *FileUploadAdapterMock) Upload(_ context.Context, key string, _ io.Reader) (string, error) {
+func (f *FileUploadAdapterMock) Upload(_ context.Context, key, contentType string, body io.Reader) (string, error) {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[key] = content
+	f.contentTypes[key] = contentType
 	return "https://example.com/" + key, nil
 }
 
 // Delete deletes a file from the file upload service.
 func (f *FileUploadAdapterMock) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.files, key)
+	delete(f.contentTypes, key)
 	return nil
 }
+
+// PresignUpload returns a fake presigned URL, files are uploaded to it with PutPresigned.
+func (f *FileUploadAdapterMock) PresignUpload(_ context.Context, key, contentType string, _ int64, expiresAt time.Time) (*ports.PresignedUpload, error) {
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	return &ports.PresignedUpload{
+		URL:       "https://example.com/" + key + "?signature=mock",
+		Method:    http.MethodPut,
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// PresignDownload returns a fake presigned URL.
+func (f *FileUploadAdapterMock) PresignDownload(_ context.Context, key string, expiresAt time.Time) (string, error) {
+	return "https://example.com/" + key + "?signature=mock&expires=" + strconv.FormatInt(expiresAt.Unix(), 10), nil
+}
+
+// Stat returns the information of a file.
+// Returns domain.ErrFileNotFound if the file does not exist.
+func (f *FileUploadAdapterMock) Stat(_ context.Context, key string) (*ports.FileInfo, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	content, ok := f.files[key]
+	if !ok {
+		return nil, domain.ErrFileNotFound
+	}
+	return &ports.FileInfo{
+		Size:        int64(len(content)),
+		ContentType: f.contentTypes[key],
+	}, nil
+}
+
+// Download opens a file for reading.
+// Returns domain.ErrFileNotFound if the file does not exist.
+func (f *FileUploadAdapterMock) Download(_ context.Context, key string) (io.ReadCloser, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	content, ok := f.files[key]
+	if !ok {
+		return nil, domain.ErrFileNotFound
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+// Ping always succeeds, the files being kept in memory.
+func (f *FileUploadAdapterMock) Ping(_ context.Context) error {
+	return nil
+}
+
+// PutPresigned simulates the upload of a file by a client to a presigned URL.
+// This is only for testing purposes.
+func (f *FileUploadAdapterMock) PutPresigned(key, contentType string, content []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[key] = content
+	f.contentTypes[key] = contentType
+}
+
+// Exists reports whether a file is stored.
+// This is only for testing purposes.
+func (f *FileUploadAdapterMock) Exists(key string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, ok := f.files[key]
+	return ok
+}
@@ -0,0 +1,373 @@
+package fileupload
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	c "go-starter/config"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/ports"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// LocalFilesPath is the path, relative to the application base URL, under which the local files are served.
+const LocalFilesPath = "/files/"
+
+// Signed URL query parameters.
+const (
+	signatureParam = "signature"
+	expiresParam   = "expires"
+	sizeParam      = "size"
+)
+
+// sniffLength is the number of bytes read to detect the content type of a file, see http.DetectContentType.
+const sniffLength = 512
+
+// LocalAdapter is an adapter for the ports.FileUploadAdapter and ports.FileServerAdapter interfaces.
+// It stores files on the local disk and serves them through URLs signed with an HMAC.
+type LocalAdapter struct {
+	dir        string
+	baseURL    string
+	signingKey []byte
+	errTracker ports.ErrTrackerAdapter
+}
+
+// NewLocalAdapter creates a new LocalAdapter instance, creating the storage directory if needed.
+// Files are served under baseURL + LocalFilesPath.
+func NewLocalAdapter(fileUploadCfg *c.FileUpload, baseURL string, errTracker ports.ErrTrackerAdapter) (*LocalAdapter, error) {
+	dir, err := filepath.Abs(fileUploadCfg.LocalDir)
+	if err != nil {
+		err = fmt.Errorf("failed to resolve storage directory %s: %w", fileUploadCfg.LocalDir, err)
+		errTracker.CaptureException(context.Background(), err)
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		err = fmt.Errorf("failed to create storage directory %s: %w", dir, err)
+		errTracker.CaptureException(context.Background(), err)
+		return nil, err
+	}
+
+	return &LocalAdapter{
+		dir:        dir,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		signingKey: []byte(fileUploadCfg.SigningKey),
+		errTracker: errTracker,
+	}, nil
+}
+
+// Upload writes a file to the storage directory.
+// The content type is not stored, it is detected from the file name or content when the file is served.
+// Returns the signed URL of the uploaded file or an error if the upload fails.
+func (a *LocalAdapter) Upload(ctx context.Context, key, _ string, body io.Reader) (string, error) {
+	if err := a.write(key, body); err != nil {
+		a.errTracker.CaptureException(ctx, err)
+		return "", err
+	}
+	return a.SignedURL(key, time.Time{}), nil
+}
+
+// Delete removes a file from the storage directory.
+// Deleting a file that does not exist is not an error, like with S3.
+func (a *LocalAdapter) Delete(ctx context.Context, key string) error {
+	filename, err := a.path(key)
+	if err != nil {
+		a.errTracker.CaptureException(ctx, err)
+		return err
+	}
+
+	err = os.Remove(filename)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		err = fmt.Errorf("failed to delete file %s: %w", key, err)
+		a.errTracker.CaptureException(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// Open verifies the signature of a file URL and opens the file.
+// Returns domain.ErrInvalidFileSignature if the signature is invalid or expired,
+// or domain.ErrFileNotFound if the file does not exist.
+func (a *LocalAdapter) Open(ctx context.Context, key string, params url.Values) (*ports.StoredFile, error) {
+	if !a.verify(key+"\n"+params.Get(expiresParam), params) {
+		return nil, domain.ErrInvalidFileSignature
+	}
+
+	filename, err := a.path(key)
+	if err != nil {
+		return nil, domain.ErrFileNotFound
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, domain.ErrFileNotFound
+		}
+		err = fmt.Errorf("failed to open file %s: %w", key, err)
+		a.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return nil, domain.ErrFileNotFound
+	}
+
+	return &ports.StoredFile{
+		Content: file,
+		Name:    info.Name(),
+		ModTime: info.ModTime(),
+	}, nil
+}
+
+// PresignUpload returns a URL the client uploads a file to with a PUT request, handled by Store, until expiresAt.
+// The content type and the maximum size are part of the signature.
+func (a *LocalAdapter) PresignUpload(ctx context.Context, key, contentType string, size int64, expiresAt time.Time) (*ports.PresignedUpload, error) {
+	if _, err := a.path(key); err != nil {
+		a.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	params := url.Values{}
+	params.Set(signatureParam, a.sign(uploadPayload(key, expires, contentType, strconv.FormatInt(size, 10))))
+	params.Set(expiresParam, expires)
+	params.Set(sizeParam, strconv.FormatInt(size, 10))
+
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+
+	return &ports.PresignedUpload{
+		URL:       a.fileURL(key) + "?" + params.Encode(),
+		Method:    http.MethodPut,
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// PresignDownload returns the URL serving a file until expiresAt.
+func (a *LocalAdapter) PresignDownload(ctx context.Context, key string, expiresAt time.Time) (string, error) {
+	if _, err := a.path(key); err != nil {
+		a.errTracker.CaptureException(ctx, err)
+		return "", err
+	}
+	return a.SignedURL(key, expiresAt), nil
+}
+
+// Store verifies the signature of a presigned upload URL and writes the file.
+// Returns domain.ErrInvalidFileSignature if the signature is invalid, expired or does not match the content type,
+// or domain.ErrFileTooLarge if the file is larger than the signed size.
+func (a *LocalAdapter) Store(ctx context.Context, key string, params url.Values, contentType string, body io.Reader) error {
+	expires := params.Get(expiresParam)
+	size, err := strconv.ParseInt(params.Get(sizeParam), 10, 64)
+	if expires == "" || err != nil || !a.verify(uploadPayload(key, expires, contentType, params.Get(sizeParam)), params) {
+		return domain.ErrInvalidFileSignature
+	}
+
+	err = a.write(key, &sizeLimitedReader{reader: body, remaining: size})
+	if err != nil {
+		if errors.Is(err, domain.ErrFileTooLarge) {
+			return domain.ErrFileTooLarge
+		}
+		a.errTracker.CaptureException(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// Stat returns the size of a file of the storage directory, and its content type detected from its first bytes.
+// Returns domain.ErrFileNotFound if the file does not exist.
+func (a *LocalAdapter) Stat(ctx context.Context, key string) (*ports.FileInfo, error) {
+	file, err := a.Download(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.(*os.File).Stat()
+	if err != nil {
+		err = fmt.Errorf("failed to stat file %s: %w", key, err)
+		a.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		err = fmt.Errorf("failed to read file %s: %w", key, err)
+		a.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	return &ports.FileInfo{
+		Size:        info.Size(),
+		ContentType: http.DetectContentType(head[:n]),
+	}, nil
+}
+
+// Download opens a file of the storage directory for reading.
+// Returns domain.ErrFileNotFound if the file does not exist.
+func (a *LocalAdapter) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	filename, err := a.path(key)
+	if err != nil {
+		return nil, domain.ErrFileNotFound
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, domain.ErrFileNotFound
+		}
+		err = fmt.Errorf("failed to open file %s: %w", key, err)
+		a.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+	return file, nil
+}
+
+// Ping checks that the storage directory exists.
+func (a *LocalAdapter) Ping(_ context.Context) error {
+	info, err := os.Stat(a.dir)
+	if err != nil {
+		return fmt.Errorf("failed to stat storage directory %s: %w", a.dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("storage directory %s is not a directory", a.dir)
+	}
+	return nil
+}
+
+// SignedURL returns the URL serving a file, valid until expiresAt or forever if expiresAt is zero.
+func (a *LocalAdapter) SignedURL(key string, expiresAt time.Time) string {
+	var expires string
+	if !expiresAt.IsZero() {
+		expires = strconv.FormatInt(expiresAt.Unix(), 10)
+	}
+
+	params := url.Values{}
+	params.Set(signatureParam, a.sign(key+"\n"+expires))
+	if expires != "" {
+		params.Set(expiresParam, expires)
+	}
+
+	return a.fileURL(key) + "?" + params.Encode()
+}
+
+// fileURL returns the URL of a file, without its signature.
+func (a *LocalAdapter) fileURL(key string) string {
+	escapedKey := (&url.URL{Path: key}).EscapedPath()
+	return a.baseURL + LocalFilesPath + escapedKey
+}
+
+// verify checks the signature of a URL against the signed payload, and its expiration.
+func (a *LocalAdapter) verify(payload string, params url.Values) bool {
+	expires := params.Get(expiresParam)
+	if expires != "" {
+		expiresAt, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || time.Now().Unix() > expiresAt {
+			return false
+		}
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(params.Get(signatureParam))
+	if err != nil {
+		return false
+	}
+
+	expected, _ := base64.RawURLEncoding.DecodeString(a.sign(payload))
+	return hmac.Equal(signature, expected)
+}
+
+// sign computes the signature of a payload.
+// The download payload is the file key and its expiration, see uploadPayload for the upload one.
+func (a *LocalAdapter) sign(payload string) string {
+	mac := hmac.New(sha256.New, a.signingKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// uploadPayload returns the payload signed for an upload URL.
+// It starts with the method so that an upload signature is never valid for a download, and conversely.
+func uploadPayload(key, expires, contentType, size string) string {
+	return http.MethodPut + "\n" + key + "\n" + expires + "\n" + contentType + "\n" + size
+}
+
+// write writes a file to the storage directory.
+func (a *LocalAdapter) write(key string, body io.Reader) error {
+	filename, err := a.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	// The file is written next to its destination then renamed, so readers never see a partial file.
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file for %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write file %s: %w", key, err)
+	}
+
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to move file %s: %w", key, err)
+	}
+	return nil
+}
+
+// path returns the location of a file in the storage directory.
+// Keys escaping the storage directory or holding control characters are rejected.
+func (a *LocalAdapter) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != key || strings.ContainsFunc(key, unicode.IsControl) || !filepath.IsLocal(filepath.FromSlash(cleaned)) {
+		return "", fmt.Errorf("invalid file key %q", key)
+	}
+	return filepath.Join(a.dir, filepath.FromSlash(cleaned)), nil
+}
+
+// sizeLimitedReader reads from a reader until more than remaining bytes are read,
+// at which point it fails with domain.ErrFileTooLarge.
+type sizeLimitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+// Read implements the io.Reader interface.
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, domain.ErrFileTooLarge
+	}
+	// One more byte than allowed is read to detect the files exceeding the limit.
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, domain.ErrFileTooLarge
+	}
+	return n, err
+}
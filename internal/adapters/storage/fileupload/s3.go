@@ -2,7 +2,11 @@ package fileupload
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	c "go-starter/config"
+	"go-starter/internal/adapters/tracing"
+	"go-starter/internal/domain"
 	"go-starter/internal/domain/ports"
 	"io"
 	"net/http"
@@ -12,19 +16,24 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go/aws"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // S3Adapter is an adapter for the ports.FileUploadAdapter interface.
 type S3Adapter struct {
 	client     *s3.Client
+	presigner  *s3.PresignClient
 	uploader   *manager.Uploader
 	errTracker ports.ErrTrackerAdapter
+	tracer     trace.Tracer
 	cfg        *c.FileUpload
 }
 
 // NewS3Adapter creates a new S3Adapter instance.
-func NewS3Adapter(fileUploadCfg *c.FileUpload, errTracker ports.ErrTrackerAdapter) (*S3Adapter, error) {
+func NewS3Adapter(fileUploadCfg *c.FileUpload, errTracker ports.ErrTrackerAdapter, tracerProvider trace.TracerProvider) (*S3Adapter, error) {
 	cfg, err := config.LoadDefaultConfig(context.Background(),
 		config.WithRegion(fileUploadCfg.Region),
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
@@ -35,7 +44,7 @@ func NewS3Adapter(fileUploadCfg *c.FileUpload, errTracker ports.ErrTrackerAdapte
 	)
 
 	if err != nil {
-		errTracker.CaptureException(err)
+		errTracker.CaptureException(context.Background(), err)
 		return nil, err
 	}
 
@@ -43,23 +52,49 @@ func NewS3Adapter(fileUploadCfg *c.FileUpload, errTracker ports.ErrTrackerAdapte
 		o.HTTPClient = &http.Client{
 			Timeout: 30 * time.Second,
 		}
+		// S3-compatible stores (MinIO, ...) are reached through a custom endpoint, usually with path-style addressing.
+		if fileUploadCfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(fileUploadCfg.Endpoint)
+		}
+		o.UsePathStyle = fileUploadCfg.UsePathStyle
 	})
 	uploader := manager.NewUploader(client)
 
-	return &S3Adapter{client: client, uploader: uploader, errTracker: errTracker, cfg: fileUploadCfg}, nil
+	return &S3Adapter{
+		client:     client,
+		presigner:  s3.NewPresignClient(client),
+		uploader:   uploader,
+		errTracker: errTracker,
+		tracer:     tracerProvider.Tracer(tracing.TracerName),
+		cfg:        fileUploadCfg,
+	}, nil
 }
 
-// Upload uploads a file to the S3 bucket.
+// Upload uploads a file to the S3 bucket, with the given content type.
+// The upload is traced in a span.
 // Returns the URL of the uploaded file or an error if the upload fails.
-func (s *S3Adapter) Upload(ctx context.Context, key string, body io.Reader) (string, error) {
+func (s *S3Adapter) Upload(ctx context.Context, key, contentType string, body io.Reader) (url string, err error) {
+	ctx, span := s.tracer.Start(ctx, "S3.PutObject",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("aws-api"),
+			semconv.RPCService("S3"),
+			semconv.RPCMethod("PutObject"),
+			semconv.AWSS3Bucket(s.cfg.Bucket),
+			semconv.AWSS3Key(key),
+		),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	result, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(s.cfg.Bucket),
-		Key:    aws.String(key),
-		Body:   body,
+		Bucket:      aws.String(s.cfg.Bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
 	})
 
 	if err != nil {
-		s.errTracker.CaptureException(err)
+		s.errTracker.CaptureException(ctx, err)
 		return "", err
 	}
 
@@ -75,8 +110,103 @@ func (s *S3Adapter) Delete(ctx context.Context, key string) error {
 	})
 
 	if err != nil {
-		s.errTracker.CaptureException(err)
+		s.errTracker.CaptureException(ctx, err)
 		return err
 	}
 	return nil
 }
+
+// PresignUpload returns a presigned PUT URL the client uploads a file to until expiresAt.
+// The content type and the content length are signed, S3 rejects any other file.
+func (s *S3Adapter) PresignUpload(ctx context.Context, key, contentType string, size int64, expiresAt time.Time) (*ports.PresignedUpload, error) {
+	request, err := s.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.cfg.Bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(time.Until(expiresAt)))
+
+	if err != nil {
+		err = fmt.Errorf("failed to presign upload of %s: %w", key, err)
+		s.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	// The host is set by the client from the URL.
+	headers := request.SignedHeader.Clone()
+	headers.Del("Host")
+
+	return &ports.PresignedUpload{
+		URL:       request.URL,
+		Method:    request.Method,
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// PresignDownload returns a presigned GET URL the file is downloaded from until expiresAt.
+func (s *S3Adapter) PresignDownload(ctx context.Context, key string, expiresAt time.Time) (string, error) {
+	request, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(time.Until(expiresAt)))
+
+	if err != nil {
+		err = fmt.Errorf("failed to presign download of %s: %w", key, err)
+		s.errTracker.CaptureException(ctx, err)
+		return "", err
+	}
+	return request.URL, nil
+}
+
+// Stat returns the size and the content type of a file of the S3 bucket.
+// Returns domain.ErrFileNotFound if the file does not exist.
+func (s *S3Adapter) Stat(ctx context.Context, key string) (*ports.FileInfo, error) {
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, domain.ErrFileNotFound
+		}
+		s.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	return &ports.FileInfo{
+		Size:        aws.Int64Value(output.ContentLength),
+		ContentType: aws.StringValue(output.ContentType),
+	}, nil
+}
+
+// Download opens a file of the S3 bucket for reading.
+// Returns domain.ErrFileNotFound if the file does not exist.
+func (s *S3Adapter) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, domain.ErrFileNotFound
+		}
+		s.errTracker.CaptureException(ctx, err)
+		return nil, err
+	}
+
+	return output.Body, nil
+}
+
+// Ping checks that the S3 bucket exists and is reachable with the configured credentials.
+// The failures are not captured, they are reported by the health checks.
+func (s *S3Adapter) Ping(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.cfg.Bucket),
+	})
+	return err
+}
@@ -0,0 +1,57 @@
+//go:build !integration
+
+package fileupload_test
+
+import (
+	"context"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/storage/fileupload"
+	"go-starter/internal/adapters/tracing"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestS3Adapter_TracesUploads(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	tracerProvider := tracing.NewTracerProviderMock()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	adapter, err := fileupload.NewS3Adapter(&config.FileUpload{
+		Region:       "us-east-1",
+		AccessKey:    "access-key",
+		SecretKey:    "secret-key",
+		Bucket:       "bucket",
+		Endpoint:     server.URL,
+		UsePathStyle: true,
+	}, errtracker.NewErrTrackerAdapterMock(), tracerProvider)
+	if err != nil {
+		t.Fatalf("failed to create S3 adapter: %v", err)
+	}
+
+	// Act
+	if _, err := adapter.Upload(context.Background(), "avatars/42.png", "image/png", strings.NewReader("content")); err == nil {
+		t.Fatal("expected the upload to fail")
+	}
+
+	// Assert
+	span, ok := tracerProvider.Span("S3.PutObject")
+	if !ok {
+		t.Fatalf("no span named %q recorded", "S3.PutObject")
+	}
+	if got := tracing.SpanAttribute(span, "aws.s3.key"); got != "avatars/42.png" {
+		t.Errorf("expected aws.s3.key avatars/42.png, got %q", got)
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected the span of the failed upload to fail, got %v", span.Status())
+	}
+}
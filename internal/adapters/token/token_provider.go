@@ -1,6 +1,7 @@
 package token
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
@@ -32,7 +33,7 @@ func NewTokenProvider(timeGenerator ports.TimeGenerator, errTracker ports.ErrTra
 func (p *Provider) GenerateRandomToken() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
-		p.errTracker.CaptureException(err)
+		p.errTracker.CaptureException(context.Background(), err)
 		return "", err
 	}
 	return base64.URLEncoding.EncodeToString(bytes), nil
@@ -44,7 +45,7 @@ func (p *Provider) GenerateRandomToken() (string, error) {
 func (p *Provider) GenerateOneTimeToken(userID entities.UserID) (token string, err error) {
 	randomBytes := make([]byte, 16)
 	if _, err := rand.Read(randomBytes); err != nil {
-		p.errTracker.CaptureException(err)
+		p.errTracker.CaptureException(context.Background(), err)
 		return "", err
 	}
 	randomPart := base64.URLEncoding.EncodeToString(randomBytes)
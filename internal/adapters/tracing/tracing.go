@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go-starter/config"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TracerName is the name of the tracer the spans of the application are started with.
+const TracerName = "go-starter"
+
+// Propagator reads and writes the W3C trace context and baggage of the requests.
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// New creates the tracer provider exporting the spans to the OTLP collector.
+// A no-op provider is returned when tracing is disabled.
+func New(tracingCfg *config.Tracing, appCfg *config.App) (trace.TracerProvider, error) {
+	if !tracingCfg.Enabled {
+		return noop.NewTracerProvider(), nil
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(tracingCfg.OTLPEndpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(tracingCfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(tracingCfg.ServiceName),
+			semconv.DeploymentEnvironment(appCfg.Env),
+		)),
+	), nil
+}
+
+// Shutdown exports the pending spans of the tracer provider and stops it.
+// It does nothing for the providers not exporting spans.
+func Shutdown(ctx context.Context, tracerProvider trace.TracerProvider) error {
+	if provider, ok := tracerProvider.(interface{ Shutdown(context.Context) error }); ok {
+		return provider.Shutdown(ctx)
+	}
+	return nil
+}
+
+// EndSpan ends the span, marking it failed with the error if err is not nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
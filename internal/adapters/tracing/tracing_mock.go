@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TracerProviderMock is a tracer provider keeping the spans in memory, for the tests to assert on them.
+type TracerProviderMock struct {
+	*sdktrace.TracerProvider
+	recorder *tracetest.SpanRecorder
+}
+
+// NewTracerProviderMock creates a new TracerProviderMock instance.
+func NewTracerProviderMock() *TracerProviderMock {
+	recorder := tracetest.NewSpanRecorder()
+	return &TracerProviderMock{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		recorder:       recorder,
+	}
+}
+
+// Span returns the first ended span with the name.
+func (m *TracerProviderMock) Span(name string) (sdktrace.ReadOnlySpan, bool) {
+	for _, span := range m.recorder.Ended() {
+		if span.Name() == name {
+			return span, true
+		}
+	}
+	return nil, false
+}
+
+// SpanAttribute returns the value of the attribute of the span, or an empty string if it is missing.
+func SpanAttribute(span sdktrace.ReadOnlySpan, key string) string {
+	for _, attr := range span.Attributes() {
+		if string(attr.Key) == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
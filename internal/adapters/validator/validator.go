@@ -2,7 +2,6 @@ package validator
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"go-starter/internal/domain"
 	"log/slog"
@@ -39,7 +38,7 @@ func notBlank(fl validator.FieldLevel) bool {
 }
 
 // ErrInvalidJSON is returned when the JSON payload is invalid.
-var ErrInvalidJSON = errors.New("invalid json")
+var ErrInvalidJSON = domain.ErrInvalidJSON
 
 // validationMessages holds custom error messages for specific validation failures.
 var validationMessages = map[string]error{
@@ -61,10 +60,17 @@ var validationMessages = map[string]error{
 	"resetPasswordRequest.PasswordConfirmation.required": domain.ErrPasswordConfirmationRequired,
 
 	// Users
+	"presignAvatarUploadRequest.ContentType.required":     domain.ErrContentTypeRequired,
+	"presignAvatarUploadRequest.Size.required":            domain.ErrFileSizeRequired,
+	"presignAvatarUploadRequest.Size.gt":                  domain.ErrFileSizeRequired,
+	"updateLocaleRequest.Locale.required":                 domain.ErrLocaleRequired,
 	"updatePasswordRequest.Password.required":             domain.ErrPasswordRequired,
 	"updatePasswordRequest.Password.min":                  domain.ErrPasswordTooShort,
 	"updatePasswordRequest.Password.eqfield":              domain.ErrPasswordsNotMatch,
 	"updatePasswordRequest.PasswordConfirmation.required": domain.ErrPasswordConfirmationRequired,
+
+	// Admin
+	"updateLogLevelRequest.Level.required": domain.ErrLogLevelRequired,
 }
 
 // ValidateRequest takes a payload from an HTTP request and verifies it.
@@ -73,8 +79,7 @@ func ValidateRequest(w http.ResponseWriter, r *http.Request, payload interface{}
 	defer func() {
 		err := r.Body.Close()
 		if err != nil {
-			errMsg := fmt.Sprintf("failed to close body: %v", err)
-			slog.Error(errMsg)
+			slog.ErrorContext(r.Context(), "failed to close body", "error", err)
 		}
 	}()
 	decoder := json.NewDecoder(r.Body)
@@ -6,9 +6,11 @@ import (
 	"go-starter/internal/adapters"
 	"go-starter/internal/adapters/errtracker"
 	"go-starter/internal/adapters/server/handlers"
+	"go-starter/internal/adapters/tracing"
 	"go-starter/internal/domain/ports"
 	"go-starter/internal/domain/services"
 	"log/slog"
+	"time"
 )
 
 // Application is the main application struct.
@@ -20,14 +22,16 @@ type Application struct {
 }
 
 // New creates a new Application instance.
-func New(ctx context.Context, cfg *config.Container) (*Application, func()) {
+// The level of the logs is changed at runtime through the logLevel controller.
+func New(ctx context.Context, cfg *config.Container, logLevel ports.LogLevelController) (*Application, func()) {
 	errTracker := errtracker.New(cfg)
 
 	apiAdapters := adapters.New(ctx, cfg, errTracker)
 	apiServices := services.New(cfg, apiAdapters)
-	apiHandlers := handlers.New(apiServices, errTracker)
+	apiHandlers := handlers.New(cfg, apiServices, errTracker, logLevel)
 
-	cleanup := createCleanupFunction(apiAdapters)
+	stopJobs := startJobs(cfg, apiServices, errTracker)
+	cleanup := createCleanupFunction(apiAdapters, stopJobs)
 
 	app := &Application{
 		ErrTracker: errTracker,
@@ -40,9 +44,12 @@ func New(ctx context.Context, cfg *config.Container) (*Application, func()) {
 }
 
 // createCleanupFunction creates a cleanup function for the application.
-func createCleanupFunction(apiAdapters *adapters.Adapters) func() {
+// The background jobs are stopped before the adapters they use are closed.
+func createCleanupFunction(apiAdapters *adapters.Adapters, stopJobs func()) func() {
 	return func() {
 		slog.Info("cleaning app")
+		stopJobs()
+
 		err := apiAdapters.DB.Close()
 		if err != nil {
 			slog.Error("failed to close database", "error", err)
@@ -51,5 +58,12 @@ func createCleanupFunction(apiAdapters *adapters.Adapters) func() {
 		if err != nil {
 			slog.Error("failed to close cache repository", "error", err)
 		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = tracing.Shutdown(ctx, apiAdapters.TracerProvider)
+		if err != nil {
+			slog.Error("failed to export the pending spans", "error", err)
+		}
 	}
 }
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/domain/ports"
+	"go-starter/internal/domain/services"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// startJobs starts the background jobs of the application, their failures and panics are reported to the error tracker.
+// Returns a function stopping the jobs and waiting for the running ones to finish.
+func startJobs(cfg *config.Container, apiServices *services.Services, errTracker ports.ErrTrackerAdapter) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runPeriodically(ctx, errTracker, "pending uploads cleanup", cfg.FileUpload.PendingUploadCleanupInterval, func(ctx context.Context) error {
+			deleted, err := apiServices.FileUploadService.CleanupPendingUploads(ctx)
+			if deleted > 0 {
+				slog.Info("deleted unconfirmed uploads", "count", deleted)
+			}
+			return err
+		})
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// runPeriodically runs a job at each interval until the context is canceled.
+// A failed or panicking run is logged and reported, the job is retried at the next interval.
+func runPeriodically(ctx context.Context, errTracker ports.ErrTrackerAdapter, name string, interval time.Duration, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := runJob(ctx, errTracker, job); err != nil {
+				slog.Error("background job failed", "job", name, "error", err)
+			}
+		}
+	}
+}
+
+// runJob runs a job once with its own error tracker scope, recovering from its panic.
+// The panic is reported to the error tracker with its stack trace, and returned as an error.
+func runJob(ctx context.Context, errTracker ports.ErrTrackerAdapter, job func(ctx context.Context) error) (err error) {
+	ctx = errTracker.NewScope(ctx)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = errtracker.PanicError(recovered)
+			errTracker.CaptureException(ctx, err)
+		}
+	}()
+
+	return job(ctx)
+}
@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/i18n"
+	"strconv"
+	"time"
+)
+
+// EmailDeliveryStatus represents the outcome of the last attempt to send an email.
+type EmailDeliveryStatus string
+
+// Email delivery status constants.
+const (
+	EmailDeliveryPending    EmailDeliveryStatus = "pending"
+	EmailDeliverySent       EmailDeliveryStatus = "sent"
+	EmailDeliveryFailed     EmailDeliveryStatus = "failed"
+	EmailDeliverySuppressed EmailDeliveryStatus = "suppressed"
+	EmailDeliveryThrottled  EmailDeliveryStatus = "throttled"
+)
+
+// String converts the EmailDeliveryStatus to its string representation.
+func (s EmailDeliveryStatus) String() string {
+	return string(s)
+}
+
+// ParseEmailDeliveryStatus creates an EmailDeliveryStatus from a string.
+// Returns domain.ErrInvalidEmailDeliveryStatus if the status is unknown.
+func ParseEmailDeliveryStatus(s string) (EmailDeliveryStatus, error) {
+	status := EmailDeliveryStatus(s)
+	switch status {
+	case EmailDeliveryPending, EmailDeliverySent, EmailDeliveryFailed, EmailDeliverySuppressed, EmailDeliveryThrottled:
+		return status, nil
+	default:
+		return "", domain.ErrInvalidEmailDeliveryStatus
+	}
+}
+
+// EmailDeliveryID is a type that represents a unique identifier for an email delivery.
+type EmailDeliveryID int64
+
+// String returns the string representation of the EmailDeliveryID.
+func (id EmailDeliveryID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// ParseEmailDeliveryID creates an EmailDeliveryID from a string.
+func ParseEmailDeliveryID(s string) (EmailDeliveryID, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, domain.ErrInvalidEmailDeliveryID
+	}
+	return EmailDeliveryID(id), nil
+}
+
+// EmailDelivery is an entity that represents an email sent, or attempted to be sent, by the application.
+// Subject and Body are kept as rendered so that a failed email can be resent as is,
+// unless Redacted: the secrets of the body, such as one-time tokens, are not kept, and the email is rendered again
+// from its template in the locale of the user, with new secrets.
+type EmailDelivery struct {
+	ID                EmailDeliveryID
+	Template          string
+	UserID            UserID
+	Locale            i18n.Locale
+	Recipients        []string
+	Subject           string
+	Body              string
+	Redacted          bool
+	Status            EmailDeliveryStatus
+	ProviderMessageID string
+	Error             string
+	Attempts          int
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// EmailDeliveryFilter represents the criteria used to list email deliveries.
+// Empty fields are ignored.
+type EmailDeliveryFilter struct {
+	Status    EmailDeliveryStatus
+	Template  string
+	Recipient string
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// SuppressionReason represents the reason why an email address is suppressed.
+type SuppressionReason string
+
+// Suppression reason constants define why the provider stopped accepting mail for an address.
+const (
+	SuppressionReasonBounce    SuppressionReason = "bounce"
+	SuppressionReasonComplaint SuppressionReason = "complaint"
+)
+
+// String converts the SuppressionReason to its string representation.
+func (r SuppressionReason) String() string {
+	return string(r)
+}
+
+// EmailSuppression is an entity that represents an email address that must not receive emails anymore.
+type EmailSuppression struct {
+	Email     string
+	Reason    SuppressionReason
+	Details   string
+	CreatedAt time.Time
+}
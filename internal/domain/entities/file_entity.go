@@ -0,0 +1,97 @@
+package entities
+
+import (
+	"go-starter/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileVisibility represents who can access a stored file.
+type FileVisibility string
+
+// File visibility constants.
+const (
+	// FileVisibilityPublic files are served through permanent URLs.
+	FileVisibilityPublic FileVisibility = "public"
+	// FileVisibilityPrivate files are only served to their owner, through signed expiring URLs.
+	FileVisibilityPrivate FileVisibility = "private"
+)
+
+// String converts the FileVisibility to its string representation.
+func (v FileVisibility) String() string {
+	return string(v)
+}
+
+// ParseFileVisibility creates a FileVisibility from a string.
+// Returns domain.ErrInvalidFileVisibility if the visibility is unknown.
+func ParseFileVisibility(s string) (FileVisibility, error) {
+	visibility := FileVisibility(s)
+	switch visibility {
+	case FileVisibilityPublic, FileVisibilityPrivate:
+		return visibility, nil
+	default:
+		return "", domain.ErrInvalidFileVisibility
+	}
+}
+
+// FileStatus represents whether a stored file can be downloaded.
+type FileStatus string
+
+// File status constants.
+const (
+	// FileStatusQuarantined files are held until their malware scan passes.
+	FileStatusQuarantined FileStatus = "quarantined"
+	// FileStatusAvailable files passed their malware scan and can be downloaded.
+	FileStatusAvailable FileStatus = "available"
+)
+
+// String converts the FileStatus to its string representation.
+func (s FileStatus) String() string {
+	return string(s)
+}
+
+// FileID is a type that represents a unique identifier for a stored file, based on UUID.
+type FileID uuid.UUID
+
+// NewFileID generates a new random FileID.
+func NewFileID() FileID {
+	return FileID(uuid.New())
+}
+
+// String returns the string representation of the FileID.
+func (id FileID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// ParseFileID creates a FileID from a string.
+func ParseFileID(s string) (FileID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return FileID{}, domain.ErrInvalidFileID
+	}
+	return FileID(id), nil
+}
+
+// File is an entity that represents a file stored for a user.
+// ContentType is detected from the content of the file, Checksum is its hex-encoded SHA-256 digest.
+// URL is only set for available public files, private ones are served through signed URLs generated on demand.
+type File struct {
+	ID          FileID
+	OwnerID     UserID
+	Key         string
+	Name        string
+	ContentType string
+	Size        int64
+	Checksum    string
+	Visibility  FileVisibility
+	Status      FileStatus
+	URL         string
+	CreatedAt   time.Time
+}
+
+// StorageUsage represents the storage used by a user, and the quota it is limited to, in bytes.
+type StorageUsage struct {
+	Used  int64
+	Quota int64
+}
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"go-starter/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadID is a type that represents a unique identifier for a pending upload, based on UUID.
+type UploadID uuid.UUID
+
+// NewUploadID generates a new random UploadID.
+func NewUploadID() UploadID {
+	return UploadID(uuid.New())
+}
+
+// String returns the string representation of the UploadID.
+func (id UploadID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// ParseUploadID creates an UploadID from a string.
+func ParseUploadID(s string) (UploadID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return UploadID{}, domain.ErrInvalidUploadID
+	}
+	return UploadID(id), nil
+}
+
+// PendingUpload is an entity that represents a file uploaded directly to the storage, waiting for its confirmation.
+// ContentType and Size are the ones declared when the upload URL was requested, the uploaded file must match them.
+type PendingUpload struct {
+	ID          UploadID
+	UserID      UserID
+	Key         string
+	ContentType string
+	Size        int64
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
@@ -2,6 +2,8 @@ package entities
 
 import (
 	"go-starter/internal/domain"
+	"go-starter/internal/domain/i18n"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,7 +23,9 @@ type User struct {
 	Email           string
 	IsEmailVerified bool
 	RoleID          RoleID
-	AvatarURL       *string
+	AvatarURLs      map[string]string
+	AvatarFileIDs   []FileID
+	Locale          i18n.Locale
 }
 
 // NilUserID is the nil UserID.
@@ -59,6 +63,18 @@ func (r RoleID) Int() int {
 	return int(r)
 }
 
+// String returns the name of the RoleID, as used in the configuration.
+func (r RoleID) String() string {
+	switch r {
+	case RoleAdmin:
+		return "admin"
+	case RoleUser:
+		return "user"
+	default:
+		return strconv.Itoa(int(r))
+	}
+}
+
 // UpdateUserParams holds the parameters required for updating a user's information.
 type UpdateUserParams struct {
 	Password             *string
@@ -14,6 +14,10 @@ var (
 	ErrUnauthorized = errors.New("unauthorized")
 	// ErrBadRequest represents a bad request error.
 	ErrBadRequest = errors.New("bad request")
+	// ErrInvalidJSON represents an error for a malformed JSON payload.
+	ErrInvalidJSON = errors.New("invalid json")
+	// ErrServiceUnavailable represents an error when a dependency of the service is temporarily unavailable.
+	ErrServiceUnavailable = errors.New("service temporarily unavailable")
 )
 
 // File upload errors.
@@ -28,6 +32,34 @@ var (
 	ErrInvalidMultipartForm = errors.New("invalid multipart form")
 	// ErrInvalidFileType represents an invalid file type error.
 	ErrInvalidFileType = errors.New("invalid file type")
+	// ErrFileNotFound represents an error when a stored file is not found.
+	ErrFileNotFound = errors.New("file not found")
+	// ErrInvalidFileSignature represents an error when a file URL signature is invalid or expired.
+	ErrInvalidFileSignature = errors.New("invalid or expired file signature")
+	// ErrInvalidImage represents an error when an uploaded image cannot be decoded.
+	ErrInvalidImage = errors.New("invalid image")
+	// ErrImageTooLarge represents an error when the dimensions of an uploaded image exceed the limits.
+	ErrImageTooLarge = errors.New("image dimensions too large")
+	// ErrInvalidUploadID represents an error for an invalid upload ID format.
+	ErrInvalidUploadID = errors.New("invalid upload id")
+	// ErrUploadNotFound represents an error when a pending upload is not found or has expired.
+	ErrUploadNotFound = errors.New("upload not found")
+	// ErrUploadNotCompleted represents an error when confirming an upload whose file has not been uploaded.
+	ErrUploadNotCompleted = errors.New("the file has not been uploaded")
+	// ErrUploadMismatch represents an error when an uploaded file does not match its upload request.
+	ErrUploadMismatch = errors.New("the uploaded file does not match the upload request")
+	// ErrInvalidFileID represents an error for an invalid file ID format.
+	ErrInvalidFileID = errors.New("invalid file id")
+	// ErrInvalidFileVisibility represents an error for an unknown file visibility.
+	ErrInvalidFileVisibility = errors.New("invalid file visibility")
+	// ErrStorageQuotaExceeded represents an error when a file does not fit in the storage quota of its owner.
+	ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
+	// ErrFileInfected represents an error when a file is rejected by the malware scan.
+	ErrFileInfected = errors.New("file rejected by the malware scan")
+	// ErrFileScanFailed represents an error when a file cannot be scanned for malware.
+	ErrFileScanFailed = errors.New("file could not be scanned")
+	// ErrFileQuarantined represents an error when a file is not available until its malware scan passes.
+	ErrFileQuarantined = errors.New("file is being scanned")
 )
 
 // Auth errors.
@@ -48,8 +80,32 @@ var (
 	ErrEmailAlreadyVerified = errors.New("email already verified")
 )
 
+// Mailer errors.
+var (
+	// ErrEmailSuppressed represents an error when a recipient is on the suppression list.
+	ErrEmailSuppressed = errors.New("email address cannot receive emails")
+	// ErrEmailSuppressionNotFound represents an error when an email address is not on the suppression list.
+	ErrEmailSuppressionNotFound = errors.New("email suppression not found")
+	// ErrInvalidWebhookSignature represents an error when a webhook payload cannot be authenticated.
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	// ErrInvalidEmailDeliveryID represents an error for an invalid email delivery ID format.
+	ErrInvalidEmailDeliveryID = errors.New("invalid email delivery id")
+	// ErrInvalidEmailDeliveryStatus represents an error for an unknown email delivery status.
+	ErrInvalidEmailDeliveryStatus = errors.New("invalid email delivery status")
+	// ErrEmailDeliveryNotFound represents an error when an email delivery is not found.
+	ErrEmailDeliveryNotFound = errors.New("email delivery not found")
+	// ErrEmailDeliveryNotResendable represents an error when resending an email that did not fail.
+	ErrEmailDeliveryNotResendable = errors.New("only failed emails can be resent")
+	// ErrEmailDeliveryRedacted represents an error when resending an email whose one-time tokens were not kept.
+	ErrEmailDeliveryRedacted = errors.New("emails with one-time tokens cannot be resent, a new one must be requested")
+	// ErrEmailThrottled represents an error when too many emails were sent to a recipient or a user.
+	ErrEmailThrottled = errors.New("too many emails sent, please try again later")
+)
+
 // Errors not returned in responses.
 var (
 	// ErrCacheNotFound represents an error for an empty cache value for a given key.
 	ErrCacheNotFound = errors.New("cache not found")
+	// ErrCacheUnavailable represents an error when the cache server cannot be reached.
+	ErrCacheUnavailable = errors.New("cache unavailable")
 )
@@ -0,0 +1,98 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"go-starter/internal/domain"
+)
+
+// Locale represents a language supported by the application (e.g., "en", "fr").
+type Locale string
+
+// Supported locales.
+const (
+	LocaleEnglish Locale = "en"
+	LocaleFrench  Locale = "fr"
+)
+
+// DefaultLocale is the locale used when no preference is known or the preference is not supported.
+const DefaultLocale = LocaleEnglish
+
+// SupportedLocales lists every locale having a message catalogue, the default locale first.
+var SupportedLocales = []Locale{LocaleEnglish, LocaleFrench}
+
+// catalogues maps each supported locale to its message catalogue.
+var catalogues = map[Locale]map[string]string{
+	LocaleEnglish: english,
+	LocaleFrench:  french,
+}
+
+// String returns the string representation of the Locale.
+func (l Locale) String() string {
+	return string(l)
+}
+
+// IsSupported reports whether the locale has a message catalogue.
+func (l Locale) IsSupported() bool {
+	_, ok := catalogues[l]
+	return ok
+}
+
+// OrDefault returns the locale if it is supported, DefaultLocale otherwise.
+func (l Locale) OrDefault() Locale {
+	if l.IsSupported() {
+		return l
+	}
+	return DefaultLocale
+}
+
+// ParseLocale creates a Locale from a string.
+// Returns domain.ErrLocaleInvalid if the locale is not supported.
+func ParseLocale(s string) (Locale, error) {
+	locale := Locale(s)
+	if !locale.IsSupported() {
+		return "", domain.ErrLocaleInvalid
+	}
+	return locale, nil
+}
+
+// Translate returns the message identified by key in the given locale, formatted with args.
+// It falls back to the default locale, then to the key itself, when no translation exists.
+func Translate(locale Locale, key string, args ...any) string {
+	message, ok := catalogues[locale.OrDefault()][key]
+	if !ok {
+		message, ok = catalogues[DefaultLocale][key]
+		if !ok {
+			return key
+		}
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// ErrorKey returns the stable key identifying a domain error, whatever the locale.
+// Returns ErrKeyUnknown if the error is not part of the catalogue.
+func ErrorKey(err error) string {
+	if key, ok := errorKeys[err]; ok {
+		return key
+	}
+	for target, key := range errorKeys {
+		if errors.Is(err, target) {
+			return key
+		}
+	}
+	return ErrKeyUnknown
+}
+
+// TranslateError returns the message of a domain error in the given locale.
+// Errors that are not part of the catalogue keep their original message.
+func TranslateError(locale Locale, err error) string {
+	key := ErrorKey(err)
+	if key == ErrKeyUnknown {
+		return err.Error()
+	}
+	return Translate(locale, key)
+}
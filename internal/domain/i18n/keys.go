@@ -0,0 +1,160 @@
+package i18n
+
+import "go-starter/internal/domain"
+
+// Error keys are stable identifiers returned next to translated error messages.
+// They must never change once released, clients rely on them.
+const (
+	ErrKeyUnknown = "unknown_error"
+
+	// Generic errors
+	ErrKeyInternal           = "internal_error"
+	ErrKeyForbidden          = "forbidden"
+	ErrKeyUnauthorized       = "unauthorized"
+	ErrKeyBadRequest         = "bad_request"
+	ErrKeyInvalidJSON        = "invalid_json"
+	ErrKeyServiceUnavailable = "service_unavailable"
+
+	// File upload errors
+	ErrKeyFileUpload            = "file_upload_error"
+	ErrKeyFileTooLarge          = "file_too_large"
+	ErrKeyMissingBoundary       = "missing_boundary"
+	ErrKeyInvalidMultipartForm  = "invalid_multipart_form"
+	ErrKeyInvalidFileType       = "invalid_file_type"
+	ErrKeyFileNotFound          = "file_not_found"
+	ErrKeyInvalidFileSignature  = "invalid_file_signature"
+	ErrKeyInvalidImage          = "invalid_image"
+	ErrKeyImageTooLarge         = "image_too_large"
+	ErrKeyInvalidUploadID       = "invalid_upload_id"
+	ErrKeyUploadNotFound        = "upload_not_found"
+	ErrKeyUploadNotCompleted    = "upload_not_completed"
+	ErrKeyUploadMismatch        = "upload_mismatch"
+	ErrKeyInvalidFileID         = "invalid_file_id"
+	ErrKeyInvalidFileVisibility = "invalid_file_visibility"
+	ErrKeyStorageQuotaExceeded  = "storage_quota_exceeded"
+	ErrKeyFileInfected          = "file_infected"
+	ErrKeyFileScanFailed        = "file_scan_failed"
+	ErrKeyFileQuarantined       = "file_quarantined"
+
+	// Auth errors
+	ErrKeyInvalidToken       = "invalid_token"
+	ErrKeyInvalidCredentials = "invalid_credentials"
+
+	// User errors
+	ErrKeyInvalidUserID                = "invalid_user_id"
+	ErrKeyUserNotFound                 = "user_not_found"
+	ErrKeyEmailAlreadyVerified         = "email_already_verified"
+	ErrKeyUsernameConflict             = "username_conflict"
+	ErrKeyEmailConflict                = "email_conflict"
+	ErrKeyUsernameRequired             = "username_required"
+	ErrKeyPasswordRequired             = "password_required"
+	ErrKeyPasswordConfirmationRequired = "password_confirmation_required"
+	ErrKeyNameRequired                 = "name_required"
+	ErrKeyEmailRequired                = "email_required"
+	ErrKeyPasswordsNotMatch            = "passwords_not_match"
+	ErrKeyPasswordTooShort             = "password_too_short"
+	ErrKeyUsernameTooShort             = "username_too_short"
+	ErrKeyUsernameTooLong              = "username_too_long"
+	ErrKeyUsernameInvalid              = "username_invalid"
+	ErrKeyNameTooLong                  = "name_too_long"
+	ErrKeyEmailInvalid                 = "email_invalid"
+	ErrKeyLocaleRequired               = "locale_required"
+	ErrKeyContentTypeRequired          = "content_type_required"
+	ErrKeyFileSizeRequired             = "file_size_required"
+	ErrKeyLocaleInvalid                = "locale_invalid"
+	ErrKeyLogLevelRequired             = "log_level_required"
+	ErrKeyLogLevelInvalid              = "log_level_invalid"
+
+	// Mailer errors
+	ErrKeyEmailSuppressed            = "email_suppressed"
+	ErrKeyEmailSuppressionNotFound   = "email_suppression_not_found"
+	ErrKeyInvalidWebhookSignature    = "invalid_webhook_signature"
+	ErrKeyInvalidEmailDeliveryID     = "invalid_email_delivery_id"
+	ErrKeyInvalidEmailDeliveryStatus = "invalid_email_delivery_status"
+	ErrKeyEmailDeliveryNotFound      = "email_delivery_not_found"
+	ErrKeyEmailDeliveryNotResendable = "email_delivery_not_resendable"
+	ErrKeyEmailDeliveryRedacted      = "email_delivery_redacted"
+	ErrKeyEmailThrottled             = "email_throttled"
+)
+
+// Mail template keys.
+const (
+	MailVerifyEmailSubject   = "mail.verify_email.subject"
+	MailVerifyEmailBody      = "mail.verify_email.body"
+	MailResetPasswordSubject = "mail.reset_password.subject"
+	MailResetPasswordBody    = "mail.reset_password.body"
+)
+
+// errorKeys maps domain errors to their stable keys.
+var errorKeys = map[error]string{
+	// Generic errors
+	domain.ErrInternal:           ErrKeyInternal,
+	domain.ErrForbidden:          ErrKeyForbidden,
+	domain.ErrUnauthorized:       ErrKeyUnauthorized,
+	domain.ErrBadRequest:         ErrKeyBadRequest,
+	domain.ErrInvalidJSON:        ErrKeyInvalidJSON,
+	domain.ErrServiceUnavailable: ErrKeyServiceUnavailable,
+
+	// File upload errors
+	domain.ErrFileUpload:            ErrKeyFileUpload,
+	domain.ErrFileTooLarge:          ErrKeyFileTooLarge,
+	domain.ErrMissingBoundary:       ErrKeyMissingBoundary,
+	domain.ErrInvalidMultipartForm:  ErrKeyInvalidMultipartForm,
+	domain.ErrInvalidFileType:       ErrKeyInvalidFileType,
+	domain.ErrFileNotFound:          ErrKeyFileNotFound,
+	domain.ErrInvalidFileSignature:  ErrKeyInvalidFileSignature,
+	domain.ErrInvalidImage:          ErrKeyInvalidImage,
+	domain.ErrImageTooLarge:         ErrKeyImageTooLarge,
+	domain.ErrInvalidUploadID:       ErrKeyInvalidUploadID,
+	domain.ErrUploadNotFound:        ErrKeyUploadNotFound,
+	domain.ErrUploadNotCompleted:    ErrKeyUploadNotCompleted,
+	domain.ErrUploadMismatch:        ErrKeyUploadMismatch,
+	domain.ErrInvalidFileID:         ErrKeyInvalidFileID,
+	domain.ErrInvalidFileVisibility: ErrKeyInvalidFileVisibility,
+	domain.ErrStorageQuotaExceeded:  ErrKeyStorageQuotaExceeded,
+	domain.ErrFileInfected:          ErrKeyFileInfected,
+	domain.ErrFileScanFailed:        ErrKeyFileScanFailed,
+	domain.ErrFileQuarantined:       ErrKeyFileQuarantined,
+
+	// Auth errors
+	domain.ErrInvalidToken:       ErrKeyInvalidToken,
+	domain.ErrInvalidCredentials: ErrKeyInvalidCredentials,
+
+	// User errors
+	domain.ErrInvalidUserId:        ErrKeyInvalidUserID,
+	domain.ErrUserNotFound:         ErrKeyUserNotFound,
+	domain.ErrEmailAlreadyVerified: ErrKeyEmailAlreadyVerified,
+
+	// Validation errors
+	domain.ErrUsernameRequired:             ErrKeyUsernameRequired,
+	domain.ErrPasswordRequired:             ErrKeyPasswordRequired,
+	domain.ErrPasswordConfirmationRequired: ErrKeyPasswordConfirmationRequired,
+	domain.ErrNameRequired:                 ErrKeyNameRequired,
+	domain.ErrEmailRequired:                ErrKeyEmailRequired,
+	domain.ErrPasswordsNotMatch:            ErrKeyPasswordsNotMatch,
+	domain.ErrPasswordTooShort:             ErrKeyPasswordTooShort,
+	domain.ErrUsernameTooShort:             ErrKeyUsernameTooShort,
+	domain.ErrUsernameConflict:             ErrKeyUsernameConflict,
+	domain.ErrUsernameTooLong:              ErrKeyUsernameTooLong,
+	domain.ErrUsernameInvalid:              ErrKeyUsernameInvalid,
+	domain.ErrNameTooLong:                  ErrKeyNameTooLong,
+	domain.ErrEmailInvalid:                 ErrKeyEmailInvalid,
+	domain.ErrEmailConflict:                ErrKeyEmailConflict,
+	domain.ErrLocaleRequired:               ErrKeyLocaleRequired,
+	domain.ErrContentTypeRequired:          ErrKeyContentTypeRequired,
+	domain.ErrFileSizeRequired:             ErrKeyFileSizeRequired,
+	domain.ErrLocaleInvalid:                ErrKeyLocaleInvalid,
+	domain.ErrLogLevelRequired:             ErrKeyLogLevelRequired,
+	domain.ErrLogLevelInvalid:              ErrKeyLogLevelInvalid,
+
+	// Mailer errors
+	domain.ErrEmailSuppressed:            ErrKeyEmailSuppressed,
+	domain.ErrEmailSuppressionNotFound:   ErrKeyEmailSuppressionNotFound,
+	domain.ErrInvalidWebhookSignature:    ErrKeyInvalidWebhookSignature,
+	domain.ErrInvalidEmailDeliveryID:     ErrKeyInvalidEmailDeliveryID,
+	domain.ErrInvalidEmailDeliveryStatus: ErrKeyInvalidEmailDeliveryStatus,
+	domain.ErrEmailDeliveryNotFound:      ErrKeyEmailDeliveryNotFound,
+	domain.ErrEmailDeliveryNotResendable: ErrKeyEmailDeliveryNotResendable,
+	domain.ErrEmailDeliveryRedacted:      ErrKeyEmailDeliveryRedacted,
+	domain.ErrEmailThrottled:             ErrKeyEmailThrottled,
+}
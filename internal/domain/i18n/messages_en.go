@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"fmt"
+	"go-starter/internal/domain"
+)
+
+// english is the English message catalogue.
+var english = map[string]string{
+	// Generic errors
+	ErrKeyInternal:           "internal error",
+	ErrKeyForbidden:          "forbidden",
+	ErrKeyUnauthorized:       "unauthorized",
+	ErrKeyBadRequest:         "bad request",
+	ErrKeyInvalidJSON:        "invalid json",
+	ErrKeyServiceUnavailable: "service temporarily unavailable, please try again later",
+
+	// File upload errors
+	ErrKeyFileUpload:            "file upload error",
+	ErrKeyFileTooLarge:          "file too large",
+	ErrKeyMissingBoundary:       "missing boundary",
+	ErrKeyInvalidMultipartForm:  "invalid multipart form",
+	ErrKeyInvalidFileType:       "invalid file type",
+	ErrKeyFileNotFound:          "file not found",
+	ErrKeyInvalidFileSignature:  "invalid or expired file signature",
+	ErrKeyInvalidImage:          "invalid image",
+	ErrKeyImageTooLarge:         "image dimensions too large",
+	ErrKeyInvalidUploadID:       "invalid upload id",
+	ErrKeyUploadNotFound:        "upload not found",
+	ErrKeyUploadNotCompleted:    "the file has not been uploaded",
+	ErrKeyUploadMismatch:        "the uploaded file does not match the upload request",
+	ErrKeyInvalidFileID:         "invalid file id",
+	ErrKeyInvalidFileVisibility: "invalid file visibility",
+	ErrKeyStorageQuotaExceeded:  "storage quota exceeded",
+	ErrKeyFileInfected:          "file rejected by the malware scan",
+	ErrKeyFileScanFailed:        "file could not be scanned, please try again later",
+	ErrKeyFileQuarantined:       "file is being scanned, please try again later",
+
+	// Auth errors
+	ErrKeyInvalidToken:       "invalid token",
+	ErrKeyInvalidCredentials: "invalid credentials",
+
+	// User errors
+	ErrKeyInvalidUserID:                "invalid user id",
+	ErrKeyUserNotFound:                 "user not found",
+	ErrKeyEmailAlreadyVerified:         "email already verified",
+	ErrKeyUsernameConflict:             "username already taken",
+	ErrKeyEmailConflict:                "email already taken",
+	ErrKeyUsernameRequired:             "username is required",
+	ErrKeyPasswordRequired:             "password is required",
+	ErrKeyPasswordConfirmationRequired: "password confirmation required",
+	ErrKeyNameRequired:                 "name is required",
+	ErrKeyEmailRequired:                "email is required",
+	ErrKeyPasswordsNotMatch:            "passwords does not match",
+	ErrKeyPasswordTooShort:             fmt.Sprintf("password is too short, it should be at least %d characters", domain.PasswordMinLength),
+	ErrKeyUsernameTooShort:             fmt.Sprintf("username is too short, it should be at least %d characters", domain.UsernameMinLength),
+	ErrKeyUsernameTooLong:              fmt.Sprintf("username is too long, it should be at most %d characters", domain.UsernameMaxLength),
+	ErrKeyUsernameInvalid:              "username can only contain alphanumeric characters and underscore",
+	ErrKeyNameTooLong:                  fmt.Sprintf("name is too long, it should be at most %d characters", domain.NameMaxLength),
+	ErrKeyEmailInvalid:                 "email is invalid",
+	ErrKeyLocaleRequired:               "locale is required",
+	ErrKeyContentTypeRequired:          "content type is required",
+	ErrKeyFileSizeRequired:             "file size is required",
+	ErrKeyLocaleInvalid:                "locale is not supported",
+	ErrKeyLogLevelRequired:             "log level is required",
+	ErrKeyLogLevelInvalid:              "log level should be debug, info, warn or error",
+
+	// Mailer errors
+	ErrKeyEmailSuppressed:            "email address cannot receive emails",
+	ErrKeyEmailSuppressionNotFound:   "email suppression not found",
+	ErrKeyInvalidWebhookSignature:    "invalid webhook signature",
+	ErrKeyInvalidEmailDeliveryID:     "invalid email delivery id",
+	ErrKeyInvalidEmailDeliveryStatus: "invalid email delivery status",
+	ErrKeyEmailDeliveryNotFound:      "email delivery not found",
+	ErrKeyEmailDeliveryNotResendable: "only failed emails can be resent",
+	ErrKeyEmailDeliveryRedacted:      "emails with one-time tokens cannot be resent, a new one must be requested",
+	ErrKeyEmailThrottled:             "too many emails sent, please try again later",
+
+	// Mail templates
+	MailVerifyEmailSubject:   "Verify your email!",
+	MailVerifyEmailBody:      `Hello, verify your email by visiting <a href="%s/users/me/verify-email/%s">this link</a>!<br><br>This link will expire in %.0f hours.<br>token: %s`,
+	MailResetPasswordSubject: "Reset your password!",
+	MailResetPasswordBody:    `Hello, reset your password by visiting <a href="%s/auth/password-reset?token=%s">this link</a>!<br><br>This link will expire in %.0f minutes.<br>token: %s`,
+}
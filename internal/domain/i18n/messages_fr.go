@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"fmt"
+	"go-starter/internal/domain"
+)
+
+// french is the French message catalogue.
+var french = map[string]string{
+	// Generic errors
+	ErrKeyInternal:           "erreur interne",
+	ErrKeyForbidden:          "accès interdit",
+	ErrKeyUnauthorized:       "non autorisé",
+	ErrKeyBadRequest:         "requête invalide",
+	ErrKeyInvalidJSON:        "json invalide",
+	ErrKeyServiceUnavailable: "service temporairement indisponible, veuillez réessayer plus tard",
+
+	// File upload errors
+	ErrKeyFileUpload:            "erreur lors de l'envoi du fichier",
+	ErrKeyFileTooLarge:          "fichier trop volumineux",
+	ErrKeyMissingBoundary:       "délimiteur manquant",
+	ErrKeyInvalidMultipartForm:  "formulaire multipart invalide",
+	ErrKeyInvalidFileType:       "type de fichier invalide",
+	ErrKeyFileNotFound:          "fichier introuvable",
+	ErrKeyInvalidFileSignature:  "signature du fichier invalide ou expirée",
+	ErrKeyInvalidImage:          "image invalide",
+	ErrKeyImageTooLarge:         "dimensions de l'image trop grandes",
+	ErrKeyInvalidUploadID:       "identifiant d'envoi invalide",
+	ErrKeyUploadNotFound:        "envoi introuvable",
+	ErrKeyUploadNotCompleted:    "le fichier n'a pas été envoyé",
+	ErrKeyUploadMismatch:        "le fichier envoyé ne correspond pas à la demande d'envoi",
+	ErrKeyInvalidFileID:         "identifiant de fichier invalide",
+	ErrKeyInvalidFileVisibility: "visibilité de fichier invalide",
+	ErrKeyStorageQuotaExceeded:  "quota de stockage dépassé",
+	ErrKeyFileInfected:          "fichier rejeté par l'analyse antivirus",
+	ErrKeyFileScanFailed:        "le fichier n'a pas pu être analysé, veuillez réessayer plus tard",
+	ErrKeyFileQuarantined:       "le fichier est en cours d'analyse, veuillez réessayer plus tard",
+
+	// Auth errors
+	ErrKeyInvalidToken:       "jeton invalide",
+	ErrKeyInvalidCredentials: "identifiants invalides",
+
+	// User errors
+	ErrKeyInvalidUserID:                "identifiant utilisateur invalide",
+	ErrKeyUserNotFound:                 "utilisateur introuvable",
+	ErrKeyEmailAlreadyVerified:         "email déjà vérifié",
+	ErrKeyUsernameConflict:             "nom d'utilisateur déjà pris",
+	ErrKeyEmailConflict:                "email déjà utilisé",
+	ErrKeyUsernameRequired:             "le nom d'utilisateur est requis",
+	ErrKeyPasswordRequired:             "le mot de passe est requis",
+	ErrKeyPasswordConfirmationRequired: "la confirmation du mot de passe est requise",
+	ErrKeyNameRequired:                 "le nom est requis",
+	ErrKeyEmailRequired:                "l'email est requis",
+	ErrKeyPasswordsNotMatch:            "les mots de passe ne correspondent pas",
+	ErrKeyPasswordTooShort:             fmt.Sprintf("le mot de passe est trop court, il doit contenir au moins %d caractères", domain.PasswordMinLength),
+	ErrKeyUsernameTooShort:             fmt.Sprintf("le nom d'utilisateur est trop court, il doit contenir au moins %d caractères", domain.UsernameMinLength),
+	ErrKeyUsernameTooLong:              fmt.Sprintf("le nom d'utilisateur est trop long, il doit contenir au plus %d caractères", domain.UsernameMaxLength),
+	ErrKeyUsernameInvalid:              "le nom d'utilisateur ne peut contenir que des caractères alphanumériques et des underscores",
+	ErrKeyNameTooLong:                  fmt.Sprintf("le nom est trop long, il doit contenir au plus %d caractères", domain.NameMaxLength),
+	ErrKeyEmailInvalid:                 "l'email est invalide",
+	ErrKeyLocaleRequired:               "la langue est requise",
+	ErrKeyContentTypeRequired:          "le type de contenu est requis",
+	ErrKeyFileSizeRequired:             "la taille du fichier est requise",
+	ErrKeyLocaleInvalid:                "la langue n'est pas prise en charge",
+	ErrKeyLogLevelRequired:             "le niveau de log est requis",
+	ErrKeyLogLevelInvalid:              "le niveau de log doit être debug, info, warn ou error",
+
+	// Mailer errors
+	ErrKeyEmailSuppressed:            "cette adresse email ne peut pas recevoir d'emails",
+	ErrKeyEmailSuppressionNotFound:   "adresse email absente de la liste de suppression",
+	ErrKeyInvalidWebhookSignature:    "signature du webhook invalide",
+	ErrKeyInvalidEmailDeliveryID:     "identifiant d'envoi d'email invalide",
+	ErrKeyInvalidEmailDeliveryStatus: "statut d'envoi d'email invalide",
+	ErrKeyEmailDeliveryNotFound:      "envoi d'email introuvable",
+	ErrKeyEmailDeliveryNotResendable: "seuls les emails en échec peuvent être renvoyés",
+	ErrKeyEmailDeliveryRedacted:      "les emails contenant des jetons à usage unique ne peuvent pas être renvoyés, un nouveau doit être demandé",
+	ErrKeyEmailThrottled:             "trop d'emails envoyés, veuillez réessayer plus tard",
+
+	// Mail templates
+	MailVerifyEmailSubject:   "Vérifiez votre email !",
+	MailVerifyEmailBody:      `Bonjour, vérifiez votre email en visitant <a href="%s/users/me/verify-email/%s">ce lien</a> !<br><br>Ce lien expirera dans %.0f heures.<br>jeton : %s`,
+	MailResetPasswordSubject: "Réinitialisez votre mot de passe !",
+	MailResetPasswordBody:    `Bonjour, réinitialisez votre mot de passe en visitant <a href="%s/auth/password-reset?token=%s">ce lien</a> !<br><br>Ce lien expirera dans %.0f minutes.<br>jeton : %s`,
+}
@@ -1,12 +1,20 @@
 package mailtemplates
 
 import (
-	"fmt"
+	"go-starter/internal/domain/i18n"
 	"time"
 )
 
+// ResetPasswordTemplate is the name of the ResetPassword template, recorded in the email delivery log.
+const ResetPasswordTemplate = "reset_password"
+
+// ResetPasswordSubject returns the subject of the ResetPassword email template in the given locale.
+func ResetPasswordSubject(locale i18n.Locale) string {
+	return i18n.Translate(locale, i18n.MailResetPasswordSubject)
+}
+
 // ResetPassword is an email template to reset user's password.
-// Returns a string representing the mail body (HTML).
-func ResetPassword(baseURL, token string, expirationTime time.Duration) string {
-	return fmt.Sprintf(`Hello, reset your password by visiting <a href="%s/auth/password-reset?token=%s">this link</a>!<br><br>This link will expire in %.0f minutes.<br>token: %s`, baseURL, token, expirationTime.Minutes(), token)
+// Returns a string representing the mail body (HTML) in the given locale.
+func ResetPassword(locale i18n.Locale, baseURL, token string, expirationTime time.Duration) string {
+	return i18n.Translate(locale, i18n.MailResetPasswordBody, baseURL, token, expirationTime.Minutes(), token)
 }
@@ -1,12 +1,20 @@
 package mailtemplates
 
 import (
-	"fmt"
+	"go-starter/internal/domain/i18n"
 	"time"
 )
 
+// VerifyEmailTemplate is the name of the VerifyEmail template, recorded in the email delivery log.
+const VerifyEmailTemplate = "verify_email"
+
+// VerifyEmailSubject returns the subject of the VerifyEmail email template in the given locale.
+func VerifyEmailSubject(locale i18n.Locale) string {
+	return i18n.Translate(locale, i18n.MailVerifyEmailSubject)
+}
+
 // VerifyEmail is an email template to validate user's email.
-// Returns a string representing the mail body (HTML).
-func VerifyEmail(baseURL, token string, expirationTime time.Duration) string {
-	return fmt.Sprintf(`Hello, verify your email by visiting <a href="%s/users/me/verify-email/%s">this link</a>!<br><br>This link will expire in %.0f hours.<br>token: %s`, baseURL, token, expirationTime.Hours(), token)
+// Returns a string representing the mail body (HTML) in the given locale.
+func VerifyEmail(locale i18n.Locale, baseURL, token string, expirationTime time.Duration) string {
+	return i18n.Translate(locale, i18n.MailVerifyEmailBody, baseURL, token, expirationTime.Hours(), token)
 }
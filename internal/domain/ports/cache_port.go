@@ -6,16 +6,23 @@ import (
 )
 
 // CacheService is an interface for interacting with cache-related business logic.
+// The operations return domain.ErrServiceUnavailable when the cache server cannot be reached.
 type CacheService interface {
 	// Set stores the value in the cache with a specified key and time-to-live (TTL).
+	// The key is attached to the tags, so that InvalidateTags removes it.
 	// Returns an error if the operation fails (e.g., if the cache is unreachable).
-	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
 
 	// Get retrieves the value associated with the specified key from the cache.
 	// Returns the value as a byte slice and an error if the key is not found (domain.ErrCacheNotFound)
 	// or if there are issues accessing the cache.
 	Get(ctx context.Context, key string) ([]byte, error)
 
+	// Touch extends the time-to-live (TTL) of the key if it still exists, without changing its value.
+	// The key stays attached to the tags it was stored with, whose sets are kept alive as long as the key.
+	// Returns domain.ErrCacheNotFound if the key is not found, e.g. once its tags have been invalidated.
+	Touch(ctx context.Context, key string, ttl time.Duration, tags ...string) error
+
 	// Delete removes the value associated with the specified key from the cache.
 	// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
 	Delete(ctx context.Context, key string) error
@@ -24,22 +31,72 @@ type CacheService interface {
 	// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
 	DeleteByPrefix(ctx context.Context, prefix string) error
 
+	// InvalidateTags removes all values from the cache attached to one of the tags.
+	// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+	InvalidateTags(ctx context.Context, tags ...string) error
+
+	// Ping checks that the cache server is reachable.
+	// Returns domain.ErrServiceUnavailable if it is not.
+	Ping(ctx context.Context) error
+
+	// GetOrLoad retrieves the value associated with the specified key from the cache, loading and caching it on a miss.
+	// The concurrent misses of a key in the process share a single load, and a stale value is returned at once while
+	// it is reloaded in the background. The value is loaded without being cached when the cache is unavailable.
+	// Returns the error of the load as is, or opts.NotFoundErr while a missing value is cached.
+	GetOrLoad(ctx context.Context, key string, opts LoadOptions, load LoadFunc) ([]byte, error)
+
+	// Store stores a value in the cache so that GetOrLoad reads it, e.g. once it has been updated.
+	// Returns an error if the operation fails (e.g., if the cache is unreachable).
+	Store(ctx context.Context, key string, value []byte, opts LoadOptions) error
+
+	// Stats returns the hit and miss statistics of each layer of the cache, from the nearest to the farthest.
+	// Returns nil if the cache does not keep statistics.
+	Stats() []CacheStats
+
 	// Close closes the connection to the cache server, ensuring that all resources are freed.
 	// Returns an error if the operation fails (e.g., if there are issues closing the connection).
 	Close() error
 }
 
+// LoadFunc loads the value of a key missing from the cache, e.g. from the database.
+type LoadFunc func(ctx context.Context) ([]byte, error)
+
+// LoadOptions configures how CacheService.GetOrLoad caches the values it loads.
+type LoadOptions struct {
+	// TTL is the duration a loaded value is fresh.
+	TTL time.Duration
+	// Jitter is the maximum duration randomly removed from TTL, so that the values loaded together do not expire together.
+	Jitter time.Duration
+	// StaleTTL is the duration a value is still returned once it is no longer fresh, while it is reloaded in the background.
+	StaleTTL time.Duration
+	// Tags are attached to the loaded value, so that CacheService.InvalidateTags removes it.
+	Tags []string
+	// NotFoundErr is the error of the load reporting a missing value, cached for NotFoundTTL when both are set.
+	NotFoundErr error
+	NotFoundTTL time.Duration
+	// LockTimeout, when positive, makes a single instance of the application load a missing value under a
+	// distributed lock; the other instances wait for it up to LockTimeout, then load the value themselves.
+	LockTimeout time.Duration
+}
+
 // CacheRepository is an interface for interacting with cache-related data.
+// The errors caused by an unreachable cache server wrap domain.ErrCacheUnavailable.
 type CacheRepository interface {
 	// Set stores the value in the cache with a specified key and time-to-live (TTL).
+	// The key is attached to the tags, so that InvalidateTags removes it.
 	// Returns an error if the operation fails (e.g., if the cache is unreachable).
-	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
 
 	// Get retrieves the value associated with the specified key from the cache.
 	// Returns the value as a byte slice and an error if the key is not found
 	// or if there are issues accessing the cache.
 	Get(ctx context.Context, key string) ([]byte, error)
 
+	// Touch extends the time-to-live (TTL) of the key if it still exists, without changing its value.
+	// The key stays attached to the tags it was stored with, whose sets are kept alive as long as the key.
+	// Returns domain.ErrCacheNotFound if the key is not found or if there are issues accessing the cache.
+	Touch(ctx context.Context, key string, ttl time.Duration, tags ...string) error
+
 	// Delete removes the value associated with the specified key from the cache.
 	// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
 	Delete(ctx context.Context, key string) error
@@ -48,6 +105,14 @@ type CacheRepository interface {
 	// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
 	DeleteByPrefix(ctx context.Context, prefix string) error
 
+	// InvalidateTags removes all values from the cache attached to one of the tags.
+	// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+	InvalidateTags(ctx context.Context, tags ...string) error
+
+	// Ping checks that the cache server is reachable.
+	// Returns an error wrapping domain.ErrCacheUnavailable if it is not.
+	Ping(ctx context.Context) error
+
 	// Close closes the connection to the cache server, ensuring that all resources are freed.
 	// Returns an error if the operation fails (e.g., if there are issues closing the connection).
 	Close() error
@@ -56,3 +121,20 @@ type CacheRepository interface {
 	// Returns the result of the script and an error if the operation fails (e.g., if there are issues executing the script).
 	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
 }
+
+// CacheStatsReporter is implemented by the cache repositories keeping hit and miss statistics.
+type CacheStatsReporter interface {
+	// Stats returns the statistics of each layer of the cache, from the nearest to the farthest.
+	Stats() []CacheStats
+}
+
+// CacheStats represents the statistics of a layer of the cache since the application started.
+// Evictions, Entries and Size (in bytes) are only known for the layers held in memory.
+type CacheStats struct {
+	Layer     string
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int64
+	Size      int64
+}
@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"context"
+	"go-starter/internal/domain/entities"
+)
+
+// EmailSuppressionService is an interface for interacting with the email suppression list.
+type EmailSuppressionService interface {
+	// HandleNotification verifies and processes a delivery notification sent by the email provider.
+	// Hard bounces and complaints add the recipients to the suppression list.
+	// Returns domain.ErrInvalidWebhookSignature if the notification cannot be authenticated.
+	HandleNotification(ctx context.Context, payload []byte) error
+
+	// List returns the suppressed email addresses, most recent first.
+	// Returns an error if the retrieval fails.
+	List(ctx context.Context, limit, offset int) ([]*entities.EmailSuppression, error)
+
+	// Delete removes an email address from the suppression list.
+	// Returns domain.ErrEmailSuppressionNotFound if the address is not suppressed.
+	Delete(ctx context.Context, email string) error
+}
+
+// EmailSuppressionRepository is an interface for interacting with email suppression data.
+type EmailSuppressionRepository interface {
+	// Upsert inserts an email suppression, or updates the reason and details if the address is already suppressed.
+	// Returns an error if the operation fails.
+	Upsert(ctx context.Context, suppression *entities.EmailSuppression) error
+
+	// GetSuppressed returns the subset of the given email addresses that are suppressed.
+	// Returns an error if the retrieval fails.
+	GetSuppressed(ctx context.Context, emails []string) ([]string, error)
+
+	// List returns the suppressed email addresses, most recent first.
+	// Returns an error if the retrieval fails.
+	List(ctx context.Context, limit, offset int) ([]*entities.EmailSuppression, error)
+
+	// Delete removes an email address from the suppression list.
+	// Returns domain.ErrEmailSuppressionNotFound if the address is not suppressed.
+	Delete(ctx context.Context, email string) error
+}
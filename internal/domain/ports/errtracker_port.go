@@ -1,29 +1,45 @@
 package ports
 
 import (
+	"context"
 	"net/http"
 	"time"
 )
 
 // ErrTrackerAdapter is an interface for interacting with error monitoring business logic.
+// The details of the reports are set on the scope bound to a context, so that the concurrent requests
+// never share them: each request gets its own scope from Handle, any other work from NewScope.
+// The details set with a context bound to no scope are dropped.
 type ErrTrackerAdapter interface {
-	// SetUser associates the current scope with user information identified by
+	// NewScope returns a copy of the context bound to a new scope, cloned from the scope
+	// the context is bound to, if any. The details set with the returned context are only
+	// attached to the reports captured with it.
+	NewScope(ctx context.Context) context.Context
+	// SetUser associates the scope of the context with user information identified by
 	// the provided ID and IP address.
-	SetUser(id, ipAddr string)
-	// SetRequest attaches the provided HTTP request to the current scope for
+	SetUser(ctx context.Context, id, ipAddr string)
+	// SetRequest attaches the provided HTTP request to the scope of the context for
 	// additional context in error reports.
-	SetRequest(r *http.Request)
-	// SetBody attaches the provided request body to the current scope for
-	// additional context in error reports.
-	SetBody(body []byte)
+	SetRequest(ctx context.Context, r *http.Request)
+	// SetBody attaches the provided body of a request of the route (e.g., "POST /v1/auth/login")
+	// with the content type to the scope of the context for additional context in error reports.
+	// Only the part of the body allowed by the PII policy of the adapter is attached.
+	SetBody(ctx context.Context, route, contentType string, body []byte)
+	// SetTrace attaches the IDs of the trace and span of the request to the scope of the context,
+	// to correlate the error reports with the traces.
+	SetTrace(ctx context.Context, traceID, spanID string)
+	// SetRequestID attaches the ID of the request to the scope of the context,
+	// to look up the error reports from the ID sent back to the client.
+	SetRequestID(ctx context.Context, requestID string)
 	// Handle wraps the provided http.Handler with a middleware for automatic
-	// error tracking and request monitoring.
+	// error tracking and request monitoring, binding the context of each request to a new scope.
 	Handle(handler http.Handler) http.Handler
-	// CaptureException sends an error and returns the event ID as a string.
-	CaptureException(err error) string
-	// AddBreadcrumb adds a new breadcrumb to the current scope with the specified
+	// CaptureException sends an error with the details of the scope of the context,
+	// and returns the event ID as a string.
+	CaptureException(ctx context.Context, err error) string
+	// AddBreadcrumb adds a new breadcrumb to the scope of the context with the specified
 	// message and options. Breadcrumbs track the series of events leading up to an error.
-	AddBreadcrumb(message string, options BreadCrumbOptions)
+	AddBreadcrumb(ctx context.Context, message string, options BreadCrumbOptions)
 	// Flush waits for queued events to be sent for the specified duration.
 	// It should be called before program termination to ensure all events are sent.
 	Flush(duration time.Duration)
@@ -0,0 +1,69 @@
+package ports
+
+import (
+	"context"
+	"go-starter/internal/domain/entities"
+	"io"
+	"time"
+)
+
+// FileService is a service that stores the files of the users, within their storage quota.
+type FileService interface {
+	// Upload scans a file for malware and stores it for a user, its content type is detected from its content.
+	// Returns the stored file, domain.ErrFileTooLarge if it exceeds the maximum file size,
+	// domain.ErrStorageQuotaExceeded if it does not fit in the storage quota of the user,
+	// domain.ErrFileInfected if the scan rejects it, domain.ErrFileScanFailed if it cannot be scanned, or an error if the upload fails.
+	Upload(ctx context.Context, ownerID entities.UserID, name string, visibility entities.FileVisibility, body io.Reader) (*entities.File, error)
+	// StoreGenerated stores a file generated by the application from content it has already scanned,
+	// e.g. a resized variant of an avatar, without scanning it again.
+	// Returns the stored file, domain.ErrFileTooLarge if it exceeds the maximum file size,
+	// domain.ErrStorageQuotaExceeded if it does not fit in the storage quota of the user, or an error if the upload fails.
+	StoreGenerated(ctx context.Context, ownerID entities.UserID, name string, visibility entities.FileVisibility, body io.Reader) (*entities.File, error)
+	// Download returns a file of a user and the link it is downloaded from, signed and expiring for a private file.
+	// Returns domain.ErrFileNotFound if the file does not exist or belongs to another user,
+	// or domain.ErrFileQuarantined if its malware scan has not passed.
+	Download(ctx context.Context, ownerID entities.UserID, id entities.FileID) (*entities.File, *FileLink, error)
+	// List returns the files of a user, most recent first.
+	// Returns an error if the retrieval fails.
+	List(ctx context.Context, ownerID entities.UserID, limit, offset int) ([]*entities.File, error)
+	// Usage returns the storage used by a user, and its quota.
+	// Returns an error if the retrieval fails.
+	Usage(ctx context.Context, ownerID entities.UserID) (*entities.StorageUsage, error)
+	// Delete deletes a file of a user.
+	// Returns domain.ErrFileNotFound if the file does not exist or belongs to another user, or an error if the deletion fails.
+	Delete(ctx context.Context, ownerID entities.UserID, id entities.FileID) error
+}
+
+// FileLink represents the URL a file is downloaded from.
+// ExpiresAt is zero for the public files, whose URL does not expire.
+type FileLink struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// FileRepository is an interface for interacting with the metadata of the stored files.
+type FileRepository interface {
+	// Create inserts a file, unless the total size of the files of its owner would then exceed quota bytes.
+	// Returns domain.ErrStorageQuotaExceeded if the file does not fit in the quota, or an error if the operation fails.
+	Create(ctx context.Context, file *entities.File, quota int64) error
+
+	// GetByID returns a file.
+	// Returns domain.ErrFileNotFound if the file does not exist.
+	GetByID(ctx context.Context, id entities.FileID) (*entities.File, error)
+
+	// ListByOwner returns the files of a user, most recent first.
+	// Returns an error if the retrieval fails.
+	ListByOwner(ctx context.Context, ownerID entities.UserID, limit, offset int) ([]*entities.File, error)
+
+	// GetUsage returns the total size, in bytes, of the files of a user.
+	// Returns an error if the retrieval fails.
+	GetUsage(ctx context.Context, ownerID entities.UserID) (int64, error)
+
+	// MarkAvailable sets a quarantined file available, once stored and served from the given URL.
+	// Returns an error if the operation fails.
+	MarkAvailable(ctx context.Context, id entities.FileID, url string) error
+
+	// Delete removes a file.
+	// Returns an error if the operation fails.
+	Delete(ctx context.Context, id entities.FileID) error
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"io"
+)
+
+// FileScanner scans the uploaded files for malware, before they are made available.
+type FileScanner interface {
+	// Scan reads a file to its end and scans it.
+	// Returns the verdict of the scan, or an error if the file cannot be scanned.
+	Scan(ctx context.Context, body io.Reader) (*ScanResult, error)
+}
+
+// ScanResult represents the verdict of a malware scan.
+// Signature is the name of the malware found in an infected file.
+type ScanResult struct {
+	Infected  bool
+	Signature string
+}
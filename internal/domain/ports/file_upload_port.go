@@ -4,24 +4,114 @@ import (
 	"context"
 	"go-starter/internal/domain/entities"
 	"io"
+	"net/http"
+	"net/url"
+	"time"
 )
 
 // FileUploadService is a service that uploads files to a file upload service.
 type FileUploadService interface {
-	// UploadAvatar uploads a user avatar to the S3 bucket.
-	// Returns the URL of the uploaded file or an error if the upload fails.
-	UploadAvatar(ctx context.Context, userID entities.UserID, filename string, body io.Reader) (string, error)
-	// DeleteAvatar deletes a user avatar from the S3 bucket.
+	// UploadAvatar validates a user avatar and stores each of its variants as a public file of the user.
+	// Returns the files of the variants keyed by size, or an error if the image is rejected or the upload fails.
+	UploadAvatar(ctx context.Context, userID entities.UserID, body io.Reader) (map[string]*entities.File, error)
+	// DeleteAvatar deletes the files storing the variants of a user avatar.
 	// Returns an error if the deletion fails.
-	DeleteAvatar(ctx context.Context, userID entities.UserID, avatarURL string) error
+	DeleteAvatar(ctx context.Context, userID entities.UserID, fileIDs []entities.FileID) error
+	// PresignAvatarUpload returns a short-lived URL the client uploads its avatar to, without going through the API.
+	// Returns domain.ErrInvalidFileType or domain.ErrFileTooLarge if the declared file is not accepted.
+	PresignAvatarUpload(ctx context.Context, userID entities.UserID, contentType string, size int64) (*entities.PendingUpload, *PresignedUpload, error)
+	// ConfirmAvatarUpload checks the file uploaded to a presigned URL, then processes it like UploadAvatar.
+	// Returns the files of the variants keyed by size, domain.ErrUploadNotFound if the upload does not exist or has expired,
+	// domain.ErrUploadNotCompleted if the file has not been uploaded, or domain.ErrUploadMismatch if it does not match the upload request.
+	ConfirmAvatarUpload(ctx context.Context, userID entities.UserID, uploadID entities.UploadID) (map[string]*entities.File, error)
+	// CleanupPendingUploads deletes the uploads that were never confirmed, and their files.
+	// Returns the number of uploads deleted.
+	CleanupPendingUploads(ctx context.Context) (int, error)
+	// OpenFile opens a file served by the application from a signed URL.
+	// Returns domain.ErrInvalidFileSignature if the URL signature is invalid or expired,
+	// or domain.ErrFileNotFound if the file does not exist or the storage does not serve its files itself.
+	OpenFile(ctx context.Context, key string, params url.Values) (*StoredFile, error)
+	// StoreFile stores a file uploaded to a presigned URL of a storage whose files are served by the application.
+	// Returns domain.ErrInvalidFileSignature if the URL signature is invalid, expired or does not match the content type,
+	// domain.ErrFileTooLarge if the file is larger than allowed, or domain.ErrFileNotFound if the storage serves its files itself.
+	StoreFile(ctx context.Context, key string, params url.Values, contentType string, body io.Reader) error
 }
 
 // FileUploadAdapter is an adapter for the FileUploadService interface.
 type FileUploadAdapter interface {
-	// Upload uploads a file to the S3 bucket.
+	// Upload uploads a file to the S3 bucket, served with the given content type.
 	// Returns the URL of the uploaded file or an error if the upload fails.
-	Upload(ctx context.Context, key string, body io.Reader) (string, error)
+	Upload(ctx context.Context, key, contentType string, body io.Reader) (string, error)
 	// Delete deletes a file from the S3 bucket.
 	// Returns an error if the deletion fails.
 	Delete(ctx context.Context, key string) error
+	// PresignUpload returns a URL the client uploads a file to until expiresAt.
+	// The file must have the given content type and size.
+	// Returns an error if the URL cannot be signed.
+	PresignUpload(ctx context.Context, key, contentType string, size int64, expiresAt time.Time) (*PresignedUpload, error)
+	// PresignDownload returns a URL the file is downloaded from until expiresAt, whether or not the storage serves it publicly.
+	// Returns an error if the URL cannot be signed.
+	PresignDownload(ctx context.Context, key string, expiresAt time.Time) (string, error)
+	// Stat returns the information of a file.
+	// Returns domain.ErrFileNotFound if the file does not exist.
+	Stat(ctx context.Context, key string) (*FileInfo, error)
+	// Download opens a file for reading, the content must be closed by the caller.
+	// Returns domain.ErrFileNotFound if the file does not exist.
+	Download(ctx context.Context, key string) (io.ReadCloser, error)
+	// Ping checks that the storage is reachable.
+	// Returns an error if it is not.
+	Ping(ctx context.Context) error
+}
+
+// FileServerAdapter is implemented by the storages whose files are served by the application, through signed URLs.
+type FileServerAdapter interface {
+	// Open verifies the signature of a file URL and opens the file.
+	// Returns domain.ErrInvalidFileSignature if the signature is invalid or expired,
+	// or domain.ErrFileNotFound if the file does not exist.
+	Open(ctx context.Context, key string, params url.Values) (*StoredFile, error)
+	// Store verifies the signature of a presigned upload URL and writes the file.
+	// Returns domain.ErrInvalidFileSignature if the signature is invalid, expired or does not match the content type,
+	// or domain.ErrFileTooLarge if the file is larger than the signed size.
+	Store(ctx context.Context, key string, params url.Values, contentType string, body io.Reader) error
+}
+
+// StoredFile represents a file opened from the storage.
+// Content must be closed by the caller.
+type StoredFile struct {
+	Content io.ReadSeekCloser
+	Name    string
+	ModTime time.Time
+}
+
+// FileInfo represents the information of a stored file.
+type FileInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// PresignedUpload represents the request the client sends to upload a file directly to the storage.
+type PresignedUpload struct {
+	URL       string
+	Method    string
+	Headers   http.Header
+	ExpiresAt time.Time
+}
+
+// PendingUploadRepository is an interface for interacting with the uploads waiting for their confirmation.
+type PendingUploadRepository interface {
+	// Create inserts a pending upload.
+	// Returns an error if the operation fails.
+	Create(ctx context.Context, upload *entities.PendingUpload) error
+
+	// GetByID returns a pending upload.
+	// Returns domain.ErrUploadNotFound if the upload does not exist.
+	GetByID(ctx context.Context, id entities.UploadID) (*entities.PendingUpload, error)
+
+	// Delete removes a pending upload.
+	// Returns an error if the operation fails.
+	Delete(ctx context.Context, id entities.UploadID) error
+
+	// ListExpired returns at most limit pending uploads that expired before the given time, oldest first.
+	// Returns an error if the retrieval fails.
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*entities.PendingUpload, error)
 }
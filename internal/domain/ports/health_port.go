@@ -0,0 +1,61 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// HealthChecker is a registry of the checks of the dependencies of the application.
+type HealthChecker interface {
+	// Register adds the check of a dependency to the registry.
+	Register(check HealthCheck)
+	// Check runs the registered checks concurrently, each within its timeout, and returns their report.
+	// The report is cached, so that frequent probes do not overload the dependencies.
+	// A failing check never panics nor returns an error, it is reported as down.
+	Check(ctx context.Context) HealthReport
+}
+
+// HealthCheck is the check of a dependency of the application.
+// The application is unavailable when a critical dependency is down, and degraded when another one is.
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	// Timeout bounds the duration of the check, the default timeout of the checker being used if it is zero.
+	Timeout time.Duration
+	Check   func(ctx context.Context) error
+}
+
+// Health statuses of the application.
+const (
+	HealthStatusReady       = "ready"
+	HealthStatusDegraded    = "degraded"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// Health statuses of a dependency.
+const (
+	HealthCheckStatusUp   = "up"
+	HealthCheckStatusDown = "down"
+)
+
+// HealthReport is the result of the checks of the dependencies, in their registration order.
+type HealthReport struct {
+	Status    string
+	CheckedAt time.Time
+	Checks    []HealthCheckResult
+}
+
+// Ready reports whether the application can serve requests, possibly degraded.
+func (r HealthReport) Ready() bool {
+	return r.Status != HealthStatusUnavailable
+}
+
+// HealthCheckResult is the result of the check of a dependency.
+// Error holds the reason the dependency is down, if it is.
+type HealthCheckResult struct {
+	Name     string
+	Critical bool
+	Status   string
+	Error    string
+	Duration time.Duration
+}
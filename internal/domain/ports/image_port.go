@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"io"
+)
+
+// ImageProcessor decodes the images uploaded by the users and re-encodes them to safe, fixed size variants.
+type ImageProcessor interface {
+	// Thumbnails decodes an image and returns it cropped to a square and resized to each of the given sizes.
+	// The variants are re-encoded without any of the metadata of the original image (EXIF, ...).
+	// Returns domain.ErrInvalidFileType if the content is not a supported image,
+	// domain.ErrInvalidImage if it cannot be decoded,
+	// or domain.ErrImageTooLarge if its dimensions exceed the limits.
+	Thumbnails(ctx context.Context, body io.Reader, sizes []int) ([]*Thumbnail, error)
+}
+
+// Thumbnail represents a square variant of an image.
+type Thumbnail struct {
+	Size      int
+	Extension string
+	Content   []byte
+}
@@ -0,0 +1,10 @@
+package ports
+
+// LogLevelController is an interface for changing the minimum level of the logs while the application runs.
+type LogLevelController interface {
+	// Level returns the minimum level of the logs: debug, info, warn or error.
+	Level() string
+	// SetLevel changes the minimum level of the logs.
+	// Returns domain.ErrLogLevelInvalid if the level is not one of debug, info, warn and error.
+	SetLevel(level string) error
+}
@@ -1,25 +1,112 @@
 package ports
 
+import (
+	"context"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/i18n"
+)
+
 // MailerService defines the interface for email service operations.
 // It provides a high-level abstraction for sending emails.
 type MailerService interface {
 	// Send sends an email message.
 	// It takes a pointer to EmailMessage and returns an error if the sending fails.
-	Send(msg *EmailMessage) error
+	// Suppressed recipients are skipped and domain.ErrEmailSuppressed is returned.
+	// Recipients over their quota are skipped and domain.ErrEmailThrottled is returned.
+	// Every message is recorded in the delivery log.
+	Send(ctx context.Context, msg *EmailMessage) error
+
+	// ListDeliveries returns the email delivery log matching the filter, most recent first.
+	// Returns an error if the retrieval fails.
+	ListDeliveries(ctx context.Context, filter entities.EmailDeliveryFilter) ([]*entities.EmailDelivery, error)
+
+	// Resend sends again an email whose delivery failed and returns the updated delivery.
+	// Returns domain.ErrEmailDeliveryNotFound if the delivery does not exist,
+	// or domain.ErrEmailDeliveryNotResendable if it did not fail,
+	// or domain.ErrEmailDeliveryRedacted if its secrets were not kept and it cannot be rendered again.
+	Resend(ctx context.Context, id entities.EmailDeliveryID) (*entities.EmailDelivery, error)
 }
 
 // MailerAdapter defines the interface for email adapter operations.
 // It provides low-level email sending functionality and connection management.
 type MailerAdapter interface {
 	// Send sends an email message.
-	// It takes an EmailMessage by value and returns an error if the sending fails.
-	Send(msg EmailMessage) error
+	// It takes an EmailMessage by value and returns the provider message ID, or an error if the sending fails.
+	Send(ctx context.Context, msg EmailMessage) (string, error)
+	// Ping checks that the email provider is reachable with the configured credentials.
+	// Returns an error if it is not.
+	Ping(ctx context.Context) error
+}
+
+// EmailDeliveryRepository defines the interface for the email delivery log.
+type EmailDeliveryRepository interface {
+	// Create inserts an email delivery and sets its ID and timestamps.
+	// Returns an error if the operation fails.
+	Create(ctx context.Context, delivery *entities.EmailDelivery) error
+
+	// Update saves the recipients, status, provider message ID, error and attempt count of an email delivery.
+	// Returns domain.ErrEmailDeliveryNotFound if the delivery does not exist.
+	Update(ctx context.Context, delivery *entities.EmailDelivery) error
+
+	// GetByID retrieves an email delivery by its ID.
+	// Returns domain.ErrEmailDeliveryNotFound if the delivery does not exist.
+	GetByID(ctx context.Context, id entities.EmailDeliveryID) (*entities.EmailDelivery, error)
+
+	// List returns the email deliveries matching the filter, most recent first.
+	// Returns an error if the retrieval fails.
+	List(ctx context.Context, filter entities.EmailDeliveryFilter) ([]*entities.EmailDelivery, error)
+}
+
+// MailerWebhookAdapter defines the interface for the delivery notifications sent by the email provider.
+type MailerWebhookAdapter interface {
+	// ParseNotification verifies the signature of a raw webhook payload and decodes it.
+	// Returns domain.ErrInvalidWebhookSignature if the payload cannot be authenticated.
+	ParseNotification(ctx context.Context, payload []byte) (*MailerNotification, error)
+
+	// ConfirmSubscription confirms the subscription of the webhook to the provider notifications.
+	// Returns an error if the confirmation fails.
+	ConfirmSubscription(ctx context.Context, notification *MailerNotification) error
 }
 
 // EmailMessage represents an email to be sent.
 // It contains the basic elements of an email message.
 type EmailMessage struct {
+	// Template is the name of the template used to render the message, recorded in the delivery log.
+	// It selects the quotas applied to the message.
+	Template string
+	// UserID is the user the message is sent on behalf of, counted in the user quotas.
+	// It is entities.NilUserID when the message is not related to a user.
+	UserID entities.UserID
+	// Locale is the locale the message is rendered in, recorded in the delivery log.
+	Locale  i18n.Locale
 	To      []string
 	Subject string
 	Body    string
+	// Secrets are the parts of the body that must not be kept, such as one-time tokens.
+	// They are redacted from the delivery log, the message being rendered again with new secrets when it is resent.
+	Secrets []string
+}
+
+// MailerNotificationType represents the type of delivery notification sent by the email provider.
+type MailerNotificationType string
+
+// Mailer notification type constants.
+const (
+	MailerNotificationSubscription MailerNotificationType = "subscription"
+	MailerNotificationBounce       MailerNotificationType = "bounce"
+	MailerNotificationComplaint    MailerNotificationType = "complaint"
+	MailerNotificationOther        MailerNotificationType = "other"
+)
+
+// MailerNotification represents a verified delivery notification sent by the email provider.
+type MailerNotification struct {
+	Type MailerNotificationType
+	// Recipients are the email addresses concerned by the bounce or complaint.
+	Recipients []string
+	// Permanent is true for hard bounces, which must stop any further delivery.
+	Permanent bool
+	// Details is a human-readable description of the notification.
+	Details string
+	// SubscribeURL is the URL used to confirm a subscription notification.
+	SubscribeURL string
 }
@@ -0,0 +1,40 @@
+package ports
+
+import "time"
+
+// Domain events counted by Metrics.IncDomainEvent.
+const (
+	DomainEventLogin                = "login"
+	DomainEventRegistration         = "registration"
+	DomainEventPasswordResetRequest = "password_reset_request"
+	DomainEventPasswordReset        = "password_reset"
+)
+
+// Outcomes of the domain events.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Cache operation results recorded by Metrics.ObserveCacheOperation.
+const (
+	CacheResultHit   = "hit"
+	CacheResultMiss  = "miss"
+	CacheResultOK    = "ok"
+	CacheResultError = "error"
+)
+
+// Metrics is an interface for recording the operational metrics of the application.
+// The implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveHTTPRequest records a served HTTP request with the pattern of its route, its status and its duration.
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+	// ObserveCacheOperation records a cache operation with its result (hit, miss, ok or error) and its duration.
+	ObserveCacheOperation(operation, result string, duration time.Duration)
+	// IncRateLimitRejection counts a request denied by the rate limit policy.
+	IncRateLimitRejection(policy string)
+	// IncMailSent counts an email of the template with the outcome of its delivery (sent, failed, suppressed or throttled).
+	IncMailSent(template, outcome string)
+	// IncDomainEvent counts a domain event (e.g., login, registration) with its outcome.
+	IncDomainEvent(event, outcome string)
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter is an interface for counting hits against a limit over a time window.
+type RateLimiter interface {
+	// Check records a hit for the key and reports whether it is within the limit over the window.
+	// Returns an error if the counter cannot be updated (e.g., if the cache is unreachable).
+	Check(ctx context.Context, key string, limit int64, window time.Duration) (*RateLimitResult, error)
+
+	// Peek reports whether a hit for the key would be within the limit over the window, without recording it.
+	// Returns an error if the counter cannot be read (e.g., if the cache is unreachable).
+	Peek(ctx context.Context, key string, limit int64, window time.Duration) (*RateLimitResult, error)
+}
+
+// RateLimitResult represents the outcome of a rate limit check.
+// ResetAfter is how long to wait before the next allowed hit when the hit is denied.
+type RateLimitResult struct {
+	Allowed    bool
+	Current    int64
+	Limit      int64
+	ResetAfter time.Duration
+}
@@ -12,7 +12,8 @@ type TokenService interface {
 	GenerateAuthToken(ctx context.Context, userID entities.UserID) (string, error)
 
 	// VerifyAuthToken verifies an access token.
-	// Returns the user ID or an error if the token is not found or if the token is invalid.
+	// Returns the user ID, domain.ErrInvalidToken if the token is not found or invalid,
+	// or domain.ErrServiceUnavailable if the tokens cannot be read because the cache is unavailable.
 	VerifyAuthToken(ctx context.Context, token string) (entities.UserID, error)
 
 	// RevokeAuthToken revokes an access token.
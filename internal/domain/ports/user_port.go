@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/i18n"
 	"io"
 )
 
@@ -24,8 +25,9 @@ type UserService interface {
 	// Returns the created user or an error if the registration fails (e.g., due to validation issues).
 	Register(ctx context.Context, user *entities.User) (*entities.User, error)
 
-	// UpdatePassword updates a user password.
-	// Returns an error if the update fails (e.g., due to validation issues).
+	// UpdatePassword updates a user password and revokes all the sessions and one-time tokens of the user.
+	// Returns an error if the update fails (e.g., due to validation issues),
+	// or domain.ErrServiceUnavailable if the sessions cannot be revoked because the cache is unavailable.
 	UpdatePassword(ctx context.Context, userID entities.UserID, params entities.UpdateUserParams) error
 
 	// VerifyEmail verifies a user email.
@@ -37,12 +39,24 @@ type UserService interface {
 	ResendEmailVerification(ctx context.Context, userID entities.UserID) error
 
 	// UpdateAvatar updates a user avatar.
-	// Returns an error if the update fails.
-	UpdateAvatar(ctx context.Context, userID entities.UserID, filename string, file io.Reader) (string, error)
+	// Returns the URLs of the avatar variants keyed by size, or an error if the update fails.
+	UpdateAvatar(ctx context.Context, userID entities.UserID, file io.Reader) (map[string]string, error)
+
+	// PresignAvatarUpload returns a short-lived URL the user uploads its avatar to, directly to the storage.
+	// Returns an error if the declared file is not accepted or if the URL cannot be signed.
+	PresignAvatarUpload(ctx context.Context, userID entities.UserID, contentType string, size int64) (*entities.PendingUpload, *PresignedUpload, error)
+
+	// ConfirmAvatarUpload updates a user avatar from a file uploaded to a presigned URL.
+	// Returns the URLs of the avatar variants keyed by size, or an error if the upload is not valid or the update fails.
+	ConfirmAvatarUpload(ctx context.Context, userID entities.UserID, uploadID entities.UploadID) (map[string]string, error)
 
 	// DeleteAvatar deletes a user avatar.
 	// Returns an error if the deletion fails.
 	DeleteAvatar(ctx context.Context, userID entities.UserID) error
+
+	// UpdateLocale updates a user preferred locale, used for the emails sent to the user.
+	// Returns an error if the locale is not supported or if the update fails.
+	UpdateLocale(ctx context.Context, userID entities.UserID, locale string) error
 }
 
 // UserRepository is an interface for interacting with user-related data.
@@ -75,11 +89,15 @@ type UserRepository interface {
 	// Returns the updated user or an error if the verification fails.
 	VerifyEmail(ctx context.Context, userID entities.UserID) (*entities.User, error)
 
-	// UpdateAvatar updates a user avatar.
+	// UpdateAvatar updates the URLs of a user avatar variants, and the files storing them.
 	// Returns an error if the update fails.
-	UpdateAvatar(ctx context.Context, userID entities.UserID, avatarURL string) error
+	UpdateAvatar(ctx context.Context, userID entities.UserID, avatarURLs map[string]string, avatarFileIDs []entities.FileID) error
 
 	// DeleteAvatar deletes a user avatar.
 	// Returns an error if the deletion fails.
 	DeleteAvatar(ctx context.Context, userID entities.UserID) error
+
+	// UpdateLocale updates a user preferred locale.
+	// Returns an error if the update fails.
+	UpdateLocale(ctx context.Context, userID entities.UserID, locale i18n.Locale) error
 }
@@ -17,6 +17,7 @@ type AuthService struct {
 	userSvc   ports.UserService
 	tokenSvc  ports.TokenService
 	mailerSvc ports.MailerService
+	metrics   ports.Metrics
 }
 
 // NewAuthService creates a new instance of AuthService.
@@ -25,6 +26,7 @@ func NewAuthService(
 	userSvc ports.UserService,
 	tokenSvc ports.TokenService,
 	mailerSvc ports.MailerService,
+	metrics ports.Metrics,
 ) *AuthService {
 
 	return &AuthService{
@@ -32,14 +34,26 @@ func NewAuthService(
 		userSvc:   userSvc,
 		tokenSvc:  tokenSvc,
 		mailerSvc: mailerSvc,
+		metrics:   metrics,
 	}
 }
 
+// recordEvent counts the domain event, failed if err is not nil.
+func (as *AuthService) recordEvent(event string, err error) {
+	outcome := ports.OutcomeSuccess
+	if err != nil {
+		outcome = ports.OutcomeFailure
+	}
+	as.metrics.IncDomainEvent(event, outcome)
+}
+
 // Login authenticates a user.
 // Returns auth tokens upon successful authentication,
 // or an error if the login fails (e.g., due to incorrect credentials).
-func (as *AuthService) Login(ctx context.Context, username, password string) (*entities.User, string, error) {
-	user, err := as.userSvc.GetByUsername(ctx, username)
+func (as *AuthService) Login(ctx context.Context, username, password string) (user *entities.User, accessToken string, err error) {
+	defer func() { as.recordEvent(ports.DomainEventLogin, err) }()
+
+	user, err = as.userSvc.GetByUsername(ctx, username)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
 			return nil, "", domain.ErrInvalidCredentials
@@ -52,7 +66,7 @@ func (as *AuthService) Login(ctx context.Context, username, password string) (*e
 		return nil, "", domain.ErrInvalidCredentials
 	}
 
-	accessToken, err := as.tokenSvc.GenerateAuthToken(ctx, user.ID)
+	accessToken, err = as.tokenSvc.GenerateAuthToken(ctx, user.ID)
 	if err != nil {
 		return nil, "", err
 	}
@@ -63,8 +77,10 @@ func (as *AuthService) Login(ctx context.Context, username, password string) (*e
 // Register registers a new user in the system.
 // Returns the created user entity and an error if the registration fails
 // (e.g., due to username already existing or validation issues).
-func (as *AuthService) Register(ctx context.Context, user *entities.User) (*entities.User, error) {
-	createdUser, err := as.userSvc.Register(ctx, user)
+func (as *AuthService) Register(ctx context.Context, user *entities.User) (createdUser *entities.User, err error) {
+	defer func() { as.recordEvent(ports.DomainEventRegistration, err) }()
+
+	createdUser, err = as.userSvc.Register(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -74,12 +90,17 @@ func (as *AuthService) Register(ctx context.Context, user *entities.User) (*enti
 		return nil, err
 	}
 
-	err = as.mailerSvc.Send(&ports.EmailMessage{
-		To:      []string{createdUser.Email},
-		Subject: "Verify your email!",
-		Body:    mailtemplates.VerifyEmail(as.cfg.Application.BaseURL, token, as.cfg.Token.EmailVerificationTokenDuration),
+	err = as.mailerSvc.Send(ctx, &ports.EmailMessage{
+		Template: mailtemplates.VerifyEmailTemplate,
+		UserID:   createdUser.ID,
+		Locale:   createdUser.Locale,
+		To:       []string{createdUser.Email},
+		Subject:  mailtemplates.VerifyEmailSubject(createdUser.Locale),
+		Body:     mailtemplates.VerifyEmail(createdUser.Locale, as.cfg.Application.BaseURL, token, as.cfg.Token.EmailVerificationTokenDuration),
+		Secrets:  []string{token},
 	})
-	if err != nil {
+	// The account exists even if the address is suppressed or throttled, the verification email can be resent later.
+	if err != nil && !errors.Is(err, domain.ErrEmailSuppressed) && !errors.Is(err, domain.ErrEmailThrottled) {
 		return nil, err
 	}
 
@@ -94,7 +115,9 @@ func (as *AuthService) Logout(ctx context.Context, accessToken string) error {
 
 // SendPasswordResetEmail sends a password reset email to the user.
 // Returns an error if the email fails to send.
-func (as *AuthService) SendPasswordResetEmail(ctx context.Context, email string) error {
+func (as *AuthService) SendPasswordResetEmail(ctx context.Context, email string) (err error) {
+	defer func() { as.recordEvent(ports.DomainEventPasswordResetRequest, err) }()
+
 	userID, err := as.userSvc.GetIDByVerifiedEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
@@ -103,17 +126,28 @@ func (as *AuthService) SendPasswordResetEmail(ctx context.Context, email string)
 		return err
 	}
 
+	// The recipient's stored locale is used, not the one of the request.
+	user, err := as.userSvc.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
 	token, err := as.tokenSvc.GenerateOneTimeToken(ctx, entities.PasswordResetToken, userID)
 	if err != nil {
 		return err
 	}
 
-	err = as.mailerSvc.Send(&ports.EmailMessage{
-		To:      []string{email},
-		Subject: "Reset your password!",
-		Body:    mailtemplates.ResetPassword(as.cfg.Application.BaseURL, token, as.cfg.Token.PasswordResetTokenDuration),
+	err = as.mailerSvc.Send(ctx, &ports.EmailMessage{
+		Template: mailtemplates.ResetPasswordTemplate,
+		UserID:   userID,
+		Locale:   user.Locale,
+		To:       []string{email},
+		Subject:  mailtemplates.ResetPasswordSubject(user.Locale),
+		Body:     mailtemplates.ResetPassword(user.Locale, as.cfg.Application.BaseURL, token, as.cfg.Token.PasswordResetTokenDuration),
+		Secrets:  []string{token},
 	})
-	if err != nil {
+	// Like unknown addresses, suppressed and throttled addresses are not disclosed, the email is silently dropped.
+	if err != nil && !errors.Is(err, domain.ErrEmailSuppressed) && !errors.Is(err, domain.ErrEmailThrottled) {
 		return err
 	}
 
@@ -128,20 +162,18 @@ func (as *AuthService) VerifyPasswordResetToken(ctx context.Context, token strin
 }
 
 // ResetPassword resets a user's password.
+// The token is consumed with the sessions and other one-time tokens of the user, revoked by the update of the password.
 // Returns an error if the password reset fails.
-func (as *AuthService) ResetPassword(ctx context.Context, token, password, passwordConfirmation string) error {
+func (as *AuthService) ResetPassword(ctx context.Context, token, password, passwordConfirmation string) (err error) {
+	defer func() { as.recordEvent(ports.DomainEventPasswordReset, err) }()
+
 	userID, err := as.tokenSvc.VerifyOneTimeToken(ctx, entities.PasswordResetToken, token)
 	if err != nil {
 		return err
 	}
 
-	err = as.userSvc.UpdatePassword(ctx, userID, entities.UpdateUserParams{
+	return as.userSvc.UpdatePassword(ctx, userID, entities.UpdateUserParams{
 		Password:             &password,
 		PasswordConfirmation: &passwordConfirmation,
 	})
-	if err != nil {
-		return err
-	}
-
-	return as.tokenSvc.ConsumeOneTimeToken(ctx, entities.PasswordResetToken, token)
 }
@@ -2,30 +2,45 @@ package services
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"go-starter/internal/domain"
 	"go-starter/internal/domain/ports"
+	"go-starter/internal/domain/utils"
+	"math/rand/v2"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 )
 
 // CacheService implements ports.CacheService interface and provides access to the cache repository
+// The errors of an unreachable cache server are returned as domain.ErrServiceUnavailable.
 type CacheService struct {
-	repo ports.CacheRepository
+	repo          ports.CacheRepository
+	timeGenerator ports.TimeGenerator
+
+	// loads shares the loads of the missing keys, refreshing holds the keys reloaded in the background.
+	loads      singleflight.Group
+	refreshing sync.Map
 }
 
 // NewCacheService creates a new cache service instance
-func NewCacheService(repo ports.CacheRepository) *CacheService {
+func NewCacheService(repo ports.CacheRepository, timeGenerator ports.TimeGenerator) *CacheService {
 	return &CacheService{
-		repo: repo,
+		repo:          repo,
+		timeGenerator: timeGenerator,
 	}
 }
 
 // Set stores the value in the cache with a specified key and time-to-live (TTL).
+// The key is attached to the tags, so that InvalidateTags removes it.
 // Returns an error if the operation fails (e.g., if the cache is unreachable).
-func (cs *CacheService) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
-	err := cs.repo.Set(ctx, key, value, ttl)
+func (cs *CacheService) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	err := cs.repo.Set(ctx, key, value, ttl, tags...)
 	if err != nil {
-		return domain.ErrInternal
+		return cacheError(err)
 	}
 	return nil
 }
@@ -39,17 +54,31 @@ func (cs *CacheService) Get(ctx context.Context, key string) ([]byte, error) {
 		if errors.Is(err, domain.ErrCacheNotFound) {
 			return nil, err
 		}
-		return nil, domain.ErrInternal
+		return nil, cacheError(err)
 	}
 	return value, nil
 }
 
+// Touch extends the time-to-live (TTL) of the key if it still exists, without changing its value.
+// Returns domain.ErrCacheNotFound if the key is not found,
+// or domain.ErrServiceUnavailable if there are issues accessing the cache.
+func (cs *CacheService) Touch(ctx context.Context, key string, ttl time.Duration, tags ...string) error {
+	err := cs.repo.Touch(ctx, key, ttl, tags...)
+	if err != nil {
+		if errors.Is(err, domain.ErrCacheNotFound) {
+			return err
+		}
+		return cacheError(err)
+	}
+	return nil
+}
+
 // Delete removes the value associated with the specified key from the cache.
 // Returns an error if the operation fails (e.g., if there are issues accessing the cache).
 func (cs *CacheService) Delete(ctx context.Context, key string) error {
 	err := cs.repo.Delete(ctx, key)
 	if err != nil {
-		return domain.ErrInternal
+		return cacheError(err)
 	}
 	return nil
 }
@@ -59,7 +88,267 @@ func (cs *CacheService) Delete(ctx context.Context, key string) error {
 func (cs *CacheService) DeleteByPrefix(ctx context.Context, prefix string) error {
 	err := cs.repo.DeleteByPrefix(ctx, prefix)
 	if err != nil {
-		return domain.ErrInternal
+		return cacheError(err)
+	}
+	return nil
+}
+
+// InvalidateTags removes all values from the cache attached to one of the tags.
+// Returns an error if the operation fails (e.g., if there are issues accessing the cache).
+func (cs *CacheService) InvalidateTags(ctx context.Context, tags ...string) error {
+	err := cs.repo.InvalidateTags(ctx, tags...)
+	if err != nil {
+		return cacheError(err)
+	}
+	return nil
+}
+
+// Ping checks that the cache server is reachable.
+// Returns domain.ErrServiceUnavailable if it is not.
+func (cs *CacheService) Ping(ctx context.Context) error {
+	err := cs.repo.Ping(ctx)
+	if err != nil {
+		return cacheError(err)
+	}
+	return nil
+}
+
+// Kinds of the entries stored by Store and GetOrLoad.
+const (
+	cacheEntryValue byte = iota + 1
+	cacheEntryNotFound
+)
+
+// cacheEntryHeaderSize is the size of the header of an entry: its kind, then the time it becomes stale in Unix nanoseconds.
+const cacheEntryHeaderSize = 9
+
+// Settings of the loads of GetOrLoad.
+const (
+	// loadLockPollInterval is the interval between two reads of a key being loaded by another instance.
+	loadLockPollInterval = 50 * time.Millisecond
+	// loadRefreshTimeout is the maximum duration of the reload of a stale value in the background.
+	loadRefreshTimeout = 10 * time.Second
+)
+
+// Lua scripts of the distributed lock of the loads.
+const (
+	loadLockAcquireScript = `
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return 1
+end
+return 0`
+	loadLockReleaseScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0`
+)
+
+// loadLockPrefix is the prefix of the keys of the distributed lock of the loads.
+const loadLockPrefix = "load_lock"
+
+// cacheEntry is a value stored by Store and GetOrLoad, or the record of a missing value.
+type cacheEntry struct {
+	kind    byte
+	staleAt time.Time
+	value   []byte
+}
+
+// encode returns the entry as stored in the cache.
+func (e *cacheEntry) encode() []byte {
+	raw := make([]byte, cacheEntryHeaderSize, cacheEntryHeaderSize+len(e.value))
+	raw[0] = e.kind
+	binary.BigEndian.PutUint64(raw[1:cacheEntryHeaderSize], uint64(e.staleAt.UnixNano()))
+	return append(raw, e.value...)
+}
+
+// decodeCacheEntry returns the entry stored in the cache.
+// Returns false if the value was not stored by Store or GetOrLoad.
+func decodeCacheEntry(raw []byte) (*cacheEntry, bool) {
+	if len(raw) < cacheEntryHeaderSize || (raw[0] != cacheEntryValue && raw[0] != cacheEntryNotFound) {
+		return nil, false
+	}
+	return &cacheEntry{
+		kind:    raw[0],
+		staleAt: time.Unix(0, int64(binary.BigEndian.Uint64(raw[1:cacheEntryHeaderSize]))),
+		value:   raw[cacheEntryHeaderSize:],
+	}, true
+}
+
+// result returns the value of the entry, or the error of the load reporting a missing value.
+func (e *cacheEntry) result(opts ports.LoadOptions) ([]byte, error) {
+	if e.kind == cacheEntryNotFound {
+		return nil, opts.NotFoundErr
+	}
+	return e.value, nil
+}
+
+// GetOrLoad retrieves the value associated with the specified key from the cache, loading and caching it on a miss.
+// The concurrent misses of a key in the process share a single load, and a stale value is returned at once while
+// it is reloaded in the background. The value is loaded without being cached when the cache is unavailable.
+// Returns the error of the load as is, or opts.NotFoundErr while a missing value is cached.
+func (cs *CacheService) GetOrLoad(ctx context.Context, key string, opts ports.LoadOptions, load ports.LoadFunc) ([]byte, error) {
+	raw, err := cs.repo.Get(ctx, key)
+	if errors.Is(err, domain.ErrCacheUnavailable) {
+		return load(ctx)
+	}
+	if err == nil {
+		if entry, ok := decodeCacheEntry(raw); ok {
+			if !cs.timeGenerator.Now().Before(entry.staleAt) {
+				cs.refresh(ctx, key, opts, load)
+			}
+			return entry.result(opts)
+		}
+	}
+
+	// The load is shared by the callers, it must not be canceled by the first one.
+	results := cs.loads.DoChan(key, func() (interface{}, error) {
+		return cs.load(context.WithoutCancel(ctx), key, opts, load)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-results:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]byte), nil
+	}
+}
+
+// refresh reloads a stale value in the background, unless it is already being reloaded.
+func (cs *CacheService) refresh(ctx context.Context, key string, opts ports.LoadOptions, load ports.LoadFunc) {
+	if _, loaded := cs.refreshing.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	go func() {
+		defer cs.refreshing.Delete(key)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadRefreshTimeout)
+		defer cancel()
+		_, _ = cs.load(ctx, key, opts, load)
+	}()
+}
+
+// load loads the value of a key and caches it, under the distributed lock if enabled.
+// The value is returned even if it cannot be cached.
+func (cs *CacheService) load(ctx context.Context, key string, opts ports.LoadOptions, load ports.LoadFunc) ([]byte, error) {
+	if opts.LockTimeout > 0 {
+		release, held := cs.acquireLoadLock(ctx, key, opts.LockTimeout)
+		defer release()
+		if held {
+			if entry, ok := cs.waitForLoad(ctx, key, opts.LockTimeout); ok {
+				return entry.result(opts)
+			}
+		}
+	}
+
+	value, err := load(ctx)
+	if err != nil {
+		if opts.NotFoundErr != nil && opts.NotFoundTTL > 0 && errors.Is(err, opts.NotFoundErr) {
+			entry := &cacheEntry{kind: cacheEntryNotFound, staleAt: cs.timeGenerator.Now().Add(opts.NotFoundTTL)}
+			_ = cs.repo.Set(ctx, key, entry.encode(), opts.NotFoundTTL, opts.Tags...)
+		}
+		return nil, err
+	}
+
+	_ = cs.Store(ctx, key, value, opts)
+	return value, nil
+}
+
+// acquireLoadLock takes the distributed lock of the load of a key.
+// Returns the function releasing it, and whether another instance holds it.
+// The lock is considered free when the cache cannot run the scripts.
+func (cs *CacheService) acquireLoadLock(ctx context.Context, key string, timeout time.Duration) (func(), bool) {
+	lockKey := utils.GenerateCacheKey(loadLockPrefix, key)
+	token := uuid.NewString()
+
+	result, err := cs.repo.Eval(ctx, loadLockAcquireScript, []string{lockKey}, token, timeout.Milliseconds())
+	if err != nil {
+		return func() {}, false
+	}
+	if acquired, _ := result.(int64); acquired != 1 {
+		return func() {}, true
+	}
+
+	return func() {
+		_, _ = cs.repo.Eval(ctx, loadLockReleaseScript, []string{lockKey}, token)
+	}, false
+}
+
+// waitForLoad waits up to the timeout for another instance to load a key.
+// Returns false if the key is still missing.
+func (cs *CacheService) waitForLoad(ctx context.Context, key string, timeout time.Duration) (*cacheEntry, bool) {
+	ticker := time.NewTicker(loadLockPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-deadline:
+			return nil, false
+		case <-ticker.C:
+			raw, err := cs.repo.Get(ctx, key)
+			if err != nil {
+				if errors.Is(err, domain.ErrCacheNotFound) {
+					continue
+				}
+				return nil, false
+			}
+			return decodeCacheEntry(raw)
+		}
+	}
+}
+
+// Store stores a value in the cache so that GetOrLoad reads it, e.g. once it has been updated.
+// Returns an error if the operation fails (e.g., if the cache is unreachable).
+func (cs *CacheService) Store(ctx context.Context, key string, value []byte, opts ports.LoadOptions) error {
+	ttl := opts.TTL
+	if opts.Jitter > 0 && opts.Jitter < ttl {
+		ttl -= rand.N(opts.Jitter)
+	}
+
+	entry := &cacheEntry{kind: cacheEntryValue, staleAt: cs.timeGenerator.Now().Add(ttl), value: value}
+	err := cs.repo.Set(ctx, key, entry.encode(), ttl+opts.StaleTTL, opts.Tags...)
+	if err != nil {
+		return cacheError(err)
+	}
+	return nil
+}
+
+// GetOrLoad is the typed version of CacheService.GetOrLoad, the values being cached as JSON.
+// Returns the error of the load as is, or opts.NotFoundErr while a missing value is cached.
+func GetOrLoad[T any](ctx context.Context, cacheSvc ports.CacheService, key string, opts ports.LoadOptions, load func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	raw, err := cacheSvc.GetOrLoad(ctx, key, opts, func(ctx context.Context) ([]byte, error) {
+		loaded, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := utils.Serialize(loaded)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		return raw, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	err = utils.Deserialize(raw, &value)
+	if err != nil {
+		return value, domain.ErrInternal
+	}
+	return value, nil
+}
+
+// Stats returns the hit and miss statistics of each layer of the cache, from the nearest to the farthest.
+// Returns nil if the cache repository does not keep statistics.
+func (cs *CacheService) Stats() []ports.CacheStats {
+	if reporter, ok := cs.repo.(ports.CacheStatsReporter); ok {
+		return reporter.Stats()
 	}
 	return nil
 }
@@ -73,3 +362,11 @@ func (cs *CacheService) Close() error {
 	}
 	return nil
 }
+
+// cacheError converts an error of the cache repository to the domain error returned by the service.
+func cacheError(err error) error {
+	if errors.Is(err, domain.ErrCacheUnavailable) {
+		return domain.ErrServiceUnavailable
+	}
+	return domain.ErrInternal
+}
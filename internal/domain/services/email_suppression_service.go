@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+	"strings"
+)
+
+// EmailSuppressionService implements ports.EmailSuppressionService interface.
+// It keeps the list of email addresses that hard bounced or complained about our emails.
+type EmailSuppressionService struct {
+	repo    ports.EmailSuppressionRepository
+	webhook ports.MailerWebhookAdapter
+}
+
+// NewEmailSuppressionService creates a new instance of EmailSuppressionService.
+func NewEmailSuppressionService(repo ports.EmailSuppressionRepository, webhook ports.MailerWebhookAdapter) *EmailSuppressionService {
+	return &EmailSuppressionService{
+		repo:    repo,
+		webhook: webhook,
+	}
+}
+
+// Email suppression list pagination constants.
+const (
+	DefaultEmailSuppressionLimit = 20
+	MaxEmailSuppressionLimit     = 100
+)
+
+// HandleNotification verifies and processes a delivery notification sent by the email provider.
+// Hard bounces and complaints add the recipients to the suppression list, other notifications are ignored.
+// Returns domain.ErrInvalidWebhookSignature if the notification cannot be authenticated.
+func (s *EmailSuppressionService) HandleNotification(ctx context.Context, payload []byte) error {
+	notification, err := s.webhook.ParseNotification(ctx, payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidWebhookSignature) {
+			return err
+		}
+		return domain.ErrBadRequest
+	}
+
+	var reason entities.SuppressionReason
+	switch notification.Type {
+	case ports.MailerNotificationSubscription:
+		if err := s.webhook.ConfirmSubscription(ctx, notification); err != nil {
+			return domain.ErrInternal
+		}
+		return nil
+	case ports.MailerNotificationBounce:
+		// Soft bounces (full mailbox, ...) are transient, only hard bounces are suppressed.
+		if !notification.Permanent {
+			return nil
+		}
+		reason = entities.SuppressionReasonBounce
+	case ports.MailerNotificationComplaint:
+		reason = entities.SuppressionReasonComplaint
+	default:
+		return nil
+	}
+
+	for _, recipient := range notification.Recipients {
+		err := s.repo.Upsert(ctx, &entities.EmailSuppression{
+			Email:   normalizeEmail(recipient),
+			Reason:  reason,
+			Details: notification.Details,
+		})
+		if err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	return nil
+}
+
+// List returns the suppressed email addresses, most recent first.
+// Returns an error if the retrieval fails.
+func (s *EmailSuppressionService) List(ctx context.Context, limit, offset int) ([]*entities.EmailSuppression, error) {
+	if limit <= 0 {
+		limit = DefaultEmailSuppressionLimit
+	}
+	limit = min(limit, MaxEmailSuppressionLimit)
+	offset = max(offset, 0)
+
+	suppressions, err := s.repo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return suppressions, nil
+}
+
+// Delete removes an email address from the suppression list.
+// Returns domain.ErrEmailSuppressionNotFound if the address is not suppressed.
+func (s *EmailSuppressionService) Delete(ctx context.Context, email string) error {
+	err := s.repo.Delete(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailSuppressionNotFound) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+	return nil
+}
+
+// normalizeEmail formats an email address the way it is stored in the suppression list.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
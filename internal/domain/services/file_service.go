@@ -0,0 +1,282 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go-starter/config"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// File storage paths, by visibility.
+// Storages serving their files publicly (e.g., S3 with a public bucket policy) must not serve the private path.
+const (
+	PublicFilePath  = "files"
+	PrivateFilePath = "private"
+)
+
+// File listing pagination constants.
+const (
+	DefaultFileLimit = 20
+	MaxFileLimit     = 100
+)
+
+// maxFileNameLength is the maximum length, in characters, of a stored file name.
+const maxFileNameLength = 255
+
+// FileService implements the ports.FileService interface.
+// Files are stored with the file upload adapter, their metadata in the file repository.
+type FileService struct {
+	cfg           *config.FileUpload
+	adapter       ports.FileUploadAdapter
+	repo          ports.FileRepository
+	scanner       ports.FileScanner
+	timeGenerator ports.TimeGenerator
+}
+
+// NewFileService creates a new instance of FileService.
+func NewFileService(cfg *config.FileUpload, adapter ports.FileUploadAdapter, repo ports.FileRepository, scanner ports.FileScanner, timeGenerator ports.TimeGenerator) *FileService {
+	return &FileService{
+		cfg:           cfg,
+		adapter:       adapter,
+		repo:          repo,
+		scanner:       scanner,
+		timeGenerator: timeGenerator,
+	}
+}
+
+// Upload scans a file for malware and stores it for a user, its content type is detected from its content.
+// The file is recorded in quarantine, reserving its size in the quota, and only written to the storage once its scan passes.
+// Returns the stored file, domain.ErrFileTooLarge if it exceeds the maximum file size,
+// domain.ErrStorageQuotaExceeded if it does not fit in the storage quota of the user,
+// domain.ErrFileInfected if the scan rejects it, domain.ErrFileScanFailed if it cannot be scanned, or an error if the upload fails.
+func (s *FileService) Upload(ctx context.Context, ownerID entities.UserID, name string, visibility entities.FileVisibility, body io.Reader) (*entities.File, error) {
+	return s.store(ctx, ownerID, name, visibility, body, true)
+}
+
+// StoreGenerated stores a file generated by the application from content it has already scanned,
+// e.g. a resized variant of an avatar, without scanning it again.
+// Returns the stored file, domain.ErrFileTooLarge if it exceeds the maximum file size,
+// domain.ErrStorageQuotaExceeded if it does not fit in the storage quota of the user, or an error if the upload fails.
+func (s *FileService) StoreGenerated(ctx context.Context, ownerID entities.UserID, name string, visibility entities.FileVisibility, body io.Reader) (*entities.File, error) {
+	return s.store(ctx, ownerID, name, visibility, body, false)
+}
+
+// store stores a file for a user within its quota, scanning it first if scan is set.
+func (s *FileService) store(ctx context.Context, ownerID entities.UserID, name string, visibility entities.FileVisibility, body io.Reader, scan bool) (*entities.File, error) {
+	if _, err := entities.ParseFileVisibility(visibility.String()); err != nil {
+		return nil, err
+	}
+
+	used, err := s.repo.GetUsage(ctx, ownerID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	// The file is read up to the smallest limit, one byte more telling which one it exceeds.
+	limit := min(s.cfg.MaxFileSize, max(s.cfg.UserStorageQuota-used, 0))
+	content, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, domain.ErrFileUpload
+	}
+	if int64(len(content)) > limit {
+		if limit == s.cfg.MaxFileSize {
+			return nil, domain.ErrFileTooLarge
+		}
+		return nil, domain.ErrStorageQuotaExceeded
+	}
+	if len(content) == 0 {
+		return nil, domain.ErrFileSizeRequired
+	}
+
+	checksum := sha256.Sum256(content)
+	file := &entities.File{
+		ID:          entities.NewFileID(),
+		OwnerID:     ownerID,
+		Name:        sanitizeFileName(name),
+		ContentType: http.DetectContentType(content),
+		Size:        int64(len(content)),
+		Checksum:    hex.EncodeToString(checksum[:]),
+		Visibility:  visibility,
+		Status:      entities.FileStatusQuarantined,
+	}
+	file.Key = fileKey(file)
+
+	// The quota is checked again with the insertion, other files may have been stored in the meantime.
+	if err := s.repo.Create(ctx, file, s.cfg.UserStorageQuota); err != nil {
+		if errors.Is(err, domain.ErrStorageQuotaExceeded) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if scan {
+		if err := scanFile(ctx, s.scanner, content); err != nil {
+			_ = s.repo.Delete(ctx, file.ID)
+			return nil, err
+		}
+	}
+
+	fileURL, err := s.adapter.Upload(ctx, file.Key, file.ContentType, bytes.NewReader(content))
+	if err != nil {
+		_ = s.repo.Delete(ctx, file.ID)
+		return nil, domain.ErrFileUpload
+	}
+	if visibility == entities.FileVisibilityPublic {
+		file.URL = fileURL
+	}
+
+	if err := s.repo.MarkAvailable(ctx, file.ID, file.URL); err != nil {
+		_ = s.adapter.Delete(ctx, file.Key)
+		_ = s.repo.Delete(ctx, file.ID)
+		return nil, domain.ErrInternal
+	}
+	file.Status = entities.FileStatusAvailable
+	return file, nil
+}
+
+// Download returns a file of a user and the link it is downloaded from, signed and expiring for a private file.
+// Returns domain.ErrFileNotFound if the file does not exist or belongs to another user,
+// or domain.ErrFileQuarantined if its malware scan has not passed.
+func (s *FileService) Download(ctx context.Context, ownerID entities.UserID, id entities.FileID) (*entities.File, *ports.FileLink, error) {
+	file, err := s.get(ctx, ownerID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if file.Status != entities.FileStatusAvailable {
+		return nil, nil, domain.ErrFileQuarantined
+	}
+
+	if file.Visibility == entities.FileVisibilityPublic {
+		return file, &ports.FileLink{URL: file.URL}, nil
+	}
+
+	expiresAt := s.timeGenerator.Now().Add(s.cfg.PrivateFileURLDuration)
+	fileURL, err := s.adapter.PresignDownload(ctx, file.Key, expiresAt)
+	if err != nil {
+		return nil, nil, domain.ErrFileUpload
+	}
+	return file, &ports.FileLink{URL: fileURL, ExpiresAt: expiresAt}, nil
+}
+
+// List returns the files of a user, most recent first.
+// Returns an error if the retrieval fails.
+func (s *FileService) List(ctx context.Context, ownerID entities.UserID, limit, offset int) ([]*entities.File, error) {
+	if limit <= 0 {
+		limit = DefaultFileLimit
+	}
+	limit = min(limit, MaxFileLimit)
+	offset = max(offset, 0)
+
+	files, err := s.repo.ListByOwner(ctx, ownerID, limit, offset)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return files, nil
+}
+
+// Usage returns the storage used by a user, and its quota.
+// Returns an error if the retrieval fails.
+func (s *FileService) Usage(ctx context.Context, ownerID entities.UserID) (*entities.StorageUsage, error) {
+	used, err := s.repo.GetUsage(ctx, ownerID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return &entities.StorageUsage{Used: used, Quota: s.cfg.UserStorageQuota}, nil
+}
+
+// Delete deletes a file of a user.
+// The stored file is deleted first, so that a failure leaves the file listed and the deletion can be retried.
+// Returns domain.ErrFileNotFound if the file does not exist or belongs to another user, or an error if the deletion fails.
+func (s *FileService) Delete(ctx context.Context, ownerID entities.UserID, id entities.FileID) error {
+	file, err := s.get(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.adapter.Delete(ctx, file.Key); err != nil {
+		return domain.ErrFileUpload
+	}
+	if err := s.repo.Delete(ctx, file.ID); err != nil {
+		return domain.ErrInternal
+	}
+	return nil
+}
+
+// get returns a file of a user.
+// The files of the other users are reported as not found, so that their existence is not disclosed.
+func (s *FileService) get(ctx context.Context, ownerID entities.UserID, id entities.FileID) (*entities.File, error) {
+	file, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrFileNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if file.OwnerID != ownerID {
+		return nil, domain.ErrFileNotFound
+	}
+	return file, nil
+}
+
+// scanFile scans the content of a file for malware.
+// Returns domain.ErrFileInfected if the scan rejects it, or domain.ErrFileScanFailed if it cannot be scanned.
+func scanFile(ctx context.Context, scanner ports.FileScanner, content []byte) error {
+	result, err := scanner.Scan(ctx, bytes.NewReader(content))
+	if err != nil {
+		return domain.ErrFileScanFailed
+	}
+	if result.Infected {
+		return domain.ErrFileInfected
+	}
+	return nil
+}
+
+// fileKey returns the key of a file, under the path of its visibility.
+// The extension of the file name is kept when it matches the content type, storages serve files with it.
+func fileKey(file *entities.File) string {
+	prefix := PublicFilePath
+	if file.Visibility == entities.FileVisibilityPrivate {
+		prefix = PrivateFilePath
+	}
+
+	key := prefix + "/" + file.OwnerID.String() + "/" + file.ID.String()
+
+	extension := strings.ToLower(path.Ext(file.Name))
+	extensionType, _, _ := mime.ParseMediaType(mime.TypeByExtension(extension))
+	contentType, _, _ := mime.ParseMediaType(file.ContentType)
+	if extensionType != "" && extensionType == contentType {
+		key += extension
+	}
+	return key
+}
+
+// sanitizeFileName returns the base name of a file, without control characters and truncated to maxFileNameLength characters.
+func sanitizeFileName(name string) string {
+	name = strings.ToValidUTF8(name, "")
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+
+	if runes := []rune(name); len(runes) > maxFileNameLength {
+		name = string(runes[:maxFileNameLength])
+	}
+	return name
+}
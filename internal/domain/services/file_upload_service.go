@@ -1,49 +1,300 @@
 package services
 
 import (
+	"bytes"
 	"context"
-	"go-starter/internal/adapters/server/helpers"
+	"errors"
+	"go-starter/config"
 	"go-starter/internal/domain"
 	"go-starter/internal/domain/entities"
 	"go-starter/internal/domain/ports"
 	"io"
-	"strings"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
 )
 
 // FileUploadService is a service that uploads files to a file upload service.
 type FileUploadService struct {
-	adapter ports.FileUploadAdapter
+	cfg            *config.FileUpload
+	adapter        ports.FileUploadAdapter
+	fileServer     ports.FileServerAdapter
+	imageProcessor ports.ImageProcessor
+	scanner        ports.FileScanner
+	fileSvc        ports.FileService
+	pendingRepo    ports.PendingUploadRepository
+	timeGenerator  ports.TimeGenerator
 }
 
 // NewFileUploadService creates a new instance of FileUploadService.
-func NewFileUploadService(adapter ports.FileUploadAdapter) *FileUploadService {
+// fileServer is nil when the storage serves its files itself (e.g., S3).
+func NewFileUploadService(
+	cfg *config.FileUpload,
+	adapter ports.FileUploadAdapter,
+	fileServer ports.FileServerAdapter,
+	imageProcessor ports.ImageProcessor,
+	scanner ports.FileScanner,
+	fileSvc ports.FileService,
+	pendingRepo ports.PendingUploadRepository,
+	timeGenerator ports.TimeGenerator,
+) *FileUploadService {
 	return &FileUploadService{
-		adapter: adapter,
+		cfg:            cfg,
+		adapter:        adapter,
+		fileServer:     fileServer,
+		imageProcessor: imageProcessor,
+		scanner:        scanner,
+		fileSvc:        fileSvc,
+		pendingRepo:    pendingRepo,
+		timeGenerator:  timeGenerator,
 	}
 }
 
-// UserAvatarPath is the path to the user avatar directory.
-const UserAvatarPath = "avatars"
+// PendingUploadPath is the path to the files uploaded directly to the storage, until their confirmation.
+const PendingUploadPath = "uploads"
 
-// UploadAvatar uploads a user avatar to the file upload service.
-// Returns the URL of the uploaded file or an error if the upload fails.
-func (s *FileUploadService) UploadAvatar(ctx context.Context, userID entities.UserID, filename string, body io.Reader) (string, error) {
-	key := UserAvatarPath + "/" + helpers.GenerateFileKey(userID.String(), filename)
-	url, err := s.adapter.Upload(ctx, key, body)
+// AvatarMaxSize is the maximum size, in bytes, of an uploaded avatar.
+const AvatarMaxSize = 3 << 20
+
+// AvatarContentTypes are the content types accepted for the avatars uploaded directly to the storage.
+var AvatarContentTypes = []string{"image/png", "image/jpeg", "image/webp"}
+
+// Pending upload constants.
+const (
+	// pendingUploadGracePeriod is how long an upload can still be confirmed once its URL has expired.
+	pendingUploadGracePeriod = time.Hour
+	// pendingUploadCleanupBatchSize is the number of expired uploads deleted at once by the cleanup.
+	pendingUploadCleanupBatchSize = 100
+)
+
+// AvatarSizes are the sizes, in pixels, of the square variants stored for each avatar.
+var AvatarSizes = []int{64, 256, 512}
+
+// UploadAvatar validates a user avatar and stores each of its variants as a public file of the user.
+// The uploaded image is scanned for malware before being decoded, the variants count against the storage quota of the user.
+// Returns the files of the variants keyed by size, domain.ErrInvalidFileType, domain.ErrInvalidImage,
+// domain.ErrImageTooLarge, domain.ErrFileTooLarge or domain.ErrFileInfected if the image is rejected,
+// domain.ErrFileScanFailed if it cannot be scanned,
+// domain.ErrStorageQuotaExceeded if the variants do not fit in the storage quota, or an error if the upload fails.
+func (s *FileUploadService) UploadAvatar(ctx context.Context, userID entities.UserID, body io.Reader) (map[string]*entities.File, error) {
+	return s.storeAvatar(ctx, userID, body)
+}
+
+// PresignAvatarUpload returns a short-lived URL the client uploads its avatar to, without going through the API.
+// The upload is recorded until its confirmation, or its deletion by CleanupPendingUploads.
+// Returns domain.ErrInvalidFileType or domain.ErrFileTooLarge if the declared file is not accepted.
+func (s *FileUploadService) PresignAvatarUpload(ctx context.Context, userID entities.UserID, contentType string, size int64) (*entities.PendingUpload, *ports.PresignedUpload, error) {
+	if !slices.Contains(AvatarContentTypes, contentType) {
+		return nil, nil, domain.ErrInvalidFileType
+	}
+	if size <= 0 {
+		return nil, nil, domain.ErrFileSizeRequired
+	}
+	if size > AvatarMaxSize {
+		return nil, nil, domain.ErrFileTooLarge
+	}
+
+	urlExpiresAt := s.timeGenerator.Now().Add(s.cfg.PresignedUploadDuration)
+	id := entities.NewUploadID()
+	upload := &entities.PendingUpload{
+		ID:          id,
+		UserID:      userID,
+		Key:         PendingUploadPath + "/" + userID.String() + "/" + id.String(),
+		ContentType: contentType,
+		Size:        size,
+		ExpiresAt:   urlExpiresAt.Add(pendingUploadGracePeriod),
+	}
+
+	// The upload is recorded first, so that no file can be uploaded without being cleaned up if never confirmed.
+	if err := s.pendingRepo.Create(ctx, upload); err != nil {
+		return nil, nil, domain.ErrInternal
+	}
+
+	presigned, err := s.adapter.PresignUpload(ctx, upload.Key, contentType, size, urlExpiresAt)
 	if err != nil {
-		return "", domain.ErrFileUpload
+		return nil, nil, domain.ErrFileUpload
 	}
-	return url, nil
+	return upload, presigned, nil
 }
 
-// DeleteAvatar deletes a user avatar from the file upload service.
+// ConfirmAvatarUpload checks the file uploaded to a presigned URL, then processes it like UploadAvatar.
+// The uploaded file stays in quarantine under the pending upload path until then, it is deleted once its variants are stored,
+// or as soon as the malware scan rejects it.
+// Returns the files of the variants keyed by size, domain.ErrUploadNotFound if the upload does not exist or has expired,
+// domain.ErrUploadNotCompleted if the file has not been uploaded, or domain.ErrUploadMismatch if it does not match the upload request.
+func (s *FileUploadService) ConfirmAvatarUpload(ctx context.Context, userID entities.UserID, uploadID entities.UploadID) (map[string]*entities.File, error) {
+	upload, err := s.pendingRepo.GetByID(ctx, uploadID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUploadNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if upload.UserID != userID || !s.timeGenerator.Now().Before(upload.ExpiresAt) {
+		return nil, domain.ErrUploadNotFound
+	}
+
+	info, err := s.adapter.Stat(ctx, upload.Key)
+	if err != nil {
+		if errors.Is(err, domain.ErrFileNotFound) {
+			return nil, domain.ErrUploadNotCompleted
+		}
+		return nil, domain.ErrFileUpload
+	}
+	if info.Size != upload.Size || info.ContentType != upload.ContentType {
+		return nil, domain.ErrUploadMismatch
+	}
+
+	body, err := s.adapter.Download(ctx, upload.Key)
+	if err != nil {
+		if errors.Is(err, domain.ErrFileNotFound) {
+			return nil, domain.ErrUploadNotCompleted
+		}
+		return nil, domain.ErrFileUpload
+	}
+	defer body.Close()
+
+	avatarFiles, err := s.storeAvatar(ctx, userID, io.LimitReader(body, upload.Size))
+	if err != nil {
+		if errors.Is(err, domain.ErrFileInfected) {
+			// The cleanup deletes the upload later if this fails.
+			if err := s.adapter.Delete(ctx, upload.Key); err == nil {
+				_ = s.pendingRepo.Delete(ctx, upload.ID)
+			}
+		}
+		return nil, err
+	}
+
+	// The cleanup deletes the upload later if this fails.
+	if err := s.adapter.Delete(ctx, upload.Key); err == nil {
+		_ = s.pendingRepo.Delete(ctx, upload.ID)
+	}
+	return avatarFiles, nil
+}
+
+// CleanupPendingUploads deletes the uploads that were never confirmed, and their files.
+// Returns the number of uploads deleted.
+func (s *FileUploadService) CleanupPendingUploads(ctx context.Context) (int, error) {
+	now := s.timeGenerator.Now()
+	deleted := 0
+	for {
+		uploads, err := s.pendingRepo.ListExpired(ctx, now, pendingUploadCleanupBatchSize)
+		if err != nil {
+			return deleted, domain.ErrInternal
+		}
+
+		for _, upload := range uploads {
+			if err := s.adapter.Delete(ctx, upload.Key); err != nil {
+				return deleted, domain.ErrFileUpload
+			}
+			if err := s.pendingRepo.Delete(ctx, upload.ID); err != nil {
+				return deleted, domain.ErrInternal
+			}
+			deleted++
+		}
+
+		if len(uploads) < pendingUploadCleanupBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// storeAvatar scans and validates an avatar image, then stores each of its variants.
+// Only the image is scanned, the variants generated from it are not.
+// The variants already stored are deleted if one of them cannot be.
+func (s *FileUploadService) storeAvatar(ctx context.Context, userID entities.UserID, body io.Reader) (map[string]*entities.File, error) {
+	content, err := io.ReadAll(io.LimitReader(body, AvatarMaxSize+1))
+	if err != nil {
+		return nil, domain.ErrFileUpload
+	}
+	if len(content) > AvatarMaxSize {
+		return nil, domain.ErrFileTooLarge
+	}
+
+	// The image is scanned before reaching the decoders.
+	if err := scanFile(ctx, s.scanner, content); err != nil {
+		return nil, err
+	}
+
+	thumbnails, err := s.imageProcessor.Thumbnails(ctx, bytes.NewReader(content), AvatarSizes)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidFileType),
+			errors.Is(err, domain.ErrInvalidImage),
+			errors.Is(err, domain.ErrImageTooLarge),
+			errors.Is(err, domain.ErrFileTooLarge):
+			return nil, err
+		default:
+			return nil, domain.ErrFileUpload
+		}
+	}
+
+	avatarFiles := make(map[string]*entities.File, len(thumbnails))
+	fileIDs := make([]entities.FileID, 0, len(thumbnails))
+	for _, thumbnail := range thumbnails {
+		name := strconv.Itoa(thumbnail.Size)
+		file, err := s.fileSvc.StoreGenerated(ctx, userID, "avatar-"+name+thumbnail.Extension, entities.FileVisibilityPublic, bytes.NewReader(thumbnail.Content))
+		if err != nil {
+			_ = s.DeleteAvatar(ctx, userID, fileIDs)
+			switch {
+			case errors.Is(err, domain.ErrStorageQuotaExceeded),
+				errors.Is(err, domain.ErrFileTooLarge):
+				return nil, err
+			}
+			return nil, domain.ErrFileUpload
+		}
+		avatarFiles[name] = file
+		fileIDs = append(fileIDs, file.ID)
+	}
+	return avatarFiles, nil
+}
+
+// DeleteAvatar deletes the files storing the variants of a user avatar.
+// The files already deleted are skipped.
 // Returns an error if the deletion fails.
-func (s *FileUploadService) DeleteAvatar(ctx context.Context, userID entities.UserID, avatarURL string) error {
-	split := strings.Split(avatarURL, ".")
-	key := UserAvatarPath + "/" + userID.String() + "." + split[len(split)-1]
-	err := s.adapter.Delete(ctx, key)
+func (s *FileUploadService) DeleteAvatar(ctx context.Context, userID entities.UserID, fileIDs []entities.FileID) error {
+	for _, id := range fileIDs {
+		err := s.fileSvc.Delete(ctx, userID, id)
+		if err != nil && !errors.Is(err, domain.ErrFileNotFound) {
+			return domain.ErrFileUpload
+		}
+	}
+	return nil
+}
+
+// OpenFile opens a file served by the application from a signed URL.
+// Returns domain.ErrInvalidFileSignature if the URL signature is invalid or expired,
+// or domain.ErrFileNotFound if the file does not exist or the storage does not serve its files itself.
+func (s *FileUploadService) OpenFile(ctx context.Context, key string, params url.Values) (*ports.StoredFile, error) {
+	if s.fileServer == nil {
+		return nil, domain.ErrFileNotFound
+	}
+
+	file, err := s.fileServer.Open(ctx, key, params)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidFileSignature) || errors.Is(err, domain.ErrFileNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	return file, nil
+}
+
+// StoreFile stores a file uploaded to a presigned URL of a storage whose files are served by the application.
+// Returns domain.ErrInvalidFileSignature if the URL signature is invalid, expired or does not match the content type,
+// domain.ErrFileTooLarge if the file is larger than allowed, or domain.ErrFileNotFound if the storage serves its files itself.
+func (s *FileUploadService) StoreFile(ctx context.Context, key string, params url.Values, contentType string, body io.Reader) error {
+	if s.fileServer == nil {
+		return domain.ErrFileNotFound
+	}
+
+	err := s.fileServer.Store(ctx, key, params, contentType, body)
 	if err != nil {
-		return domain.ErrFileUpload
+		if errors.Is(err, domain.ErrInvalidFileSignature) || errors.Is(err, domain.ErrFileTooLarge) {
+			return err
+		}
+		return domain.ErrInternal
 	}
 	return nil
 }
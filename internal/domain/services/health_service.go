@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go-starter/config"
+	"go-starter/internal/domain/ports"
+	"sync"
+	"time"
+)
+
+// HealthService implements ports.HealthChecker interface and checks the dependencies of the application.
+// The report is kept for the configured duration, the checks running again once it expires.
+type HealthService struct {
+	healthCfg     *config.Health
+	timeGenerator ports.TimeGenerator
+
+	// mu guards the checks and the cached report, and lets a single caller refresh it at a time.
+	mu     sync.Mutex
+	checks []ports.HealthCheck
+	report *ports.HealthReport
+}
+
+// NewHealthService creates a new health service instance, without any check.
+func NewHealthService(healthCfg *config.Health, timeGenerator ports.TimeGenerator) *HealthService {
+	return &HealthService{
+		healthCfg:     healthCfg,
+		timeGenerator: timeGenerator,
+	}
+}
+
+// Register adds the check of a dependency to the registry, dropping the cached report.
+func (hs *HealthService) Register(check ports.HealthCheck) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.checks = append(hs.checks, check)
+	hs.report = nil
+}
+
+// Check returns the cached report if it has not expired, or runs the registered checks concurrently
+// and caches their report. The application is unavailable when a critical dependency is down,
+// and degraded when another one is.
+func (hs *HealthService) Check(ctx context.Context) ports.HealthReport {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	now := hs.timeGenerator.Now()
+	if hs.report != nil && now.Before(hs.report.CheckedAt.Add(hs.healthCfg.CacheTTL)) {
+		return *hs.report
+	}
+
+	results := make([]ports.HealthCheckResult, len(hs.checks))
+	var wg sync.WaitGroup
+	for i, check := range hs.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = hs.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := ports.HealthReport{Status: ports.HealthStatusReady, CheckedAt: now, Checks: results}
+	for _, result := range results {
+		if result.Status == ports.HealthCheckStatusUp {
+			continue
+		}
+		if result.Critical {
+			report.Status = ports.HealthStatusUnavailable
+		} else if report.Status == ports.HealthStatusReady {
+			report.Status = ports.HealthStatusDegraded
+		}
+	}
+
+	hs.report = &report
+	return report
+}
+
+// run runs the check within its timeout. A check panicking, or not returning in time even though its context
+// is canceled, is reported as down.
+func (hs *HealthService) run(ctx context.Context, check ports.HealthCheck) ports.HealthCheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = hs.healthCfg.CheckTimeout
+	}
+	// The report being shared, the cancellation of the request triggering the checks must not fail them
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", timeout)
+	}
+
+	result := ports.HealthCheckResult{
+		Name:     check.Name,
+		Critical: check.Critical,
+		Status:   ports.HealthCheckStatusUp,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		result.Status = ports.HealthCheckStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
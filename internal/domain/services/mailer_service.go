@@ -1,46 +1,392 @@
 package services
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
 	"go-starter/config"
 	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/i18n"
+	"go-starter/internal/domain/mailtemplates"
 	"go-starter/internal/domain/ports"
+	"slices"
+	"strings"
+	"time"
 )
 
+// Email delivery log pagination constants.
+const (
+	DefaultEmailDeliveryLimit = 20
+	MaxEmailDeliveryLimit     = 100
+)
+
+// mailQuotaWindows are the windows over which the email quotas are counted.
+var mailQuotaWindows = []struct {
+	name     string
+	duration time.Duration
+}{
+	{name: "hourly", duration: time.Hour},
+	{name: "daily", duration: 24 * time.Hour},
+}
+
 // MailerService implements the ports.MailerService interface.
 // It provides high-level email sending functionality with error tracking and debug capabilities.
+// Every message is recorded in the email delivery log.
 type MailerService struct {
-	cfg     *config.Container
-	adapter ports.MailerAdapter
+	cfg             *config.Container
+	adapter         ports.MailerAdapter
+	suppressionRepo ports.EmailSuppressionRepository
+	deliveryRepo    ports.EmailDeliveryRepository
+	limiter         ports.RateLimiter
+	metrics         ports.Metrics
+	tokenSvc        ports.TokenService
 }
 
 // NewMailerService creates a new instance of MailerService.
 func NewMailerService(
 	cfg *config.Container,
 	adapter ports.MailerAdapter,
+	suppressionRepo ports.EmailSuppressionRepository,
+	deliveryRepo ports.EmailDeliveryRepository,
+	limiter ports.RateLimiter,
+	metrics ports.Metrics,
+	tokenSvc ports.TokenService,
 ) *MailerService {
 	return &MailerService{
-		cfg:     cfg,
-		adapter: adapter,
+		cfg:             cfg,
+		adapter:         adapter,
+		suppressionRepo: suppressionRepo,
+		deliveryRepo:    deliveryRepo,
+		limiter:         limiter,
+		metrics:         metrics,
+		tokenSvc:        tokenSvc,
 	}
 }
 
-// Send sends an email message through the repository.
+// Send sends an email message through the repository and records it in the delivery log, without its secrets.
+// Suppressed recipients are removed before sending, in which case domain.ErrEmailSuppressed is returned
+// once the message has been sent to the remaining recipients.
+// Recipients over the hourly or daily quotas of the template are removed the same way, returning domain.ErrEmailThrottled.
 // In non-production environments, it modifies the message for debugging purposes.
 // Returns domain.ErrInternal if sending fails or if no recipients are specified.
-func (m *MailerService) Send(msg *ports.EmailMessage) error {
+func (m *MailerService) Send(ctx context.Context, msg *ports.EmailMessage) error {
 	if len(msg.To) == 0 {
 		return domain.ErrInternal
 	}
 
-	if m.cfg.Application.Env != config.EnvProduction {
-		m.updateForDebug(msg)
+	delivery := &entities.EmailDelivery{
+		Template:   msg.Template,
+		UserID:     msg.UserID,
+		Locale:     msg.Locale,
+		Recipients: slices.Clone(msg.To),
+		Subject:    msg.Subject,
+		Body:       redactSecrets(msg.Body, msg.Secrets),
+		Redacted:   len(msg.Secrets) > 0,
+		Status:     entities.EmailDeliveryPending,
+	}
+
+	recipients, err := m.removeSuppressed(ctx, msg.To)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return m.skip(ctx, delivery, entities.EmailDeliverySuppressed, domain.ErrEmailSuppressed)
+	}
+	suppressed := len(recipients) < len(msg.To)
+
+	allowed, err := m.removeThrottled(ctx, msg.Template, msg.UserID, recipients)
+	if err != nil {
+		return err
+	}
+	if len(allowed) == 0 {
+		delivery.Recipients = slices.Clone(recipients)
+		return m.skip(ctx, delivery, entities.EmailDeliveryThrottled, domain.ErrEmailThrottled)
+	}
+	throttled := len(allowed) < len(recipients)
+
+	msg.To = allowed
+	delivery.Recipients = slices.Clone(allowed)
+
+	if err := m.deliveryRepo.Create(ctx, delivery); err != nil {
+		return domain.ErrInternal
+	}
+
+	if err := m.deliver(ctx, delivery, msg); err != nil {
+		return err
+	}
+	m.countQuotas(ctx, delivery.Template, delivery.UserID, allowed)
+
+	switch {
+	case suppressed:
+		return domain.ErrEmailSuppressed
+	case throttled:
+		return domain.ErrEmailThrottled
+	default:
+		return nil
+	}
+}
+
+// ListDeliveries returns the email delivery log matching the filter, most recent first.
+// Returns an error if the retrieval fails.
+func (m *MailerService) ListDeliveries(ctx context.Context, filter entities.EmailDeliveryFilter) ([]*entities.EmailDelivery, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultEmailDeliveryLimit
+	}
+	filter.Limit = min(filter.Limit, MaxEmailDeliveryLimit)
+	filter.Offset = max(filter.Offset, 0)
+
+	deliveries, err := m.deliveryRepo.List(ctx, filter)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return deliveries, nil
+}
+
+// Resend sends again an email whose delivery failed and returns the updated delivery.
+// Recipients suppressed since the first attempt are skipped, quotas are not applied to this admin action.
+// An email whose secrets were redacted is rendered again from its template, with a new one-time token
+// replacing the one of the failed email.
+// Returns domain.ErrEmailDeliveryNotFound if the delivery does not exist,
+// or domain.ErrEmailDeliveryNotResendable if it did not fail,
+// or domain.ErrEmailDeliveryRedacted if its secrets were not kept and it cannot be rendered again.
+func (m *MailerService) Resend(ctx context.Context, id entities.EmailDeliveryID) (*entities.EmailDelivery, error) {
+	delivery, err := m.deliveryRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailDeliveryNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if delivery.Status != entities.EmailDeliveryFailed {
+		return nil, domain.ErrEmailDeliveryNotResendable
+	}
+
+	recipients, err := m.removeSuppressed(ctx, delivery.Recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recipients) == 0 {
+		delivery.Status = entities.EmailDeliverySuppressed
+		if err := m.deliveryRepo.Update(ctx, delivery); err != nil {
+			return nil, domain.ErrInternal
+		}
+		return nil, domain.ErrEmailSuppressed
+	}
+	delivery.Recipients = recipients
+
+	msg := &ports.EmailMessage{
+		Template: delivery.Template,
+		UserID:   delivery.UserID,
+		Locale:   delivery.Locale,
+		To:       slices.Clone(recipients),
+		Subject:  delivery.Subject,
+		Body:     delivery.Body,
+	}
+	if delivery.Redacted {
+		if err := m.render(ctx, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.deliver(ctx, delivery, msg); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// tokenTemplate is a template of the emails holding a one-time token, rendered again with a new token when resent.
+type tokenTemplate struct {
+	tokenType entities.TokenType
+	subject   func(locale i18n.Locale) string
+	body      func(locale i18n.Locale, baseURL, token string, expirationTime time.Duration) string
+}
+
+// tokenTemplates are the templates of the emails holding a one-time token, by name.
+var tokenTemplates = map[string]tokenTemplate{
+	mailtemplates.VerifyEmailTemplate: {
+		tokenType: entities.EmailVerificationToken,
+		subject:   mailtemplates.VerifyEmailSubject,
+		body:      mailtemplates.VerifyEmail,
+	},
+	mailtemplates.ResetPasswordTemplate: {
+		tokenType: entities.PasswordResetToken,
+		subject:   mailtemplates.ResetPasswordSubject,
+		body:      mailtemplates.ResetPassword,
+	},
+}
+
+// render renders the message again from its template in its locale, with a new one-time token for its user.
+// Returns domain.ErrEmailDeliveryRedacted if the template or the user of the message is unknown,
+// or an error if the token cannot be generated.
+func (m *MailerService) render(ctx context.Context, msg *ports.EmailMessage) error {
+	template, ok := tokenTemplates[msg.Template]
+	if !ok || msg.UserID == entities.NilUserID {
+		return domain.ErrEmailDeliveryRedacted
+	}
+
+	token, err := m.tokenSvc.GenerateOneTimeToken(ctx, template.tokenType, msg.UserID)
+	if err != nil {
+		return err
+	}
+
+	var expirationTime time.Duration
+	switch template.tokenType {
+	case entities.EmailVerificationToken:
+		expirationTime = m.cfg.Token.EmailVerificationTokenDuration
+	case entities.PasswordResetToken:
+		expirationTime = m.cfg.Token.PasswordResetTokenDuration
 	}
 
-	err := m.adapter.Send(*msg)
+	msg.Subject = template.subject(msg.Locale)
+	msg.Body = template.body(msg.Locale, m.cfg.Application.BaseURL, token, expirationTime)
+	msg.Secrets = []string{token}
+	return nil
+}
+
+// redactSecrets returns the body with the secrets replaced by a placeholder.
+func redactSecrets(body string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			body = strings.ReplaceAll(body, secret, "[REDACTED]")
+		}
+	}
+	return body
+}
+
+// removeSuppressed returns the recipients that are not on the suppression list.
+// The given slice is left untouched.
+func (m *MailerService) removeSuppressed(ctx context.Context, recipients []string) ([]string, error) {
+	suppressed, err := m.suppressionRepo.GetSuppressed(ctx, recipients)
 	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if len(suppressed) == 0 {
+		return recipients, nil
+	}
+
+	return slices.DeleteFunc(slices.Clone(recipients), func(to string) bool {
+		return slices.Contains(suppressed, to)
+	}), nil
+}
+
+// removeThrottled returns the recipients still within the quotas of the template, without counting the message.
+// The user quotas apply to the whole message, the recipient quotas to each recipient.
+func (m *MailerService) removeThrottled(ctx context.Context, template string, userID entities.UserID, recipients []string) ([]string, error) {
+	quota := m.cfg.MailThrottle.Quota(template)
+	template = cmp.Or(template, "default")
+
+	if userID != entities.NilUserID {
+		allowed, err := m.withinQuota(ctx, template+":user:"+userID.String(), quota.UserHourly, quota.UserDaily)
+		if err != nil || !allowed {
+			return nil, err
+		}
+	}
+
+	allowedRecipients := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		allowed, err := m.withinQuota(ctx, template+":recipient:"+normalizeEmail(recipient), quota.RecipientHourly, quota.RecipientDaily)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			allowedRecipients = append(allowedRecipients, recipient)
+		}
+	}
+
+	return allowedRecipients, nil
+}
+
+// withinQuota reports whether an email for the key is within both the hourly and daily limits, without counting it.
+// A zero limit is not enforced.
+// Returns domain.ErrInternal if the counters cannot be read.
+func (m *MailerService) withinQuota(ctx context.Context, key string, hourly, daily int) (bool, error) {
+	for i, limit := range []int{hourly, daily} {
+		if limit == 0 {
+			continue
+		}
+
+		window := mailQuotaWindows[i]
+		result, err := m.limiter.Peek(ctx, fmt.Sprintf("%s:%s", key, window.name), int64(limit), window.duration)
+		if err != nil {
+			return false, domain.ErrInternal
+		}
+		if !result.Allowed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// countQuotas counts a sent message against the quotas of its template, for its user and each of its recipients.
+// The quotas are checked before sending and counted once sent, so that a message that is not sent is never counted;
+// concurrent messages may exceed them by the few checked meanwhile.
+// A failure to count only lets the next messages through, the message having been sent.
+func (m *MailerService) countQuotas(ctx context.Context, template string, userID entities.UserID, recipients []string) {
+	quota := m.cfg.MailThrottle.Quota(template)
+	template = cmp.Or(template, "default")
+
+	if userID != entities.NilUserID {
+		m.countQuota(ctx, template+":user:"+userID.String(), quota.UserHourly, quota.UserDaily)
+	}
+	for _, recipient := range recipients {
+		m.countQuota(ctx, template+":recipient:"+normalizeEmail(recipient), quota.RecipientHourly, quota.RecipientDaily)
+	}
+}
+
+// countQuota counts an email for the key in the hourly and daily windows whose limit is enforced.
+func (m *MailerService) countQuota(ctx context.Context, key string, hourly, daily int) {
+	for i, limit := range []int{hourly, daily} {
+		if limit == 0 {
+			continue
+		}
+
+		window := mailQuotaWindows[i]
+		_, _ = m.limiter.Check(ctx, fmt.Sprintf("%s:%s", key, window.name), int64(limit), window.duration)
+	}
+}
+
+// skip records a message that is not sent and returns the reason why.
+func (m *MailerService) skip(ctx context.Context, delivery *entities.EmailDelivery, status entities.EmailDeliveryStatus, reason error) error {
+	delivery.Status = status
+	m.metrics.IncMailSent(delivery.Template, status.String())
+	if err := m.deliveryRepo.Create(ctx, delivery); err != nil {
 		return domain.ErrInternal
 	}
+	return reason
+}
+
+// deliver sends the message and records the outcome of the attempt in the delivery log.
+// Returns domain.ErrInternal if sending fails.
+func (m *MailerService) deliver(ctx context.Context, delivery *entities.EmailDelivery, msg *ports.EmailMessage) error {
+	if m.cfg.Application.Env != config.EnvProduction {
+		m.updateForDebug(msg)
+	}
 
+	messageID, sendErr := m.adapter.Send(ctx, *msg)
+
+	delivery.Attempts++
+	if sendErr != nil {
+		delivery.Status = entities.EmailDeliveryFailed
+		delivery.Error = sendErr.Error()
+	} else {
+		delivery.Status = entities.EmailDeliverySent
+		delivery.ProviderMessageID = messageID
+		delivery.Error = ""
+	}
+	m.metrics.IncMailSent(delivery.Template, delivery.Status.String())
+
+	// The repository reports its own failures, the outcome of the send itself must not be hidden by them.
+	_ = m.deliveryRepo.Update(ctx, delivery)
+
+	if sendErr != nil {
+		return domain.ErrInternal
+	}
 	return nil
 }
 
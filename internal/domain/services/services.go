@@ -8,28 +8,49 @@ import (
 
 // Services holds all service implementations for the application.
 type Services struct {
-	CacheService      ports.CacheService
-	UserService       ports.UserService
-	AuthService       ports.AuthService
-	TokenService      ports.TokenService
-	MailerService     ports.MailerService
-	FileUploadService ports.FileUploadService
+	CacheService            ports.CacheService
+	UserService             ports.UserService
+	AuthService             ports.AuthService
+	TokenService            ports.TokenService
+	MailerService           ports.MailerService
+	EmailSuppressionService ports.EmailSuppressionService
+	FileUploadService       ports.FileUploadService
+	FileService             ports.FileService
+	HealthChecker           ports.HealthChecker
 }
 
 // New creates and initializes a new Services instance with the provided dependencies.
 func New(cfg *config.Container, a *adapters.Adapters) *Services {
-	fileUploadSvc := NewFileUploadService(a.FileUploadAdapter)
-	cacheSvc := NewCacheService(a.CacheRepository)
+	fileSvc := NewFileService(cfg.FileUpload, a.FileUploadAdapter, a.FileRepository, a.FileScanner, a.TimeGenerator)
+	fileUploadSvc := NewFileUploadService(cfg.FileUpload, a.FileUploadAdapter, a.FileServerAdapter, a.ImageProcessor, a.FileScanner, fileSvc, a.PendingUploadRepository, a.TimeGenerator)
+	cacheSvc := NewCacheService(a.CacheRepository, a.TimeGenerator)
 	tokenSvc := NewTokenService(cfg.Token, a.TokenRepository, cacheSvc)
-	mailerSvc := NewMailerService(cfg, a.MailerAdapter)
+	mailerSvc := NewMailerService(cfg, a.MailerAdapter, a.EmailSuppressionRepository, a.EmailDeliveryRepository, a.MailRateLimiter, a.Metrics, tokenSvc)
+	emailSuppressionSvc := NewEmailSuppressionService(a.EmailSuppressionRepository, a.MailerWebhookAdapter)
 	userSvc := NewUserService(cfg, a.UserRepository, cacheSvc, tokenSvc, mailerSvc, fileUploadSvc)
-	authSvc := NewAuthService(cfg, userSvc, tokenSvc, mailerSvc)
+	authSvc := NewAuthService(cfg, userSvc, tokenSvc, mailerSvc, a.Metrics)
+	healthSvc := newHealthChecker(cfg, a, cacheSvc)
 	return &Services{
-		CacheService:      cacheSvc,
-		UserService:       userSvc,
-		AuthService:       authSvc,
-		TokenService:      tokenSvc,
-		MailerService:     mailerSvc,
-		FileUploadService: fileUploadSvc,
+		CacheService:            cacheSvc,
+		UserService:             userSvc,
+		AuthService:             authSvc,
+		TokenService:            tokenSvc,
+		MailerService:           mailerSvc,
+		EmailSuppressionService: emailSuppressionSvc,
+		FileUploadService:       fileUploadSvc,
+		FileService:             fileSvc,
+		HealthChecker:           healthSvc,
 	}
 }
+
+// newHealthChecker creates the health checker of the dependencies of the application.
+// Only the database is critical: without the cache the application runs in degraded mode,
+// and without the storage or the mailer only the features using them fail.
+func newHealthChecker(cfg *config.Container, a *adapters.Adapters, cacheSvc ports.CacheService) *HealthService {
+	healthSvc := NewHealthService(cfg.Health, a.TimeGenerator)
+	healthSvc.Register(ports.HealthCheck{Name: "postgres", Critical: true, Check: a.DB.PingContext})
+	healthSvc.Register(ports.HealthCheck{Name: "cache", Check: cacheSvc.Ping})
+	healthSvc.Register(ports.HealthCheck{Name: "storage", Check: a.FileUploadAdapter.Ping})
+	healthSvc.Register(ports.HealthCheck{Name: "mailer", Check: a.MailerAdapter.Ping})
+	return healthSvc
+}
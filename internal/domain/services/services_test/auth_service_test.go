@@ -7,6 +7,8 @@ import (
 	"errors"
 	"go-starter/internal/domain"
 	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/i18n"
+	"go-starter/internal/domain/mailtemplates"
 	"testing"
 )
 
@@ -87,7 +89,6 @@ func TestAuthService_SendPasswordResetEmail(t *testing.T) {
 
 	// Arrange
 	ctx := context.Background()
-	builder := NewTestBuilder().Build()
 
 	tests := map[string]struct {
 		input                  string
@@ -119,6 +120,7 @@ func TestAuthService_SendPasswordResetEmail(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
+			builder := NewTestBuilder().Build()
 			if tt.prepare != nil {
 				tt.prepare(builder)
 			}
@@ -137,6 +139,78 @@ func TestAuthService_SendPasswordResetEmail(t *testing.T) {
 	}
 }
 
+func TestAuthService_SendPasswordResetEmail_UsesRecipientLocale(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().SetEnvToProduction().Build()
+
+	userToCreate := newValidUserToCreate()
+	userToCreate.Locale = i18n.LocaleFrench
+	user, err := builder.AuthService.Register(ctx, userToCreate)
+	if err != nil {
+		t.Fatalf("error while registering user: %v", err)
+	}
+
+	_, err = builder.UserRepo.VerifyEmail(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("error while verifying email: %v", err)
+	}
+
+	// Act
+	err = builder.AuthService.SendPasswordResetEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("error while sending password reset email: %v", err)
+	}
+
+	// Assert
+	msg := getLastSentTo(t, builder.MailerAdapter, user.Email)
+	expectedSubject := mailtemplates.ResetPasswordSubject(i18n.LocaleFrench)
+	if msg.Subject != expectedSubject {
+		t.Errorf("expected subject %q, got %q", expectedSubject, msg.Subject)
+	}
+}
+
+func TestAuthService_SendPasswordResetEmail_Throttled(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().SetEnvToProduction().Build()
+
+	user, err := builder.AuthService.Register(ctx, newValidUserToCreate())
+	if err != nil {
+		t.Fatalf("error while registering user: %v", err)
+	}
+
+	_, err = builder.UserRepo.VerifyEmail(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("error while verifying email: %v", err)
+	}
+
+	// Act & Assert
+	for i := range resetPasswordRecipientHourlyQuota + 1 {
+		err = builder.AuthService.SendPasswordResetEmail(ctx, user.Email)
+		if err != nil {
+			t.Fatalf("expected throttled email to be dropped silently on attempt %d, got %v", i+1, err)
+		}
+	}
+
+	deliveries, err := builder.MailerService.ListDeliveries(ctx, entities.EmailDeliveryFilter{
+		Template: mailtemplates.ResetPasswordTemplate,
+	})
+	if err != nil {
+		t.Fatalf("failed to list deliveries: %v", err)
+	}
+	if len(deliveries) != resetPasswordRecipientHourlyQuota+1 {
+		t.Fatalf("expected %d deliveries, got %d", resetPasswordRecipientHourlyQuota+1, len(deliveries))
+	}
+	if deliveries[0].Status != entities.EmailDeliveryThrottled {
+		t.Errorf("expected last delivery to be %v, got %v", entities.EmailDeliveryThrottled, deliveries[0].Status)
+	}
+}
+
 func TestAuthService_ResetPassword(t *testing.T) {
 	t.Parallel()
 
@@ -181,9 +255,13 @@ func TestAuthService_ResetPassword(t *testing.T) {
 			ctx := context.Background()
 			builder := NewTestBuilder().Build()
 			token, user := tt.prepare(t, builder, ctx)
+			session, err := builder.TokenService.GenerateAuthToken(ctx, user.ID)
+			if err != nil {
+				t.Fatalf("error while generating auth token: %v", err)
+			}
 
 			// Act & Assert
-			err := builder.AuthService.ResetPassword(ctx, token, tt.newPassword, tt.confirm)
+			err = builder.AuthService.ResetPassword(ctx, token, tt.newPassword, tt.confirm)
 			if !errors.Is(err, tt.expectedErr) {
 				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
 			}
@@ -194,6 +272,13 @@ func TestAuthService_ResetPassword(t *testing.T) {
 				if err != nil {
 					t.Errorf("failed to login with new password: %v", err)
 				}
+				// The token and the sessions opened with the previous password are revoked
+				if err := builder.AuthService.ResetPassword(ctx, token, tt.newPassword, tt.confirm); !errors.Is(err, domain.ErrInvalidToken) {
+					t.Errorf("expected the reset token to be consumed, got %v", err)
+				}
+				if _, err := builder.TokenService.VerifyAuthToken(ctx, session); !errors.Is(err, domain.ErrInvalidToken) {
+					t.Errorf("expected the session to be revoked, got %v", err)
+				}
 			}
 		})
 	}
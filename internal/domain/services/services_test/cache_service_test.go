@@ -6,11 +6,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/storage/cache"
 	"go-starter/internal/adapters/timegen"
 	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+	"go-starter/internal/domain/services"
+	"go-starter/internal/domain/utils"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestCacheService_Get(t *testing.T) {
@@ -151,3 +164,354 @@ func TestCacheService_DeleteByPrefix(t *testing.T) {
 		t.Errorf("expected value to be %v, got %v", otherValue, cachedOtherValue)
 	}
 }
+
+// countingLoader loads a value, counting its calls.
+type countingLoader struct {
+	calls atomic.Int64
+	value []byte
+	err   error
+	// gate, when set, blocks the loads until it is closed.
+	gate chan struct{}
+}
+
+func (l *countingLoader) load(_ context.Context) ([]byte, error) {
+	l.calls.Add(1)
+	if l.gate != nil {
+		<-l.gate
+	}
+	return l.value, l.err
+}
+
+func TestCacheService_GetOrLoad(t *testing.T) {
+	t.Parallel()
+
+	const key = "key"
+	opts := ports.LoadOptions{
+		TTL:         time.Minute,
+		StaleTTL:    time.Minute,
+		NotFoundErr: domain.ErrUserNotFound,
+		NotFoundTTL: time.Minute,
+	}
+
+	tests := map[string]struct {
+		loader        *countingLoader
+		initCache     func(*TestBuilder)
+		advance       time.Duration
+		expectedValue []byte
+		expectedErr   error
+		expectedCalls int64
+	}{
+		"load a missing key": {
+			loader:        &countingLoader{value: []byte("loaded")},
+			expectedValue: []byte("loaded"),
+			expectedCalls: 1,
+		},
+		"get a stored key without loading it": {
+			loader: &countingLoader{value: []byte("loaded")},
+			initCache: func(builder *TestBuilder) {
+				err := builder.CacheService.Store(context.Background(), key, []byte("stored"), opts)
+				if err != nil {
+					t.Fatalf("failed to store value: %v", err)
+				}
+			},
+			expectedValue: []byte("stored"),
+			expectedCalls: 0,
+		},
+		"reload a key past its stale TTL": {
+			loader: &countingLoader{value: []byte("loaded")},
+			initCache: func(builder *TestBuilder) {
+				err := builder.CacheService.Store(context.Background(), key, []byte("stored"), opts)
+				if err != nil {
+					t.Fatalf("failed to store value: %v", err)
+				}
+			},
+			advance:       opts.TTL + opts.StaleTTL,
+			expectedValue: []byte("loaded"),
+			expectedCalls: 1,
+		},
+		"reload a key stored in another format": {
+			loader: &countingLoader{value: []byte("loaded")},
+			initCache: func(builder *TestBuilder) {
+				err := builder.CacheService.Set(context.Background(), key, []byte(`{"id":1}`), time.Hour)
+				if err != nil {
+					t.Fatalf("failed to set cache: %v", err)
+				}
+			},
+			expectedValue: []byte("loaded"),
+			expectedCalls: 1,
+		},
+		"return a load error": {
+			loader:        &countingLoader{err: domain.ErrInternal},
+			expectedErr:   domain.ErrInternal,
+			expectedCalls: 1,
+		},
+		"return a cached missing value without loading it": {
+			loader: &countingLoader{err: domain.ErrInternal},
+			initCache: func(builder *TestBuilder) {
+				_, err := builder.CacheService.GetOrLoad(context.Background(), key, opts, func(_ context.Context) ([]byte, error) {
+					return nil, domain.ErrUserNotFound
+				})
+				if !errors.Is(err, domain.ErrUserNotFound) {
+					t.Fatalf("expected error %v, got %v", domain.ErrUserNotFound, err)
+				}
+			},
+			expectedErr:   domain.ErrUserNotFound,
+			expectedCalls: 0,
+		},
+		"reload a missing value past its TTL": {
+			loader: &countingLoader{value: []byte("loaded")},
+			initCache: func(builder *TestBuilder) {
+				_, _ = builder.CacheService.GetOrLoad(context.Background(), key, opts, func(_ context.Context) ([]byte, error) {
+					return nil, domain.ErrUserNotFound
+				})
+			},
+			advance:       opts.NotFoundTTL,
+			expectedValue: []byte("loaded"),
+			expectedCalls: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			timeGenerator := timegen.NewTimeGeneratorMock(time.Now())
+			builder := NewTestBuilder().WithTimeGenerator(timeGenerator).Build()
+			if tt.initCache != nil {
+				tt.initCache(builder)
+			}
+			advanceTime(t, builder.TimeGenerator, tt.advance)
+
+			// Act
+			value, err := builder.CacheService.GetOrLoad(context.Background(), key, opts, tt.loader.load)
+
+			// Assert
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if !reflect.DeepEqual(value, tt.expectedValue) {
+				t.Errorf("expected value %q, got %q", tt.expectedValue, value)
+			}
+			if calls := tt.loader.calls.Load(); calls != tt.expectedCalls {
+				t.Errorf("expected %d loads, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestCacheService_GetOrLoad_SharesConcurrentLoads(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	builder := NewTestBuilder().Build()
+	loader := &countingLoader{value: []byte("loaded"), gate: make(chan struct{})}
+	opts := ports.LoadOptions{TTL: time.Minute}
+
+	// Act
+	const callers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := builder.CacheService.GetOrLoad(context.Background(), "key", opts, loader.load)
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(loader.gate)
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+	if calls := loader.calls.Load(); calls != 1 {
+		t.Errorf("expected a single load, got %d", calls)
+	}
+}
+
+func TestCacheService_GetOrLoad_RefreshesStaleValue(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().WithTimeGenerator(timegen.NewTimeGeneratorMock(time.Now())).Build()
+	opts := ports.LoadOptions{TTL: time.Minute, StaleTTL: time.Minute}
+	if err := builder.CacheService.Store(ctx, "key", []byte("stale"), opts); err != nil {
+		t.Fatalf("failed to store value: %v", err)
+	}
+	advanceTime(t, builder.TimeGenerator, opts.TTL)
+	loader := &countingLoader{value: []byte("fresh")}
+
+	// Act
+	value, err := builder.CacheService.GetOrLoad(ctx, "key", opts, loader.load)
+
+	// Assert
+	if err != nil || string(value) != "stale" {
+		t.Fatalf("expected the stale value at once, got %q (%v)", value, err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		value, err = builder.CacheService.GetOrLoad(ctx, "key", opts, loader.load)
+		if err == nil && string(value) == "fresh" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the value to be refreshed, got %q (%v)", value, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if calls := loader.calls.Load(); calls != 1 {
+		t.Errorf("expected a single refresh, got %d", calls)
+	}
+}
+
+func TestCacheService_GetOrLoad_WaitsForLockHolder(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	redis := miniredis.RunT(t)
+	redisCache, err := cache.New(ctx, &config.Redis{Addrs: []string{redis.Addr()}}, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	t.Cleanup(func() { _ = redisCache.Close() })
+
+	timeGenerator := timegen.NewTimeGenerator()
+	holder := services.NewCacheService(redisCache, timeGenerator)
+	waiter := services.NewCacheService(redisCache, timeGenerator)
+	opts := ports.LoadOptions{TTL: time.Minute, LockTimeout: 5 * time.Second}
+
+	holderLoader := &countingLoader{value: []byte("loaded"), gate: make(chan struct{})}
+	holderDone := make(chan error, 1)
+	go func() {
+		_, err := holder.GetOrLoad(ctx, "key", opts, holderLoader.load)
+		holderDone <- err
+	}()
+	for holderLoader.calls.Load() == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Act
+	waiterLoader := &countingLoader{value: []byte("loaded by the waiter")}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		close(holderLoader.gate)
+	}()
+	value, err := waiter.GetOrLoad(ctx, "key", opts, waiterLoader.load)
+
+	// Assert
+	if err != nil || string(value) != "loaded" {
+		t.Errorf("expected the value loaded by the lock holder, got %q (%v)", value, err)
+	}
+	if calls := waiterLoader.calls.Load(); calls != 0 {
+		t.Errorf("expected the waiter not to load the value, got %d loads", calls)
+	}
+	if err := <-holderDone; err != nil {
+		t.Errorf("expected the lock holder to load the value, got %v", err)
+	}
+}
+
+func TestUserService_GetByID_CachesUnknownUser(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	userID := entities.UserID(uuid.New())
+
+	// Act
+	_, err := builder.UserService.GetByID(ctx, userID)
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("expected error %v, got %v", domain.ErrUserNotFound, err)
+	}
+
+	// Assert
+	key := utils.GenerateCacheKey(services.UserCachePrefix, userID.String())
+	opts := ports.LoadOptions{NotFoundErr: domain.ErrUserNotFound}
+	_, err = builder.CacheService.GetOrLoad(ctx, key, opts, func(_ context.Context) ([]byte, error) {
+		t.Error("expected the unknown user to be cached")
+		return nil, domain.ErrInternal
+	})
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("expected error %v, got %v", domain.ErrUserNotFound, err)
+	}
+}
+
+func TestCacheService_InvalidateTags(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	values := map[string][]string{
+		"profile":    {"user:1"},
+		"session":    {"user:1", "session"},
+		"permission": {"user:2"},
+		"untagged":   nil,
+	}
+	for key, tags := range values {
+		if err := builder.CacheService.Set(ctx, key, []byte(key), time.Hour, tags...); err != nil {
+			t.Fatalf("failed to set cache: %v", err)
+		}
+	}
+
+	// Act
+	err := builder.CacheService.InvalidateTags(ctx, "user:1")
+	if err != nil {
+		t.Fatalf("failed to invalidate tags: %v", err)
+	}
+
+	// Assert
+	for key, expectedErr := range map[string]error{
+		"profile":    domain.ErrCacheNotFound,
+		"session":    domain.ErrCacheNotFound,
+		"permission": nil,
+		"untagged":   nil,
+	} {
+		if _, err := builder.CacheService.Get(ctx, key); !errors.Is(err, expectedErr) {
+			t.Errorf("expected error %v for %s, got %v", expectedErr, key, err)
+		}
+	}
+}
+
+func TestCacheService_InvalidateTags_DropsUserEntries(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	user, err := builder.UserService.Register(ctx, newValidUserToCreate())
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	token, err := builder.TokenService.GenerateAuthToken(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("failed to generate auth token: %v", err)
+	}
+	if _, err = builder.UserService.GetByID(ctx, user.ID); err != nil {
+		t.Fatalf("failed to get user: %v", err)
+	}
+
+	// Act
+	err = builder.CacheService.InvalidateTags(ctx, services.UserCacheTag(user.ID))
+	if err != nil {
+		t.Fatalf("failed to invalidate tags: %v", err)
+	}
+
+	// Assert
+	if _, err = builder.TokenService.VerifyAuthToken(ctx, token); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("expected error %v, got %v", domain.ErrInvalidToken, err)
+	}
+	key := utils.GenerateCacheKey(services.UserCachePrefix, user.ID.String())
+	if _, err = builder.CacheService.Get(ctx, key); !errors.Is(err, domain.ErrCacheNotFound) {
+		t.Errorf("expected the cached user to be dropped, got %v", err)
+	}
+}
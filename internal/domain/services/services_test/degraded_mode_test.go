@@ -0,0 +1,145 @@
+//go:build !integration
+
+package services_test
+
+import (
+	"context"
+	"errors"
+	"go-starter/config"
+	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/storage/cache"
+	"go-starter/internal/adapters/timegen"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/i18n"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Circuit breaker settings used in tests.
+const (
+	breakerFailureThreshold = 2
+	breakerOpenTimeout      = 10 * time.Second
+)
+
+// degradedModeHarness runs the services against an in-memory Redis server behind a circuit breaker,
+// with a registered user holding an access token.
+type degradedModeHarness struct {
+	builder *TestBuilder
+	breaker *cache.CircuitBreaker
+	redis   *miniredis.Miniredis
+	user    *entities.User
+	token   string
+}
+
+func newDegradedModeHarness(t *testing.T) *degradedModeHarness {
+	t.Helper()
+	ctx := context.Background()
+
+	redis := miniredis.RunT(t)
+	redisCfg := &config.Redis{
+		Addrs:                   []string{redis.Addr()},
+		BreakerFailureThreshold: breakerFailureThreshold,
+		BreakerOpenTimeout:      breakerOpenTimeout,
+	}
+	redisCache, err := cache.New(ctx, redisCfg, errtracker.NewErrTrackerAdapterMock(), noop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	t.Cleanup(func() { _ = redisCache.Close() })
+
+	builder := NewTestBuilder().WithTimeGenerator(timegen.NewTimeGeneratorMock(time.Now()))
+	breaker := cache.NewCircuitBreaker(redisCache, redisCfg, builder.TimeGenerator)
+	builder.CacheRepo = breaker
+	builder.Build()
+
+	user, err := builder.UserService.Register(ctx, newValidUserToCreate())
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	token, err := builder.TokenService.GenerateAuthToken(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("failed to generate auth token: %v", err)
+	}
+
+	return &degradedModeHarness{builder: builder, breaker: breaker, redis: redis, user: user, token: token}
+}
+
+// openBreaker stops the Redis server and fails enough calls for the breaker to open.
+func (h *degradedModeHarness) openBreaker(t *testing.T) {
+	t.Helper()
+	h.redis.Close()
+	for range breakerFailureThreshold {
+		if err := h.breaker.Ping(context.Background()); !errors.Is(err, domain.ErrCacheUnavailable) {
+			t.Fatalf("expected the cache to be unavailable, got %v", err)
+		}
+	}
+}
+
+func TestDegradedMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("token verification should report the service as unavailable", func(t *testing.T) {
+		t.Parallel()
+		h := newDegradedModeHarness(t)
+		h.redis.Close()
+
+		_, err := h.builder.TokenService.VerifyAuthToken(context.Background(), h.token)
+		if !errors.Is(err, domain.ErrServiceUnavailable) {
+			t.Errorf("expected error %v, got %v", domain.ErrServiceUnavailable, err)
+		}
+	})
+
+	t.Run("user lookup should fall back to the database", func(t *testing.T) {
+		t.Parallel()
+		h := newDegradedModeHarness(t)
+		h.openBreaker(t)
+
+		user, err := h.builder.UserService.GetByID(context.Background(), h.user.ID)
+		if err != nil {
+			t.Fatalf("expected the user to be read from the database, got %v", err)
+		}
+		if user.ID != h.user.ID {
+			t.Errorf("expected user %s, got %s", h.user.ID, user.ID)
+		}
+	})
+
+	t.Run("user update should succeed once saved in the database", func(t *testing.T) {
+		t.Parallel()
+		h := newDegradedModeHarness(t)
+		h.openBreaker(t)
+
+		if err := h.builder.UserService.UpdateLocale(context.Background(), h.user.ID, "fr"); err != nil {
+			t.Fatalf("expected the update not to fail on the cache, got %v", err)
+		}
+		user, err := h.builder.UserService.GetByID(context.Background(), h.user.ID)
+		if err != nil {
+			t.Fatalf("expected the user to be read from the database, got %v", err)
+		}
+		if user.Locale != i18n.LocaleFrench {
+			t.Errorf("expected locale %s, got %s", i18n.LocaleFrench, user.Locale)
+		}
+	})
+
+	t.Run("service should recover once redis returns", func(t *testing.T) {
+		t.Parallel()
+		h := newDegradedModeHarness(t)
+		h.openBreaker(t)
+
+		if err := h.redis.Restart(); err != nil {
+			t.Fatalf("failed to restart redis: %v", err)
+		}
+		advanceTime(t, h.builder.TimeGenerator, breakerOpenTimeout)
+
+		userID, err := h.builder.TokenService.VerifyAuthToken(context.Background(), h.token)
+		if err != nil {
+			t.Fatalf("expected the token to be verified once redis returns, got %v", err)
+		}
+		if userID != h.user.ID {
+			t.Errorf("expected user %s, got %s", h.user.ID, userID)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+//go:build !integration
+
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/ports"
+	"reflect"
+	"testing"
+)
+
+func TestEmailSuppressionService_HandleNotification(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	tests := map[string]struct {
+		notification          *ports.MailerNotification
+		payload               []byte
+		expectedSuppressed    []string
+		expectedReason        entities.SuppressionReason
+		expectedConfirmations int
+		expectedErr           error
+	}{
+		"hard bounce should suppress the recipients": {
+			notification: &ports.MailerNotification{
+				Type:       ports.MailerNotificationBounce,
+				Recipients: []string{"Bounced@Example.com"},
+				Permanent:  true,
+				Details:    "Permanent/General",
+			},
+			expectedSuppressed: []string{"bounced@example.com"},
+			expectedReason:     entities.SuppressionReasonBounce,
+		},
+		"soft bounce should be ignored": {
+			notification: &ports.MailerNotification{
+				Type:       ports.MailerNotificationBounce,
+				Recipients: []string{"bounced@example.com"},
+				Permanent:  false,
+				Details:    "Transient/MailboxFull",
+			},
+			expectedSuppressed: nil,
+		},
+		"complaint should suppress the recipients": {
+			notification: &ports.MailerNotification{
+				Type:       ports.MailerNotificationComplaint,
+				Recipients: []string{"complained@example.com"},
+				Details:    "abuse",
+			},
+			expectedSuppressed: []string{"complained@example.com"},
+			expectedReason:     entities.SuppressionReasonComplaint,
+		},
+		"subscription should be confirmed": {
+			notification: &ports.MailerNotification{
+				Type:         ports.MailerNotificationSubscription,
+				SubscribeURL: "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription",
+			},
+			expectedConfirmations: 1,
+		},
+		"other notification should be ignored": {
+			notification: &ports.MailerNotification{
+				Type:       ports.MailerNotificationOther,
+				Recipients: []string{"delivered@example.com"},
+			},
+			expectedSuppressed: nil,
+		},
+		"invalid payload should fail": {
+			payload:     []byte("not a notification"),
+			expectedErr: domain.ErrInvalidWebhookSignature,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			builder := NewTestBuilder().Build()
+
+			payload := tt.payload
+			if tt.notification != nil {
+				var err error
+				payload, err = json.Marshal(tt.notification)
+				if err != nil {
+					t.Fatalf("failed to encode notification: %v", err)
+				}
+			}
+
+			err := builder.EmailSuppressionService.HandleNotification(ctx, payload)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			candidates := []string{"bounced@example.com", "complained@example.com", "delivered@example.com"}
+			suppressed, err := builder.SuppressionRepo.GetSuppressed(ctx, candidates)
+			if err != nil {
+				t.Fatalf("failed to get suppressed emails: %v", err)
+			}
+			if !reflect.DeepEqual(suppressed, tt.expectedSuppressed) {
+				t.Errorf("expected suppressed %v, got %v", tt.expectedSuppressed, suppressed)
+			}
+
+			if len(tt.expectedSuppressed) > 0 {
+				suppressions, err := builder.EmailSuppressionService.List(ctx, 0, 0)
+				if err != nil {
+					t.Fatalf("failed to list suppressions: %v", err)
+				}
+				for _, suppression := range suppressions {
+					if suppression.Reason != tt.expectedReason {
+						t.Errorf("expected reason %v, got %v", tt.expectedReason, suppression.Reason)
+					}
+				}
+			}
+
+			confirmations := getConfirmedSubscriptionsCount(t, builder.MailerWebhookAdapter)
+			if confirmations != tt.expectedConfirmations {
+				t.Errorf("expected confirmations %v, got %v", tt.expectedConfirmations, confirmations)
+			}
+		})
+	}
+}
+
+func TestEmailSuppressionService_Delete(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	tests := map[string]struct {
+		input       string
+		expectedErr error
+	}{
+		"suppressed email should be removed": {
+			input:       "bounced@example.com",
+			expectedErr: nil,
+		},
+		"suppressed email should be removed case insensitively": {
+			input:       " Bounced@Example.com ",
+			expectedErr: nil,
+		},
+		"unknown email should fail": {
+			input:       "unknown@example.com",
+			expectedErr: domain.ErrEmailSuppressionNotFound,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			builder := NewTestBuilder().SetEnvToProduction().Build()
+			err := builder.SuppressionRepo.Upsert(ctx, &entities.EmailSuppression{
+				Email:  "bounced@example.com",
+				Reason: entities.SuppressionReasonBounce,
+			})
+			if err != nil {
+				t.Fatalf("failed to suppress email: %v", err)
+			}
+
+			err = builder.EmailSuppressionService.Delete(ctx, tt.input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if tt.expectedErr == nil {
+				err = builder.MailerService.Send(ctx, &ports.EmailMessage{
+					To:      []string{"bounced@example.com"},
+					Subject: "Test",
+					Body:    "Test",
+				})
+				if err != nil {
+					t.Errorf("expected email to be sent after removal, got %v", err)
+				}
+			}
+		})
+	}
+}
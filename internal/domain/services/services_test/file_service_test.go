@@ -0,0 +1,480 @@
+//go:build !integration
+
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go-starter/internal/adapters/scanner"
+	"go-starter/internal/adapters/server/handlers"
+	"go-starter/internal/adapters/timegen"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/services"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFileService_Upload(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	pdf := []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<<>>\nendobj\n")
+
+	tests := map[string]struct {
+		name                string
+		visibility          entities.FileVisibility
+		content             []byte
+		used                int
+		scanErr             error
+		expectedName        string
+		expectedContentType string
+		expectedKeyPrefix   string
+		expectedExtension   string
+		expectedErr         error
+	}{
+		"private pdf should be stored": {
+			name:                "report.pdf",
+			visibility:          entities.FileVisibilityPrivate,
+			content:             pdf,
+			expectedName:        "report.pdf",
+			expectedContentType: "application/pdf",
+			expectedKeyPrefix:   services.PrivateFilePath + "/",
+			expectedExtension:   ".pdf",
+		},
+		"public text file should be stored": {
+			name:                "notes.txt",
+			visibility:          entities.FileVisibilityPublic,
+			content:             []byte("some notes"),
+			expectedName:        "notes.txt",
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedKeyPrefix:   services.PublicFilePath + "/",
+			expectedExtension:   ".txt",
+		},
+		"extension not matching the content should be dropped from the key": {
+			name:                "../../avatar.png",
+			visibility:          entities.FileVisibilityPrivate,
+			content:             pdf,
+			expectedName:        "avatar.png",
+			expectedContentType: "application/pdf",
+			expectedKeyPrefix:   services.PrivateFilePath + "/",
+		},
+		"file of the maximum size should be stored": {
+			name:                "zeros.bin",
+			visibility:          entities.FileVisibilityPrivate,
+			content:             make([]byte, maxFileSize),
+			expectedName:        "zeros.bin",
+			expectedContentType: "application/octet-stream",
+			expectedKeyPrefix:   services.PrivateFilePath + "/",
+			expectedExtension:   ".bin",
+		},
+		"file larger than the maximum size should fail": {
+			name:        "zeros.bin",
+			visibility:  entities.FileVisibilityPrivate,
+			content:     make([]byte, maxFileSize+1),
+			expectedErr: domain.ErrFileTooLarge,
+		},
+		"file exceeding the storage quota should fail": {
+			name:        "notes.txt",
+			visibility:  entities.FileVisibilityPrivate,
+			content:     []byte("some notes"),
+			used:        userStorageQuota - 5,
+			expectedErr: domain.ErrStorageQuotaExceeded,
+		},
+		"empty file should fail": {
+			name:        "empty.txt",
+			visibility:  entities.FileVisibilityPrivate,
+			content:     []byte{},
+			expectedErr: domain.ErrFileSizeRequired,
+		},
+		"infected file should fail": {
+			name:        "notes.txt",
+			visibility:  entities.FileVisibilityPublic,
+			content:     []byte(scanner.EICARTestFile),
+			expectedErr: domain.ErrFileInfected,
+		},
+		"file that cannot be scanned should fail": {
+			name:        "notes.txt",
+			visibility:  entities.FileVisibilityPublic,
+			content:     []byte("some notes"),
+			scanErr:     errors.New("scanner unavailable"),
+			expectedErr: domain.ErrFileScanFailed,
+		},
+		"unknown visibility should fail": {
+			name:        "notes.txt",
+			visibility:  entities.FileVisibility("shared"),
+			content:     []byte("some notes"),
+			expectedErr: domain.ErrInvalidFileVisibility,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := NewTestBuilder().Build()
+			userID := entities.UserID(uuid.New())
+			fillStorage(t, builder, userID, tt.used)
+			setScanError(t, builder.FileScanner, tt.scanErr)
+
+			file, err := builder.FileService.Upload(ctx, userID, tt.name, tt.visibility, bytes.NewReader(tt.content))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				// The rejected files are neither stored nor counted against the quota.
+				files, err := builder.FileService.List(ctx, userID, services.MaxFileLimit, 0)
+				if err != nil {
+					t.Fatalf("failed to list files: %v", err)
+				}
+				if len(files) != (tt.used+maxFileSize-1)/maxFileSize {
+					t.Errorf("expected the rejected file not to be recorded, got %d files", len(files))
+				}
+				return
+			}
+
+			if file.Status != entities.FileStatusAvailable {
+				t.Errorf("expected status %s, got %s", entities.FileStatusAvailable, file.Status)
+			}
+
+			if file.Name != tt.expectedName {
+				t.Errorf("expected name %q, got %q", tt.expectedName, file.Name)
+			}
+			if file.ContentType != tt.expectedContentType {
+				t.Errorf("expected content type %s, got %s", tt.expectedContentType, file.ContentType)
+			}
+			if file.Size != int64(len(tt.content)) {
+				t.Errorf("expected size %d, got %d", len(tt.content), file.Size)
+			}
+			checksum := sha256.Sum256(tt.content)
+			if file.Checksum != hex.EncodeToString(checksum[:]) {
+				t.Errorf("expected checksum %x, got %s", checksum, file.Checksum)
+			}
+
+			expectedKey := tt.expectedKeyPrefix + userID.String() + "/" + file.ID.String() + tt.expectedExtension
+			if file.Key != expectedKey {
+				t.Errorf("expected key %s, got %s", expectedKey, file.Key)
+			}
+			if !fileExists(t, builder.FileUploadAdapter, file.Key) {
+				t.Error("expected the file to be stored")
+			}
+
+			expectedURL := ""
+			if tt.visibility == entities.FileVisibilityPublic {
+				expectedURL = "https://example.com/" + file.Key
+			}
+			if file.URL != expectedURL {
+				t.Errorf("expected url %q, got %q", expectedURL, file.URL)
+			}
+		})
+	}
+}
+
+func TestFileService_Download(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	now := time.Now()
+	builder := NewTestBuilder().WithTimeGenerator(timegen.NewTimeGeneratorMock(now)).Build()
+	userID := entities.UserID(uuid.New())
+
+	publicFile, err := builder.FileService.Upload(ctx, userID, "public.txt", entities.FileVisibilityPublic, strings.NewReader("public"))
+	if err != nil {
+		t.Fatalf("failed to upload file: %v", err)
+	}
+	privateFile, err := builder.FileService.Upload(ctx, userID, "private.txt", entities.FileVisibilityPrivate, strings.NewReader("private"))
+	if err != nil {
+		t.Fatalf("failed to upload file: %v", err)
+	}
+	quarantinedFile := &entities.File{
+		ID:         entities.NewFileID(),
+		OwnerID:    userID,
+		Key:        services.PrivateFilePath + "/" + userID.String() + "/quarantined",
+		Size:       1,
+		Visibility: entities.FileVisibilityPrivate,
+		Status:     entities.FileStatusQuarantined,
+	}
+	if err := builder.FileRepo.Create(ctx, quarantinedFile, userStorageQuota); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	tests := map[string]struct {
+		ownerID           entities.UserID
+		fileID            entities.FileID
+		expectedURL       string
+		expectedExpiresAt time.Time
+		expectedErr       error
+	}{
+		"public file should be downloaded from its url": {
+			ownerID:     userID,
+			fileID:      publicFile.ID,
+			expectedURL: publicFile.URL,
+		},
+		"private file should be downloaded from a signed url": {
+			ownerID:           userID,
+			fileID:            privateFile.ID,
+			expectedURL:       "https://example.com/" + privateFile.Key + "?signature=mock&expires=" + strconv.FormatInt(now.Add(privateFileURLDuration).Unix(), 10),
+			expectedExpiresAt: now.Add(privateFileURLDuration),
+		},
+		"quarantined file should fail": {
+			ownerID:     userID,
+			fileID:      quarantinedFile.ID,
+			expectedErr: domain.ErrFileQuarantined,
+		},
+		"file of another user should not be found": {
+			ownerID:     entities.UserID(uuid.New()),
+			fileID:      privateFile.ID,
+			expectedErr: domain.ErrFileNotFound,
+		},
+		"unknown file should not be found": {
+			ownerID:     userID,
+			fileID:      entities.NewFileID(),
+			expectedErr: domain.ErrFileNotFound,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file, link, err := builder.FileService.Download(ctx, tt.ownerID, tt.fileID)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if file.ID != tt.fileID {
+				t.Errorf("expected file %s, got %s", tt.fileID, file.ID)
+			}
+			if link.URL != tt.expectedURL {
+				t.Errorf("expected url %s, got %s", tt.expectedURL, link.URL)
+			}
+			if !link.ExpiresAt.Equal(tt.expectedExpiresAt) {
+				t.Errorf("expected url to expire at %v, got %v", tt.expectedExpiresAt, link.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestFileService_Download_LocalStorage(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().WithLocalStorage(t).Build()
+	userID := entities.UserID(uuid.New())
+
+	file, err := builder.FileService.Upload(ctx, userID, "private.txt", entities.FileVisibilityPrivate, strings.NewReader("private"))
+	if err != nil {
+		t.Fatalf("failed to upload file: %v", err)
+	}
+
+	// Act
+	_, link, err := builder.FileService.Download(ctx, userID, file.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	fileURL, err := url.Parse(link.URL)
+	if err != nil {
+		t.Fatalf("failed to parse file url: %v", err)
+	}
+	if fileURL.Query().Get("expires") != strconv.FormatInt(link.ExpiresAt.Unix(), 10) {
+		t.Errorf("expected a url expiring at %d, got %s", link.ExpiresAt.Unix(), fileURL.Query().Get("expires"))
+	}
+
+	stored, err := builder.FileUploadService.OpenFile(ctx, file.Key, fileURL.Query())
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer stored.Content.Close()
+}
+
+func TestFileService_List(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	userID := entities.UserID(uuid.New())
+
+	for i := range 3 {
+		_, err := builder.FileService.Upload(ctx, userID, "file"+strconv.Itoa(i)+".txt", entities.FileVisibilityPrivate, strings.NewReader("content"))
+		if err != nil {
+			t.Fatalf("failed to upload file: %v", err)
+		}
+	}
+	_, err := builder.FileService.Upload(ctx, entities.UserID(uuid.New()), "other.txt", entities.FileVisibilityPrivate, strings.NewReader("content"))
+	if err != nil {
+		t.Fatalf("failed to upload file: %v", err)
+	}
+
+	tests := map[string]struct {
+		limit         int
+		offset        int
+		expectedCount int
+	}{
+		"default limit should list all the files of the user": {
+			expectedCount: 3,
+		},
+		"limit should be applied": {
+			limit:         2,
+			expectedCount: 2,
+		},
+		"offset should be applied": {
+			limit:         2,
+			offset:        2,
+			expectedCount: 1,
+		},
+		"offset past the end should list nothing": {
+			offset:        5,
+			expectedCount: 0,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			files, err := builder.FileService.List(ctx, userID, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(files) != tt.expectedCount {
+				t.Errorf("expected %d files, got %d", tt.expectedCount, len(files))
+			}
+			for _, file := range files {
+				if file.OwnerID != userID {
+					t.Errorf("expected only the files of the user, got one of %s", file.OwnerID)
+				}
+			}
+		})
+	}
+}
+
+func TestFileService_Delete(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	userID := entities.UserID(uuid.New())
+
+	tests := map[string]struct {
+		ownerID     entities.UserID
+		expectedErr error
+	}{
+		"file of the user should be deleted": {
+			ownerID: userID,
+		},
+		"file of another user should not be found": {
+			ownerID:     entities.UserID(uuid.New()),
+			expectedErr: domain.ErrFileNotFound,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := NewTestBuilder().Build()
+			file, err := builder.FileService.Upload(ctx, userID, "notes.txt", entities.FileVisibilityPrivate, strings.NewReader("some notes"))
+			if err != nil {
+				t.Fatalf("failed to upload file: %v", err)
+			}
+
+			err = builder.FileService.Delete(ctx, tt.ownerID, file.ID)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			deleted := err == nil
+			if fileExists(t, builder.FileUploadAdapter, file.Key) == deleted {
+				t.Errorf("expected the stored file to be deleted: %t", deleted)
+			}
+
+			usage, err := builder.FileService.Usage(ctx, userID)
+			if err != nil {
+				t.Fatalf("failed to get storage usage: %v", err)
+			}
+			expectedUsed := file.Size
+			if deleted {
+				expectedUsed = 0
+			}
+			if usage.Used != expectedUsed || usage.Quota != userStorageQuota {
+				t.Errorf("expected %d of %d bytes used, got %d of %d", expectedUsed, userStorageQuota, usage.Used, usage.Quota)
+			}
+		})
+	}
+}
+
+// fillStorage stores files for a user until they use the given number of bytes.
+func fillStorage(t *testing.T, builder *TestBuilder, userID entities.UserID, used int) {
+	t.Helper()
+	for used > 0 {
+		size := min(used, maxFileSize)
+		_, err := builder.FileService.Upload(context.Background(), userID, "filler.bin", entities.FileVisibilityPrivate, bytes.NewReader(make([]byte, size)))
+		if err != nil {
+			t.Fatalf("failed to fill storage: %v", err)
+		}
+		used -= size
+	}
+}
+
+func TestFileHandler_Upload_RejectsLargeFile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		size int
+	}{
+		"file larger than the maximum size should be rejected":     {size: 2 << 10},
+		"body larger than the maximum size should not be buffered": {size: 3 << 20},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			builder := NewTestBuilder().Build()
+			builder.Config.FileUpload.MaxFileSize = 1 << 10
+			handler := handlers.NewFileHandler(builder.Config.FileUpload, builder.FileUploadService, builder.FileService, builder.ErrTrackerAdapter)
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, _ := form.CreateFormFile("file", "report.pdf")
+			_, _ = part.Write(bytes.Repeat([]byte("a"), tt.size))
+			_ = form.Close()
+			req := httptest.NewRequest(http.MethodPost, "/v1/users/me/files", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.Upload(rec, req)
+
+			// Assert
+			if rec.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("expected status %d, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+			}
+		})
+	}
+}
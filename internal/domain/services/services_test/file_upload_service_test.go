@@ -0,0 +1,766 @@
+//go:build !integration
+
+package services_test
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"go-starter/internal/adapters/scanner"
+	"go-starter/internal/adapters/timegen"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/services"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFileUploadService_OpenFile_LocalStorage(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	userID := entities.UserID(uuid.New())
+
+	// The key of the 64px avatar is opened when none is given.
+	tests := map[string]struct {
+		key          string
+		params       func(avatarURL *url.URL) url.Values
+		deleteBefore bool
+		expectedSize int
+		expectedErr  error
+	}{
+		"signed url should open the file": {
+			params: func(avatarURL *url.URL) url.Values {
+				return avatarURL.Query()
+			},
+			expectedSize: 64,
+		},
+		"tampered signature should fail": {
+			params: func(avatarURL *url.URL) url.Values {
+				params := avatarURL.Query()
+				params.Set("signature", strings.Repeat("A", 43))
+				return params
+			},
+			expectedErr: domain.ErrInvalidFileSignature,
+		},
+		"missing signature should fail": {
+			params: func(_ *url.URL) url.Values {
+				return url.Values{}
+			},
+			expectedErr: domain.ErrInvalidFileSignature,
+		},
+		"signature of another file should fail": {
+			key: "avatars/other.png",
+			params: func(avatarURL *url.URL) url.Values {
+				return avatarURL.Query()
+			},
+			expectedErr: domain.ErrInvalidFileSignature,
+		},
+		"signature extended with an expiration should fail": {
+			params: func(avatarURL *url.URL) url.Values {
+				params := avatarURL.Query()
+				params.Set("expires", "1")
+				return params
+			},
+			expectedErr: domain.ErrInvalidFileSignature,
+		},
+		"deleted file should not be found": {
+			params: func(avatarURL *url.URL) url.Values {
+				return avatarURL.Query()
+			},
+			deleteBefore: true,
+			expectedErr:  domain.ErrFileNotFound,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := NewTestBuilder().WithLocalStorage(t).Build()
+			avatarFiles, err := builder.FileUploadService.UploadAvatar(ctx, userID, bytes.NewReader(newTestImage(t, "png", 100, 100)))
+			if err != nil {
+				t.Fatalf("failed to upload avatar: %v", err)
+			}
+			avatarURL, err := url.Parse(avatarFiles["64"].URL)
+			if err != nil {
+				t.Fatalf("failed to parse avatar url: %v", err)
+			}
+			avatarKey := avatarFiles["64"].Key
+			if !strings.HasSuffix(avatarURL.Path, "/files/"+avatarKey) {
+				t.Fatalf("expected avatar url to serve %s, got %s", avatarKey, avatarURL.Path)
+			}
+
+			if tt.deleteBefore {
+				err = builder.FileUploadService.DeleteAvatar(ctx, userID, avatarFileIDs(avatarFiles))
+				if err != nil {
+					t.Fatalf("failed to delete avatar: %v", err)
+				}
+			}
+
+			key := cmp.Or(tt.key, avatarKey)
+			file, err := builder.FileUploadService.OpenFile(ctx, key, tt.params(avatarURL))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			defer file.Content.Close()
+
+			cfg, err := png.DecodeConfig(file.Content)
+			if err != nil {
+				t.Fatalf("failed to decode avatar: %v", err)
+			}
+			if cfg.Width != tt.expectedSize || cfg.Height != tt.expectedSize {
+				t.Errorf("expected a %dx%d avatar, got %dx%d", tt.expectedSize, tt.expectedSize, cfg.Width, cfg.Height)
+			}
+		})
+	}
+}
+
+func TestFileUploadService_OpenFile_ExpiredURL(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().WithLocalStorage(t).Build()
+	local, ok := builder.FileServerAdapter.(interface {
+		SignedURL(key string, expiresAt time.Time) string
+	})
+	if !ok {
+		t.Fatal("the file server adapter does not implement SignedURL()")
+	}
+
+	_, err := builder.FileUploadAdapter.Upload(ctx, "documents/report.txt", "text/plain; charset=utf-8", strings.NewReader("report"))
+	if err != nil {
+		t.Fatalf("failed to upload file: %v", err)
+	}
+
+	tests := map[string]struct {
+		expiresAt   time.Time
+		expectedErr error
+	}{
+		"url before its expiration should open the file": {
+			expiresAt:   time.Now().Add(time.Hour),
+			expectedErr: nil,
+		},
+		"expired url should fail": {
+			expiresAt:   time.Now().Add(-time.Second),
+			expectedErr: domain.ErrInvalidFileSignature,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			signedURL, err := url.Parse(local.SignedURL("documents/report.txt", tt.expiresAt))
+			if err != nil {
+				t.Fatalf("failed to parse signed url: %v", err)
+			}
+
+			file, err := builder.FileUploadService.OpenFile(ctx, "documents/report.txt", signedURL.Query())
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err == nil {
+				file.Content.Close()
+			}
+		})
+	}
+}
+
+func TestFileUploadService_OpenFile_RemoteStorage(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	builder := NewTestBuilder().Build()
+
+	// Act
+	_, err := builder.FileUploadService.OpenFile(context.Background(), "avatars/avatar.png", url.Values{})
+
+	// Assert
+	if !errors.Is(err, domain.ErrFileNotFound) {
+		t.Errorf("expected error %v, got %v", domain.ErrFileNotFound, err)
+	}
+}
+
+func TestFileUploadService_UploadAvatar(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	userID := entities.UserID(uuid.New())
+
+	// 1x1 lossless WebP image.
+	webpImage, err := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	if err != nil {
+		t.Fatalf("failed to decode webp image: %v", err)
+	}
+	pngImage := newTestImage(t, "png", 300, 200)
+
+	tests := map[string]struct {
+		input       []byte
+		expectedErr error
+	}{
+		"png image should be resized": {
+			input:       pngImage,
+			expectedErr: nil,
+		},
+		"jpeg image should be resized": {
+			input:       newTestImage(t, "jpeg", 200, 300),
+			expectedErr: nil,
+		},
+		"webp image should be resized": {
+			input:       webpImage,
+			expectedErr: nil,
+		},
+		"gif image should fail": {
+			input:       newTestImage(t, "gif", 10, 10),
+			expectedErr: domain.ErrInvalidFileType,
+		},
+		"renamed executable should fail": {
+			input:       []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff\x00\x00"),
+			expectedErr: domain.ErrInvalidFileType,
+		},
+		"truncated image should fail": {
+			input:       pngImage[:len(pngImage)/2],
+			expectedErr: domain.ErrInvalidImage,
+		},
+		"image wider than the limit should fail": {
+			input:       withPNGDimensions(t, pngImage, 100_000, 1),
+			expectedErr: domain.ErrImageTooLarge,
+		},
+		"decompression bomb should fail": {
+			input:       withPNGDimensions(t, pngImage, 8000, 8000),
+			expectedErr: domain.ErrImageTooLarge,
+		},
+		"infected image should fail": {
+			input:       append(slices.Clone(pngImage), scanner.EICARTestFile...),
+			expectedErr: domain.ErrFileInfected,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			avatarFiles, err := builder.FileUploadService.UploadAvatar(ctx, userID, bytes.NewReader(tt.input))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(avatarFiles) != len(services.AvatarSizes) {
+				t.Errorf("expected %d avatar variants, got %d", len(services.AvatarSizes), len(avatarFiles))
+			}
+			for _, size := range services.AvatarSizes {
+				file := avatarFiles[strconv.Itoa(size)]
+				if file == nil {
+					t.Fatalf("expected a %dpx avatar variant", size)
+				}
+				expectedKeyPrefix := services.PublicFilePath + "/" + userID.String() + "/"
+				if !strings.HasPrefix(file.Key, expectedKeyPrefix) || !strings.HasSuffix(file.Key, ".png") {
+					t.Errorf("expected %dpx avatar key to be a png under %s, got %s", size, expectedKeyPrefix, file.Key)
+				}
+				if file.URL != "https://example.com/"+file.Key {
+					t.Errorf("expected %dpx avatar url to serve %s, got %s", size, file.Key, file.URL)
+				}
+				if file.ContentType != "image/png" || file.Visibility != entities.FileVisibilityPublic {
+					t.Errorf("expected %dpx avatar to be a public png, got a %s %s file", size, file.Visibility, file.ContentType)
+				}
+			}
+		})
+	}
+}
+
+func TestFileUploadService_UploadAvatar_ScansImageOnly(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	userID := entities.UserID(uuid.New())
+
+	// Act
+	avatarFiles, err := builder.FileUploadService.UploadAvatar(ctx, userID, bytes.NewReader(newTestImage(t, "png", 100, 100)))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("failed to upload avatar: %v", err)
+	}
+	if len(avatarFiles) != len(services.AvatarSizes) {
+		t.Errorf("expected %d variants, got %d", len(services.AvatarSizes), len(avatarFiles))
+	}
+	if scans := getScanCount(t, builder.FileScanner); scans != 1 {
+		t.Errorf("expected the image to be scanned once, got %d scans", scans)
+	}
+}
+
+func TestFileUploadService_UploadAvatar_Variants(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	userID := entities.UserID(uuid.New())
+
+	// Left half red, right half blue: rotated 90° clockwise, red ends up on top.
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+	halves := image.NewNRGBA(image.Rect(0, 0, 128, 128))
+	draw.Draw(halves, image.Rect(0, 0, 64, 128), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(halves, image.Rect(64, 0, 128, 128), image.NewUniform(blue), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, halves, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("failed to encode jpeg image: %v", err)
+	}
+
+	tests := map[string]struct {
+		input            []byte
+		expectedTopLeft  color.NRGBA
+		expectedTopRight color.NRGBA
+	}{
+		"image without orientation should be kept as is": {
+			input:            buf.Bytes(),
+			expectedTopLeft:  red,
+			expectedTopRight: blue,
+		},
+		"image rotated 90° clockwise should be displayed upright": {
+			input:            withJPEGOrientation(buf.Bytes(), 6),
+			expectedTopLeft:  red,
+			expectedTopRight: red,
+		},
+		"image rotated 90° counterclockwise should be displayed upright": {
+			input:            withJPEGOrientation(buf.Bytes(), 8),
+			expectedTopLeft:  blue,
+			expectedTopRight: blue,
+		},
+		"image rotated 180° should be displayed upright": {
+			input:            withJPEGOrientation(buf.Bytes(), 3),
+			expectedTopLeft:  blue,
+			expectedTopRight: red,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := NewTestBuilder().WithLocalStorage(t).Build()
+			avatarFiles, err := builder.FileUploadService.UploadAvatar(ctx, userID, bytes.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("failed to upload avatar: %v", err)
+			}
+
+			for _, size := range services.AvatarSizes {
+				avatarFile := avatarFiles[strconv.Itoa(size)]
+				avatarURL, err := url.Parse(avatarFile.URL)
+				if err != nil {
+					t.Fatalf("failed to parse avatar url: %v", err)
+				}
+				file, err := builder.FileUploadService.OpenFile(ctx, avatarFile.Key, avatarURL.Query())
+				if err != nil {
+					t.Fatalf("failed to open %dpx avatar: %v", size, err)
+				}
+				variant, err := png.Decode(file.Content)
+				file.Content.Close()
+				if err != nil {
+					t.Fatalf("failed to decode %dpx avatar: %v", size, err)
+				}
+
+				if variant.Bounds().Dx() != size || variant.Bounds().Dy() != size {
+					t.Errorf("expected a %dx%d avatar, got %v", size, size, variant.Bounds())
+				}
+
+				// The centers of the top quarters are away from the edge between both halves.
+				topLeft := color.NRGBAModel.Convert(variant.At(size/4, size/4)).(color.NRGBA)
+				if !similarColors(topLeft, tt.expectedTopLeft) {
+					t.Errorf("expected the top left of the %dpx avatar to be %v, got %v", size, tt.expectedTopLeft, topLeft)
+				}
+				topRight := color.NRGBAModel.Convert(variant.At(3*size/4, size/4)).(color.NRGBA)
+				if !similarColors(topRight, tt.expectedTopRight) {
+					t.Errorf("expected the top right of the %dpx avatar to be %v, got %v", size, tt.expectedTopRight, topRight)
+				}
+			}
+		})
+	}
+}
+
+// newTestImage returns a gradient image encoded in the given format.
+func TestFileUploadService_PresignAvatarUpload(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	userID := entities.UserID(uuid.New())
+
+	tests := map[string]struct {
+		contentType string
+		size        int64
+		expectedErr error
+	}{
+		"png avatar should be presigned": {
+			contentType: "image/png",
+			size:        1024,
+			expectedErr: nil,
+		},
+		"avatar of the maximum size should be presigned": {
+			contentType: "image/webp",
+			size:        services.AvatarMaxSize,
+			expectedErr: nil,
+		},
+		"gif avatar should fail": {
+			contentType: "image/gif",
+			size:        1024,
+			expectedErr: domain.ErrInvalidFileType,
+		},
+		"empty avatar should fail": {
+			contentType: "image/jpeg",
+			size:        0,
+			expectedErr: domain.ErrFileSizeRequired,
+		},
+		"avatar larger than the limit should fail": {
+			contentType: "image/jpeg",
+			size:        services.AvatarMaxSize + 1,
+			expectedErr: domain.ErrFileTooLarge,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			upload, presigned, err := builder.FileUploadService.PresignAvatarUpload(ctx, userID, tt.contentType, tt.size)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			expectedKey := services.PendingUploadPath + "/" + userID.String() + "/" + upload.ID.String()
+			if upload.Key != expectedKey {
+				t.Errorf("expected upload key %s, got %s", expectedKey, upload.Key)
+			}
+			if presigned.Headers.Get("Content-Type") != tt.contentType {
+				t.Errorf("expected signed content type %s, got %s", tt.contentType, presigned.Headers.Get("Content-Type"))
+			}
+			if !upload.ExpiresAt.After(presigned.ExpiresAt) {
+				t.Errorf("expected the upload to outlive its url, got %v and %v", upload.ExpiresAt, presigned.ExpiresAt)
+			}
+			if _, err := builder.PendingUploadRepo.GetByID(ctx, upload.ID); err != nil {
+				t.Errorf("expected the upload to be recorded, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFileUploadService_ConfirmAvatarUpload(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	userID := entities.UserID(uuid.New())
+	pngImage := newTestImage(t, "png", 100, 100)
+
+	tests := map[string]struct {
+		contentType string
+		content     []byte
+		skipUpload  bool
+		confirmedBy entities.UserID
+		advance     time.Duration
+		expectedErr error
+	}{
+		"uploaded avatar should be confirmed": {
+			contentType: "image/png",
+			content:     pngImage,
+			expectedErr: nil,
+		},
+		"missing file should fail": {
+			contentType: "image/png",
+			content:     pngImage,
+			skipUpload:  true,
+			expectedErr: domain.ErrUploadNotCompleted,
+		},
+		"file of another size should fail": {
+			contentType: "image/png",
+			content:     append(slices.Clone(pngImage), 0),
+			expectedErr: domain.ErrUploadMismatch,
+		},
+		"file of another content type should fail": {
+			contentType: "image/jpeg",
+			content:     pngImage,
+			expectedErr: domain.ErrUploadMismatch,
+		},
+		"upload of another user should not be found": {
+			contentType: "image/png",
+			content:     pngImage,
+			confirmedBy: entities.UserID(uuid.New()),
+			expectedErr: domain.ErrUploadNotFound,
+		},
+		"expired upload should not be found": {
+			contentType: "image/png",
+			content:     pngImage,
+			advance:     presignedUploadDuration + 2*time.Hour,
+			expectedErr: domain.ErrUploadNotFound,
+		},
+		"corrupted image should fail": {
+			contentType: "image/png",
+			content:     append(slices.Clone(pngImage[:len(pngImage)/2]), make([]byte, len(pngImage)-len(pngImage)/2)...),
+			expectedErr: domain.ErrInvalidImage,
+		},
+		"infected image should fail": {
+			contentType: "image/png",
+			content:     append(slices.Clone(pngImage[:len(pngImage)-len(scanner.EICARTestFile)]), scanner.EICARTestFile...),
+			expectedErr: domain.ErrFileInfected,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			timeGenerator := timegen.NewTimeGeneratorMock(time.Now())
+			builder := NewTestBuilder().WithTimeGenerator(timeGenerator).Build()
+
+			upload, _, err := builder.FileUploadService.PresignAvatarUpload(ctx, userID, "image/png", int64(len(pngImage)))
+			if err != nil {
+				t.Fatalf("failed to presign upload: %v", err)
+			}
+			if !tt.skipUpload {
+				putPresigned(t, builder.FileUploadAdapter, upload.Key, tt.contentType, tt.content)
+			}
+			timeGenerator.Advance(tt.advance)
+
+			confirmedBy := userID
+			if tt.confirmedBy != entities.NilUserID {
+				confirmedBy = tt.confirmedBy
+			}
+
+			avatarFiles, err := builder.FileUploadService.ConfirmAvatarUpload(ctx, confirmedBy, upload.ID)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if errors.Is(err, domain.ErrFileInfected) && fileExists(t, builder.FileUploadAdapter, upload.Key) {
+				t.Error("expected the infected file to be deleted")
+			}
+			if err != nil {
+				return
+			}
+
+			if len(avatarFiles) != len(services.AvatarSizes) {
+				t.Errorf("expected %d avatar variants, got %d", len(services.AvatarSizes), len(avatarFiles))
+			}
+			if fileExists(t, builder.FileUploadAdapter, upload.Key) {
+				t.Error("expected the uploaded file to be deleted")
+			}
+			if _, err := builder.PendingUploadRepo.GetByID(ctx, upload.ID); !errors.Is(err, domain.ErrUploadNotFound) {
+				t.Errorf("expected the upload record to be deleted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFileUploadService_CleanupPendingUploads(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	timeGenerator := timegen.NewTimeGeneratorMock(time.Now())
+	builder := NewTestBuilder().WithTimeGenerator(timeGenerator).Build()
+	userID := entities.UserID(uuid.New())
+
+	expired, _, err := builder.FileUploadService.PresignAvatarUpload(ctx, userID, "image/png", 4)
+	if err != nil {
+		t.Fatalf("failed to presign upload: %v", err)
+	}
+	putPresigned(t, builder.FileUploadAdapter, expired.Key, "image/png", []byte("test"))
+
+	timeGenerator.Advance(presignedUploadDuration + 2*time.Hour)
+	pending, _, err := builder.FileUploadService.PresignAvatarUpload(ctx, userID, "image/png", 4)
+	if err != nil {
+		t.Fatalf("failed to presign upload: %v", err)
+	}
+	putPresigned(t, builder.FileUploadAdapter, pending.Key, "image/png", []byte("test"))
+
+	// Act
+	deleted, err := builder.FileUploadService.CleanupPendingUploads(ctx)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 upload to be deleted, got %d", deleted)
+	}
+	if fileExists(t, builder.FileUploadAdapter, expired.Key) {
+		t.Error("expected the expired file to be deleted")
+	}
+	if _, err := builder.PendingUploadRepo.GetByID(ctx, expired.ID); !errors.Is(err, domain.ErrUploadNotFound) {
+		t.Errorf("expected the expired upload record to be deleted, got %v", err)
+	}
+	if !fileExists(t, builder.FileUploadAdapter, pending.Key) {
+		t.Error("expected the pending file to be kept")
+	}
+}
+
+func TestFileUploadService_StoreFile_LocalStorage(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	userID := entities.UserID(uuid.New())
+	pngImage := newTestImage(t, "png", 100, 100)
+
+	tests := map[string]struct {
+		contentType string
+		content     []byte
+		expectedErr error
+	}{
+		"signed upload should be stored and confirmed": {
+			contentType: "image/png",
+			content:     pngImage,
+			expectedErr: nil,
+		},
+		"upload of another content type should fail": {
+			contentType: "image/jpeg",
+			content:     pngImage,
+			expectedErr: domain.ErrInvalidFileSignature,
+		},
+		"upload larger than the signed size should fail": {
+			contentType: "image/png",
+			content:     append(slices.Clone(pngImage), make([]byte, 64)...),
+			expectedErr: domain.ErrFileTooLarge,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := NewTestBuilder().WithLocalStorage(t).Build()
+			upload, presigned, err := builder.FileUploadService.PresignAvatarUpload(ctx, userID, "image/png", int64(len(pngImage)))
+			if err != nil {
+				t.Fatalf("failed to presign upload: %v", err)
+			}
+			uploadURL, err := url.Parse(presigned.URL)
+			if err != nil {
+				t.Fatalf("failed to parse upload url: %v", err)
+			}
+			if !strings.HasSuffix(uploadURL.Path, "/files/"+upload.Key) {
+				t.Fatalf("expected upload url to store %s, got %s", upload.Key, uploadURL.Path)
+			}
+
+			err = builder.FileUploadService.StoreFile(ctx, upload.Key, uploadURL.Query(), tt.contentType, bytes.NewReader(tt.content))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			avatarFiles, err := builder.FileUploadService.ConfirmAvatarUpload(ctx, userID, upload.ID)
+			if err != nil {
+				t.Fatalf("failed to confirm upload: %v", err)
+			}
+			if len(avatarFiles) != len(services.AvatarSizes) {
+				t.Errorf("expected %d avatar variants, got %d", len(services.AvatarSizes), len(avatarFiles))
+			}
+		})
+	}
+}
+
+func newTestImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		t.Fatalf("unsupported test image format %s", format)
+	}
+	if err != nil {
+		t.Fatalf("failed to encode %s image: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+// withPNGDimensions returns a copy of a PNG image whose header claims the given dimensions.
+func withPNGDimensions(t *testing.T, pngImage []byte, width, height uint32) []byte {
+	t.Helper()
+	// Signature (8 bytes), then the IHDR chunk: length (4), type (4), width (4), height (4), ..., CRC at offset 29.
+	patched := bytes.Clone(pngImage)
+	if string(patched[12:16]) != "IHDR" {
+		t.Fatal("the test image does not start with an IHDR chunk")
+	}
+	binary.BigEndian.PutUint32(patched[16:], width)
+	binary.BigEndian.PutUint32(patched[20:], height)
+	binary.BigEndian.PutUint32(patched[29:], crc32.ChecksumIEEE(patched[12:29]))
+	return patched
+}
+
+// withJPEGOrientation returns a copy of a JPEG image with an EXIF segment holding the given orientation.
+func withJPEGOrientation(jpegImage []byte, orientation uint16) []byte {
+	exif := []byte("Exif\x00\x00")
+	exif = append(exif, "MM\x00\x2a\x00\x00\x00\x08"...)       // big endian TIFF header, first IFD at offset 8
+	exif = append(exif, 0x00, 0x01)                            // 1 entry
+	exif = append(exif, 0x01, 0x12, 0x00, 0x03, 0, 0, 0, 0x01) // orientation tag, SHORT, count 1
+	exif = binary.BigEndian.AppendUint16(exif, orientation)
+	exif = append(exif, 0, 0, 0, 0, 0, 0) // padding, no next IFD
+
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(exif)+2))
+	segment = append(segment, exif...)
+
+	// The segment goes right after the start of image marker.
+	return slices.Concat(jpegImage[:2], segment, jpegImage[2:])
+}
+
+// similarColors reports whether two colors are close enough, given the JPEG compression and the resampling.
+func similarColors(a, b color.NRGBA) bool {
+	diff := func(x, y uint8) int {
+		return max(int(x), int(y)) - min(int(x), int(y))
+	}
+	return diff(a.R, b.R) < 48 && diff(a.G, b.G) < 48 && diff(a.B, b.B) < 48
+}
@@ -0,0 +1,229 @@
+//go:build !integration
+
+package services_test
+
+import (
+	"context"
+	"errors"
+	"go-starter/config"
+	"go-starter/internal/adapters/server/handlers"
+	"go-starter/internal/adapters/timegen"
+	"go-starter/internal/domain/ports"
+	"go-starter/internal/domain/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Health check settings used in tests.
+const (
+	healthCheckTimeout = 50 * time.Millisecond
+	healthCacheTTL     = 5 * time.Second
+)
+
+// newHealthService creates a health service registering the checks.
+func newHealthService(timeGenerator ports.TimeGenerator, checks ...ports.HealthCheck) *services.HealthService {
+	healthSvc := services.NewHealthService(&config.Health{CheckTimeout: healthCheckTimeout, CacheTTL: healthCacheTTL}, timeGenerator)
+	for _, check := range checks {
+		healthSvc.Register(check)
+	}
+	return healthSvc
+}
+
+// healthCheck returns a check of the dependency failing with err, if not nil.
+func healthCheck(name string, critical bool, err error) ports.HealthCheck {
+	return ports.HealthCheck{Name: name, Critical: critical, Check: func(context.Context) error { return err }}
+}
+
+func TestHealthService_Check(t *testing.T) {
+	t.Parallel()
+
+	down := errors.New("connection refused")
+	builder := NewTestBuilder().Build()
+	setSendError(t, builder.MailerAdapter, down)
+
+	tests := map[string]struct {
+		checks         []ports.HealthCheck
+		expectedStatus string
+		expectedDown   []string
+	}{
+		"all dependencies up should be ready": {
+			checks:         []ports.HealthCheck{healthCheck("postgres", true, nil), healthCheck("cache", false, nil)},
+			expectedStatus: ports.HealthStatusReady,
+		},
+		"non-critical dependency down should be degraded": {
+			checks:         []ports.HealthCheck{healthCheck("postgres", true, nil), {Name: "mailer", Check: builder.MailerAdapter.Ping}},
+			expectedStatus: ports.HealthStatusDegraded,
+			expectedDown:   []string{"mailer"},
+		},
+		"critical dependency down should be unavailable": {
+			checks:         []ports.HealthCheck{healthCheck("postgres", true, down), healthCheck("cache", false, down)},
+			expectedStatus: ports.HealthStatusUnavailable,
+			expectedDown:   []string{"postgres", "cache"},
+		},
+		"panicking check should be down": {
+			checks: []ports.HealthCheck{{Name: "postgres", Critical: true, Check: func(context.Context) error {
+				panic("nil database")
+			}}},
+			expectedStatus: ports.HealthStatusUnavailable,
+			expectedDown:   []string{"postgres"},
+		},
+		"check ignoring its timeout should be down": {
+			checks: []ports.HealthCheck{{Name: "storage", Check: func(context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			}}},
+			expectedStatus: ports.HealthStatusDegraded,
+			expectedDown:   []string{"storage"},
+		},
+		"check with its own timeout should use it": {
+			checks: []ports.HealthCheck{{Name: "storage", Timeout: 500 * time.Millisecond, Check: func(ctx context.Context) error {
+				select {
+				case <-time.After(2 * healthCheckTimeout):
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}}},
+			expectedStatus: ports.HealthStatusReady,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			healthSvc := newHealthService(timegen.NewTimeGenerator(), tt.checks...)
+
+			// Act
+			start := time.Now()
+			report := healthSvc.Check(context.Background())
+
+			// Assert
+			if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+				t.Errorf("expected the checks to stop at their timeout, took %s", elapsed)
+			}
+			if report.Status != tt.expectedStatus {
+				t.Errorf("expected status %s, got %s", tt.expectedStatus, report.Status)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Fatalf("expected %d check results, got %d", len(tt.checks), len(report.Checks))
+			}
+
+			var gotDown []string
+			for i, result := range report.Checks {
+				if result.Name != tt.checks[i].Name {
+					t.Errorf("expected check %d to be %s, got %s", i, tt.checks[i].Name, result.Name)
+				}
+				if result.Status == ports.HealthCheckStatusDown {
+					gotDown = append(gotDown, result.Name)
+					if result.Error == "" {
+						t.Errorf("expected the failure of %s to be reported", result.Name)
+					}
+				}
+			}
+			if strings.Join(gotDown, ",") != strings.Join(tt.expectedDown, ",") {
+				t.Errorf("expected down dependencies %v, got %v", tt.expectedDown, gotDown)
+			}
+		})
+	}
+}
+
+func TestHealthService_CachesReport(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	timeGenerator := timegen.NewTimeGeneratorMock(time.Now())
+	var calls atomic.Int32
+	healthSvc := newHealthService(timeGenerator, ports.HealthCheck{Name: "postgres", Critical: true, Check: func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}})
+
+	// Act
+	healthSvc.Check(context.Background())
+	healthSvc.Check(context.Background())
+	cachedCalls := calls.Load()
+	timeGenerator.Advance(healthCacheTTL)
+	healthSvc.Check(context.Background())
+
+	// Assert
+	if cachedCalls != 1 {
+		t.Errorf("expected the report to be reused within its TTL, got %d checks", cachedCalls)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected the checks to run again once the report expired, got %d checks", got)
+	}
+}
+
+func TestHealthHandler_Probes(t *testing.T) {
+	t.Parallel()
+
+	down := errors.New("connection refused")
+
+	tests := map[string]struct {
+		target         string
+		checks         []ports.HealthCheck
+		expectedStatus int
+		expectedBody   string
+	}{
+		"liveness should not check the dependencies": {
+			target:         "/livez",
+			checks:         []ports.HealthCheck{healthCheck("postgres", true, down)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		"verbose liveness should list its checks": {
+			target:         "/livez?verbose",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[+]ping ok\nlivez check passed\n",
+		},
+		"readiness should pass when all dependencies are up": {
+			target:         "/readyz",
+			checks:         []ports.HealthCheck{healthCheck("postgres", true, nil)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		"verbose readiness should withhold the failures": {
+			target:         "/readyz?verbose",
+			checks:         []ports.HealthCheck{healthCheck("postgres", true, nil), healthCheck("cache", false, down)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[+]postgres ok\n[-]cache failed: reason withheld\nreadyz check passed\n",
+		},
+		"readiness should fail when a critical dependency is down": {
+			target:         "/readyz",
+			checks:         []ports.HealthCheck{healthCheck("postgres", true, down), healthCheck("cache", false, nil)},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "[-]postgres failed: reason withheld\n[+]cache ok\nreadyz check failed\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			builder := NewTestBuilder().Build()
+			handler := handlers.NewHealthHandler(newHealthService(builder.TimeGenerator, tt.checks...), builder.CacheService)
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /livez", handler.Livez)
+			mux.HandleFunc("GET /readyz", handler.Readyz)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			// Assert
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, recorder.Code)
+			}
+			if got := recorder.Body.String(); got != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, got)
+			}
+		})
+	}
+}
@@ -1,11 +1,29 @@
 package services_test
 
 import (
+	"context"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
 	"go-starter/internal/domain/ports"
+	"go-starter/internal/domain/services"
+	"go-starter/internal/domain/utils"
 	"testing"
 	"time"
 )
 
+// getCachedUser returns the user cached by the user service, failing the test if it is not cached.
+func getCachedUser(t *testing.T, cacheSvc ports.CacheService, userID entities.UserID) entities.User {
+	t.Helper()
+	key := utils.GenerateCacheKey(services.UserCachePrefix, userID.String())
+	user, err := services.GetOrLoad(context.Background(), cacheSvc, key, ports.LoadOptions{}, func(_ context.Context) (*entities.User, error) {
+		return nil, domain.ErrCacheNotFound
+	})
+	if err != nil {
+		t.Fatalf("user %s not cached: %v", userID, err)
+	}
+	return *user
+}
+
 func getSentEmailsCount(t *testing.T, mailer ports.MailerAdapter) int {
 	t.Helper()
 	if v, ok := mailer.(interface{ SentEmailsCount() int }); ok {
@@ -15,9 +33,110 @@ func getSentEmailsCount(t *testing.T, mailer ports.MailerAdapter) int {
 	return 0
 }
 
+func getLastSentTo(t *testing.T, mailer ports.MailerAdapter, email string) ports.EmailMessage {
+	t.Helper()
+	v, ok := mailer.(interface {
+		GetLastSentTo(email string) (ports.EmailMessage, error)
+	})
+	if !ok {
+		t.Fatal("the mailer adapter does not implement GetLastSentTo()")
+	}
+	msg, err := v.GetLastSentTo(email)
+	if err != nil {
+		t.Fatalf("no email sent to %s: %v", email, err)
+	}
+	return msg
+}
+
 func advanceTime(t *testing.T, timeGenerator ports.TimeGenerator, duration time.Duration) {
 	t.Helper()
 	if v, ok := timeGenerator.(interface{ Advance(d time.Duration) }); ok {
 		v.Advance(duration)
 	}
 }
+
+func getConfirmedSubscriptionsCount(t *testing.T, webhook ports.MailerWebhookAdapter) int {
+	t.Helper()
+	if v, ok := webhook.(interface{ ConfirmedSubscriptionsCount() int }); ok {
+		return v.ConfirmedSubscriptionsCount()
+	}
+	t.Fatal("the mailer webhook adapter does not implement ConfirmedSubscriptionsCount()")
+	return 0
+}
+
+func setSendError(t *testing.T, mailer ports.MailerAdapter, err error) {
+	t.Helper()
+	v, ok := mailer.(interface{ SetSendError(err error) })
+	if !ok {
+		t.Fatal("the mailer adapter does not implement SetSendError()")
+	}
+	v.SetSendError(err)
+}
+
+func suppressEmail(t *testing.T, builder *TestBuilder, email string) {
+	t.Helper()
+	err := builder.SuppressionRepo.Upsert(context.Background(), &entities.EmailSuppression{
+		Email:  email,
+		Reason: entities.SuppressionReasonBounce,
+	})
+	if err != nil {
+		t.Fatalf("failed to suppress email: %v", err)
+	}
+}
+
+func putPresigned(t *testing.T, adapter ports.FileUploadAdapter, key, contentType string, content []byte) {
+	t.Helper()
+	v, ok := adapter.(interface {
+		PutPresigned(key, contentType string, content []byte)
+	})
+	if !ok {
+		t.Fatal("the file upload adapter does not implement PutPresigned()")
+	}
+	v.PutPresigned(key, contentType, content)
+}
+
+func fileExists(t *testing.T, adapter ports.FileUploadAdapter, key string) bool {
+	t.Helper()
+	if v, ok := adapter.(interface{ Exists(key string) bool }); ok {
+		return v.Exists(key)
+	}
+	t.Fatal("the file upload adapter does not implement Exists()")
+	return false
+}
+
+func setScanError(t *testing.T, scanner ports.FileScanner, err error) {
+	t.Helper()
+	v, ok := scanner.(interface{ SetError(err error) })
+	if !ok {
+		t.Fatal("the file scanner does not implement SetError()")
+	}
+	v.SetError(err)
+}
+
+func getScanCount(t *testing.T, scanner ports.FileScanner) int {
+	t.Helper()
+	v, ok := scanner.(interface{ Scans() int })
+	if !ok {
+		t.Fatal("the file scanner does not implement Scans()")
+	}
+	return v.Scans()
+}
+
+func avatarFileIDs(avatarFiles map[string]*entities.File) []entities.FileID {
+	fileIDs := make([]entities.FileID, 0, len(avatarFiles))
+	for _, file := range avatarFiles {
+		fileIDs = append(fileIDs, file.ID)
+	}
+	return fileIDs
+}
+
+func getMetricCount(t *testing.T, metrics ports.Metrics, name string, labels ...string) int {
+	t.Helper()
+	v, ok := metrics.(interface {
+		Count(name string, labels ...string) int
+	})
+	if !ok {
+		t.Fatal("the metrics adapter does not implement Count()")
+	}
+	return v.Count(name, labels...)
+}
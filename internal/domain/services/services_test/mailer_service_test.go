@@ -3,11 +3,19 @@
 package services_test
 
 import (
+	"context"
 	"errors"
+	"go-starter/internal/adapters/timegen"
 	"go-starter/internal/domain"
+	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/mailtemplates"
 	"go-starter/internal/domain/ports"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 func TestMailerService_Send_Debug(t *testing.T) {
@@ -70,7 +78,7 @@ func TestMailerService_Send_Debug(t *testing.T) {
 			t.Parallel()
 
 			builder := NewTestBuilder().Build()
-			err := builder.MailerService.Send(tt.input)
+			err := builder.MailerService.Send(context.Background(), tt.input)
 			sentCount := getSentEmailsCount(t, builder.MailerAdapter)
 
 			if !errors.Is(err, tt.expectedErr) {
@@ -145,7 +153,7 @@ func TestMailerService_Send_Production(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 			builder := NewTestBuilder().SetEnvToProduction().Build()
-			err := builder.MailerService.Send(tt.input)
+			err := builder.MailerService.Send(context.Background(), tt.input)
 			sentCount := getSentEmailsCount(t, builder.MailerAdapter)
 			if !errors.Is(err, tt.expectedErr) {
 				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
@@ -159,3 +167,530 @@ func TestMailerService_Send_Production(t *testing.T) {
 		})
 	}
 }
+
+func TestMailerService_Send_Suppressed(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	tests := map[string]struct {
+		input             *ports.EmailMessage
+		expectedTo        []string
+		expectedSentCount int
+		expectedErr       error
+	}{
+		"suppressed recipient should not be sent": {
+			input: &ports.EmailMessage{
+				To:      []string{"bounced@example.com"},
+				Subject: "Test",
+				Body:    "Test",
+			},
+			expectedTo:        []string{"bounced@example.com"},
+			expectedSentCount: 0,
+			expectedErr:       domain.ErrEmailSuppressed,
+		},
+		"suppressed recipient should be matched case insensitively": {
+			input: &ports.EmailMessage{
+				To:      []string{"Bounced@Example.com"},
+				Subject: "Test",
+				Body:    "Test",
+			},
+			expectedTo:        []string{"Bounced@Example.com"},
+			expectedSentCount: 0,
+			expectedErr:       domain.ErrEmailSuppressed,
+		},
+		"remaining recipients should be sent": {
+			input: &ports.EmailMessage{
+				To:      []string{"bounced@example.com", "test@example.com"},
+				Subject: "Test",
+				Body:    "Test",
+			},
+			expectedTo:        []string{"test@example.com"},
+			expectedSentCount: 1,
+			expectedErr:       domain.ErrEmailSuppressed,
+		},
+		"not suppressed recipient should be sent": {
+			input: &ports.EmailMessage{
+				To:      []string{"test@example.com"},
+				Subject: "Test",
+				Body:    "Test",
+			},
+			expectedTo:        []string{"test@example.com"},
+			expectedSentCount: 1,
+			expectedErr:       nil,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			builder := NewTestBuilder().SetEnvToProduction().Build()
+			err := builder.SuppressionRepo.Upsert(ctx, &entities.EmailSuppression{
+				Email:  "bounced@example.com",
+				Reason: entities.SuppressionReasonBounce,
+			})
+			if err != nil {
+				t.Fatalf("failed to suppress email: %v", err)
+			}
+
+			err = builder.MailerService.Send(ctx, tt.input)
+			sentCount := getSentEmailsCount(t, builder.MailerAdapter)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if !reflect.DeepEqual(tt.input.To, tt.expectedTo) {
+				t.Errorf("expected recipients %v, got %v", tt.expectedTo, tt.input.To)
+			}
+			if tt.expectedSentCount != sentCount {
+				t.Errorf("expected count %v, got %v", tt.expectedSentCount, sentCount)
+			}
+		})
+	}
+}
+
+func TestMailerService_Send_RecordsDelivery(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	tests := map[string]struct {
+		input              *ports.EmailMessage
+		sendErr            error
+		expectedStatus     entities.EmailDeliveryStatus
+		expectedRecipients []string
+		expectedAttempts   int
+		expectedErr        error
+	}{
+		"sent email should be recorded as sent": {
+			input: &ports.EmailMessage{
+				Template: "test",
+				To:       []string{"test@example.com"},
+				Subject:  "Test",
+				Body:     "Test",
+			},
+			expectedStatus:     entities.EmailDeliverySent,
+			expectedRecipients: []string{"test@example.com"},
+			expectedAttempts:   1,
+			expectedErr:        nil,
+		},
+		"failed email should be recorded as failed": {
+			input: &ports.EmailMessage{
+				Template: "test",
+				To:       []string{"test@example.com"},
+				Subject:  "Test",
+				Body:     "Test",
+			},
+			sendErr:            errors.New("provider unavailable"),
+			expectedStatus:     entities.EmailDeliveryFailed,
+			expectedRecipients: []string{"test@example.com"},
+			expectedAttempts:   1,
+			expectedErr:        domain.ErrInternal,
+		},
+		"email with secrets should be recorded without them": {
+			input: &ports.EmailMessage{
+				Template: "test",
+				To:       []string{"test@example.com"},
+				Subject:  "Test",
+				Body:     "Test https://example.com/reset?token=secret-token",
+				Secrets:  []string{"secret-token"},
+			},
+			expectedStatus:     entities.EmailDeliverySent,
+			expectedRecipients: []string{"test@example.com"},
+			expectedAttempts:   1,
+			expectedErr:        nil,
+		},
+		"suppressed email should be recorded as suppressed": {
+			input: &ports.EmailMessage{
+				Template: "test",
+				To:       []string{"bounced@example.com"},
+				Subject:  "Test",
+				Body:     "Test",
+			},
+			expectedStatus:     entities.EmailDeliverySuppressed,
+			expectedRecipients: []string{"bounced@example.com"},
+			expectedAttempts:   0,
+			expectedErr:        domain.ErrEmailSuppressed,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			builder := NewTestBuilder().Build()
+			suppressEmail(t, builder, "bounced@example.com")
+			setSendError(t, builder.MailerAdapter, tt.sendErr)
+
+			err := builder.MailerService.Send(ctx, tt.input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			deliveries, err := builder.MailerService.ListDeliveries(ctx, entities.EmailDeliveryFilter{})
+			if err != nil {
+				t.Fatalf("failed to list deliveries: %v", err)
+			}
+			if len(deliveries) != 1 {
+				t.Fatalf("expected 1 delivery, got %d", len(deliveries))
+			}
+
+			delivery := deliveries[0]
+			if delivery.Status != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v", tt.expectedStatus, delivery.Status)
+			}
+			if !reflect.DeepEqual(delivery.Recipients, tt.expectedRecipients) {
+				t.Errorf("expected recipients %v, got %v", tt.expectedRecipients, delivery.Recipients)
+			}
+			if delivery.Attempts != tt.expectedAttempts {
+				t.Errorf("expected attempts %v, got %v", tt.expectedAttempts, delivery.Attempts)
+			}
+			if delivery.Template != tt.input.Template {
+				t.Errorf("expected template %v, got %v", tt.input.Template, delivery.Template)
+			}
+			// The original message is recorded, not the one redirected in debug mode.
+			if delivery.Subject != "Test" {
+				t.Errorf("expected subject %v, got %v", "Test", delivery.Subject)
+			}
+			for _, secret := range tt.input.Secrets {
+				if strings.Contains(delivery.Body, secret) {
+					t.Errorf("expected secret %q to be redacted from body %q", secret, delivery.Body)
+				}
+			}
+			if delivery.Redacted != (len(tt.input.Secrets) > 0) {
+				t.Errorf("expected redacted %v, got %v", len(tt.input.Secrets) > 0, delivery.Redacted)
+			}
+			if tt.expectedStatus == entities.EmailDeliverySent && delivery.ProviderMessageID == "" {
+				t.Error("expected provider message ID to be recorded")
+			}
+			if tt.expectedStatus == entities.EmailDeliveryFailed && delivery.Error == "" {
+				t.Error("expected error to be recorded")
+			}
+		})
+	}
+}
+
+func TestMailerService_ListDeliveries(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	messages := []*ports.EmailMessage{
+		{Template: "verify_email", To: []string{"john@example.com"}, Subject: "Test", Body: "Test"},
+		{Template: "reset_password", To: []string{"john@example.com"}, Subject: "Test", Body: "Test"},
+		{Template: "verify_email", To: []string{"jane@example.com"}, Subject: "Test", Body: "Test"},
+		{Template: "verify_email", To: []string{"bounced@example.com"}, Subject: "Test", Body: "Test"},
+	}
+	suppressEmail(t, builder, "bounced@example.com")
+	for _, msg := range messages {
+		_ = builder.MailerService.Send(ctx, msg)
+	}
+
+	tests := map[string]struct {
+		filter        entities.EmailDeliveryFilter
+		expectedCount int
+	}{
+		"no filter should list all deliveries": {
+			filter:        entities.EmailDeliveryFilter{},
+			expectedCount: 4,
+		},
+		"status filter should list matching deliveries": {
+			filter:        entities.EmailDeliveryFilter{Status: entities.EmailDeliverySuppressed},
+			expectedCount: 1,
+		},
+		"template filter should list matching deliveries": {
+			filter:        entities.EmailDeliveryFilter{Template: "verify_email"},
+			expectedCount: 3,
+		},
+		"recipient filter should be case insensitive": {
+			filter:        entities.EmailDeliveryFilter{Recipient: "John@Example.com"},
+			expectedCount: 2,
+		},
+		"combined filters should list matching deliveries": {
+			filter:        entities.EmailDeliveryFilter{Template: "verify_email", Recipient: "john@example.com"},
+			expectedCount: 1,
+		},
+		"limit should be applied": {
+			filter:        entities.EmailDeliveryFilter{Limit: 3},
+			expectedCount: 3,
+		},
+		"offset should be applied": {
+			filter:        entities.EmailDeliveryFilter{Offset: 3},
+			expectedCount: 1,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			deliveries, err := builder.MailerService.ListDeliveries(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("failed to list deliveries: %v", err)
+			}
+			if len(deliveries) != tt.expectedCount {
+				t.Errorf("expected %d deliveries, got %d", tt.expectedCount, len(deliveries))
+			}
+		})
+	}
+}
+
+func TestMailerService_Resend(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	tests := map[string]struct {
+		firstSendErr     error
+		secrets          []string
+		suppressAfter    bool
+		id               entities.EmailDeliveryID
+		expectedStatus   entities.EmailDeliveryStatus
+		expectedAttempts int
+		expectedErr      error
+	}{
+		"failed email should be resent": {
+			firstSendErr:     errors.New("provider unavailable"),
+			id:               1,
+			expectedStatus:   entities.EmailDeliverySent,
+			expectedAttempts: 2,
+			expectedErr:      nil,
+		},
+		"sent email should not be resent": {
+			id:          1,
+			expectedErr: domain.ErrEmailDeliveryNotResendable,
+		},
+		"failed email with secrets of an unknown template should not be resent": {
+			firstSendErr: errors.New("provider unavailable"),
+			secrets:      []string{"secret-token"},
+			id:           1,
+			expectedErr:  domain.ErrEmailDeliveryRedacted,
+		},
+		"unknown email should fail": {
+			firstSendErr: errors.New("provider unavailable"),
+			id:           42,
+			expectedErr:  domain.ErrEmailDeliveryNotFound,
+		},
+		"email suppressed since the failure should not be resent": {
+			firstSendErr:     errors.New("provider unavailable"),
+			suppressAfter:    true,
+			id:               1,
+			expectedStatus:   entities.EmailDeliverySuppressed,
+			expectedAttempts: 1,
+			expectedErr:      domain.ErrEmailSuppressed,
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			builder := NewTestBuilder().Build()
+			setSendError(t, builder.MailerAdapter, tt.firstSendErr)
+			_ = builder.MailerService.Send(ctx, &ports.EmailMessage{
+				Template: "test",
+				To:       []string{"test@example.com"},
+				Subject:  "Test",
+				Body:     "Test secret-token",
+				Secrets:  tt.secrets,
+			})
+			setSendError(t, builder.MailerAdapter, nil)
+			if tt.suppressAfter {
+				suppressEmail(t, builder, "test@example.com")
+			}
+
+			delivery, err := builder.MailerService.Resend(ctx, tt.id)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err == nil && delivery.ID != tt.id {
+				t.Errorf("expected delivery %v, got %v", tt.id, delivery.ID)
+			}
+
+			if tt.expectedStatus == "" {
+				return
+			}
+			stored, err := builder.DeliveryRepo.GetByID(ctx, tt.id)
+			if err != nil {
+				t.Fatalf("failed to get delivery: %v", err)
+			}
+			if stored.Status != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v", tt.expectedStatus, stored.Status)
+			}
+			if stored.Attempts != tt.expectedAttempts {
+				t.Errorf("expected attempts %v, got %v", tt.expectedAttempts, stored.Attempts)
+			}
+		})
+	}
+}
+
+func TestMailerService_Resend_RendersVerifyEmail(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	user, err := builder.UserService.Register(ctx, newValidUserToCreate())
+	if err != nil {
+		t.Fatalf("error while registering user: %v", err)
+	}
+	setSendError(t, builder.MailerAdapter, errors.New("provider unavailable"))
+	_ = builder.UserService.ResendEmailVerification(ctx, user.ID)
+	setSendError(t, builder.MailerAdapter, nil)
+	deliveries, err := builder.MailerService.ListDeliveries(ctx, entities.EmailDeliveryFilter{Status: entities.EmailDeliveryFailed})
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("expected the failed verification email to be recorded, got %v and error %v", deliveries, err)
+	}
+
+	// Act
+	delivery, err := builder.MailerService.Resend(ctx, deliveries[0].ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected the verification email to be resent, got %v", err)
+	}
+	if delivery.Status != entities.EmailDeliverySent || delivery.Attempts != 2 {
+		t.Errorf("expected the delivery to be sent on the second attempt, got %v after %d attempts", delivery.Status, delivery.Attempts)
+	}
+	sent := getLastSentTo(t, builder.MailerAdapter, debugEmail)
+	if len(sent.Secrets) != 1 || !strings.Contains(sent.Body, sent.Secrets[0]) {
+		t.Fatalf("expected the email to be rendered with a new token, got %q", sent.Body)
+	}
+	if sent.Subject != "[DEBUG] "+mailtemplates.VerifyEmailSubject(user.Locale) {
+		t.Errorf("expected the subject of the template, got %q", sent.Subject)
+	}
+	if err := builder.UserService.VerifyEmail(ctx, sent.Secrets[0]); err != nil {
+		t.Errorf("expected the new token to verify the email, got %v", err)
+	}
+}
+
+func TestMailerService_Send_Throttled(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	type attempt struct {
+		advance     time.Duration
+		to          []string
+		userID      entities.UserID
+		sendErr     error
+		expectedErr error
+	}
+
+	userID := entities.UserID(uuid.New())
+	sendTimes := func(n int, a attempt) []attempt {
+		attempts := make([]attempt, n)
+		for i := range attempts {
+			attempts[i] = a
+		}
+		return attempts
+	}
+
+	tests := map[string]struct {
+		template string
+		attempts []attempt
+	}{
+		"recipient over the hourly quota should be throttled": {
+			attempts: append(
+				sendTimes(mailRecipientHourlyQuota, attempt{to: []string{"test@example.com"}}),
+				attempt{to: []string{"test@example.com"}, expectedErr: domain.ErrEmailThrottled},
+			),
+		},
+		"recipient quota should be case insensitive": {
+			attempts: append(
+				sendTimes(mailRecipientHourlyQuota, attempt{to: []string{"test@example.com"}}),
+				attempt{to: []string{"Test@Example.com"}, expectedErr: domain.ErrEmailThrottled},
+			),
+		},
+		"recipient should be allowed again in the next hour": {
+			attempts: append(
+				sendTimes(mailRecipientHourlyQuota, attempt{to: []string{"test@example.com"}}),
+				attempt{to: []string{"test@example.com"}, expectedErr: domain.ErrEmailThrottled},
+				attempt{advance: time.Hour, to: []string{"test@example.com"}},
+			),
+		},
+		"recipient over the daily quota should be throttled": {
+			attempts: []attempt{
+				{to: []string{"test@example.com"}},
+				{to: []string{"test@example.com"}},
+				{to: []string{"test@example.com"}},
+				{advance: time.Hour, to: []string{"test@example.com"}},
+				{to: []string{"test@example.com"}},
+				{advance: time.Hour, to: []string{"test@example.com"}, expectedErr: domain.ErrEmailThrottled},
+			},
+		},
+		"throttled messages should not count against the daily quota": {
+			attempts: append(
+				append(
+					sendTimes(mailRecipientHourlyQuota, attempt{to: []string{"test@example.com"}}),
+					sendTimes(3, attempt{to: []string{"test@example.com"}, expectedErr: domain.ErrEmailThrottled})...,
+				),
+				attempt{advance: time.Hour, to: []string{"test@example.com"}},
+				attempt{to: []string{"test@example.com"}},
+				attempt{to: []string{"test@example.com"}, expectedErr: domain.ErrEmailThrottled},
+			),
+		},
+		"failed messages should not count against the quotas": {
+			attempts: append(
+				sendTimes(mailRecipientHourlyQuota, attempt{to: []string{"test@example.com"}, sendErr: errors.New("provider unavailable"), expectedErr: domain.ErrInternal}),
+				sendTimes(mailRecipientHourlyQuota, attempt{to: []string{"test@example.com"}})...,
+			),
+		},
+		"other recipients should still be sent": {
+			attempts: append(
+				sendTimes(mailRecipientHourlyQuota, attempt{to: []string{"test@example.com"}}),
+				attempt{to: []string{"test@example.com", "other@example.com"}, expectedErr: domain.ErrEmailThrottled},
+				attempt{to: []string{"other@example.com"}},
+			),
+		},
+		"user over the hourly quota should be throttled for any recipient": {
+			attempts: []attempt{
+				{to: []string{"test1@example.com"}, userID: userID},
+				{to: []string{"test2@example.com"}, userID: userID},
+				{to: []string{"test3@example.com"}, userID: userID},
+				{to: []string{"test4@example.com"}, userID: userID, expectedErr: domain.ErrEmailThrottled},
+				{to: []string{"test4@example.com"}},
+			},
+		},
+		"template quotas should override the default ones": {
+			template: mailtemplates.ResetPasswordTemplate,
+			attempts: append(
+				sendTimes(resetPasswordRecipientHourlyQuota, attempt{to: []string{"test@example.com"}}),
+				attempt{to: []string{"test@example.com"}, expectedErr: domain.ErrEmailThrottled},
+			),
+		},
+	}
+
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			// Start at the beginning of a day so that the hourly windows do not overlap two days.
+			start := time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC)
+			timeGenerator := timegen.NewTimeGeneratorMock(start)
+			builder := NewTestBuilder().WithTimeGenerator(timeGenerator).SetEnvToProduction().Build()
+
+			for i, a := range tt.attempts {
+				timeGenerator.Advance(a.advance)
+				setSendError(t, builder.MailerAdapter, a.sendErr)
+				err := builder.MailerService.Send(ctx, &ports.EmailMessage{
+					Template: tt.template,
+					UserID:   a.userID,
+					To:       a.to,
+					Subject:  "Test",
+					Body:     "Test",
+				})
+				if !errors.Is(err, a.expectedErr) {
+					t.Errorf("attempt %d: expected error %v, got %v", i+1, a.expectedErr, err)
+				}
+			}
+		})
+	}
+}
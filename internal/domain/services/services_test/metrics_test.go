@@ -0,0 +1,94 @@
+//go:build !integration
+
+package services_test
+
+import (
+	"context"
+	"errors"
+	"go-starter/internal/domain"
+	"go-starter/internal/domain/mailtemplates"
+	"go-starter/internal/domain/ports"
+	"testing"
+)
+
+func TestAuthService_RecordsDomainEvents(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	userToCreate := newValidUserToCreate()
+	password := userToCreate.Password
+
+	// Act
+	if _, err := builder.AuthService.Register(ctx, userToCreate); err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	if _, _, err := builder.AuthService.Login(ctx, userToCreate.Username, password); err != nil {
+		t.Fatalf("failed to login: %v", err)
+	}
+	if _, _, err := builder.AuthService.Login(ctx, userToCreate.Username, "wrong-password"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Fatalf("expected error %v, got %v", domain.ErrInvalidCredentials, err)
+	}
+	if err := builder.AuthService.ResetPassword(ctx, "invalid-token", password, password); err == nil {
+		t.Fatal("expected the password reset with an invalid token to fail")
+	}
+
+	// Assert
+	tests := map[string]struct {
+		event    string
+		outcome  string
+		expected int
+	}{
+		"registration should be counted":              {event: ports.DomainEventRegistration, outcome: ports.OutcomeSuccess, expected: 1},
+		"successful login should be counted":          {event: ports.DomainEventLogin, outcome: ports.OutcomeSuccess, expected: 1},
+		"failed login should be counted":              {event: ports.DomainEventLogin, outcome: ports.OutcomeFailure, expected: 1},
+		"failed password reset should be counted":     {event: ports.DomainEventPasswordReset, outcome: ports.OutcomeFailure, expected: 1},
+		"successful password reset should be missing": {event: ports.DomainEventPasswordReset, outcome: ports.OutcomeSuccess, expected: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := getMetricCount(t, builder.Metrics, "domain_events", tt.event, tt.outcome); got != tt.expected {
+				t.Errorf("expected %d %s events with outcome %s, got %d", tt.expected, tt.event, tt.outcome, got)
+			}
+		})
+	}
+}
+
+func TestMailerService_RecordsOutcomes(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	msg := func() *ports.EmailMessage {
+		return &ports.EmailMessage{
+			Template: mailtemplates.VerifyEmailTemplate,
+			To:       []string{"metrics@example.com"},
+			Subject:  "Subject",
+			Body:     "Body",
+		}
+	}
+
+	// Act
+	if err := builder.MailerService.Send(ctx, msg()); err != nil {
+		t.Fatalf("failed to send email: %v", err)
+	}
+	setSendError(t, builder.MailerAdapter, errors.New("provider down"))
+	if err := builder.MailerService.Send(ctx, msg()); !errors.Is(err, domain.ErrInternal) {
+		t.Fatalf("expected error %v, got %v", domain.ErrInternal, err)
+	}
+	suppressEmail(t, builder, "metrics@example.com")
+	if err := builder.MailerService.Send(ctx, msg()); !errors.Is(err, domain.ErrEmailSuppressed) {
+		t.Fatalf("expected error %v, got %v", domain.ErrEmailSuppressed, err)
+	}
+
+	// Assert
+	for _, outcome := range []string{"sent", "failed", "suppressed"} {
+		if got := getMetricCount(t, builder.Metrics, "mails", mailtemplates.VerifyEmailTemplate, outcome); got != 1 {
+			t.Errorf("expected 1 email with outcome %s, got %d", outcome, got)
+		}
+	}
+}
@@ -5,57 +5,105 @@ package services_test
 import (
 	"go-starter/config"
 	"go-starter/internal/adapters/errtracker"
+	"go-starter/internal/adapters/imaging"
 	"go-starter/internal/adapters/mailer"
+	"go-starter/internal/adapters/metrics"
+	"go-starter/internal/adapters/ratelimiter"
+	"go-starter/internal/adapters/scanner"
 	"go-starter/internal/adapters/storage/cache"
 	"go-starter/internal/adapters/storage/database/repositories"
 	"go-starter/internal/adapters/storage/fileupload"
 	"go-starter/internal/adapters/timegen"
 	"go-starter/internal/adapters/token"
+	"go-starter/internal/domain/mailtemplates"
 	"go-starter/internal/domain/ports"
 	"go-starter/internal/domain/services"
+	"testing"
+	"time"
 )
 
 // debugEmail is the address used for mail sent in dev mode.
 // All emails in non-production environments will be redirected to this address.
 const debugEmail = "debug@example.com"
 
+// Email quotas used in tests.
+const (
+	mailRecipientHourlyQuota          = 3
+	mailRecipientDailyQuota           = 5
+	mailUserHourlyQuota               = 3
+	mailUserDailyQuota                = 5
+	resetPasswordRecipientHourlyQuota = 1
+)
+
+// File storage settings used in tests.
+const (
+	presignedUploadDuration = 15 * time.Minute
+	privateFileURLDuration  = 10 * time.Minute
+	maxFileSize             = 1 << 20
+	userStorageQuota        = 2 << 20
+)
+
 type TestBuilder struct {
-	TimeGenerator     ports.TimeGenerator
-	CacheRepo         ports.CacheRepository
-	UserRepo          ports.UserRepository
-	TokenProvider     ports.TokenProvider
-	CacheService      ports.CacheService
-	UserService       ports.UserService
-	TokenService      ports.TokenService
-	AuthService       ports.AuthService
-	Config            *config.Container
-	ErrTrackerAdapter ports.ErrTrackerAdapter
-	MailerService     ports.MailerService
-	MailerAdapter     ports.MailerAdapter
-	FileUploadAdapter ports.FileUploadAdapter
-	FileUploadService ports.FileUploadService
+	TimeGenerator           ports.TimeGenerator
+	CacheRepo               ports.CacheRepository
+	UserRepo                ports.UserRepository
+	SuppressionRepo         ports.EmailSuppressionRepository
+	DeliveryRepo            ports.EmailDeliveryRepository
+	PendingUploadRepo       ports.PendingUploadRepository
+	FileRepo                ports.FileRepository
+	MailRateLimiter         ports.RateLimiter
+	TokenProvider           ports.TokenProvider
+	CacheService            ports.CacheService
+	UserService             ports.UserService
+	TokenService            ports.TokenService
+	AuthService             ports.AuthService
+	Config                  *config.Container
+	ErrTrackerAdapter       ports.ErrTrackerAdapter
+	MailerService           ports.MailerService
+	MailerAdapter           ports.MailerAdapter
+	MailerWebhookAdapter    ports.MailerWebhookAdapter
+	EmailSuppressionService ports.EmailSuppressionService
+	FileUploadAdapter       ports.FileUploadAdapter
+	FileUploadService       ports.FileUploadService
+	FileService             ports.FileService
+	FileServerAdapter       ports.FileServerAdapter
+	ImageProcessor          ports.ImageProcessor
+	FileScanner             ports.FileScanner
+	Metrics                 ports.Metrics
 }
 
 func NewTestBuilder() *TestBuilder {
 	fileUploadAdapter := fileupload.NewFileUploadAdapterMock()
 	mailerAdapter := mailer.NewMailerAdapterMock()
+	mailerWebhookAdapter := mailer.NewMailerWebhookAdapterMock()
 	errTrackerAdapter := errtracker.NewErrTrackerAdapterMock()
 	timeGenerator := timegen.NewTimeGenerator()
 	cacheRepo := cache.NewCacheRepositoryMock(timeGenerator)
 	tokenProvider := token.NewTokenProvider(timeGenerator, errTrackerAdapter)
 	userRepo := repositories.NewUserRepositoryMock()
+	suppressionRepo := repositories.NewEmailSuppressionRepositoryMock()
+	deliveryRepo := repositories.NewEmailDeliveryRepositoryMock()
 
 	cfg := setConfig()
 
 	return &TestBuilder{
-		TimeGenerator:     timeGenerator,
-		CacheRepo:         cacheRepo,
-		UserRepo:          userRepo,
-		TokenProvider:     tokenProvider,
-		Config:            cfg,
-		ErrTrackerAdapter: errTrackerAdapter,
-		MailerAdapter:     mailerAdapter,
-		FileUploadAdapter: fileUploadAdapter,
+		TimeGenerator:        timeGenerator,
+		CacheRepo:            cacheRepo,
+		UserRepo:             userRepo,
+		SuppressionRepo:      suppressionRepo,
+		DeliveryRepo:         deliveryRepo,
+		PendingUploadRepo:    repositories.NewPendingUploadRepositoryMock(),
+		FileRepo:             repositories.NewFileRepositoryMock(),
+		MailRateLimiter:      ratelimiter.NewRateLimiterMock(timeGenerator),
+		TokenProvider:        tokenProvider,
+		Config:               cfg,
+		ErrTrackerAdapter:    errTrackerAdapter,
+		MailerAdapter:        mailerAdapter,
+		MailerWebhookAdapter: mailerWebhookAdapter,
+		FileUploadAdapter:    fileUploadAdapter,
+		ImageProcessor:       imaging.NewProcessor(),
+		FileScanner:          scanner.NewFileScannerMock(),
+		Metrics:              metrics.NewMetricsMock(),
 	}
 }
 
@@ -63,16 +111,44 @@ func (tb *TestBuilder) WithTimeGenerator(tg ports.TimeGenerator) *TestBuilder {
 	tb.TimeGenerator = tg
 	tb.CacheRepo = cache.NewCacheRepositoryMock(tg)
 	tb.TokenProvider = token.NewTokenProvider(tg, tb.ErrTrackerAdapter)
+	tb.MailRateLimiter = ratelimiter.NewRateLimiterMock(tg)
+	return tb
+}
+
+// WithLocalStorage replaces the file upload mock with the local disk storage, in a temporary directory.
+func (tb *TestBuilder) WithLocalStorage(t *testing.T) *TestBuilder {
+	t.Helper()
+	local, err := fileupload.NewLocalAdapter(&config.FileUpload{
+		Driver:     config.StorageDriverLocal,
+		LocalDir:   t.TempDir(),
+		SigningKey: "test-signing-key",
+	}, "http://localhost:8080/v1", tb.ErrTrackerAdapter)
+	if err != nil {
+		t.Fatalf("failed to create local storage: %v", err)
+	}
+	tb.FileUploadAdapter = local
+	tb.FileServerAdapter = local
 	return tb
 }
 
 func (tb *TestBuilder) Build() *TestBuilder {
-	tb.FileUploadService = services.NewFileUploadService(tb.FileUploadAdapter)
-	tb.MailerService = services.NewMailerService(tb.Config, tb.MailerAdapter)
-	tb.CacheService = services.NewCacheService(tb.CacheRepo)
+	tb.FileService = services.NewFileService(tb.Config.FileUpload, tb.FileUploadAdapter, tb.FileRepo, tb.FileScanner, tb.TimeGenerator)
+	tb.FileUploadService = services.NewFileUploadService(
+		tb.Config.FileUpload,
+		tb.FileUploadAdapter,
+		tb.FileServerAdapter,
+		tb.ImageProcessor,
+		tb.FileScanner,
+		tb.FileService,
+		tb.PendingUploadRepo,
+		tb.TimeGenerator,
+	)
+	tb.EmailSuppressionService = services.NewEmailSuppressionService(tb.SuppressionRepo, tb.MailerWebhookAdapter)
+	tb.CacheService = services.NewCacheService(tb.CacheRepo, tb.TimeGenerator)
 	tb.TokenService = services.NewTokenService(tb.Config.Token, tb.TokenProvider, tb.CacheService)
+	tb.MailerService = services.NewMailerService(tb.Config, tb.MailerAdapter, tb.SuppressionRepo, tb.DeliveryRepo, tb.MailRateLimiter, tb.Metrics, tb.TokenService)
 	tb.UserService = services.NewUserService(tb.Config, tb.UserRepo, tb.CacheService, tb.TokenService, tb.MailerService, tb.FileUploadService)
-	tb.AuthService = services.NewAuthService(tb.Config, tb.UserService, tb.TokenService, tb.MailerService)
+	tb.AuthService = services.NewAuthService(tb.Config, tb.UserService, tb.TokenService, tb.MailerService, tb.Metrics)
 	return tb
 }
 
@@ -96,9 +172,35 @@ func setConfig() *config.Container {
 		DebugTo: debugEmail,
 	}
 
+	mailThrottleConfig := &config.MailThrottle{
+		Default: config.MailQuota{
+			RecipientHourly: mailRecipientHourlyQuota,
+			RecipientDaily:  mailRecipientDailyQuota,
+			UserHourly:      mailUserHourlyQuota,
+			UserDaily:       mailUserDailyQuota,
+		},
+		Templates: map[string]config.MailQuota{
+			mailtemplates.ResetPasswordTemplate: {
+				RecipientHourly: resetPasswordRecipientHourlyQuota,
+			},
+		},
+	}
+
+	fileUploadConfig := &config.FileUpload{
+		PresignedUploadDuration: presignedUploadDuration,
+		PrivateFileURLDuration:  privateFileURLDuration,
+		MaxFileSize:             maxFileSize,
+		UserStorageQuota:        userStorageQuota,
+	}
+
+	cacheConfig := &config.Cache{}
+
 	return &config.Container{
-		Application: appConfig,
-		Token:       tokenConfig,
-		Mailer:      mailerConfig,
+		Application:  appConfig,
+		Cache:        cacheConfig,
+		Token:        tokenConfig,
+		Mailer:       mailerConfig,
+		MailThrottle: mailThrottleConfig,
+		FileUpload:   fileUploadConfig,
 	}
 }
@@ -6,10 +6,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"go-starter/internal/domain"
 	"go-starter/internal/domain/entities"
+	"go-starter/internal/domain/i18n"
+	"go-starter/internal/domain/ports"
 	"go-starter/internal/domain/services"
-	"go-starter/internal/domain/utils"
+	"slices"
 	"strings"
 	"testing"
 
@@ -214,6 +217,42 @@ func TestUserService_GetByID(t *testing.T) {
 	}
 }
 
+// notFoundWrappingUserRepository is a user repository describing the users it does not find by ID in its errors.
+type notFoundWrappingUserRepository struct {
+	ports.UserRepository
+}
+
+// GetByID selects a user by their unique identifier, wrapping domain.ErrUserNotFound with the ID.
+func (r notFoundWrappingUserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	user, err := r.UserRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: id=%s", err, id)
+	}
+	return user, nil
+}
+
+func TestUserService_GetByID_NotFound(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder()
+	builder.UserRepo = notFoundWrappingUserRepository{builder.UserRepo}
+	builder.Build()
+	userID := entities.UserID(uuid.New())
+
+	for _, attempt := range []string{"first miss", "cached miss"} {
+		// Act
+		_, err := builder.UserService.GetByID(ctx, userID)
+
+		// Assert
+		// The handlers map the domain errors by equality.
+		if err != domain.ErrUserNotFound {
+			t.Errorf("expected error %v on the %s, got %v", domain.ErrUserNotFound, attempt, err)
+		}
+	}
+}
+
 func TestUserService_GetByID_Cache(t *testing.T) {
 	t.Parallel()
 
@@ -233,16 +272,7 @@ func TestUserService_GetByID_Cache(t *testing.T) {
 	}
 
 	// Act & Assert
-	cachedUser, err := builder.CacheService.Get(ctx, utils.GenerateCacheKey(services.UserCachePrefix, createdUser.ID))
-	if err != nil {
-		t.Errorf("error while getting user from cache: %v", err)
-	}
-
-	var deserializedUser entities.User
-	err = utils.Deserialize(cachedUser, &deserializedUser)
-	if err != nil {
-		t.Errorf("error while deserializing user: %v", err)
-	}
+	deserializedUser := getCachedUser(t, builder.CacheService, createdUser.ID)
 
 	if deserializedUser.ID != createdUser.ID {
 		t.Errorf("deserialized user does not match cache")
@@ -367,16 +397,7 @@ func TestUserService_VerifyEmail(t *testing.T) {
 				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
 			}
 			if tt.expectedErr == nil {
-				user, err := builder.CacheService.Get(ctx, utils.GenerateCacheKey(services.UserCachePrefix, userID))
-				if err != nil {
-					t.Errorf("error while getting user from cache: %v", err)
-				}
-
-				var deserializedUser entities.User
-				err = utils.Deserialize(user, &deserializedUser)
-				if err != nil {
-					t.Errorf("error while deserializing user: %v", err)
-				}
+				deserializedUser := getCachedUser(t, builder.CacheService, userID)
 
 				if !deserializedUser.IsEmailVerified {
 					t.Errorf("expected user to be verified in cache, got %v", deserializedUser.IsEmailVerified)
@@ -422,6 +443,24 @@ func TestUserService_ResendEmailVerification(t *testing.T) {
 			},
 			expectedErr: domain.ErrEmailAlreadyVerified,
 		},
+		"resend email verification should fail over the user quota": {
+			prepare: func(t *testing.T) (entities.UserID, *TestBuilder) {
+				builder := NewTestBuilder().Build()
+				user, err := builder.UserService.Register(ctx, newValidUserToCreate())
+				if err != nil {
+					t.Fatalf("error while registering user: %v", err)
+				}
+
+				for range mailUserHourlyQuota {
+					err = builder.UserService.ResendEmailVerification(ctx, user.ID)
+					if err != nil {
+						t.Fatalf("error while resending email verification: %v", err)
+					}
+				}
+				return user.ID, builder
+			},
+			expectedErr: domain.ErrEmailThrottled,
+		},
 	}
 
 	// Act & Assert
@@ -518,14 +557,14 @@ func TestUserService_UpdateAvatar_Is_Caching_User(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		input           string
-		expectedURL     string
-		expectCachedURL bool
+		input             []byte
+		expectedURLPrefix string
+		expectCachedURL   bool
 	}{
 		"update avatar successfully": {
-			input:           "avatar.jpg",
-			expectedURL:     "https://example.com/avatars/" + user.ID.String() + ".jpg",
-			expectCachedURL: true,
+			input:             newTestImage(t, "jpeg", 600, 400),
+			expectedURLPrefix: "https://example.com/" + services.PublicFilePath + "/" + user.ID.String() + "/",
+			expectCachedURL:   true,
 		},
 	}
 
@@ -534,30 +573,71 @@ func TestUserService_UpdateAvatar_Is_Caching_User(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			emptyFile := bytes.NewBuffer([]byte{})
-			_, err := builder.UserService.UpdateAvatar(ctx, user.ID, tt.input, emptyFile)
+			avatarURLs, err := builder.UserService.UpdateAvatar(ctx, user.ID, bytes.NewReader(tt.input))
 			if err != nil {
 				t.Fatalf("error while updating avatar: %v", err)
 			}
-
-			cachedUser, err := builder.CacheService.Get(ctx, utils.GenerateCacheKey(services.UserCachePrefix, user.ID))
-			if err != nil {
-				t.Errorf("error while getting user from cache: %v", err)
+			if !strings.HasPrefix(avatarURLs["512"], tt.expectedURLPrefix) {
+				t.Errorf("expected URL to start with %s, got %s", tt.expectedURLPrefix, avatarURLs["512"])
 			}
 
-			var deserializedUser entities.User
-			err = utils.Deserialize(cachedUser, &deserializedUser)
-			if err != nil {
-				t.Fatalf("error while deserializing user: %v", err)
-			}
+			deserializedUser := getCachedUser(t, builder.CacheService, user.ID)
 
-			if tt.expectCachedURL && *deserializedUser.AvatarURL != tt.expectedURL {
-				t.Errorf("expected cached URL to be %s, got %s", tt.expectedURL, *deserializedUser.AvatarURL)
+			if tt.expectCachedURL && deserializedUser.AvatarURLs["512"] != avatarURLs["512"] {
+				t.Errorf("expected cached URL to be %s, got %s", avatarURLs["512"], deserializedUser.AvatarURLs["512"])
 			}
 		})
 	}
 }
 
+func TestUserService_UpdateAvatar_Deletes_Previous_Files(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	builder := NewTestBuilder().Build()
+	user, err := builder.UserService.Register(ctx, newValidUserToCreate())
+	if err != nil {
+		t.Fatalf("error while registering user: %v", err)
+	}
+
+	_, err = builder.UserService.UpdateAvatar(ctx, user.ID, bytes.NewReader(newTestImage(t, "png", 300, 300)))
+	if err != nil {
+		t.Fatalf("error while updating avatar: %v", err)
+	}
+	previous, err := builder.UserRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("error while getting user: %v", err)
+	}
+	previousFileIDs := slices.Clone(previous.AvatarFileIDs)
+
+	// Act
+	_, err = builder.UserService.UpdateAvatar(ctx, user.ID, bytes.NewReader(newTestImage(t, "jpeg", 300, 300)))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("error while updating avatar: %v", err)
+	}
+	for _, id := range previousFileIDs {
+		if _, err := builder.FileRepo.GetByID(ctx, id); !errors.Is(err, domain.ErrFileNotFound) {
+			t.Errorf("expected previous avatar file %s to be deleted, got %v", id, err)
+		}
+	}
+
+	files, err := builder.FileService.List(ctx, user.ID, 0, 0)
+	if err != nil {
+		t.Fatalf("error while listing files: %v", err)
+	}
+	if len(files) != len(services.AvatarSizes) {
+		t.Errorf("expected %d files, got %d", len(services.AvatarSizes), len(files))
+	}
+	for _, file := range files {
+		if !fileExists(t, builder.FileUploadAdapter, file.Key) {
+			t.Errorf("expected avatar file %s to be stored", file.Key)
+		}
+	}
+}
+
 func TestUserService_DeleteAvatar_Is_Caching_User(t *testing.T) {
 	t.Parallel()
 
@@ -587,19 +667,60 @@ func TestUserService_DeleteAvatar_Is_Caching_User(t *testing.T) {
 				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
 			}
 
-			cachedUser, err := builder.CacheService.Get(ctx, utils.GenerateCacheKey(services.UserCachePrefix, user.ID))
-			if err != nil {
-				t.Errorf("error while getting user from cache: %v", err)
+			deserializedUser := getCachedUser(t, builder.CacheService, user.ID)
+
+			if deserializedUser.AvatarURLs != nil {
+				t.Errorf("expected avatar URLs to be empty, got %v", deserializedUser.AvatarURLs)
 			}
+		})
+	}
+}
+
+func TestUserService_UpdateLocale(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		input          string
+		expectedLocale i18n.Locale
+		expectedErr    error
+	}{
+		"update locale successfully": {
+			input:          "fr",
+			expectedLocale: i18n.LocaleFrench,
+			expectedErr:    nil,
+		},
+		"update locale with unsupported locale": {
+			input:          "xx",
+			expectedLocale: i18n.DefaultLocale,
+			expectedErr:    domain.ErrLocaleInvalid,
+		},
+	}
 
-			var deserializedUser entities.User
-			err = utils.Deserialize(cachedUser, &deserializedUser)
+	// Act & Assert
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := NewTestBuilder().Build()
+			user, err := builder.UserService.Register(ctx, newValidUserToCreate())
 			if err != nil {
-				t.Fatalf("error while deserializing user: %v", err)
+				t.Fatalf("error while registering user: %v", err)
 			}
 
-			if deserializedUser.AvatarURL != nil {
-				t.Errorf("expected avatar URL to be empty, got %s", *deserializedUser.AvatarURL)
+			err = builder.UserService.UpdateLocale(ctx, user.ID, tt.input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			updatedUser, err := builder.UserService.GetByID(ctx, user.ID)
+			if err != nil {
+				t.Fatalf("error while fetching user: %v", err)
+			}
+			if updatedUser.Locale != tt.expectedLocale {
+				t.Errorf("expected locale %s, got %s", tt.expectedLocale, updatedUser.Locale)
 			}
 		})
 	}
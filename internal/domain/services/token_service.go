@@ -47,7 +47,7 @@ func (ts *TokenService) GenerateAuthToken(ctx context.Context, userID entities.U
 
 	key := utils.GenerateCacheKey(entities.AccessToken.String(), token)
 
-	err = ts.cacheSvc.Set(ctx, key, []byte(userID.String()), ts.getTokenTypeDuration(entities.AccessToken))
+	err = ts.cacheSvc.Set(ctx, key, []byte(userID.String()), ts.getTokenTypeDuration(entities.AccessToken), UserCacheTag(userID))
 	if err != nil {
 		return "", err
 	}
@@ -56,7 +56,8 @@ func (ts *TokenService) GenerateAuthToken(ctx context.Context, userID entities.U
 }
 
 // VerifyAuthToken verifies an access token.
-// Returns the user ID or an error if the token is not found or if the token is invalid.
+// Returns the user ID, domain.ErrInvalidToken if the token is not found or invalid,
+// or domain.ErrServiceUnavailable if the tokens cannot be read because the cache is unavailable.
 func (ts *TokenService) VerifyAuthToken(ctx context.Context, token string) (entities.UserID, error) {
 	key := utils.GenerateCacheKey(entities.AccessToken.String(), token)
 	userIDBytes, err := ts.cacheSvc.Get(ctx, key)
@@ -67,14 +68,18 @@ func (ts *TokenService) VerifyAuthToken(ctx context.Context, token string) (enti
 		return entities.NilUserID, err
 	}
 
-	err = ts.cacheSvc.Set(ctx, key, userIDBytes, ts.getTokenTypeDuration(entities.AccessToken))
+	userID, err := entities.ParseUserID(string(userIDBytes))
 	if err != nil {
-		return entities.NilUserID, err
+		return entities.NilUserID, domain.ErrInternal
 	}
 
-	userID, err := entities.ParseUserID(string(userIDBytes))
+	// The session is only extended if it still exists, so that a revocation meanwhile is not undone.
+	err = ts.cacheSvc.Touch(ctx, key, ts.getTokenTypeDuration(entities.AccessToken), UserCacheTag(userID))
 	if err != nil {
-		return entities.NilUserID, domain.ErrInternal
+		if errors.Is(err, domain.ErrCacheNotFound) {
+			return entities.NilUserID, domain.ErrInvalidToken
+		}
+		return entities.NilUserID, err
 	}
 
 	return userID, nil
@@ -96,7 +101,7 @@ func (ts *TokenService) GenerateOneTimeToken(ctx context.Context, tokenType enti
 	}
 
 	key := utils.GenerateCacheKey(tokenType.String(), userID.String())
-	err = ts.cacheSvc.Set(ctx, key, []byte(token), ts.getTokenTypeDuration(tokenType))
+	err = ts.cacheSvc.Set(ctx, key, []byte(token), ts.getTokenTypeDuration(tokenType), UserCacheTag(userID))
 	if err != nil {
 		return "", err
 	}
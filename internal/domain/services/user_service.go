@@ -7,6 +7,7 @@ import (
 	"go-starter/internal/domain"
 	"go-starter/internal/domain/entities"
 	"go-starter/internal/domain/helpers"
+	"go-starter/internal/domain/i18n"
 	"go-starter/internal/domain/mailtemplates"
 	"go-starter/internal/domain/ports"
 	"go-starter/internal/domain/utils"
@@ -27,6 +28,7 @@ type UserService struct {
 	mailerSvc     ports.MailerService
 	fileUploadSvc ports.FileUploadService
 	cfg           *config.Container
+	cacheOpts     ports.LoadOptions
 }
 
 // NewUserService creates a new instance of UserService.
@@ -38,34 +40,49 @@ func NewUserService(cfg *config.Container, repo ports.UserRepository, cacheSvc p
 		mailerSvc:     mailerSvc,
 		fileUploadSvc: fileUploadSvc,
 		cfg:           cfg,
+		cacheOpts: ports.LoadOptions{
+			TTL:         userCacheTTL,
+			Jitter:      userCacheTTL / 10,
+			StaleTTL:    userCacheStaleTTL,
+			NotFoundErr: domain.ErrUserNotFound,
+			NotFoundTTL: userCacheNotFoundTTL,
+			LockTimeout: cfg.Cache.LoadLockTimeout,
+		},
 	}
 }
 
 // UserCachePrefix is the prefix for caching users.
 const UserCachePrefix = "user"
 
+// UserCacheTag returns the cache tag attached to the entries of a user: profile, sessions and one-time tokens.
+// Invalidating it drops them all.
+func UserCacheTag(userID entities.UserID) string {
+	return utils.GenerateCacheKey(UserCachePrefix, userID.String())
+}
+
+// Durations of the users in the cache.
+const (
+	userCacheTTL         = time.Hour
+	userCacheStaleTTL    = 5 * time.Minute
+	userCacheNotFoundTTL = time.Minute
+)
+
 // GetByID retrieves a user by their unique identifier.
+// The user is read through the cache, and from the database only when the cache is unavailable.
 // Returns the user entity if found or an error if not found or any other issue occurs.
 func (us *UserService) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
-	cacheUser, err := us.getUserFromCache(ctx, id)
-	if err == nil {
-		return cacheUser, nil
-	}
-
-	user, err := us.repo.GetByID(ctx, id)
-	if err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			return nil, err
+	cacheKey := utils.GenerateCacheKey(UserCachePrefix, id.String())
+	return GetOrLoad(ctx, us.cacheSvc, cacheKey, us.userCacheOptions(id), func(ctx context.Context) (*entities.User, error) {
+		user, err := us.repo.GetByID(ctx, id)
+		if err != nil {
+			// The error is returned bare, as it is once the miss is cached, for the handlers to map it.
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return nil, domain.ErrUserNotFound
+			}
+			return nil, domain.ErrInternal
 		}
-		return nil, domain.ErrInternal
-	}
-
-	err = us.cacheUser(ctx, user)
-	if err != nil {
-		return nil, err
-	}
-
-	return user, nil
+		return user, nil
+	})
 }
 
 // GetByUsername retrieves a user by their username.
@@ -120,6 +137,7 @@ func (us *UserService) Register(ctx context.Context, user *entities.User) (*enti
 		Username: user.Username,
 		Password: hashedPassword,
 		Email:    user.Email,
+		Locale:   user.Locale.OrDefault(),
 	}
 
 	created, err := us.repo.Create(ctx, userToCreate)
@@ -149,15 +167,12 @@ func (us *UserService) VerifyEmail(ctx context.Context, token string) error {
 		return domain.ErrInternal
 	}
 
-	err = us.cacheUser(ctx, user)
-	if err != nil {
-		return err
-	}
+	us.cacheUser(ctx, user)
 	return nil
 }
 
 // ResendEmailVerification resends a user email verification email.
-// Returns an error if the resend fails.
+// Returns domain.ErrEmailThrottled if too many emails were sent to the user, or an error if the resend fails.
 func (us *UserService) ResendEmailVerification(ctx context.Context, userID entities.UserID) error {
 	user, err := us.GetByID(ctx, userID)
 	if err != nil {
@@ -173,10 +188,14 @@ func (us *UserService) ResendEmailVerification(ctx context.Context, userID entit
 		return err
 	}
 
-	err = us.mailerSvc.Send(&ports.EmailMessage{
-		To:      []string{user.Email},
-		Subject: "Verify your email!",
-		Body:    mailtemplates.VerifyEmail(us.cfg.Application.BaseURL, token, us.cfg.Token.EmailVerificationTokenDuration),
+	err = us.mailerSvc.Send(ctx, &ports.EmailMessage{
+		Template: mailtemplates.VerifyEmailTemplate,
+		UserID:   userID,
+		Locale:   user.Locale,
+		To:       []string{user.Email},
+		Subject:  mailtemplates.VerifyEmailSubject(user.Locale),
+		Body:     mailtemplates.VerifyEmail(user.Locale, us.cfg.Application.BaseURL, token, us.cfg.Token.EmailVerificationTokenDuration),
+		Secrets:  []string{token},
 	})
 	if err != nil {
 		return err
@@ -185,8 +204,9 @@ func (us *UserService) ResendEmailVerification(ctx context.Context, userID entit
 	return nil
 }
 
-// UpdatePassword updates a user password.
-// Returns an error if the update fails (e.g., due to validation issues).
+// UpdatePassword updates a user password and revokes all the sessions and one-time tokens of the user.
+// Returns an error if the update fails (e.g., due to validation issues),
+// or domain.ErrServiceUnavailable if the sessions cannot be revoked because the cache is unavailable.
 func (us *UserService) UpdatePassword(ctx context.Context, userID entities.UserID, params entities.UpdateUserParams) error {
 	if params.Password == nil {
 		return domain.ErrPasswordRequired
@@ -208,32 +228,71 @@ func (us *UserService) UpdatePassword(ctx context.Context, userID entities.UserI
 	if err != nil {
 		return domain.ErrInternal
 	}
-	return nil
+
+	// The sessions and one-time tokens of the user are revoked with the previous password.
+	return us.cacheSvc.InvalidateTags(ctx, UserCacheTag(userID))
 }
 
 // UpdateAvatar updates a user avatar.
-// Returns an error if the update fails.
-func (us *UserService) UpdateAvatar(ctx context.Context, userID entities.UserID, filename string, file io.Reader) (string, error) {
-	avatarURL, err := us.fileUploadSvc.UploadAvatar(ctx, userID, filename, file)
+// Returns the URLs of the avatar variants keyed by size, or an error if the update fails.
+func (us *UserService) UpdateAvatar(ctx context.Context, userID entities.UserID, file io.Reader) (map[string]string, error) {
+	return us.replaceAvatar(ctx, userID, func() (map[string]*entities.File, error) {
+		return us.fileUploadSvc.UploadAvatar(ctx, userID, file)
+	})
+}
+
+// PresignAvatarUpload returns a short-lived URL the user uploads its avatar to, directly to the storage.
+// Returns an error if the declared file is not accepted or if the URL cannot be signed.
+func (us *UserService) PresignAvatarUpload(ctx context.Context, userID entities.UserID, contentType string, size int64) (*entities.PendingUpload, *ports.PresignedUpload, error) {
+	return us.fileUploadSvc.PresignAvatarUpload(ctx, userID, contentType, size)
+}
+
+// ConfirmAvatarUpload updates a user avatar from a file uploaded to a presigned URL.
+// Returns the URLs of the avatar variants keyed by size, or an error if the upload is not valid or the update fails.
+func (us *UserService) ConfirmAvatarUpload(ctx context.Context, userID entities.UserID, uploadID entities.UploadID) (map[string]string, error) {
+	return us.replaceAvatar(ctx, userID, func() (map[string]*entities.File, error) {
+		return us.fileUploadSvc.ConfirmAvatarUpload(ctx, userID, uploadID)
+	})
+}
+
+// replaceAvatar stores a new avatar with the given upload function, then saves its URLs and deletes the files of the previous one.
+func (us *UserService) replaceAvatar(ctx context.Context, userID entities.UserID, upload func() (map[string]*entities.File, error)) (map[string]string, error) {
+	user, err := us.GetByID(ctx, userID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	previousFileIDs := user.AvatarFileIDs
 
-	err = us.repo.UpdateAvatar(ctx, userID, avatarURL)
+	avatarFiles, err := upload()
 	if err != nil {
-		return "", domain.ErrInternal
+		return nil, err
 	}
 
-	user, err := us.repo.GetByID(ctx, userID)
+	avatarURLs := make(map[string]string, len(avatarFiles))
+	fileIDs := make([]entities.FileID, 0, len(avatarFiles))
+	for name, file := range avatarFiles {
+		avatarURLs[name] = file.URL
+		fileIDs = append(fileIDs, file.ID)
+	}
+
+	err = us.repo.UpdateAvatar(ctx, userID, avatarURLs, fileIDs)
 	if err != nil {
-		return "", err
+		_ = us.fileUploadSvc.DeleteAvatar(ctx, userID, fileIDs)
+		return nil, domain.ErrInternal
 	}
 
-	err = us.cacheUser(ctx, user)
+	// The previous avatar is no longer referenced, a failure only leaves its files in the storage of the user.
+	if len(previousFileIDs) > 0 {
+		_ = us.fileUploadSvc.DeleteAvatar(ctx, userID, previousFileIDs)
+	}
+
+	user, err = us.repo.GetByID(ctx, userID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return avatarURL, nil
+
+	us.cacheUser(ctx, user)
+	return avatarURLs, nil
 }
 
 // DeleteAvatar deletes a user avatar.
@@ -244,11 +303,11 @@ func (us *UserService) DeleteAvatar(ctx context.Context, userID entities.UserID)
 		return err
 	}
 
-	if user.AvatarURL == nil {
+	if len(user.AvatarURLs) == 0 && len(user.AvatarFileIDs) == 0 {
 		return nil
 	}
 
-	err = us.fileUploadSvc.DeleteAvatar(ctx, userID, *user.AvatarURL)
+	err = us.fileUploadSvc.DeleteAvatar(ctx, userID, user.AvatarFileIDs)
 	if err != nil {
 		return err
 	}
@@ -258,11 +317,31 @@ func (us *UserService) DeleteAvatar(ctx context.Context, userID entities.UserID)
 		return err
 	}
 
-	user.AvatarURL = nil
-	err = us.cacheUser(ctx, user)
+	user.AvatarURLs = nil
+	user.AvatarFileIDs = nil
+	us.cacheUser(ctx, user)
+	return nil
+}
+
+// UpdateLocale updates a user preferred locale, used for the emails sent to the user.
+// Returns an error if the locale is not supported or if the update fails.
+func (us *UserService) UpdateLocale(ctx context.Context, userID entities.UserID, locale string) error {
+	parsedLocale, err := i18n.ParseLocale(locale)
 	if err != nil {
 		return err
 	}
+
+	err = us.repo.UpdateLocale(ctx, userID, parsedLocale)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	user, err := us.repo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	us.cacheUser(ctx, user)
 	return nil
 }
 
@@ -338,36 +417,24 @@ func validateRegisterRequest(user *entities.User) error {
 	return nil
 }
 
-// getUserFromCache retrieves a user from the cache.
-// Returns an error if the retrieval fails.
-func (us *UserService) getUserFromCache(ctx context.Context, userID entities.UserID) (*entities.User, error) {
-	var user *entities.User
-	cacheKey := utils.GenerateCacheKey(UserCachePrefix, userID.String())
-	cachedUser, err := us.cacheSvc.Get(ctx, cacheKey)
-	if err != nil {
-		return nil, err
-	}
-
-	err = utils.Deserialize(cachedUser, &user)
-	if err != nil {
-		return nil, domain.ErrInternal
-	}
-	return user, nil
+// userCacheOptions returns the options of the cache of a user, tagged with the user.
+func (us *UserService) userCacheOptions(userID entities.UserID) ports.LoadOptions {
+	opts := us.cacheOpts
+	opts.Tags = []string{UserCacheTag(userID)}
+	return opts
 }
 
-// cacheUser caches a user in the cache.
-// Returns an error if the caching fails.
-func (us *UserService) cacheUser(ctx context.Context, user *entities.User) error {
-	userSerialized, err := utils.Serialize(user)
-	if err != nil {
-		return domain.ErrInternal
-	}
-
+// cacheUser caches a user in the cache, once the user is saved in the database.
+// A failure is not fatal, the change being committed: the entry of the user is deleted on a best-effort basis,
+// so that the user is loaded from the database again once the cache is available.
+func (us *UserService) cacheUser(ctx context.Context, user *entities.User) {
 	cacheKey := utils.GenerateCacheKey(UserCachePrefix, user.ID.String())
 
-	err = us.cacheSvc.Set(ctx, cacheKey, userSerialized, time.Hour)
+	userSerialized, err := utils.Serialize(user)
+	if err == nil {
+		err = us.cacheSvc.Store(ctx, cacheKey, userSerialized, us.userCacheOptions(user.ID))
+	}
 	if err != nil {
-		return domain.ErrInternal
+		_ = us.cacheSvc.Delete(ctx, cacheKey)
 	}
-	return nil
 }
@@ -26,6 +26,14 @@ var (
 	ErrNameRequired = errors.New("name is required")
 	// ErrEmailRequired represents an error when email is required but not provided.
 	ErrEmailRequired = errors.New("email is required")
+	// ErrLocaleRequired represents an error when the locale is required but not provided.
+	ErrLocaleRequired = errors.New("locale is required")
+	// ErrContentTypeRequired represents an error when the content type of a file is required but not provided.
+	ErrContentTypeRequired = errors.New("content type is required")
+	// ErrFileSizeRequired represents an error when the size of a file is required but not provided.
+	ErrFileSizeRequired = errors.New("file size is required")
+	// ErrLogLevelRequired represents an error when the log level is required but not provided.
+	ErrLogLevelRequired = errors.New("log level is required")
 )
 
 // Other validation errors
@@ -48,4 +56,8 @@ var (
 	ErrEmailInvalid = errors.New("email is invalid")
 	// ErrEmailConflict represents a conflict error when trying to create a user with an existing email.
 	ErrEmailConflict = errors.New("email already taken")
+	// ErrLocaleInvalid represents an error when the locale is not supported.
+	ErrLocaleInvalid = errors.New("locale is not supported")
+	// ErrLogLevelInvalid represents an error when the log level is not one of debug, info, warn and error.
+	ErrLogLevelInvalid = errors.New("log level should be debug, info, warn or error")
 )
@@ -103,3 +103,17 @@ func GetOptionalFloat64(key string, defaultVal float64) float64 {
 	}
 	return f
 }
+
+// GetOptionalBool retrieves the value associated with the specified key from the .env file,
+// converting it to a bool. If the key is not set or the value cannot be parsed to a bool, it returns the default value.
+func GetOptionalBool(key string, defaultVal bool) bool {
+	val := GetOptionalString(key, "")
+	if val == "" {
+		return defaultVal
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return defaultVal
+	}
+	return b
+}